@@ -0,0 +1,169 @@
+package myddlmaker
+
+import (
+	"fmt"
+	"log"
+)
+
+// Check is the identifier of a validation check.
+// It is used to configure the severity in [Config], and to suppress the check with the nolint tag option.
+type Check string
+
+const (
+	// CheckFKIndex reports the columns of foreign key constraints that are not covered by any index.
+	CheckFKIndex Check = "fk-index"
+
+	// CheckFKRefIndex reports the referenced columns of foreign key constraints that are not covered by any index.
+	CheckFKRefIndex Check = "fk-ref-index"
+
+	// CheckFKType reports the columns of foreign key constraints whose types differ from the referenced columns.
+	CheckFKType Check = "fk-type"
+
+	// CheckFKCollation reports the columns of foreign key constraints whose character sets or collations
+	// differ from the referenced columns.
+	CheckFKCollation Check = "fk-collation"
+
+	// CheckReservedWord reports the table names and the column names that are reserved words.
+	CheckReservedWord Check = "reserved-word"
+
+	// CheckKeyLength reports the index keys that exceed the maximum key length.
+	CheckKeyLength Check = "key-length"
+
+	// CheckRowSize reports the tables whose row size is close to or exceeds the maximum row size.
+	CheckRowSize Check = "row-size"
+
+	// CheckLimits reports the tables that exceed the limits of the number of columns, indexes, etc.
+	CheckLimits Check = "limits"
+
+	// CheckNullablePK reports the nullable columns in primary keys.
+	CheckNullablePK Check = "nullable-pk"
+
+	// CheckRedundantIndex reports the duplicate and redundant indexes.
+	CheckRedundantIndex Check = "redundant-index"
+
+	// CheckDefaultValue reports the default values that are not compatible with the column types.
+	CheckDefaultValue Check = "default-value"
+
+	// CheckNaming reports the violations of [NamingRules].
+	CheckNaming Check = "naming"
+
+	// CheckMissingPK reports the tables that don't have any primary key.
+	CheckMissingPK Check = "missing-pk"
+
+	// CheckSpatialIndex reports the spatial indexes on the columns that are nullable, have no SRID, or are not geometry types.
+	CheckSpatialIndex Check = "spatial-index"
+
+	// CheckAutoIncrement reports the auto-increment columns that are not the first column of any key,
+	// and the tables that have multiple auto-increment columns.
+	CheckAutoIncrement Check = "auto-increment"
+
+	// CheckFullTextIndex reports the full-text indexes on the columns that are not CHAR, VARCHAR or TEXT.
+	CheckFullTextIndex Check = "fulltext-index"
+
+	// CheckCommentLength reports the comments that exceed the maximum length.
+	CheckCommentLength Check = "comment-length"
+)
+
+var checks = map[Check]struct{}{
+	CheckFKIndex:        {},
+	CheckFKRefIndex:     {},
+	CheckFKType:         {},
+	CheckFKCollation:    {},
+	CheckReservedWord:   {},
+	CheckKeyLength:      {},
+	CheckRowSize:        {},
+	CheckLimits:         {},
+	CheckNullablePK:     {},
+	CheckRedundantIndex: {},
+	CheckDefaultValue:   {},
+	CheckNaming:         {},
+	CheckMissingPK:      {},
+	CheckSpatialIndex:   {},
+	CheckAutoIncrement:  {},
+	CheckFullTextIndex:  {},
+	CheckCommentLength:  {},
+}
+
+// Severity is the severity of a validation check.
+type Severity int
+
+const (
+	// SeverityDefault uses the default severity of the check.
+	SeverityDefault Severity = iota
+
+	// SeverityError makes the check an error, and stops the generation.
+	SeverityError
+
+	// SeverityWarning makes the check a warning, which doesn't stop the generation.
+	SeverityWarning
+
+	// SeverityOff disables the check.
+	SeverityOff
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityDefault:
+		return "default"
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityOff:
+		return "off"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// validateChecks validates the severity configuration.
+// name is the name of the configuration for error messages.
+func validateChecks(name string, m map[Check]Severity) error {
+	for check, severity := range m {
+		if _, ok := checks[check]; !ok {
+			return fmt.Errorf("myddlmaker: unknown check %q in %s", check, name)
+		}
+		if severity < SeverityDefault || severity > SeverityOff {
+			return fmt.Errorf("myddlmaker: invalid severity %v of check %q in %s", severity, check, name)
+		}
+	}
+	return nil
+}
+
+// suppresses reports whether the column suppresses the check with the nolint tag option.
+func (c *column) suppresses(check Check) bool {
+	if c == nil {
+		return false
+	}
+	for _, s := range c.nolint {
+		if s == "" || s == check {
+			return true
+		}
+	}
+	return false
+}
+
+// Report reports the result of the check.
+// def is the default severity, that is overwritten by the configuration.
+// table and col are the table and the column that cause the result; col may be nil.
+func (v *validator) Report(check Check, def Severity, table *table, col *column, format string, args ...any) {
+	severity := def
+	if s := v.Checks[check]; s != SeverityDefault {
+		severity = s
+	}
+	if s := v.TableChecks[table.fullName()][check]; s != SeverityDefault {
+		severity = s
+	}
+	if severity == SeverityOff || col.suppresses(check) {
+		return
+	}
+
+	msg := fmt.Sprintf(format, args...)
+	switch severity {
+	case SeverityError:
+		v.errs = append(v.errs, msg)
+		log.Printf("%s [%s]", msg, check)
+	case SeverityWarning:
+		v.warns = append(v.warns, msg)
+		log.Printf("warning: %s [%s]", msg, check)
+	}
+}
@@ -0,0 +1,13 @@
+module github.com/shogo82148/myddlmaker/cmd/myddlmaker
+
+go 1.25.0
+
+require (
+	github.com/google/go-cmp v0.6.0
+	golang.org/x/tools v0.45.0
+)
+
+require (
+	golang.org/x/mod v0.36.0 // indirect
+	golang.org/x/sync v0.20.0 // indirect
+)
@@ -0,0 +1,180 @@
+// Command myddlmaker generates DDL and Go source code from the structs in a Go package,
+// without writing gen/main.go by hand.
+//
+//	//go:generate go run github.com/shogo82148/myddlmaker/cmd/myddlmaker .
+//
+// It loads the package with go/packages, and discovers the exported structs
+// that have the PrimaryKey method in their method sets, including the methods promoted from the embedded structs.
+// Then it runs a temporary program in the package directory that passes them to the DDL maker, e.g. AddStructs(&schema.User{}),
+// because the methods such as PrimaryKey and Indexes are evaluated at run time.
+//
+// The relative paths of -out, -out-dir and -go-out are relative to the package directory.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"text/template"
+)
+
+type options struct {
+	ImportPath    string
+	Structs       []string
+	Engine        string
+	Charset       string
+	Collate       string
+	RowFormat     string
+	OutFile       string
+	OutDir        string
+	OutGoFile     string
+	PackageName   string
+	GoPackagePath string
+	Tag           string
+	NoGo          bool
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("myddlmaker: ")
+
+	var opts options
+	var all bool
+	flag.StringVar(&opts.Engine, "engine", "", "the default database engine for creating tables")
+	flag.StringVar(&opts.Charset, "charset", "", "the default character set for creating tables")
+	flag.StringVar(&opts.Collate, "collate", "", "the default character collate for creating tables")
+	flag.StringVar(&opts.RowFormat, "row-format", "", "the row format for creating tables")
+	flag.StringVar(&opts.OutFile, "out", "schema.sql", "the file path for SQL relative to the package directory, or the template of it, e.g. {{.Schema}}.sql")
+	flag.StringVar(&opts.OutDir, "out-dir", "", "the directory for SQL relative to the package directory; -out is relative to it")
+	flag.StringVar(&opts.OutGoFile, "go-out", "schema_gen.go", "the file path for Go source code relative to the package directory")
+	flag.StringVar(&opts.PackageName, "package", "", "the package name for Go source code (default: the name of the scanned package)")
+	flag.StringVar(&opts.GoPackagePath, "go-package-path", "", "the import path of the package for Go source code, if it differs from the scanned package")
+	flag.StringVar(&opts.Tag, "tag", "myddlmaker", "the build constraint tag for Go source code")
+	flag.BoolVar(&opts.NoGo, "no-go", false, "don't generate Go source code")
+	flag.BoolVar(&all, "all", false, "use all exported structs, including the ones that don't define the PrimaryKey method")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [package directory]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	pkg, err := scanPackage(dir, &scanOptions{
+		AllStructs: all,
+		Tags:       []string{opts.Tag},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(pkg.Structs) == 0 {
+		log.Fatalf("no structs found in %s", pkg.Dir)
+	}
+	opts.ImportPath = pkg.ImportPath
+	opts.Structs = pkg.Structs
+	if opts.PackageName == "" {
+		opts.PackageName = pkg.Name
+	}
+	if opts.OutDir != "" {
+		// -out is relative to -out-dir.
+		opts.OutDir = inDir(pkg.Dir, opts.OutDir)
+	} else {
+		opts.OutFile = inDir(pkg.Dir, opts.OutFile)
+	}
+	opts.OutGoFile = inDir(pkg.Dir, opts.OutGoFile)
+
+	if err := run(pkg.Dir, &opts); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// inDir returns the path relative to the directory dir, or path itself if it is absolute.
+func inDir(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+var driver = template.Must(template.New("driver").Parse(`// Code generated by myddlmaker; DO NOT EDIT.
+
+package main
+
+import (
+	"log"
+
+	"github.com/shogo82148/myddlmaker"
+	schema {{printf "%q" .ImportPath}}
+)
+
+func main() {
+	m, err := myddlmaker.New(&myddlmaker.Config{
+		DB: &myddlmaker.DBConfig{
+			Engine:    {{printf "%q" .Engine}},
+			Charset:   {{printf "%q" .Charset}},
+			Collate:   {{printf "%q" .Collate}},
+			RowFormat: {{printf "%q" .RowFormat}},
+		},
+		OutFilePath:   {{printf "%q" .OutFile}},
+		OutDir:        {{printf "%q" .OutDir}},
+		OutGoFilePath: {{printf "%q" .OutGoFile}},
+		PackageName:   {{printf "%q" .PackageName}},
+		GoPackagePath: {{printf "%q" .GoPackagePath}},
+		Tag:           {{printf "%q" .Tag}},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	m.AddStructs(
+{{- range .Structs}}
+		&schema.{{.}}{},
+{{- end}}
+	)
+
+	if err := m.GenerateFile(); err != nil {
+		log.Fatal(err)
+	}
+{{- if not .NoGo}}
+	if err := m.GenerateGoFile(); err != nil {
+		log.Fatal(err)
+	}
+{{- end}}
+}
+`))
+
+// run generates a temporary program in the package directory dir, and runs it.
+// The program must be in the same module as the package for importing it.
+func run(dir string, opts *options) error {
+	var buf bytes.Buffer
+	if err := driver.Execute(&buf, opts); err != nil {
+		return err
+	}
+
+	tmp, err := os.MkdirTemp(dir, ".myddlmaker")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	main := filepath.Join(tmp, "main.go")
+	if err := os.WriteFile(main, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("go", "run", "-tags", opts.Tag, main)
+	cmd.Dir = dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run the generator: %w", err)
+	}
+	return nil
+}
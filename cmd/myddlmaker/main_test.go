@@ -0,0 +1,87 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// buildCommand builds the myddlmaker command, and returns the path of the executable.
+func buildCommand(t *testing.T) string {
+	t.Helper()
+	bin := filepath.Join(t.TempDir(), "myddlmaker")
+	cmd := exec.Command("go", "build", "-o", bin, ".")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("failed to build the command: %v\n%s", err, out)
+	}
+	return bin
+}
+
+func TestCommand(t *testing.T) {
+	bin := buildCommand(t)
+	pkgDir, err := filepath.Abs("testdata/scan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlFile := filepath.Join(pkgDir, "schema.sql")
+	goFile := filepath.Join(pkgDir, "schema_gen.go")
+	t.Cleanup(func() {
+		os.Remove(sqlFile)
+		os.Remove(goFile)
+	})
+
+	// run the command in another directory.
+	// the output files are relative to the package directory, not the working directory.
+	wd := t.TempDir()
+	cmd := exec.Command(bin, "-go-out", "schema_gen.go", pkgDir)
+	cmd.Dir = wd
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("failed to run the command: %v\n%s", err, out)
+	}
+	if _, err := os.Stat(filepath.Join(wd, "schema_gen.go")); !os.IsNotExist(err) {
+		t.Errorf("want schema_gen.go not to be in the working directory, got %v", err)
+	}
+
+	sql, err := os.ReadFile(sqlFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, table := range []string{"`user`", "`entry`", "`tag`"} {
+		if !strings.Contains(string(sql), "CREATE TABLE "+table) {
+			t.Errorf("want the table %s, got:\n%s", table, sql)
+		}
+	}
+	if strings.Contains(string(sql), "`timestamps`") {
+		t.Errorf("want Timestamps not to be a table, got:\n%s", sql)
+	}
+
+	// the generated code is compiled with the package.
+	vet := exec.Command("go", "vet", ".")
+	vet.Dir = pkgDir
+	if out, err := vet.CombinedOutput(); err != nil {
+		t.Errorf("failed to vet the generated code: %v\n%s", err, out)
+	}
+}
+
+func TestCommand_NoStructs(t *testing.T) {
+	bin := buildCommand(t)
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/empty\n\ngo 1.18\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "empty.go"), []byte("package empty\n\ntype Empty struct{}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(bin, dir)
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatal("want an error, got nil")
+	}
+	if !strings.Contains(string(out), "no structs found") {
+		t.Errorf("unexpected output: %s", out)
+	}
+}
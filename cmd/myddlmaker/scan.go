@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// scanOptions is options for scanPackage.
+type scanOptions struct {
+	// AllStructs makes scanPackage discover all exported structs.
+	// By default, only the structs that have the PrimaryKey method are discovered.
+	AllStructs bool
+
+	// Tags is the build tags used for selecting Go source files.
+	Tags []string
+}
+
+// schemaPackage is a Go package discovered by scanPackage.
+type schemaPackage struct {
+	// Dir is the directory of the package.
+	Dir string
+
+	// ImportPath is the import path of the package.
+	ImportPath string
+
+	// Name is the package name.
+	Name string
+
+	// Structs is the names of the structs for tables, in order of appearance in the source code.
+	Structs []string
+}
+
+// scanPackage loads the Go package in the directory dir with go/packages, and discovers the structs for tables
+// by their types, without running the package.
+//
+// The structs that have the PrimaryKey method in their method sets are discovered,
+// including the methods promoted from the embedded structs.
+// The generic structs are skipped, because they can't be passed to AddStructs without the type arguments.
+func scanPackage(dir string, opts *scanOptions) (*schemaPackage, error) {
+	if opts == nil {
+		opts = new(scanOptions)
+	}
+
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedSyntax | packages.NeedTypes,
+		Dir:  dir,
+	}
+	if len(opts.Tags) > 0 {
+		cfg.BuildFlags = []string{"-tags=" + strings.Join(opts.Tags, ",")}
+	}
+	pkgs, err := packages.Load(cfg, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the package in %q: %w", dir, err)
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected one package in %q, found %d", dir, len(pkgs))
+	}
+	pkg := pkgs[0]
+	if len(pkg.Errors) > 0 {
+		return nil, fmt.Errorf("failed to load the package in %q: %w", dir, pkg.Errors[0])
+	}
+	if len(pkg.GoFiles) == 0 {
+		return nil, fmt.Errorf("no Go files in %q", dir)
+	}
+
+	return &schemaPackage{
+		Dir:        filepath.Dir(pkg.GoFiles[0]),
+		ImportPath: pkg.PkgPath,
+		Name:       pkg.Name,
+		Structs:    findStructs(pkg, opts.AllStructs),
+	}, nil
+}
+
+// isGenerated reports whether the file has the comment "// Code generated ... DO NOT EDIT."
+// before the package clause.
+// See https://go.dev/s/generatedcode
+func isGenerated(f *ast.File) bool {
+	for _, g := range f.Comments {
+		if g.Pos() > f.Package {
+			break
+		}
+		for _, c := range g.List {
+			if strings.HasPrefix(c.Text, "// Code generated ") && strings.HasSuffix(c.Text, " DO NOT EDIT.") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// findStructs returns the names of exported non-generic structs in the package.
+// If all is false, it returns only the structs that have the PrimaryKey method.
+func findStructs(pkg *packages.Package, all bool) []string {
+	// skip the generated code, e.g. the structs generated by GenerateGoFile.
+	generated := map[string]bool{}
+	for _, f := range pkg.Syntax {
+		if isGenerated(f) {
+			generated[pkg.Fset.Position(f.Package).Filename] = true
+		}
+	}
+
+	// key: struct name
+	// value: the position of the declaration
+	structs := map[string]token.Position{}
+	scope := pkg.Types.Scope()
+	for _, name := range scope.Names() {
+		obj, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || !obj.Exported() || obj.IsAlias() {
+			continue
+		}
+		named, ok := obj.Type().(*types.Named)
+		if !ok || named.TypeParams().Len() > 0 {
+			continue
+		}
+		if _, ok := named.Underlying().(*types.Struct); !ok {
+			continue
+		}
+		pos := pkg.Fset.Position(obj.Pos())
+		if generated[pos.Filename] {
+			continue
+		}
+		if all || hasPrimaryKey(named) {
+			structs[name] = pos
+		}
+	}
+
+	names := make([]string, 0, len(structs))
+	for name := range structs {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := structs[names[i]], structs[names[j]]
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Offset < b.Offset
+	})
+	return names
+}
+
+// myddlmakerPath is the import path of the package of the PrimaryKey type.
+const myddlmakerPath = "github.com/shogo82148/myddlmaker"
+
+// hasPrimaryKey reports whether the pointer of the type has the PrimaryKey method that returns *myddlmaker.PrimaryKey,
+// e.g. AddStructs(&User{}) uses the primary key.
+// The method set includes the methods promoted from the embedded structs.
+func hasPrimaryKey(named *types.Named) bool {
+	sel := types.NewMethodSet(types.NewPointer(named)).Lookup(nil, "PrimaryKey")
+	if sel == nil {
+		return false
+	}
+	sig := sel.Type().(*types.Signature)
+	if sig.Params().Len() != 0 || sig.Results().Len() != 1 {
+		return false
+	}
+	ptr, ok := sig.Results().At(0).Type().(*types.Pointer)
+	if !ok {
+		return false
+	}
+	result, ok := ptr.Elem().(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := result.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == myddlmakerPath && obj.Name() == "PrimaryKey"
+}
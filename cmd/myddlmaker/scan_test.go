@@ -0,0 +1,31 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestScanPackage(t *testing.T) {
+	pkg, err := scanPackage("./testdata/scan", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pkg.ImportPath != "github.com/shogo82148/myddlmaker/cmd/myddlmaker/testdata/scan" {
+		t.Errorf("unexpected import path: %q", pkg.ImportPath)
+	}
+	if pkg.Name != "schema" {
+		t.Errorf("unexpected package name: %q", pkg.Name)
+	}
+	if diff := cmp.Diff([]string{"User", "Entry", "Tag"}, pkg.Structs); diff != "" {
+		t.Errorf("unexpected structs (-want/+got):\n%s", diff)
+	}
+
+	pkg, err = scanPackage("./testdata/scan", &scanOptions{AllStructs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"Timestamps", "User", "Entry", "Tag"}, pkg.Structs); diff != "" {
+		t.Errorf("unexpected structs (-want/+got):\n%s", diff)
+	}
+}
@@ -0,0 +1,7 @@
+module github.com/shogo82148/myddlmaker/cmd/myddlmaker/testdata/scan
+
+go 1.18
+
+require github.com/shogo82148/myddlmaker v0.0.0
+
+replace github.com/shogo82148/myddlmaker => ../../../..
@@ -0,0 +1,67 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/shogo82148/myddlmaker"
+)
+
+// Timestamps doesn't define the PrimaryKey method.
+// It is not a table.
+type Timestamps struct {
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type User struct {
+	ID   uint64 `ddl:",auto"`
+	Name string
+	Timestamps
+}
+
+func (*User) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
+
+type Entry struct {
+	ID     uint64 `ddl:",auto"`
+	UserID uint64
+	Title  string
+	Timestamps
+}
+
+func (*Entry) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
+
+func (*Entry) Indexes() []*myddlmaker.Index {
+	return []*myddlmaker.Index{
+		myddlmaker.NewIndex("idx_user_id", "user_id"),
+	}
+}
+
+func (*Entry) ForeignKeys() []*myddlmaker.ForeignKey {
+	return []*myddlmaker.ForeignKey{
+		myddlmaker.NewForeignKey("fk_entry_user_id", []string{"user_id"}, "user", []string{"id"}),
+	}
+}
+
+// model is embedded by the tables whose primary key is id.
+type model struct {
+	ID uint64 `ddl:",auto"`
+}
+
+func (*model) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
+
+// Tag has the PrimaryKey method promoted from model.
+type Tag struct {
+	model
+	Name string
+}
+
+// Page is generic, so it is not a table.
+type Page[T any] struct {
+	Items []T
+}
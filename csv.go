@@ -0,0 +1,414 @@
+package myddlmaker
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// hasCSV reports whether the Go source code has the CSV writers, readers and loaders of the tables.
+func (m *Maker) hasCSV() bool {
+	return m.config.GenerateCSV && len(m.tables) > 0
+}
+
+// generateGoCSV generates the helpers that the CSV functions of the tables share.
+func (m *Maker) generateGoCSV(w io.Writer) {
+	fmt.Fprintf(w, `// csvTimeLayout is the layout of the times in the CSV files, that LOAD DATA accepts.
+	const csvTimeLayout = "2006-01-02 15:04:05.999999"
+
+	// csvNull is the field of NULL in the CSV files.
+	// The other fields are enclosed in the double quotes, so that they are distinguished from NULL.
+	const csvNull = "NULL"
+
+	// csvHandlerSeq is the sequence number of the names of the reader handlers of LOAD DATA LOCAL INFILE.
+	var csvHandlerSeq int64
+
+	// csvWriter writes the records of the CSV files that LOAD DATA reads.
+	type csvWriter struct {
+		w *bufio.Writer
+	}
+
+	// newCSVWriter returns a new csvWriter that has written the header of the columns.
+	func newCSVWriter(w io.Writer, columns []string) (*csvWriter, error) {
+		cw := &csvWriter{w: bufio.NewWriter(w)}
+		fields := make([]any, 0, len(columns))
+		for _, c := range columns {
+			fields = append(fields, c)
+		}
+		if err := cw.write(fields); err != nil {
+			return nil, err
+		}
+		return cw, nil
+	}
+
+	func (w *csvWriter) write(fields []any) error {
+		for i, f := range fields {
+			if i > 0 {
+				w.w.WriteByte(',')
+			}
+			s, null, err := csvValue(f)
+			if err != nil {
+				return err
+			}
+			if null {
+				w.w.WriteString(csvNull)
+				continue
+			}
+			w.w.WriteByte('"')
+			w.w.WriteString(strings.ReplaceAll(s, "\"", "\"\""))
+			w.w.WriteByte('"')
+		}
+		return w.w.WriteByte('\n')
+	}
+
+	func (w *csvWriter) flush() error {
+		return w.w.Flush()
+	}
+
+	// csvValue returns the field of the value v, or reports that it is NULL.
+	// The nil pointers and the nil slices are NULL as well as the driver.
+	func csvValue(v any) (string, bool, error) {
+		rv := reflect.ValueOf(v)
+		if v == nil || ((rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Slice) && rv.IsNil()) {
+			return "", true, nil
+		}
+		if valuer, ok := v.(driver.Valuer); ok {
+			var err error
+			if v, err = valuer.Value(); err != nil {
+				return "", false, err
+			}
+			if v == nil {
+				return "", true, nil
+			}
+			rv = reflect.ValueOf(v)
+		}
+		if t, ok := v.(time.Time); ok {
+			return t.Format(csvTimeLayout), false, nil
+		}
+		switch rv.Kind() {
+		case reflect.Pointer:
+			return csvValue(rv.Elem().Interface())
+		case reflect.String:
+			return rv.String(), false, nil
+		case reflect.Bool:
+			if rv.Bool() {
+				return "1", false, nil
+			}
+			return "0", false, nil
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return strconv.FormatInt(rv.Int(), 10), false, nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return strconv.FormatUint(rv.Uint(), 10), false, nil
+		case reflect.Float32, reflect.Float64:
+			return strconv.FormatFloat(rv.Float(), 'g', -1, rv.Type().Bits()), false, nil
+		case reflect.Slice:
+			if rv.Type().Elem().Kind() == reflect.Uint8 {
+				return string(rv.Bytes()), false, nil
+			}
+		}
+		return "", false, fmt.Errorf("unsupported type %%T", v)
+	}
+
+	// csvReader reads the records of the CSV files written by csvWriter.
+	type csvReader struct {
+		r    *bufio.Reader
+		line int
+	}
+
+	func newCSVReader(r io.Reader) *csvReader {
+		return &csvReader{r: bufio.NewReader(r)}
+	}
+
+	// read returns the fields of the next record, and whether they are NULL, i.e. the unquoted NULL.
+	// It returns io.EOF at the end of the file.
+	func (r *csvReader) read() ([]string, []bool, error) {
+		var fields []string
+		var nulls []bool
+		var field strings.Builder
+		empty, quoted, inQuotes := true, false, false
+		appendField := func() {
+			s := field.String()
+			fields = append(fields, s)
+			nulls = append(nulls, !quoted && s == csvNull)
+			field.Reset()
+			quoted = false
+		}
+		r.line++
+		for {
+			c, err := r.r.ReadByte()
+			if err == io.EOF {
+				if inQuotes {
+					return nil, nil, fmt.Errorf("line %%d: %%w", r.line, io.ErrUnexpectedEOF)
+				}
+				if empty {
+					return nil, nil, io.EOF
+				}
+				// the last record without the newline.
+				appendField()
+				return fields, nulls, nil
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+			empty = false
+			if inQuotes {
+				if c != '"' {
+					field.WriteByte(c)
+				} else if next, err := r.r.Peek(1); err == nil && next[0] == '"' {
+					// the escaped double quote.
+					r.r.ReadByte()
+					field.WriteByte(c)
+				} else {
+					inQuotes = false
+				}
+				continue
+			}
+			switch c {
+			case '"':
+				if field.Len() == 0 && !quoted {
+					quoted, inQuotes = true, true
+				} else {
+					field.WriteByte(c)
+				}
+			case ',':
+				appendField()
+			case '\r':
+				if next, err := r.r.Peek(1); err != nil || next[0] != '\n' {
+					field.WriteByte(c)
+				}
+			case '\n':
+				appendField()
+				return fields, nulls, nil
+			default:
+				field.WriteByte(c)
+			}
+		}
+	}
+
+	// header reads the header of the column names, and returns the indexes of the columns of the fields.
+	// The header may have a subset of the columns in any order.
+	func (r *csvReader) header(columns []string) ([]int, error) {
+		fields, _, err := r.read()
+		if err == io.EOF {
+			return nil, errors.New("the header of the columns is missing")
+		}
+		if err != nil {
+			return nil, err
+		}
+		index := make([]int, 0, len(fields))
+		seen := make(map[string]bool, len(fields))
+		for _, f := range fields {
+			i := 0
+			for i < len(columns) && columns[i] != f {
+				i++
+			}
+			if i == len(columns) {
+				return nil, fmt.Errorf("unknown column %%q in the header", f)
+			}
+			if seen[f] {
+				return nil, fmt.Errorf("duplicated column %%q in the header", f)
+			}
+			seen[f] = true
+			index = append(index, i)
+		}
+		return index, nil
+	}
+
+	// readRecord reads the next record into the pointers of the fields of the columns.
+	// It returns io.EOF at the end of the file.
+	func (r *csvReader) readRecord(index []int, dest []any) error {
+		fields, nulls, err := r.read()
+		if err != nil {
+			return err
+		}
+		if len(fields) != len(index) {
+			return fmt.Errorf("line %%d: %%d fields, want %%d", r.line, len(fields), len(index))
+		}
+		for i, f := range fields {
+			if err := csvScan(dest[index[i]], f, nulls[i]); err != nil {
+				return fmt.Errorf("line %%d, field %%d: %%w", r.line, i+1, err)
+			}
+		}
+		return nil
+	}
+
+	// csvScan sets the field s to the pointer dest.
+	func csvScan(dest any, s string, null bool) error {
+		if t, ok := dest.(*sql.NullTime); ok && !null {
+			var err error
+			t.Time, err = time.ParseInLocation(csvTimeLayout, s, time.UTC)
+			t.Valid = err == nil
+			return err
+		}
+		if scanner, ok := dest.(sql.Scanner); ok {
+			if null {
+				return scanner.Scan(nil)
+			}
+			return scanner.Scan(s)
+		}
+		rv := reflect.ValueOf(dest).Elem()
+		if rv.Kind() == reflect.Pointer {
+			if null {
+				rv.Set(reflect.Zero(rv.Type()))
+				return nil
+			}
+			p := reflect.New(rv.Type().Elem())
+			if err := csvScan(p.Interface(), s, false); err != nil {
+				return err
+			}
+			rv.Set(p)
+			return nil
+		}
+		if null {
+			if rv.Kind() == reflect.Slice {
+				rv.Set(reflect.Zero(rv.Type()))
+				return nil
+			}
+			return errors.New("unexpected NULL")
+		}
+		if t, ok := dest.(*time.Time); ok {
+			var err error
+			*t, err = time.ParseInLocation(csvTimeLayout, s, time.UTC)
+			return err
+		}
+		switch rv.Kind() {
+		case reflect.String:
+			rv.SetString(s)
+		case reflect.Bool:
+			b, err := strconv.ParseBool(s)
+			if err != nil {
+				return err
+			}
+			rv.SetBool(b)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n, err := strconv.ParseInt(s, 10, rv.Type().Bits())
+			if err != nil {
+				return err
+			}
+			rv.SetInt(n)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			n, err := strconv.ParseUint(s, 10, rv.Type().Bits())
+			if err != nil {
+				return err
+			}
+			rv.SetUint(n)
+		case reflect.Float32, reflect.Float64:
+			f, err := strconv.ParseFloat(s, rv.Type().Bits())
+			if err != nil {
+				return err
+			}
+			rv.SetFloat(f)
+		case reflect.Slice:
+			if rv.Type().Elem().Kind() != reflect.Uint8 {
+				return fmt.Errorf("unsupported type %%T", dest)
+			}
+			rv.SetBytes([]byte(s))
+		default:
+			return fmt.Errorf("unsupported type %%T", dest)
+		}
+		return nil
+	}
+
+	// loadDataCSV inserts the rows of the CSV file into the table with LOAD DATA LOCAL INFILE.
+	func loadDataCSV(ctx context.Context, execer execer, table string, columns []string, r io.Reader) (int64, error) {
+		cr := newCSVReader(r)
+		index, err := cr.header(columns)
+		if err != nil {
+			return 0, err
+		}
+		names := make([]string, 0, len(index))
+		for _, i := range index {
+			names = append(names, "`+"`\"+columns[i]+\"`"+`")
+		}
+
+		// the driver reads the rest of the file after the header.
+		handler := "myddlmaker-" + strconv.FormatInt(atomic.AddInt64(&csvHandlerSeq, 1), 10)
+		mysql.RegisterReaderHandler(handler, func() io.Reader { return cr.r })
+		defer mysql.DeregisterReaderHandler(handler)
+
+		q := "LOAD DATA LOCAL INFILE 'Reader::" + handler + "' INTO TABLE " + table + " CHARACTER SET utf8mb4 " +
+			"FIELDS TERMINATED BY ',' OPTIONALLY ENCLOSED BY '\"' ESCAPED BY '' LINES TERMINATED BY '\\n' " +
+			"(" + strings.Join(names, ", ") + ")"
+		result, err := execer.ExecContext(ctx, q)
+		if err != nil {
+			return 0, err
+		}
+		return result.RowsAffected()
+	}
+
+	`)
+}
+
+// generateGoTableCSV generates the CSV writer, reader, exporter and loader of the table.
+func (m *Maker) generateGoTableCSV(w io.Writer, table *table) {
+	name := table.rawName
+	lower := goParamName(name)
+	columns := make([]string, 0, len(table.columns))
+	fields := make([]string, 0, len(table.columns))
+	for _, c := range table.columns {
+		columns = append(columns, c.name)
+		fields = append(fields, "&v."+c.goField)
+	}
+
+	fmt.Fprintf(w, "// %sCSVColumns is the columns of %s in the CSV files, in the order of the fields of %sCSVFields.\n", lower, table.fullName(), lower)
+	fmt.Fprintf(w, "var %sCSVColumns = []string{%s}\n\n", lower, strings.Join(quoteAllGo(columns), ", "))
+
+	fmt.Fprintf(w, "// %sCSVFields returns the pointers of the fields of v in the order of %sCSVColumns.\n", lower, lower)
+	fmt.Fprintf(w, "func %sCSVFields(v *%s) []any {\n return []any{%s} \n}\n\n", lower, name, strings.Join(fields, ", "))
+
+	fmt.Fprintf(w, "// Write%sCSV writes the header of the column names and the values into w in the CSV format that Load%[1]sCSV reads.\n", name)
+	fmt.Fprintf(w, "// NULL is the unquoted NULL, and the other fields are enclosed in the double quotes.\n")
+	fmt.Fprintf(w, "func Write%sCSV(w io.Writer, values ...*%[1]s) error {\n", name)
+	fmt.Fprintf(w, "cw, err := newCSVWriter(w, %sCSVColumns)\n", lower)
+	fmt.Fprintf(w, "if err != nil {\n return err \n}\n")
+	fmt.Fprintf(w, "for _, v := range values {\n")
+	fmt.Fprintf(w, "if err := cw.write(%sCSVFields(v)); err != nil {\n return err \n}\n", lower)
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "return cw.flush()\n")
+	fmt.Fprintf(w, "}\n\n")
+
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(quoteAll(columns), ", "), table.quotedName())
+	if table.primaryKey != nil {
+		query += " ORDER BY " + strings.Join(quoteAll(table.primaryKey.columns), ", ")
+	}
+	fmt.Fprintf(w, "// Export%sCSV selects all the rows of %s, including the soft-deleted ones,\n", name, table.fullName())
+	fmt.Fprintf(w, "// and writes them into w in the format of Write%sCSV.\n", name)
+	fmt.Fprintf(w, "func Export%sCSV(ctx context.Context, queryer queryer, w io.Writer) error {\n", name)
+	m.generateGoInstrument(w, table, "queryer", "Export"+name+"CSV")
+	fmt.Fprintf(w, "rows, err := queryer.QueryContext(ctx, %q)\n", query)
+	fmt.Fprintf(w, "if err != nil {\n return err \n}\n")
+	fmt.Fprintf(w, "defer rows.Close()\n")
+	fmt.Fprintf(w, "cw, err := newCSVWriter(w, %sCSVColumns)\n", lower)
+	fmt.Fprintf(w, "if err != nil {\n return err \n}\n")
+	fmt.Fprintf(w, "for rows.Next() {\n")
+	fmt.Fprintf(w, "v, err := Scan%s(rows)\n", name)
+	fmt.Fprintf(w, "if err != nil {\n return err \n}\n")
+	fmt.Fprintf(w, "if err := cw.write(%sCSVFields(v)); err != nil {\n return err \n}\n", lower)
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "if err := rows.Err(); err != nil {\n return err \n}\n")
+	fmt.Fprintf(w, "return cw.flush()\n")
+	fmt.Fprintf(w, "}\n\n")
+
+	fmt.Fprintf(w, "// Read%sCSV reads the values from the CSV file in the format of Write%[1]sCSV.\n", name)
+	fmt.Fprintf(w, "// The header may have a subset of the columns in any order, and the other fields are left zero.\n")
+	fmt.Fprintf(w, "func Read%sCSV(r io.Reader) ([]*%[1]s, error) {\n", name)
+	fmt.Fprintf(w, "cr := newCSVReader(r)\n")
+	fmt.Fprintf(w, "index, err := cr.header(%sCSVColumns)\n", lower)
+	fmt.Fprintf(w, "if err != nil {\n return nil, err \n}\n")
+	fmt.Fprintf(w, "var ret []*%s\n", name)
+	fmt.Fprintf(w, "for {\n")
+	fmt.Fprintf(w, "v := new(%s)\n", name)
+	fmt.Fprintf(w, "if err := cr.readRecord(index, %sCSVFields(v)); err == io.EOF {\n break \n} else if err != nil {\n return nil, err \n}\n", lower)
+	fmt.Fprintf(w, "ret = append(ret, v)\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "return ret, nil\n")
+	fmt.Fprintf(w, "}\n\n")
+
+	fmt.Fprintf(w, "// Load%sCSV inserts the rows of the CSV file in the format of Write%[1]sCSV into %s with LOAD DATA LOCAL INFILE,\n", name, table.fullName())
+	fmt.Fprintf(w, "// that is faster than INSERT for the large files. It returns the number of the inserted rows.\n")
+	fmt.Fprintf(w, "// The server must enable local_infile. The hooks and the auto_now columns are not applied.\n")
+	fmt.Fprintf(w, "func Load%sCSV(ctx context.Context, execer execer, r io.Reader) (int64, error) {\n", name)
+	m.generateGoInstrument(w, table, "execer", "Load"+name+"CSV")
+	fmt.Fprintf(w, "return loadDataCSV(ctx, execer, %q, %sCSVColumns, r)\n", table.quotedName(), lower)
+	fmt.Fprintf(w, "}\n\n")
+}
@@ -0,0 +1,352 @@
+package myddlmaker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// dbColumn is a column in the database.
+type dbColumn struct {
+	name     string
+	typ      string
+	null     bool
+	autoIncr bool
+}
+
+// dbIndex is an index or a foreign key constraint in the database or in the structs.
+type dbIndex struct {
+	// kind is PRIMARY KEY, INDEX, UNIQUE, FULLTEXT INDEX, SPATIAL INDEX or FOREIGN KEY.
+	kind    string
+	columns []string
+
+	// references is the referenced table and columns of the foreign key constraint, e.g. "user" (id).
+	references string
+}
+
+func (idx *dbIndex) String() string {
+	s := idx.kind + " (" + strings.Join(idx.columns, ", ") + ")"
+	if idx.references != "" {
+		s += " REFERENCES " + idx.references
+	}
+	return s
+}
+
+// noun returns the noun of the kind of the index in the messages of the differences.
+func (idx *dbIndex) noun() string {
+	switch idx.kind {
+	case "PRIMARY KEY", "FOREIGN KEY":
+		return strings.ToLower(idx.kind)
+	}
+	return "index"
+}
+
+// DiffDatabase compares the tables of the structs with the tables in the database db,
+// e.g. to detect the migrations that are applied manually but never reflected in the structs.
+// It returns the differences of the columns, their types, NULL and AUTO_INCREMENT,
+// the indexes and the foreign key constraints. It returns nil if there are no differences.
+// The tables that are not in the structs are ignored.
+// The tables without the schema are looked up in the current database of db.
+func (m *Maker) DiffDatabase(ctx context.Context, db *sql.DB) ([]string, error) {
+	if err := m.parse(); err != nil {
+		return nil, err
+	}
+	var diffs []string
+	for _, table := range m.tables {
+		d, err := diffTable(ctx, db, table)
+		if err != nil {
+			return nil, fmt.Errorf("myddlmaker: failed to read the table %q: %w", table.fullName(), err)
+		}
+		diffs = append(diffs, d...)
+	}
+	return diffs, nil
+}
+
+// diffTable returns the differences between the table in the structs and in the database.
+func diffTable(ctx context.Context, db *sql.DB, table *table) ([]string, error) {
+	// NULL means the current database.
+	var schema any
+	if table.schema != "" {
+		schema = table.schema
+	}
+
+	columns, err := queryDBColumns(ctx, db, schema, table.name)
+	if err != nil {
+		return nil, err
+	}
+	prefix := fmt.Sprintf("table %q", table.fullName())
+	if len(columns.list) == 0 {
+		return []string{prefix + ": the table is not in the database"}, nil
+	}
+
+	var diffs []string
+	found := map[string]bool{}
+	for _, col := range table.columns {
+		name := strings.ToLower(col.name)
+		found[name] = true
+		got, ok := columns.get(name)
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("%s, column %q: the column is in the structs, but not in the database", prefix, col.name))
+			continue
+		}
+		if want := col.sqlType(); normalizeColumnType(want) != normalizeColumnType(got.typ) {
+			diffs = append(diffs, fmt.Sprintf("%s, column %q: type %s in the structs, %s in the database", prefix, col.name, want, got.typ))
+		}
+		if col.null != got.null {
+			diffs = append(diffs, fmt.Sprintf("%s, column %q: %s in the structs, %s in the database", prefix, col.name, nullString(col.null), nullString(got.null)))
+		}
+		if col.autoIncr != got.autoIncr {
+			diffs = append(diffs, fmt.Sprintf("%s, column %q: %s in the structs, %s in the database", prefix, col.name, autoIncrString(col.autoIncr), autoIncrString(got.autoIncr)))
+		}
+	}
+	for _, got := range columns.list {
+		if !found[strings.ToLower(got.name)] {
+			diffs = append(diffs, fmt.Sprintf("%s, column %q: the column is in the database, but not in the structs", prefix, got.name))
+		}
+	}
+
+	indexes, err := queryDBIndexes(ctx, db, schema, table.name)
+	if err != nil {
+		return nil, err
+	}
+	diffs = append(diffs, diffIndexes(prefix, tableIndexes(table), indexes)...)
+	return diffs, nil
+}
+
+// dbColumns is the columns of a table in the database.
+type dbColumns struct {
+	list   []*dbColumn
+	byName map[string]*dbColumn
+}
+
+func queryDBColumns(ctx context.Context, db *sql.DB, schema any, name string) (*dbColumns, error) {
+	rows, err := db.QueryContext(ctx, "SELECT `COLUMN_NAME`, `COLUMN_TYPE`, `IS_NULLABLE`, `EXTRA` "+
+		"FROM `information_schema`.`COLUMNS` "+
+		"WHERE `TABLE_SCHEMA` = COALESCE(?, DATABASE()) AND `TABLE_NAME` = ? "+
+		"ORDER BY `ORDINAL_POSITION`", schema, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret := &dbColumns{byName: map[string]*dbColumn{}}
+	for rows.Next() {
+		var col dbColumn
+		var nullable, extra string
+		if err := rows.Scan(&col.name, &col.typ, &nullable, &extra); err != nil {
+			return nil, err
+		}
+		col.null = nullable == "YES"
+		col.autoIncr = strings.Contains(strings.ToLower(extra), "auto_increment")
+		ret.list = append(ret.list, &col)
+		ret.byName[strings.ToLower(col.name)] = &col
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (c *dbColumns) get(name string) (*dbColumn, bool) {
+	col, ok := c.byName[name]
+	return col, ok
+}
+
+// queryDBIndexes returns the indexes and the foreign key constraints of the table in the database.
+func queryDBIndexes(ctx context.Context, db *sql.DB, schema any, name string) (map[string]*dbIndex, error) {
+	indexes := map[string]*dbIndex{}
+
+	rows, err := db.QueryContext(ctx, "SELECT `INDEX_NAME`, `NON_UNIQUE`, `INDEX_TYPE`, `COLUMN_NAME` "+
+		"FROM `information_schema`.`STATISTICS` "+
+		"WHERE `TABLE_SCHEMA` = COALESCE(?, DATABASE()) AND `TABLE_NAME` = ? "+
+		"ORDER BY `INDEX_NAME`, `SEQ_IN_INDEX`", schema, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name, typ string
+		var nonUnique int
+		var column sql.NullString // NULL for the functional key parts.
+		if err := rows.Scan(&name, &nonUnique, &typ, &column); err != nil {
+			return nil, err
+		}
+		idx, ok := indexes[name]
+		if !ok {
+			idx = &dbIndex{}
+			switch {
+			case name == "PRIMARY":
+				idx.kind = "PRIMARY KEY"
+			case typ == "FULLTEXT" || typ == "SPATIAL":
+				idx.kind = typ + " INDEX"
+			case nonUnique == 0:
+				idx.kind = "UNIQUE"
+			default:
+				idx.kind = "INDEX"
+			}
+			indexes[name] = idx
+		}
+		idx.columns = append(idx.columns, column.String)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = db.QueryContext(ctx, "SELECT `CONSTRAINT_NAME`, `COLUMN_NAME`, `REFERENCED_TABLE_NAME`, `REFERENCED_COLUMN_NAME` "+
+		"FROM `information_schema`.`KEY_COLUMN_USAGE` "+
+		"WHERE `TABLE_SCHEMA` = COALESCE(?, DATABASE()) AND `TABLE_NAME` = ? AND `REFERENCED_TABLE_NAME` IS NOT NULL "+
+		"ORDER BY `CONSTRAINT_NAME`, `ORDINAL_POSITION`", schema, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	refs := map[string][]string{}
+	for rows.Next() {
+		var name, column, refTable, refColumn string
+		if err := rows.Scan(&name, &column, &refTable, &refColumn); err != nil {
+			return nil, err
+		}
+		key := "FOREIGN KEY " + name
+		idx, ok := indexes[key]
+		if !ok {
+			idx = &dbIndex{kind: "FOREIGN KEY"}
+			indexes[key] = idx
+		}
+		idx.columns = append(idx.columns, column)
+		refs[key] = append(refs[key], refColumn)
+		idx.references = quote(refTable) + " (" + strings.Join(refs[key], ", ") + ")"
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return indexes, nil
+}
+
+// tableIndexes returns the indexes and the foreign key constraints of the table in the structs.
+// The keys of the foreign key constraints are prefixed with "FOREIGN KEY ",
+// because they are in the different namespace from the indexes.
+func tableIndexes(table *table) map[string]*dbIndex {
+	indexes := map[string]*dbIndex{}
+	if table.primaryKey != nil {
+		indexes["PRIMARY"] = &dbIndex{kind: "PRIMARY KEY", columns: table.primaryKey.columns}
+	}
+	for _, idx := range table.indexes {
+		indexes[idx.name] = &dbIndex{kind: "INDEX", columns: idx.columns}
+	}
+	for _, idx := range table.uniqueIndexes {
+		indexes[idx.name] = &dbIndex{kind: "UNIQUE", columns: idx.columns}
+	}
+	for _, idx := range table.fullTextIndexes {
+		indexes[idx.name] = &dbIndex{kind: "FULLTEXT INDEX", columns: []string{idx.column}}
+	}
+	for _, idx := range table.spatialIndexes {
+		indexes[idx.name] = &dbIndex{kind: "SPATIAL INDEX", columns: []string{idx.column}}
+	}
+	for _, fk := range table.foreignKeys {
+		if fk.logical {
+			continue
+		}
+		indexes["FOREIGN KEY "+fk.name] = &dbIndex{
+			kind:       "FOREIGN KEY",
+			columns:    fk.columns,
+			references: quote(fk.table) + " (" + strings.Join(fk.references, ", ") + ")",
+		}
+	}
+	return indexes
+}
+
+// diffIndexes returns the differences between the indexes in the structs want and the indexes in the database got.
+func diffIndexes(prefix string, want, got map[string]*dbIndex) []string {
+	var diffs []string
+	for _, name := range sortedKeys(want) {
+		w := want[name]
+		label := indexLabel(name, w)
+		g, ok := got[name]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("%s, %s: the %s is in the structs, but not in the database", prefix, label, w.noun()))
+			continue
+		}
+		if !strings.EqualFold(w.String(), g.String()) {
+			diffs = append(diffs, fmt.Sprintf("%s, %s: %s in the structs, %s in the database", prefix, label, w, g))
+		}
+	}
+	for _, name := range sortedKeys(got) {
+		g := got[name]
+		if _, ok := want[name]; ok {
+			continue
+		}
+		if _, ok := want["FOREIGN KEY "+name]; ok && g.kind == "INDEX" {
+			// MySQL creates the index of the foreign key constraint implicitly.
+			continue
+		}
+		diffs = append(diffs, fmt.Sprintf("%s, %s: the %s is in the database, but not in the structs", prefix, indexLabel(name, g), g.noun()))
+	}
+	return diffs
+}
+
+func indexLabel(name string, idx *dbIndex) string {
+	switch idx.kind {
+	case "PRIMARY KEY":
+		return "primary key"
+	case "FOREIGN KEY":
+		return fmt.Sprintf("foreign key %q", strings.TrimPrefix(name, "FOREIGN KEY "))
+	case "UNIQUE":
+		return fmt.Sprintf("unique index %q", name)
+	case "FULLTEXT INDEX":
+		return fmt.Sprintf("full text index %q", name)
+	case "SPATIAL INDEX":
+		return fmt.Sprintf("spatial index %q", name)
+	}
+	return fmt.Sprintf("index %q", name)
+}
+
+var (
+	// intDisplayWidth matches the display widths of the integer types, e.g. int(11) of MySQL 5.7.
+	intDisplayWidth = regexp.MustCompile(`^(tinyint|smallint|mediumint|int|bigint)\(\d+\)`)
+
+	// spacesAfterComma matches the spaces in the arguments, e.g. decimal(10, 2).
+	spacesAfterComma = regexp.MustCompile(`,\s+`)
+)
+
+// normalizeColumnType returns the type in the form of COLUMN_TYPE of information_schema,
+// e.g. "int unsigned" for "INTEGER UNSIGNED", so that the types in the structs and in the database can be compared.
+func normalizeColumnType(typ string) string {
+	typ = strings.ToLower(strings.Join(strings.Fields(typ), " "))
+	typ = spacesAfterComma.ReplaceAllString(typ, ",")
+	switch {
+	case typ == "bool" || typ == "boolean":
+		return "tinyint(1)"
+	case strings.HasPrefix(typ, "integer"):
+		typ = "int" + strings.TrimPrefix(typ, "integer")
+	}
+	if !strings.HasPrefix(typ, "tinyint(1)") {
+		typ = intDisplayWidth.ReplaceAllString(typ, "$1")
+	}
+	return typ
+}
+
+func nullString(null bool) string {
+	if null {
+		return "NULL"
+	}
+	return "NOT NULL"
+}
+
+func autoIncrString(autoIncr bool) string {
+	if autoIncr {
+		return "AUTO_INCREMENT"
+	}
+	return "not AUTO_INCREMENT"
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
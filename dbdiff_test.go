@@ -0,0 +1,63 @@
+package myddlmaker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeColumnType(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"INTEGER", "int"},
+		{"INTEGER UNSIGNED", "int unsigned"},
+		{"int(11)", "int"},
+		{"bigint(20) unsigned", "bigint unsigned"},
+		{"BOOLEAN", "tinyint(1)"},
+		{"tinyint(1)", "tinyint(1)"},
+		{"tinyint(4)", "tinyint"},
+		{"VARCHAR(191)", "varchar(191)"},
+		{"DECIMAL(10, 2)", "decimal(10,2)"},
+		{"ENUM('active', 'deleted')", "enum('active','deleted')"},
+	}
+	for _, tt := range tests {
+		got := normalizeColumnType(tt.in)
+		if got != tt.want {
+			t.Errorf("normalizeColumnType(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDiffIndexes(t *testing.T) {
+	want := map[string]*dbIndex{
+		"PRIMARY":   {kind: "PRIMARY KEY", columns: []string{"id"}},
+		"idx_name":  {kind: "INDEX", columns: []string{"name"}},
+		"uniq_mail": {kind: "UNIQUE", columns: []string{"mail"}},
+		"FOREIGN KEY fk_group": {
+			kind:       "FOREIGN KEY",
+			columns:    []string{"group_id"},
+			references: "`group` (id)",
+		},
+	}
+	got := map[string]*dbIndex{
+		"PRIMARY":  {kind: "PRIMARY KEY", columns: []string{"id"}},
+		"idx_name": {kind: "INDEX", columns: []string{"name", "age"}},
+		"idx_age":  {kind: "INDEX", columns: []string{"age"}},
+		"fk_group": {kind: "INDEX", columns: []string{"group_id"}},
+		"FOREIGN KEY fk_group": {
+			kind:       "FOREIGN KEY",
+			columns:    []string{"group_id"},
+			references: "`group` (id)",
+		},
+	}
+	diffs := diffIndexes(`table "user"`, want, got)
+	wantDiffs := []string{
+		`table "user", index "idx_name": INDEX (name) in the structs, INDEX (name, age) in the database`,
+		`table "user", unique index "uniq_mail": the index is in the structs, but not in the database`,
+		`table "user", index "idx_age": the index is in the database, but not in the structs`,
+	}
+	if !reflect.DeepEqual(diffs, wantDiffs) {
+		t.Errorf("unexpected diffs:\ngot:  %q\nwant: %q", diffs, wantDiffs)
+	}
+}
@@ -0,0 +1,76 @@
+package myddlmaker
+
+import (
+	"fmt"
+	"io"
+)
+
+// generateGoDBPair generates DBPair that routes the queries to the reader.
+func (m *Maker) generateGoDBPair(w io.Writer) {
+	fmt.Fprintf(w, `// DBPair routes the queries of the generated functions to Reader, e.g. a read replica,
+	// and the other statements to Writer.
+	// The locking reads, e.g. SelectUserForUpdate, are routed to Writer.
+	type DBPair struct {
+		Writer *sql.DB
+		Reader *sql.DB
+	}
+
+	// the generated functions work with DBPair.
+	var (
+		_ execer  = DBPair{}
+		_ queryer = DBPair{}
+	)
+
+	type useWriterKey struct{}
+
+	// UseWriter returns the context that makes DBPair route the queries to Writer,
+	// e.g. to read the rows just written without the replication lag.
+	func UseWriter(ctx context.Context) context.Context {
+		return context.WithValue(ctx, useWriterKey{}, true)
+	}
+
+	func (p DBPair) reader(ctx context.Context) *sql.DB {
+		if ctx.Value(useWriterKey{}) != nil {
+			return p.Writer
+		}
+		return p.Reader
+	}
+
+	func (p DBPair) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+		return p.Writer.ExecContext(ctx, query, args...)
+	}
+
+	func (p DBPair) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+		return p.Writer.PrepareContext(ctx, query)
+	}
+
+	func (p DBPair) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+		return p.Writer.BeginTx(ctx, opts)
+	}
+
+	func (p DBPair) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+		return p.reader(ctx).QueryContext(ctx, query, args...)
+	}
+
+	func (p DBPair) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+		return p.reader(ctx).QueryRowContext(ctx, query, args...)
+	}
+
+	// writerOf returns Writer if q is DBPair, otherwise q.
+	func writerOf(q queryer) queryer {
+		if p, ok := q.(DBPair); ok {
+			return p.Writer
+		}
+		return q
+	}
+
+	`)
+}
+
+// generateGoUseWriter generates the code that routes the queries of the locking read function to the writer.
+func (m *Maker) generateGoUseWriter(w io.Writer, lock lockingRead) {
+	if !m.config.GenerateDBPair || lock.clause == "" {
+		return
+	}
+	fmt.Fprintf(w, "queryer = writerOf(queryer)\n")
+}
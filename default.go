@@ -0,0 +1,166 @@
+package myddlmaker
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	dateLiteral     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	timeLiteral     = regexp.MustCompile(`^-?\d{1,3}:\d{2}:\d{2}(\.\d{1,6})?$`)
+	datetimeLiteral = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}( \d{2}:\d{2}:\d{2}(\.\d{1,6})?)?$`)
+	currentTime     = regexp.MustCompile(`(?i)^(CURRENT_TIMESTAMP|NOW|LOCALTIME|LOCALTIMESTAMP)(\(\d?\))?$`)
+)
+
+// checkDefault checks that the default value of the column is compatible with its type.
+func (c *column) checkDefault() error {
+	def := strings.TrimSpace(c.def)
+	if def == "" {
+		return nil
+	}
+
+	// expressions are evaluated by MySQL, we can't check them.
+	if strings.HasPrefix(def, "(") {
+		return nil
+	}
+
+	if strings.EqualFold(def, "NULL") {
+		if !c.null {
+			return errors.New("the column is NOT NULL")
+		}
+		return nil
+	}
+
+	typ, args := parseType(c.typ)
+	str, quoted := unquoteString(def)
+	switch typ {
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "INTEGER", "BIGINT", "BOOL", "BOOLEAN", "YEAR":
+		if strings.EqualFold(def, "TRUE") || strings.EqualFold(def, "FALSE") {
+			return nil
+		}
+		if c.unsigned {
+			if _, err := strconv.ParseUint(str, 10, 64); err != nil {
+				return errors.New("not an unsigned integer")
+			}
+			return nil
+		}
+		if _, err := strconv.ParseInt(str, 10, 64); err != nil {
+			return errors.New("not an integer")
+		}
+	case "DECIMAL", "NUMERIC", "FLOAT", "DOUBLE", "REAL":
+		if _, err := strconv.ParseFloat(str, 64); err != nil {
+			return errors.New("not a number")
+		}
+	case "DATE":
+		if !quoted || !dateLiteral.MatchString(str) {
+			return errors.New("want a date literal like '2006-01-02'")
+		}
+	case "TIME":
+		if !quoted || !timeLiteral.MatchString(str) {
+			return errors.New("want a time literal like '15:04:05'")
+		}
+	case "DATETIME", "TIMESTAMP":
+		if currentTime.MatchString(def) {
+			return nil
+		}
+		if !quoted || !datetimeLiteral.MatchString(str) {
+			return errors.New("want CURRENT_TIMESTAMP or a datetime literal like '2006-01-02 15:04:05'")
+		}
+	case "CHAR", "VARCHAR", "BINARY", "VARBINARY":
+		if !quoted {
+			return errors.New("string literals must be quoted")
+		}
+		size := c.size
+		if size == 0 && len(args) > 0 {
+			size = args[0]
+		}
+		if size > 0 && utf8.RuneCountInString(str) > size {
+			return fmt.Errorf("longer than the column size %d", size)
+		}
+	case "TINYTEXT", "TEXT", "MEDIUMTEXT", "LONGTEXT",
+		"TINYBLOB", "BLOB", "MEDIUMBLOB", "LONGBLOB",
+		"JSON", "GEOMETRY", "POINT", "LINESTRING", "POLYGON",
+		"MULTIPOINT", "MULTILINESTRING", "MULTIPOLYGON", "GEOMETRYCOLLECTION":
+		return fmt.Errorf("%s columns can't have literal defaults, use an expression like ('value') with MySQL 8.0.13 or later", typ)
+	case "ENUM", "SET":
+		if !quoted {
+			return errors.New("string literals must be quoted")
+		}
+		members := enumMembers(c.typ)
+		values := []string{str}
+		if typ == "SET" && str != "" {
+			values = strings.Split(str, ",")
+		}
+		for _, v := range values {
+			if !containsString(members, v) {
+				return fmt.Errorf("%q is not a member of %s", v, c.typ)
+			}
+		}
+	}
+	return nil
+}
+
+// unquoteString unquotes the SQL string literal.
+// If s is not quoted, it returns s as is.
+func unquoteString(s string) (string, bool) {
+	if len(s) < 2 {
+		return s, false
+	}
+	quote := s[0]
+	if (quote != '\'' && quote != '"') || s[len(s)-1] != quote {
+		return s, false
+	}
+	s = s[1 : len(s)-1]
+	s = strings.ReplaceAll(s, string([]byte{quote, quote}), string(quote))
+	s = strings.ReplaceAll(s, `\`+string(quote), string(quote))
+	return s, true
+}
+
+// enumMembers returns the members of ENUM or SET types, e.g. ENUM('a','b').
+func enumMembers(typ string) []string {
+	_, rest, ok := strings.Cut(typ, "(")
+	if !ok {
+		return nil
+	}
+	rest = strings.TrimSuffix(strings.TrimSpace(rest), ")")
+
+	var members []string
+	for rest != "" {
+		rest = strings.TrimLeft(rest, " ,")
+		if rest == "" || (rest[0] != '\'' && rest[0] != '"') {
+			break
+		}
+		quote := rest[0]
+		i := 1
+		for i < len(rest) {
+			if rest[i] == quote {
+				if i+1 < len(rest) && rest[i+1] == quote {
+					i += 2
+					continue
+				}
+				break
+			}
+			i++
+		}
+		if i >= len(rest) {
+			break
+		}
+		member, _ := unquoteString(rest[:i+1])
+		members = append(members, member)
+		rest = rest[i+1:]
+	}
+	return members
+}
+
+func containsString(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package myddlmaker
+
+import "testing"
+
+func TestColumn_CheckDefault(t *testing.T) {
+	tests := []struct {
+		col *column
+		ok  bool
+	}{
+		{col: &column{typ: "INTEGER", def: "123"}, ok: true},
+		{col: &column{typ: "INTEGER", def: "'123'"}, ok: true},
+		{col: &column{typ: "INTEGER", def: "-1"}, ok: true},
+		{col: &column{typ: "INTEGER", def: "-1", unsigned: true}, ok: false},
+		{col: &column{typ: "INTEGER", def: "abc"}, ok: false},
+		{col: &column{typ: "TINYINT", size: 1, def: "TRUE"}, ok: true},
+		{col: &column{typ: "DOUBLE", def: "1.5"}, ok: true},
+		{col: &column{typ: "DECIMAL(9,6)", def: "1.5e"}, ok: false},
+		{col: &column{typ: "INTEGER", def: "NULL"}, ok: false},
+		{col: &column{typ: "INTEGER", def: "NULL", null: true}, ok: true},
+		{col: &column{typ: "DATETIME", size: 6, def: "CURRENT_TIMESTAMP(6)"}, ok: true},
+		{col: &column{typ: "DATETIME", size: 6, def: "'2006-01-02 15:04:05.999999'"}, ok: true},
+		{col: &column{typ: "DATETIME", size: 6, def: "'2006/01/02'"}, ok: false},
+		{col: &column{typ: "TIMESTAMP", def: "NOW()"}, ok: true},
+		{col: &column{typ: "DATE", def: "'2006-01-02'"}, ok: true},
+		{col: &column{typ: "DATE", def: "2006-01-02"}, ok: false},
+		{col: &column{typ: "TIME", def: "'15:04:05'"}, ok: true},
+		{col: &column{typ: "VARCHAR", size: 191, def: "'hello'"}, ok: true},
+		{col: &column{typ: "VARCHAR", size: 191, def: "hello"}, ok: false},
+		{col: &column{typ: "VARCHAR", size: 3, def: "'hello'"}, ok: false},
+		{col: &column{typ: "TEXT", def: "'hello'"}, ok: false},
+		{col: &column{typ: "TEXT", def: "('hello')"}, ok: true},
+		{col: &column{typ: "JSON", def: "(JSON_ARRAY())"}, ok: true},
+		{col: &column{typ: "ENUM('small','medium','large')", def: "'small'"}, ok: true},
+		{col: &column{typ: "ENUM('small','medium','large')", def: "'huge'"}, ok: false},
+		{col: &column{typ: "SET('a','b','c')", def: "'a,c'"}, ok: true},
+		{col: &column{typ: "SET('a','b','c')", def: "'a,d'"}, ok: false},
+	}
+
+	for _, tt := range tests {
+		err := tt.col.checkDefault()
+		if (err == nil) != tt.ok {
+			t.Errorf("%s DEFAULT %s: unexpected result: %v", tt.col.typ, tt.col.def, err)
+		}
+	}
+}
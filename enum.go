@@ -0,0 +1,154 @@
+package myddlmaker
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// enum is the interface of the string types of ENUM columns, e.g.
+//
+//	type Status string
+//
+//	func (Status) EnumValues() []string {
+//		return []string{"active", "deleted"}
+//	}
+type enum interface {
+	EnumValues() []string
+}
+
+var enumType = reflect.TypeOf((*enum)(nil)).Elem()
+
+// isEnumType reports whether typ is the string type that implements the enum interface.
+func isEnumType(typ reflect.Type) bool {
+	return typ.Kind() == reflect.String && typ.Implements(enumType)
+}
+
+// enumValues returns the members of the ENUM type typ.
+func enumValues(typ reflect.Type) []string {
+	return reflect.Zero(typ).Interface().(enum).EnumValues()
+}
+
+// enumSQLType returns the MySQL type of the members, e.g. ENUM('active','deleted').
+func enumSQLType(values []string) string {
+	return "ENUM(" + strings.Join(quoteMembers(values), ",") + ")"
+}
+
+// enumConstName returns the name of the constant of the member of the ENUM type,
+// e.g. StatusInProgress for "in-progress" of Status.
+func enumConstName(typeName, value string) string {
+	var buf strings.Builder
+	buf.WriteString(typeName)
+	upper := true
+	for _, r := range value {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		buf.WriteRune(r)
+	}
+	if buf.Len() == len(typeName) {
+		buf.WriteString("Empty")
+	}
+	return buf.String()
+}
+
+// hasEnums reports whether the Go source code has the constants and the methods of the ENUM types.
+func (m *Maker) hasEnums() bool {
+	return len(m.enumTypes()) > 0
+}
+
+// enumTypes returns the ENUM types of the columns in the package of the structs, sorted by their names.
+func (m *Maker) enumTypes() []reflect.Type {
+	if !m.config.GenerateEnums {
+		return nil
+	}
+	pkgPath := m.goPkgPath()
+	seen := map[reflect.Type]bool{}
+	var ret []reflect.Type
+	for _, table := range m.tables {
+		for _, c := range table.columns {
+			if c.rawType == nil {
+				continue
+			}
+			typ := c.rawType
+			if !isEnumType(typ) || typ.PkgPath() != pkgPath || seen[typ] {
+				continue
+			}
+			seen[typ] = true
+			ret = append(ret, typ)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name() < ret[j].Name()
+	})
+	return ret
+}
+
+// validateEnums checks that the constants of the members of the ENUM types have the distinct names.
+func (m *Maker) validateEnums() error {
+	for _, typ := range m.enumTypes() {
+		names := map[string]string{}
+		for _, v := range enumValues(typ) {
+			name := enumConstName(typ.Name(), v)
+			if other, ok := names[name]; ok {
+				return fmt.Errorf("myddlmaker: type %s: the members %q and %q have the same constant name %s", typ.Name(), other, v, name)
+			}
+			names[name] = v
+		}
+	}
+	return nil
+}
+
+// generateGoEnums generates the constants of the members of the ENUM types,
+// and their methods String, IsValid, Value and Scan.
+func (m *Maker) generateGoEnums(w io.Writer) {
+	for _, typ := range m.enumTypes() {
+		name := typ.Name()
+		values := enumValues(typ)
+		consts := make([]string, 0, len(values))
+		fmt.Fprintf(w, "// the members of %s, %s.\n", name, enumSQLType(values))
+		fmt.Fprintf(w, "const (\n")
+		for _, v := range values {
+			c := enumConstName(name, v)
+			consts = append(consts, c)
+			fmt.Fprintf(w, "%s %s = %q\n", c, name, v)
+		}
+		fmt.Fprintf(w, ")\n\n")
+
+		fmt.Fprintf(w, "// String implements fmt.Stringer.\n")
+		fmt.Fprintf(w, "func (v %s) String() string {\n return string(v) \n}\n\n", name)
+
+		fmt.Fprintf(w, "// IsValid reports whether v is a member of %s.\n", name)
+		fmt.Fprintf(w, "func (v %s) IsValid() bool {\n", name)
+		fmt.Fprintf(w, "switch v {\n case %s:\n return true \n}\n", strings.Join(consts, ", "))
+		fmt.Fprintf(w, "return false\n")
+		fmt.Fprintf(w, "}\n\n")
+
+		fmt.Fprintf(w, "// Value implements driver.Valuer. It returns an error if v is not a member of %s.\n", name)
+		fmt.Fprintf(w, "func (v %s) Value() (driver.Value, error) {\n", name)
+		fmt.Fprintf(w, "if !v.IsValid() {\n return nil, errors.New(%q + string(v)) \n}\n", "invalid value of "+name+": ")
+		fmt.Fprintf(w, "return string(v), nil\n")
+		fmt.Fprintf(w, "}\n\n")
+
+		fmt.Fprintf(w, "// Scan implements sql.Scanner. It returns an error if the value is not a member of %s.\n", name)
+		fmt.Fprintf(w, "func (v *%s) Scan(src any) error {\n", name)
+		fmt.Fprintf(w, "var s %s\n", name)
+		fmt.Fprintf(w, "switch src := src.(type) {\n")
+		fmt.Fprintf(w, "case string:\n s = %s(src)\n", name)
+		fmt.Fprintf(w, "case []byte:\n s = %s(src)\n", name)
+		fmt.Fprintf(w, "default:\n return errors.New(%q) \n", "unsupported type for "+name)
+		fmt.Fprintf(w, "}\n")
+		fmt.Fprintf(w, "if !s.IsValid() {\n return errors.New(%q + string(s)) \n}\n", "invalid value of "+name+": ")
+		fmt.Fprintf(w, "*v = s\n")
+		fmt.Fprintf(w, "return nil\n")
+		fmt.Fprintf(w, "}\n\n")
+	}
+}
@@ -0,0 +1,76 @@
+package myddlmaker
+
+import (
+	"fmt"
+	"io"
+)
+
+// usesMySQLDriver reports whether the Go source code imports the MySQL driver
+// to check the error numbers or to register the readers of LOAD DATA LOCAL INFILE.
+func (m *Maker) usesMySQLDriver() bool {
+	return m.config.GenerateRetry || m.config.GenerateErrorHelpers || m.hasCSV()
+}
+
+// generateGoErrorHelpers generates the helpers that classify the MySQL errors.
+func (m *Maker) generateGoErrorHelpers(w io.Writer) {
+	fmt.Fprintf(w, `// ErrDuplicateEntry is the class of the errors of the duplicated primary keys and unique keys.
+	// The errors returned by the generated write functions match it with errors.Is.
+	var ErrDuplicateEntry = errors.New("duplicate entry")
+
+	// ErrForeignKeyViolation is the class of the errors of the foreign key constraints.
+	// The errors returned by the generated write functions match it with errors.Is.
+	var ErrForeignKeyViolation = errors.New("foreign key constraint fails")
+
+	// IsDuplicateEntry reports whether err is the error of the duplicated primary key or unique key.
+	func IsDuplicateEntry(err error) bool {
+		return errors.Is(err, ErrDuplicateEntry) || errorClass(err) == ErrDuplicateEntry
+	}
+
+	// IsForeignKeyViolation reports whether err is the error of the foreign key constraints.
+	func IsForeignKeyViolation(err error) bool {
+		return errors.Is(err, ErrForeignKeyViolation) || errorClass(err) == ErrForeignKeyViolation
+	}
+
+	// errorClass returns the class of the MySQL error, or nil if it is not classified.
+	func errorClass(err error) error {
+		var myErr *mysql.MySQLError
+		if !errors.As(err, &myErr) {
+			return nil
+		}
+		switch myErr.Number {
+		case 1062, 1586: // ER_DUP_ENTRY, ER_DUP_ENTRY_WITH_KEY_NAME
+			return ErrDuplicateEntry
+		case 1216, 1217, 1451, 1452: // ER_NO_REFERENCED_ROW, ER_ROW_IS_REFERENCED, ER_ROW_IS_REFERENCED_2, ER_NO_REFERENCED_ROW_2
+			return ErrForeignKeyViolation
+		}
+		return nil
+	}
+
+	// classifiedError is the MySQL error with its class.
+	type classifiedError struct {
+		err   error
+		class error
+	}
+
+	// classifyError wraps the MySQL error, so that it matches its class with errors.Is.
+	func classifyError(err error) error {
+		if class := errorClass(err); class != nil {
+			return &classifiedError{err: err, class: class}
+		}
+		return err
+	}
+
+	func (e *classifiedError) Error() string {
+		return e.err.Error()
+	}
+
+	func (e *classifiedError) Unwrap() error {
+		return e.err
+	}
+
+	func (e *classifiedError) Is(target error) bool {
+		return target == e.class
+	}
+
+	`)
+}
@@ -0,0 +1,298 @@
+package myddlmaker
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// hasQueryPlans reports whether the Go source code has ExplainQueryPlans.
+func (m *Maker) hasQueryPlans() bool {
+	return m.config.GenerateQueryPlans && len(m.tables) > 0
+}
+
+// generateGoQueryPlans generates QueryPlan, AllQueryPlans and ExplainQueryPlans.
+func (m *Maker) generateGoQueryPlans(w io.Writer) {
+	fmt.Fprintf(w, `// QueryPlan is a generated query that looks up the rows by an index, checked by ExplainQueryPlans.
+	type QueryPlan struct {
+		// Func is the name of the generated function that executes the query.
+		Func string
+
+		// Query is the query without ORDER BY and LIMIT.
+		Query string
+
+		// Args are the zero values of the placeholders of the query.
+		Args []any
+
+		// Indexes are the names of the indexes that the query is intended to use, e.g. "PRIMARY".
+		Indexes []string
+	}
+
+	`)
+
+	fmt.Fprintf(w, "// AllQueryPlans returns the generated queries of all the tables that look up the rows by the indexes.\n")
+	fmt.Fprintf(w, "func AllQueryPlans() []*QueryPlan {\n")
+	fmt.Fprintf(w, "var ret []*QueryPlan\n")
+	for _, t := range m.tables {
+		fmt.Fprintf(w, "ret = append(ret, %sQueryPlans...)\n", goParamName(t.rawName))
+	}
+	fmt.Fprintf(w, "return ret\n")
+	fmt.Fprintf(w, "}\n\n")
+
+	fmt.Fprintf(w, `// ExplainQueryPlans runs EXPLAIN on the queries of AllQueryPlans against the database that has the tables,
+	// e.g. the empty database of the tests, and returns the queries that don't use their indexes,
+	// e.g. the full scans after an index is dropped or changed.
+	// The queries of the invisible indexes are always returned, because MySQL doesn't use the invisible indexes.
+	func ExplainQueryPlans(ctx context.Context, queryer queryer) ([]string, error) {
+		var ret []string
+		for _, plan := range AllQueryPlans() {
+			access, key, extra, err := explainQuery(ctx, queryer, plan)
+			if err != nil {
+				return nil, fmt.Errorf("%%s: failed to explain %%q: %%w", plan.Func, plan.Query, err)
+			}
+			if usesQueryPlanIndex(plan, key, extra) {
+				continue
+			}
+			if key == "" {
+				key = "no index"
+			}
+			want := strings.Join(plan.Indexes, " or ")
+			if want == "" {
+				// the indexes of the columns are invisible.
+				want = "a visible index"
+			}
+			ret = append(ret, fmt.Sprintf("%%s: the query uses %%s (type %%s), want %%s: %%s", plan.Func, key, access, want, plan.Query))
+		}
+		return ret, nil
+	}
+
+	// explainQuery runs EXPLAIN on the query of the plan, and returns the access type, the key and the extra information.
+	func explainQuery(ctx context.Context, queryer queryer, plan *QueryPlan) (access, key, extra string, err error) {
+		rows, err := queryer.QueryContext(ctx, "EXPLAIN "+plan.Query, plan.Args...)
+		if err != nil {
+			return "", "", "", err
+		}
+		defer rows.Close()
+		columns, err := rows.Columns()
+		if err != nil {
+			return "", "", "", err
+		}
+		if !rows.Next() {
+			if err := rows.Err(); err != nil {
+				return "", "", "", err
+			}
+			return "", "", "", errors.New("no rows of EXPLAIN")
+		}
+		values := make([]sql.NullString, len(columns))
+		dest := make([]any, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return "", "", "", err
+		}
+		for i, name := range columns {
+			switch name {
+			case "type":
+				access = values[i].String
+			case "key":
+				key = values[i].String
+			case "Extra":
+				extra = values[i].String
+			}
+		}
+		return access, key, extra, rows.Close()
+	}
+
+	// usesQueryPlanIndex reports whether EXPLAIN shows that the query uses one of the indexes of the plan.
+	func usesQueryPlanIndex(plan *QueryPlan, key, extra string) bool {
+		// EXPLAIN of the lookups by the unique keys in the empty tables doesn't show the key,
+		// because the optimizer reads the row before the execution.
+		if strings.Contains(extra, "no matching row in const table") || strings.Contains(extra, "Impossible WHERE noticed after reading const tables") {
+			return true
+		}
+		for _, idx := range plan.Indexes {
+			if key == idx {
+				return true
+			}
+		}
+		return false
+	}
+
+	`)
+}
+
+// generateGoTableQueryPlans generates the queries of the table that look up the rows by the indexes,
+// e.g. userQueryPlans. They are the same queries as the generated functions.
+func (m *Maker) generateGoTableQueryPlans(w io.Writer, table *table) {
+	fields := make([]string, 0, len(table.columns))
+	for _, c := range table.columns {
+		fields = append(fields, quote(c.name))
+	}
+	sqlSelect := fmt.Sprintf("SELECT %s FROM %s", strings.Join(fields, ", "), table.quotedName())
+
+	type queryPlan struct {
+		funcName string
+		query    string
+		columns  []string
+	}
+	var plans []queryPlan
+
+	// Select{{.Name}} looks up the row by the primary key in the order of the columns.
+	if table.primaryKey != nil {
+		var columns, conditions []string
+		for _, c := range table.columns {
+			for _, key := range table.primaryKey.columns {
+				if key == c.name {
+					columns = append(columns, c.name)
+					conditions = append(conditions, fmt.Sprintf("%s = ?", quote(c.name)))
+				}
+			}
+		}
+		plans = append(plans, queryPlan{
+			funcName: "Select" + table.rawName,
+			query:    sqlSelect + where(table.withNotDeleted(conditions)),
+			columns:  columns,
+		})
+	}
+
+	// Select{{.Name}}By{{.Finder}} looks up the row by the unique index.
+	generated := map[string]bool{}
+	for _, idx := range table.uniqueIndexes {
+		finder := finderOf(table, idx.columns)
+		if finder == nil || generated[finder.name] {
+			continue
+		}
+		generated[finder.name] = true
+		plans = append(plans, queryPlan{
+			funcName: "Select" + table.rawName + "By" + finder.name,
+			query:    sqlSelect + where(table.withNotDeleted(finder.conditions)),
+			columns:  idx.columns,
+		})
+	}
+
+	// SelectAll{{.Name}}By{{.Finder}} looks up the rows by the index.
+	generated = map[string]bool{}
+	for _, idx := range table.indexes {
+		finder := finderOf(table, idx.columns)
+		if finder == nil || generated[finder.name] {
+			continue
+		}
+		generated[finder.name] = true
+		plans = append(plans, queryPlan{
+			funcName: "SelectAll" + table.rawName + "By" + finder.name,
+			query:    sqlSelect + where(table.withNotDeleted(finder.conditions)),
+			columns:  idx.columns,
+		})
+	}
+
+	// Delete{{.Name}}By{{.Finder}} deletes the rows by the unique index or the index.
+	var keys [][]string
+	for _, idx := range table.uniqueIndexes {
+		keys = append(keys, idx.columns)
+	}
+	for _, idx := range table.indexes {
+		keys = append(keys, idx.columns)
+	}
+	softDelete := table.softDeleteColumn()
+	generated = map[string]bool{}
+	for _, key := range keys {
+		finder := finderOf(table, key)
+		if finder == nil || generated[finder.name] {
+			continue
+		}
+		generated[finder.name] = true
+		del := fmt.Sprintf("DELETE FROM %s%s", table.quotedName(), where(finder.conditions))
+		if softDelete == nil {
+			plans = append(plans, queryPlan{funcName: "Delete" + table.rawName + "By" + finder.name, query: del, columns: key})
+			continue
+		}
+		update := fmt.Sprintf(
+			"UPDATE %s SET %s = %s%s",
+			table.quotedName(),
+			quote(softDelete.name),
+			softDelete.currentTimestamp(),
+			where(table.withNotDeleted(finder.conditions)),
+		)
+		plans = append(plans, queryPlan{funcName: "Delete" + table.rawName + "By" + finder.name, query: update, columns: key})
+		plans = append(plans, queryPlan{funcName: "HardDelete" + table.rawName + "By" + finder.name, query: del, columns: key})
+	}
+
+	name := goParamName(table.rawName) + "QueryPlans"
+	fmt.Fprintf(w, "// %s are the generated queries of %s that look up the rows by the indexes.\n", name, table.fullName())
+	fmt.Fprintf(w, "var %s = []*QueryPlan{\n", name)
+	for _, plan := range plans {
+		args := make([]string, 0, len(plan.columns))
+		for _, col := range plan.columns {
+			args = append(args, table.column(col).zeroValue())
+		}
+		fmt.Fprintf(w, "{\n")
+		fmt.Fprintf(w, "Func: %q,\n", plan.funcName)
+		fmt.Fprintf(w, "Query: %q,\n", plan.query)
+		fmt.Fprintf(w, "Args: []any{%s},\n", strings.Join(args, ", "))
+		fmt.Fprintf(w, "Indexes: []string{%s},\n", strings.Join(quoteAllGo(table.lookupIndexes(plan.columns)), ", "))
+		fmt.Fprintf(w, "},\n")
+	}
+	fmt.Fprintf(w, "}\n\n")
+}
+
+// zeroValue returns the Go expression of the zero value of the column, used as the placeholders of EXPLAIN.
+func (c *column) zeroValue() string {
+	if c == nil || c.fieldType == nil {
+		return "nil"
+	}
+	switch c.fieldType.Kind() {
+	case reflect.String:
+		return `""`
+	case reflect.Bool:
+		return "false"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "0"
+	}
+	if c.fieldType == timeType {
+		return "time.Time{}"
+	}
+	if c.fieldType.Kind() == reflect.Slice && c.fieldType.Elem().Kind() == reflect.Uint8 {
+		return "[]byte{}"
+	}
+	return "nil"
+}
+
+// lookupIndexes returns the names of the visible indexes that look up the rows by the values of the columns,
+// i.e. the indexes whose leading columns are the columns.
+func (t *table) lookupIndexes(columns []string) []string {
+	set := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		set[col] = true
+	}
+	leads := func(index []string) bool {
+		if len(index) < len(set) {
+			return false
+		}
+		for _, col := range index[:len(set)] {
+			if !set[col] {
+				return false
+			}
+		}
+		return true
+	}
+
+	var ret []string
+	if t.primaryKey != nil && leads(t.primaryKey.columns) {
+		ret = append(ret, "PRIMARY")
+	}
+	for _, idx := range t.uniqueIndexes {
+		if !idx.invisible && leads(idx.columns) {
+			ret = append(ret, idx.name)
+		}
+	}
+	for _, idx := range t.indexes {
+		if !idx.invisible && leads(idx.columns) {
+			ret = append(ret, idx.name)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,229 @@
+package myddlmaker
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// hasFactories reports whether the Go source code has the factories of the structs.
+func (m *Maker) hasFactories() bool {
+	return (m.config.GenerateFactories || m.config.GenerateSeeder) && len(m.tables) > 0
+}
+
+// generateGoFactoryHelpers generates the helpers that the factories share.
+func (m *Maker) generateGoFactoryHelpers(w io.Writer) {
+	fmt.Fprintf(w, `// factorySeq is the sequence number of the values built by the factories,
+	// that makes the fake values of the unique columns distinct.
+	var factorySeq int64
+
+	// factoryEpoch is the base of the fake times.
+	var factoryEpoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// fakeString returns the fake string of the column, e.g. "name-1".
+	// If it is longer than size, its head is cut off so that the sequence number remains.
+	func fakeString(column string, n int, size int) string {
+		s := column + "-" + strconv.Itoa(n)
+		if size > 0 && len(s) > size {
+			s = s[len(s)-size:]
+		}
+		return s
+	}
+
+	func factoryPtr[T any](v T) *T {
+		return &v
+	}
+
+	`)
+}
+
+// generateGoTableFactory generates the factory of the table, e.g. NewUserFactory.
+func (m *Maker) generateGoTableFactory(w io.Writer, table *table) {
+	name := table.rawName
+	factory := name + "Factory"
+	pkgPath := m.goPkgPath()
+
+	fmt.Fprintf(w, "// %s builds the values of %s with the fake values for the tests.\n", factory, name)
+	fmt.Fprintf(w, "type %s struct {\n", factory)
+	fmt.Fprintf(w, "overrides []func(v *%s)\n", name)
+	fmt.Fprintf(w, "}\n\n")
+
+	fmt.Fprintf(w, "// New%[1]s returns a new %[1]s.\n", factory)
+	fmt.Fprintf(w, "func New%[1]s() *%[1]s {\n return &%[1]s{} \n}\n\n", factory)
+
+	fmt.Fprintf(w, "// With returns a copy of f that calls fn to override the fields of the built values.\n")
+	fmt.Fprintf(w, "func (f *%s) With(fn func(v *%s)) *%[1]s {\n", factory, name)
+	fmt.Fprintf(w, "overrides := make([]func(v *%s), 0, len(f.overrides)+1)\n", name)
+	fmt.Fprintf(w, "overrides = append(overrides, f.overrides...)\n")
+	fmt.Fprintf(w, "return &%s{overrides: append(overrides, fn)}\n", factory)
+	fmt.Fprintf(w, "}\n\n")
+
+	for _, c := range table.columns {
+		if c.fieldType == nil {
+			continue
+		}
+		typ := goTypeExpr(c.fieldType, pkgPath, map[string]bool{})
+		if typ == "" {
+			continue
+		}
+		param := goParamName(c.rawName)
+		fmt.Fprintf(w, "// With%s returns a copy of f that sets %s to the field %s of the built values.\n", c.rawName, param, c.goField)
+		fmt.Fprintf(w, "func (f *%s) With%s(%s %s) *%[1]s {\n", factory, c.rawName, param, typ)
+		fmt.Fprintf(w, "return f.With(func(v *%s) {\n v.%s = %s \n})\n", name, c.goField, param)
+		fmt.Fprintf(w, "}\n\n")
+	}
+
+	fmt.Fprintf(w, "// Build returns a new %s with the fake values that satisfy the sizes, the members of ENUM and the NOT NULL constraints,\n", name)
+	fmt.Fprintf(w, "// and then calls the overrides. The values of the unique columns are distinct in each call.\n")
+	fmt.Fprintf(w, "// The fields of the auto increment, auto_now, version and soft delete columns and the NULL columns are left zero,\n")
+	fmt.Fprintf(w, "// and the foreign keys must be overridden to refer to the existing rows.\n")
+	fmt.Fprintf(w, "func (f *%s) Build() *%s {\n", factory, name)
+	var fields []string
+	usesSeq := false
+	for _, c := range table.columns {
+		if expr := c.fakeValue(pkgPath); expr != "" {
+			fields = append(fields, "v."+c.goField+" = "+expr+"\n")
+			usesSeq = usesSeq || seqVar.MatchString(expr)
+		}
+	}
+	if usesSeq {
+		fmt.Fprintf(w, "n := int(atomic.AddInt64(&factorySeq, 1))\n")
+	}
+	fmt.Fprintf(w, "v := &%s{}\n%s", name, strings.Join(fields, ""))
+	fmt.Fprintf(w, "for _, fn := range f.overrides {\n fn(v) \n}\n")
+	fmt.Fprintf(w, "return v\n")
+	fmt.Fprintf(w, "}\n\n")
+
+	fmt.Fprintf(w, "// BuildList returns n new %s built by Build.\n", name)
+	fmt.Fprintf(w, "func (f *%s) BuildList(n int) []*%s {\n", factory, name)
+	fmt.Fprintf(w, "ret := make([]*%s, 0, n)\n", name)
+	fmt.Fprintf(w, "for i := 0; i < n; i++ {\n ret = append(ret, f.Build()) \n}\n")
+	fmt.Fprintf(w, "return ret\n")
+	fmt.Fprintf(w, "}\n\n")
+}
+
+// seqVar matches the sequence number n in the expressions of the fake values.
+var seqVar = regexp.MustCompile(`\bn\b`)
+
+// fakeValue returns the Go expression of the fake value of the column with the sequence number n,
+// or an empty string if the field is left zero.
+func (c *column) fakeValue(pkgPath string) string {
+	if c.fieldType == nil || c.null || c.autoIncr || c.autoNow || c.autoNowAdd || c.version || c.softDelete {
+		return ""
+	}
+
+	typ := c.fieldType
+	switch {
+	case typ.Kind() == reflect.Pointer:
+		if expr := c.fakeBaseValue(typ.Elem(), pkgPath); expr != "" {
+			return "factoryPtr(" + expr + ")"
+		}
+		return ""
+	case typ.Kind() == reflect.Struct && typ.PkgPath() == "database/sql" && typ.NumField() == 2:
+		// sql.NullString, sql.NullInt64 and so on.
+		field := typ.Field(0)
+		if expr := c.fakeBaseValue(field.Type, pkgPath); expr != "" {
+			return fmt.Sprintf("sql.%s{%s: %s, Valid: true}", typ.Name(), field.Name, expr)
+		}
+		return ""
+	}
+	return c.fakeBaseValue(typ, pkgPath)
+}
+
+// fakeBaseValue returns the Go expression of the fake value of the type typ of the column.
+func (c *column) fakeBaseValue(typ reflect.Type, pkgPath string) string {
+	if typ == timeType {
+		return "factoryEpoch.Add(time.Duration(n) * time.Second)"
+	}
+	goType := goTypeExpr(typ, pkgPath, map[string]bool{})
+	if goType == "" {
+		return ""
+	}
+
+	name, args := parseType(c.typ)
+	if fields := strings.Fields(name); len(fields) > 0 {
+		name = fields[0]
+	}
+	size := c.size
+	if size == 0 && len(args) > 0 {
+		size = args[0]
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		switch name {
+		case "ENUM":
+			members := enumMembers(c.typ)
+			if len(members) == 0 {
+				return ""
+			}
+			return fmt.Sprintf("[]%s{%s}[n%%%d]", goType, strings.Join(quoteAllGo(members), ", "), len(members))
+		case "SET":
+			members := enumMembers(c.typ)
+			if len(members) == 0 {
+				return ""
+			}
+			return convertTo(goType, fmt.Sprintf("%q", members[0]))
+		case "CHAR", "VARCHAR":
+			return convertTo(goType, fmt.Sprintf("fakeString(%q, n, %d)", c.name, withDefault(size, 1)))
+		case "TINYTEXT", "TEXT", "MEDIUMTEXT", "LONGTEXT":
+			return convertTo(goType, fmt.Sprintf("fakeString(%q, n, 0)", c.name))
+		case "JSON":
+			return convertTo(goType, `"{}"`)
+		}
+	case reflect.Slice:
+		if typ.Elem().Kind() != reflect.Uint8 || typ == jsonRawMessageType {
+			return ""
+		}
+		if typ.Name() == "" {
+			goType = "[]byte"
+		}
+		switch name {
+		case "BINARY", "VARBINARY":
+			return convertTo(goType, fmt.Sprintf("fakeString(%q, n, %d)", c.name, withDefault(size, 1)))
+		case "TINYBLOB", "BLOB", "MEDIUMBLOB", "LONGBLOB":
+			return convertTo(goType, fmt.Sprintf("fakeString(%q, n, 0)", c.name))
+		}
+	case reflect.Bool:
+		return "true"
+	case reflect.Float32, reflect.Float64:
+		return fmt.Sprintf("%s(n) + 0.5", goType)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		// the maximum value of the field and the column.
+		limit := uint64(math.MaxInt32)
+		bits := typ.Bits()
+		if typ.Kind() <= reflect.Int64 {
+			// the signed integers.
+			bits--
+		}
+		if bits < 31 {
+			limit = 1<<bits - 1
+		}
+		if bits, ok := integerBits[name]; ok && bits < 32 {
+			unsigned := c.unsigned || strings.Contains(strings.ToUpper(c.typ), "UNSIGNED")
+			if !unsigned {
+				bits--
+			}
+			if colMax := uint64(1)<<bits - 1; colMax < limit {
+				limit = colMax
+			}
+		}
+		if limit >= math.MaxInt32 {
+			return fmt.Sprintf("%s(n)", goType)
+		}
+		return fmt.Sprintf("%s(n%%%d + 1)", goType, limit)
+	}
+	return ""
+}
+
+// convertTo returns the Go expression that converts the string expression expr to the type goType.
+func convertTo(goType, expr string) string {
+	if goType == "string" {
+		return expr
+	}
+	return goType + "(" + expr + ")"
+}
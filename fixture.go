@@ -0,0 +1,185 @@
+package myddlmaker
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// hasFixtures reports whether the Go source code has the fixture loaders.
+func (m *Maker) hasFixtures() bool {
+	return m.config.GenerateFixtures && len(m.tables) > 0
+}
+
+// insertOrder returns the tables in the order that the fixtures and the seeds are inserted,
+// i.e. the tables referenced by the foreign keys come before the tables that refer to them.
+// The cycles of the references are broken in the order of the structs.
+func (m *Maker) insertOrder() []*table {
+	byName := make(map[string]*table, len(m.tables))
+	for _, t := range m.tables {
+		byName[t.fullName()] = t
+	}
+
+	ret := make([]*table, 0, len(m.tables))
+	visited := map[*table]bool{}
+	var visit func(t *table)
+	visit = func(t *table) {
+		if visited[t] {
+			return
+		}
+		visited[t] = true
+		for _, fk := range t.foreignKeys {
+			if ref, ok := byName[fk.referencedTable(t)]; ok {
+				visit(ref)
+			}
+		}
+		ret = append(ret, t)
+	}
+	for _, t := range m.tables {
+		visit(t)
+	}
+	return ret
+}
+
+// generateGoFixtures generates the loaders of the fixtures of all the tables.
+func (m *Maker) generateGoFixtures(w io.Writer) {
+	tables := m.insertOrder()
+	names := make([]string, 0, len(tables))
+	for _, t := range tables {
+		names = append(names, t.fullName())
+	}
+
+	fmt.Fprintf(w, `// Fixtures are the rows inserted by LoadFixtures.
+	// The keys are the table names, and the keys of the rows are the column names, e.g.
+	//
+	//	user:
+	//	  - id: 1
+	//	    name: Alice
+	type Fixtures map[string][]map[string]any
+
+	// FixtureUnmarshalers are the functions that decode the fixture files into Fixtures by their extensions,
+	// used by LoadFixturesFile. Only ".json" is registered by default.
+	// Register the decoder of YAML, e.g. FixtureUnmarshalers[".yaml"] = yaml.Unmarshal of gopkg.in/yaml.v3.
+	var FixtureUnmarshalers = map[string]func(data []byte, v any) error{
+		".json": unmarshalFixtureJSON,
+	}
+
+	// unmarshalFixtureJSON decodes the numbers into json.Number, so that the large integers keep their precision.
+	func unmarshalFixtureJSON(data []byte, v any) error {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		return dec.Decode(v)
+	}
+
+	// LoadFixturesFile decodes the fixture files in fsys with FixtureUnmarshalers, and inserts their rows by LoadFixtures.
+	// The rows of the same table in the files are inserted in the order of the files.
+	func LoadFixturesFile(ctx context.Context, execer execer, fsys fs.FS, names ...string) error {
+		fixtures := Fixtures{}
+		for _, name := range names {
+			unmarshal, ok := FixtureUnmarshalers[path.Ext(name)]
+			if !ok {
+				return fmt.Errorf("%%s: unknown extension of the fixture file", name)
+			}
+			data, err := fs.ReadFile(fsys, name)
+			if err != nil {
+				return err
+			}
+			var f Fixtures
+			if err := unmarshal(data, &f); err != nil {
+				return fmt.Errorf("%%s: %%w", name, err)
+			}
+			for table, rows := range f {
+				fixtures[table] = append(fixtures[table], rows...)
+			}
+		}
+		return LoadFixtures(ctx, execer, fixtures)
+	}
+
+	`)
+
+	fmt.Fprintf(w, "// LoadFixtures inserts the rows of the fixtures into the tables.\n")
+	fmt.Fprintf(w, "// The tables referenced by the foreign keys are inserted first: %s.\n", strings.Join(names, ", "))
+	fmt.Fprintf(w, "// It returns an error if the fixtures have an unknown table or column.\n")
+	fmt.Fprintf(w, "func LoadFixtures(ctx context.Context, execer execer, fixtures Fixtures) error {\n")
+	fmt.Fprintf(w, "for name := range fixtures {\n")
+	fmt.Fprintf(w, "switch name {\n case %s:\n", strings.Join(quoteAllGo(names), ", "))
+	fmt.Fprintf(w, "default:\n return fmt.Errorf(\"unknown table in the fixtures: %%q\", name) \n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "}\n")
+	for _, t := range tables {
+		fmt.Fprintf(w, "if err := Load%sFixtures(ctx, execer, fixtures[%q]); err != nil {\n return err \n}\n", t.rawName, t.fullName())
+	}
+	fmt.Fprintf(w, "return nil\n")
+	fmt.Fprintf(w, "}\n\n")
+
+	fmt.Fprintf(w, `// loadFixture inserts the row of the fixture into the table.
+	// The maps and the slices, e.g. the values of the JSON columns, are marshaled into JSON.
+	func loadFixture(ctx context.Context, execer execer, table string, columns map[string]bool, row map[string]any) error {
+		names := make([]string, 0, len(row))
+		for name := range row {
+			if !columns[name] {
+				return fmt.Errorf("unknown column %%q", name)
+			}
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var cols, values strings.Builder
+		args := make([]any, 0, len(names))
+		for i, name := range names {
+			v := row[name]
+			switch v.(type) {
+			case map[string]any, []any:
+				data, err := json.Marshal(v)
+				if err != nil {
+					return fmt.Errorf("column %%q: %%w", name, err)
+				}
+				v = string(data)
+			}
+			if i > 0 {
+				cols.WriteString(", ")
+				values.WriteString(", ")
+			}
+			cols.WriteString("`+"`\" + name + \"`"+`")
+			values.WriteString("?")
+			args = append(args, v)
+		}
+		_, err := execer.ExecContext(ctx, "INSERT INTO "+table+" ("+cols.String()+") VALUES ("+values.String()+")", args...)
+		return err
+	}
+
+	`)
+}
+
+// generateGoTableFixtures generates the loader of the fixtures of the table.
+func (m *Maker) generateGoTableFixtures(w io.Writer, table *table) {
+	name := table.rawName
+	columns := goParamName(name) + "FixtureColumns"
+
+	fmt.Fprintf(w, "// %s is the columns of %s that the fixtures can have.\n", columns, table.fullName())
+	fmt.Fprintf(w, "var %s = map[string]bool{\n", columns)
+	for _, c := range table.columns {
+		fmt.Fprintf(w, "%q: true,\n", c.name)
+	}
+	fmt.Fprintf(w, "}\n\n")
+
+	fmt.Fprintf(w, "// Load%sFixtures inserts the rows of the fixtures into %s. The keys of the rows are the column names.\n", name, table.fullName())
+	fmt.Fprintf(w, "// The maps and the slices, e.g. the values of the JSON columns, are marshaled into JSON.\n")
+	fmt.Fprintf(w, "func Load%sFixtures(ctx context.Context, execer execer, rows []map[string]any) error {\n", name)
+	fmt.Fprintf(w, "for i, row := range rows {\n")
+	fmt.Fprintf(w, "if err := loadFixture(ctx, execer, %q, %s, row); err != nil {\n", table.quotedName(), columns)
+	fmt.Fprintf(w, "return fmt.Errorf(\"%s: row #%%d: %%w\", i, err)\n", table.fullName())
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "return nil\n")
+	fmt.Fprintf(w, "}\n\n")
+}
+
+// quoteAllGo returns the Go string literals of the strings.
+func quoteAllGo(s []string) []string {
+	ret := make([]string, 0, len(s))
+	for _, v := range s {
+		ret = append(ret, fmt.Sprintf("%q", v))
+	}
+	return ret
+}
@@ -0,0 +1,153 @@
+package myddlmaker
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// hasGenericRepositories reports whether the Go source code has the generic repository.
+func (m *Maker) hasGenericRepositories() bool {
+	if !m.config.GenerateGenericRepository {
+		return false
+	}
+	for _, table := range m.tables {
+		if table.primaryKey != nil && finderOf(table, table.primaryKey.columns) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// generateGoGenericRepository generates the generic repository Repo and the metadata of the tables TableMeta.
+func (m *Maker) generateGoGenericRepository(w io.Writer) {
+	fmt.Fprintf(w, `// TableMeta is the metadata of a table that Repo works with, e.g. UserMeta.
+	// T is the struct of the table, and PK is the type of its primary key.
+	type TableMeta[T any, PK comparable] struct {
+		name   string
+		key    func(v *T) PK
+		row    func(key PK) *T
+		get    func(ctx context.Context, q queryer, primaryKeys *T) (*T, error)
+		list   func(ctx context.Context, q queryer, opts ...SelectOption) ([]*T, error)
+		insert func(ctx context.Context, e execer, values ...*T) error
+		update func(ctx context.Context, e execer, values ...*T) error
+		delete func(ctx context.Context, e execer, values ...*T) error
+	}
+
+	// Name returns the name of the table.
+	func (t TableMeta[T, PK]) Name() string {
+		return t.name
+	}
+
+	// Key returns the primary key of v.
+	func (t TableMeta[T, PK]) Key(v *T) PK {
+		return t.key(v)
+	}
+
+	// Repo is the repository of the table that calls the generated functions with db,
+	// such as *sql.DB, *sql.Conn and *sql.Tx.
+	// It allows to write the helpers that work with any table, keeping the type safety.
+	type Repo[T any, PK comparable] struct {
+		db   dbtx
+		meta TableMeta[T, PK]
+	}
+
+	// NewRepo returns a new repository of the table.
+	func NewRepo[T any, PK comparable](db dbtx, meta TableMeta[T, PK]) *Repo[T, PK] {
+		return &Repo[T, PK]{db: db, meta: meta}
+	}
+
+	// Meta returns the metadata of the table.
+	func (r *Repo[T, PK]) Meta() TableMeta[T, PK] {
+		return r.meta
+	}
+
+	// Get selects the row of the primary key. It returns sql.ErrNoRows if the row is not found.
+	func (r *Repo[T, PK]) Get(ctx context.Context, key PK) (*T, error) {
+		return r.meta.get(ctx, r.db, r.meta.row(key))
+	}
+
+	// List selects all the rows.
+	func (r *Repo[T, PK]) List(ctx context.Context, opts ...SelectOption) ([]*T, error) {
+		return r.meta.list(ctx, r.db, opts...)
+	}
+
+	// Insert inserts the values.
+	func (r *Repo[T, PK]) Insert(ctx context.Context, values ...*T) error {
+		return r.meta.insert(ctx, r.db, values...)
+	}
+
+	// Update updates the rows of the values by their primary keys.
+	func (r *Repo[T, PK]) Update(ctx context.Context, values ...*T) error {
+		return r.meta.update(ctx, r.db, values...)
+	}
+
+	// Delete deletes the rows of the primary keys.
+	func (r *Repo[T, PK]) Delete(ctx context.Context, keys ...PK) error {
+		values := make([]*T, 0, len(keys))
+		for _, key := range keys {
+			values = append(values, r.meta.row(key))
+		}
+		return r.meta.delete(ctx, r.db, values...)
+	}
+
+	`)
+}
+
+// generateGoTableMeta generates the metadata of the table for the generic repository,
+// and the type of the primary key if it has multiple columns.
+func (m *Maker) generateGoTableMeta(w io.Writer, table *table) {
+	if finderOf(table, table.primaryKey.columns) == nil {
+		return
+	}
+	name := table.rawName
+	pkgPath := m.goPkgPath()
+
+	// types are the types of the primary key columns, keys are the expressions of the primary key of v,
+	// and fields are the fields of the row with the key.
+	types := make([]string, 0, len(table.primaryKey.columns))
+	keys := make([]string, 0, len(table.primaryKey.columns))
+	fields := make([]string, 0, len(table.primaryKey.columns))
+	for _, c := range table.primaryKey.columns {
+		col := table.column(c)
+		typ := col.goTypeExpr(pkgPath, map[string]bool{})
+		if typ == "" {
+			// the type of the key can't be referred.
+			return
+		}
+		types = append(types, typ)
+		keys = append(keys, "v."+col.goField)
+		fields = append(fields, "v."+col.goField+" = key\n")
+	}
+
+	keyType, keyExpr := types[0], keys[0]
+	if len(keys) > 1 {
+		keyType = name + "Key"
+		fmt.Fprintf(w, "// %s is the primary key of %s.\n", keyType, name)
+		fmt.Fprintf(w, "type %s struct {\n", keyType)
+		exprs := make([]string, 0, len(keys))
+		for i, c := range table.primaryKey.columns {
+			col := table.column(c)
+			fmt.Fprintf(w, "%s %s\n", col.rawName, types[i])
+			exprs = append(exprs, col.rawName+": "+keys[i])
+			fields[i] = fmt.Sprintf("v.%s = key.%s\n", col.goField, col.rawName)
+		}
+		fmt.Fprintf(w, "}\n\n")
+		keyExpr = fmt.Sprintf("%s{%s}", keyType, strings.Join(exprs, ", "))
+	}
+
+	fmt.Fprintf(w, "// %[1]sMeta is the metadata of %[1]s for Repo.\n", name)
+	fmt.Fprintf(w, "var %[1]sMeta = TableMeta[%[1]s, %[2]s]{\n", name, keyType)
+	fmt.Fprintf(w, "name: %sTable,\n", name)
+	fmt.Fprintf(w, "key: func(v *%s) %s {\n return %s \n},\n", name, keyType, keyExpr)
+	fmt.Fprintf(w, "row: func(key %[2]s) *%[1]s {\n v := &%[1]s{}\n%[3]s return v \n},\n", name, keyType, strings.Join(fields, ""))
+	fmt.Fprintf(w, "get: Select%s,\n", name)
+	fmt.Fprintf(w, "list: SelectAll%s,\n", name)
+	fmt.Fprintf(w, "insert: Insert%s,\n", name)
+	fmt.Fprintf(w, "update: Update%s,\n", name)
+	fmt.Fprintf(w, "delete: Delete%s,\n", name)
+	fmt.Fprintf(w, "}\n\n")
+
+	fmt.Fprintf(w, "// New%[1]sRepo returns a new repository of %[1]s.\n", name)
+	fmt.Fprintf(w, "func New%[1]sRepo(db dbtx) *Repo[%[1]s, %[2]s] {\n return NewRepo(db, %[1]sMeta) \n}\n\n", name, keyType)
+}
@@ -0,0 +1,255 @@
+package myddlmaker
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build/constraint"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// goSource is the unformatted Go source code.
+type goSource struct {
+	// preamble is the package clause and the imports.
+	preamble []byte
+
+	// header is the declarations that the functions of the tables share.
+	header []byte
+
+	// tables are the functions of each table.
+	tables [][]byte
+
+	// iteratorPreamble is the package clause and the imports of the iterators, with the build constraint of Go 1.23.
+	iteratorPreamble []byte
+
+	// iterators are the functions of each table that use the iter package, see GenerateIterators.
+	// They are nil if the functions are not generated.
+	iterators [][]byte
+}
+
+// source returns the formatted Go source code of OutGoFilePath without the iterators.
+func (src *goSource) source() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(src.preamble)
+	buf.Write(src.header)
+	for _, table := range src.tables {
+		buf.Write(table)
+	}
+	return format.Source(buf.Bytes())
+}
+
+// parseGoBuildConstraint parses Config.GoBuildConstraint.
+func parseGoBuildConstraint(expr string) error {
+	if expr == "" {
+		return nil
+	}
+	if _, err := constraint.Parse("//go:build " + expr); err != nil {
+		return fmt.Errorf("myddlmaker: invalid GoBuildConstraint %q: %w", expr, err)
+	}
+	return nil
+}
+
+// generateGoPackageClause generates the header comment, the build constraint and the package clause.
+// go123 adds the constraint of Go 1.23 for the iter package.
+func (m *Maker) generateGoPackageClause(w io.Writer, go123 bool) {
+	m.writeHeader(w, "//")
+	expr := "!" + m.config.Tag
+	if m.config.GoBuildConstraint != "" {
+		expr += " && (" + m.config.GoBuildConstraint + ")"
+	}
+	if go123 {
+		expr += " && go1.23"
+	}
+	fmt.Fprintf(w, "//go:build %s\n\n", expr)
+	fmt.Fprintf(w, "package %s\n\n", m.config.PackageName)
+}
+
+// goIteratorFilePath returns the file path of the iterators, e.g. "schema_gen_go123.go".
+// They are separated from OutGoFilePath, because the iter package requires Go 1.23.
+func (m *Maker) goIteratorFilePath() string {
+	return strings.TrimSuffix(m.goFilePath(), ".go") + "_go123.go"
+}
+
+// goTableFilePath returns the file path of the Go source code of the table.
+func (m *Maker) goTableFilePath(table *table) string {
+	name := strings.ReplaceAll(table.fullName(), ".", "_") + "_gen.go"
+	return filepath.Join(filepath.Dir(m.goFilePath()), name)
+}
+
+// generateGoFiles writes the files of goFiles.
+func (m *Maker) generateGoFiles() error {
+	files, err := m.goFiles()
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := os.WriteFile(f.path, f.content, 0o644); err != nil {
+			return fmt.Errorf("myddlmaker: failed to write %q: %w", f.path, err)
+		}
+	}
+	return nil
+}
+
+// goFiles returns the Go files that GenerateGoFile writes: OutGoFilePath, the files of the tables if SplitGoFiles is set,
+// and the file of the iterators if GenerateIterators is set.
+func (m *Maker) goFiles() ([]generatedFile, error) {
+	src, err := m.generateGoSource()
+	if err != nil {
+		return nil, fmt.Errorf("myddlmaker: failed to generate go file: %w", err)
+	}
+
+	name := m.goFilePath()
+	var files []generatedFile
+	names := map[string]bool{name: true}
+	if m.config.SplitGoFiles {
+		header, err := formatGoFile(name, src.preamble, src.header)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, generatedFile{path: name, content: header})
+		for i, table := range m.tables {
+			name := m.goTableFilePath(table)
+			if names[name] {
+				return nil, fmt.Errorf("myddlmaker: table %q: the file %q is already generated", table.fullName(), name)
+			}
+			names[name] = true
+			source, err := formatGoFile(name, src.preamble, src.tables[i])
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, generatedFile{path: name, content: source})
+		}
+	} else {
+		source, err := src.source()
+		if err != nil {
+			return nil, fmt.Errorf("myddlmaker: failed to generate go file: %w", err)
+		}
+		files = append(files, generatedFile{path: name, content: source})
+	}
+
+	if src.iterators != nil {
+		name := m.goIteratorFilePath()
+		if names[name] {
+			return nil, fmt.Errorf("myddlmaker: the file %q of the iterators is already generated", name)
+		}
+		source, err := formatGoFile(name, src.iteratorPreamble, bytes.Join(src.iterators, nil))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, generatedFile{path: name, content: source})
+	}
+	return files, nil
+}
+
+// formatGoFile formats the Go source code of the file, and removes the unused imports.
+func formatGoFile(name string, preamble, body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(preamble)
+	buf.Write(body)
+	source, err := removeUnusedImports(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("myddlmaker: failed to generate go file %q: %w", name, err)
+	}
+	return source, nil
+}
+
+// removeUnusedImports removes the imports that the Go source code doesn't refer to, and formats it.
+func removeUnusedImports(src []byte) ([]byte, error) {
+	src, err := format.Source(src)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// the identifiers that are not declared in the file may be the package names.
+	used := map[string]bool{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok && id.Obj == nil {
+				used[id.Name] = true
+			}
+		}
+		return true
+	})
+
+	// remove the lines of the unused imports.
+	type span struct{ start, end int }
+	var unused []span
+	lines := func(from, to token.Pos) span {
+		start := fset.Position(from).Offset
+		end := fset.Position(to).Offset
+		start = bytes.LastIndexByte(src[:start], '\n') + 1
+		if i := bytes.IndexByte(src[end:], '\n'); i >= 0 {
+			end += i + 1
+		} else {
+			end = len(src)
+		}
+		return span{start, end}
+	}
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		var specs []span
+		for _, spec := range gen.Specs {
+			if name := importName(spec.(*ast.ImportSpec)); name != "" && !used[name] {
+				specs = append(specs, lines(spec.Pos(), spec.End()))
+			}
+		}
+		if len(specs) == len(gen.Specs) {
+			unused = append(unused, lines(gen.Pos(), gen.End()))
+		} else {
+			unused = append(unused, specs...)
+		}
+	}
+
+	var buf bytes.Buffer
+	offset := 0
+	for _, s := range unused {
+		buf.Write(src[offset:s.start])
+		offset = s.end
+	}
+	buf.Write(src[offset:])
+	return format.Source(buf.Bytes())
+}
+
+var majorVersion = regexp.MustCompile(`^v[0-9]+$`)
+
+// importName returns the name of the package that the import declares.
+// It returns an empty string for the blank and dot imports.
+func importName(spec *ast.ImportSpec) string {
+	if spec.Name != nil {
+		if spec.Name.Name == "_" || spec.Name.Name == "." {
+			return ""
+		}
+		return spec.Name.Name
+	}
+	p, err := strconv.Unquote(spec.Path.Value)
+	if err != nil {
+		return ""
+	}
+	name := path.Base(p)
+	if majorVersion.MatchString(name) {
+		// e.g. "github.com/foo/bar/v2"
+		name = path.Base(path.Dir(p))
+	}
+	if !token.IsIdentifier(name) {
+		// the name can't be guessed from the path, e.g. "gopkg.in/yaml.v3".
+		return ""
+	}
+	return name
+}
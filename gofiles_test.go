@@ -0,0 +1,82 @@
+package myddlmaker
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRemoveUnusedImports(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{
+			in: `package schema
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	yaml "gopkg.in/yaml.v3"
+	_ "embed"
+)
+
+func f(ctx context.Context, time int) { time++ }
+`,
+			want: `package schema
+
+import (
+	"context"
+
+	_ "embed"
+)
+
+func f(ctx context.Context, time int) { time++ }
+`,
+		},
+		{
+			in: `package schema
+
+import "errors"
+
+var x = 1
+`,
+			want: `package schema
+
+var x = 1
+`,
+		},
+		{
+			in: `package schema
+
+import (
+	"github.com/foo/bar/v2"
+	"gopkg.in/yaml.v3"
+)
+
+var x = bar.X
+`,
+			want: `package schema
+
+import (
+	"github.com/foo/bar/v2"
+	"gopkg.in/yaml.v3"
+)
+
+var x = bar.X
+`,
+		},
+	}
+	for _, tt := range tests {
+		got, err := removeUnusedImports([]byte(tt.in))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(tt.want, string(got)); diff != "" {
+			t.Errorf("removeUnusedImports mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
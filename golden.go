@@ -0,0 +1,162 @@
+package myddlmaker
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// generatedFile is a file that GenerateFile or GenerateGoFile writes.
+type generatedFile struct {
+	path    string
+	content []byte
+}
+
+// TB is the subset of testing.TB that TestGenerated uses.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+	Fatalf(format string, args ...any)
+}
+
+// TestGenerated regenerates the files of the structs in memory, and fails the test
+// if they differ from the files that GenerateFile and GenerateGoFile wrote,
+// so that CI fails when someone edits the structs without regenerating.
+// The file paths are relative to the working directory of the test, i.e. the directory of the package.
+//
+//	func TestGenerated(t *testing.T) {
+//		myddlmaker.TestGenerated(t, &myddlmaker.Config{...}, &User{}, &Post{})
+//	}
+func TestGenerated(t TB, config *Config, structs ...any) {
+	t.Helper()
+	m, err := New(config)
+	if err != nil {
+		t.Fatalf("%v", err)
+		return
+	}
+	m.AddStructs(structs...)
+	diffs, err := m.CheckGeneratedFiles()
+	if err != nil {
+		t.Fatalf("%v", err)
+		return
+	}
+	for _, diff := range diffs {
+		t.Errorf("%s", diff)
+	}
+}
+
+// CheckGeneratedFiles regenerates the files of the structs in memory, and compares them with
+// the files that GenerateFile and GenerateGoFile wrote.
+// It returns the differences, or nil if the files are up to date.
+// The Go files are compared only if OutGoFilePath exists, i.e. the Go source code is generated.
+func (m *Maker) CheckGeneratedFiles() ([]string, error) {
+	files, err := m.generatedFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []string
+	for _, f := range files {
+		data, err := os.ReadFile(f.path)
+		if errors.Is(err, fs.ErrNotExist) {
+			diffs = append(diffs, fmt.Sprintf("%s is not generated, run go generate", f.path))
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("myddlmaker: failed to read %q: %w", f.path, err)
+		}
+		// the files may be checked out with CRLF on Windows.
+		data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+		if m.config.HeaderTimestamp {
+			data = removeTimestamp(data)
+			f.content = removeTimestamp(f.content)
+		}
+		if !bytes.Equal(data, f.content) {
+			diffs = append(diffs, fmt.Sprintf("%s is out of date, run go generate:\n%s", f.path, lineDiff(data, f.content)))
+		}
+	}
+	return diffs, nil
+}
+
+// generatedFiles returns the files that GenerateFile and GenerateGoFile write.
+func (m *Maker) generatedFiles() ([]generatedFile, error) {
+	var files []generatedFile
+	if m.config.SplitSchemaFiles || m.config.SplitTableFiles {
+		sqlFiles, err := m.sqlFiles()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, sqlFiles...)
+	} else {
+		var buf bytes.Buffer
+		if err := m.Generate(&buf); err != nil {
+			return nil, fmt.Errorf("myddlmaker: failed to generate ddl: %w", err)
+		}
+		path, err := m.sqlFilePath("")
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, generatedFile{path: path, content: buf.Bytes()})
+	}
+
+	if _, err := os.Stat(m.goFilePath()); errors.Is(err, fs.ErrNotExist) {
+		// the Go source code is not generated.
+		return files, nil
+	}
+	gofiles, err := m.goFiles()
+	if err != nil {
+		return nil, err
+	}
+	return append(files, gofiles...), nil
+}
+
+// maxDiffLines is the maximum number of the lines that lineDiff shows on each side.
+const maxDiffLines = 10
+
+// lineDiff returns the first hunk of the lines that differ between the file and the generated content,
+// with the line number and the preceding lines.
+func lineDiff(file, generated []byte) string {
+	a := strings.SplitAfter(string(file), "\n")
+	b := strings.SplitAfter(string(generated), "\n")
+
+	// skip the common prefix and suffix.
+	start := 0
+	for start < len(a) && start < len(b) && a[start] == b[start] {
+		start++
+	}
+	endA, endB := len(a), len(b)
+	for endA > start && endB > start && a[endA-1] == b[endB-1] {
+		endA--
+		endB--
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "@@ line %d @@\n", start+1)
+	for i := start - 2; i < start; i++ {
+		if i >= 0 {
+			writeDiffLine(&buf, " ", a[i])
+		}
+	}
+	writeDiffLines(&buf, "-", a[start:endA])
+	writeDiffLines(&buf, "+", b[start:endB])
+	return buf.String()
+}
+
+func writeDiffLines(buf *strings.Builder, prefix string, lines []string) {
+	for i, line := range lines {
+		if i == maxDiffLines {
+			fmt.Fprintf(buf, "%s... (%d more lines)\n", prefix, len(lines)-i)
+			return
+		}
+		writeDiffLine(buf, prefix, line)
+	}
+}
+
+func writeDiffLine(buf *strings.Builder, prefix, line string) {
+	buf.WriteString(prefix)
+	buf.WriteString(strings.TrimSuffix(line, "\n"))
+	buf.WriteString("\n")
+}
@@ -0,0 +1,121 @@
+package myddlmaker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type goldenUser struct {
+	ID   uint64
+	Name string
+}
+
+func (*goldenUser) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+type goldenUser2 struct {
+	ID    uint64
+	Name  string
+	Email string
+}
+
+func (*goldenUser2) Table() string {
+	return "golden_user"
+}
+
+func (*goldenUser2) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+// recorderTB records the failures of TestGenerated.
+type recorderTB struct {
+	errors []string
+	fatal  bool
+}
+
+func (tb *recorderTB) Helper() {}
+
+func (tb *recorderTB) Errorf(format string, args ...any) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}
+
+func (tb *recorderTB) Fatalf(format string, args ...any) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+	tb.fatal = true
+}
+
+func TestTestGenerated(t *testing.T) {
+	for _, split := range []bool{false, true} {
+		t.Run(fmt.Sprintf("split=%t", split), func(t *testing.T) {
+			dir := t.TempDir()
+			config := &Config{
+				DB:            &DBConfig{Engine: "InnoDB", Charset: "utf8mb4", Collate: "utf8mb4_bin"},
+				OutFilePath:   filepath.Join(dir, "schema.sql"),
+				OutGoFilePath: filepath.Join(dir, "schema_gen.go"),
+				PackageName:   "schema",
+				SplitGoFiles:  split,
+			}
+			m, err := New(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m.AddStructs(&goldenUser{})
+			if err := m.GenerateFile(); err != nil {
+				t.Fatal(err)
+			}
+			if err := m.GenerateGoFile(); err != nil {
+				t.Fatal(err)
+			}
+
+			// the files are up to date.
+			var tb recorderTB
+			TestGenerated(&tb, config, &goldenUser{})
+			if len(tb.errors) > 0 {
+				t.Errorf("unexpected failures: %q", tb.errors)
+			}
+
+			// the struct is edited without regenerating.
+			tb = recorderTB{}
+			TestGenerated(&tb, config, &goldenUser2{})
+			if tb.fatal {
+				t.Fatalf("unexpected fatal: %q", tb.errors)
+			}
+			wantFailures := 2 // schema.sql and schema_gen.go
+			if split {
+				wantFailures = 3 // and golden_user_gen.go
+			}
+			if len(tb.errors) != wantFailures {
+				t.Fatalf("want the failures of the SQL and Go files, got %q", tb.errors)
+			}
+			if !strings.HasPrefix(tb.errors[0], config.OutFilePath+" is out of date, run go generate:\n") {
+				t.Errorf("unexpected failure: %q", tb.errors[0])
+			}
+			if !strings.Contains(tb.errors[0], "\n+    `email` VARCHAR(191) NOT NULL,\n") {
+				t.Errorf("the failure doesn't have the diff: %q", tb.errors[0])
+			}
+
+			// the Go files are not compared if they are not generated.
+			for _, f := range []string{"schema_gen.go", "golden_user_gen.go"} {
+				os.Remove(filepath.Join(dir, f))
+			}
+			tb = recorderTB{}
+			TestGenerated(&tb, config, &goldenUser2{})
+			if len(tb.errors) != 1 {
+				t.Errorf("want the failure of the SQL file, got %q", tb.errors)
+			}
+		})
+	}
+}
+
+func TestLineDiff(t *testing.T) {
+	file := "a\nb\nc\nd\ne\n"
+	generated := "a\nb\nc\nx\ny\ne\n"
+	want := "@@ line 4 @@\n b\n c\n-d\n+x\n+y\n"
+	if got := lineDiff([]byte(file), []byte(generated)); got != want {
+		t.Errorf("lineDiff() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,133 @@
+package myddlmaker
+
+import (
+	"fmt"
+	"go/token"
+	"io"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// goFilePath returns the file path of the Go source code.
+func (m *Maker) goFilePath() string {
+	if m.config.OutGoDir == "" {
+		return m.config.OutGoFilePath
+	}
+	return filepath.Join(m.config.OutGoDir, m.config.OutGoFilePath)
+}
+
+// isForeignGoPackage reports whether the Go source code is in the different package from the structs.
+func (m *Maker) isForeignGoPackage() bool {
+	return m.config.GoPackagePath != "" && len(m.structs) > 0 && m.config.GoPackagePath != m.goPkgPath()
+}
+
+// structPkgName returns the name of the import of the package of the structs.
+func (m *Maker) structPkgName() string {
+	name := strings.Map(func(r rune) rune {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, path.Base(m.goPkgPath()))
+	if name == "" || !token.IsIdentifier(name) {
+		// e.g. "example.com/v2" and "example.com/1st"
+		name = "pkg" + name
+	}
+	return name
+}
+
+// goPkgTypes returns the names of the types that the Go source code refers to from the package of the structs.
+func (m *Maker) goPkgTypes() []string {
+	pkgPath := m.goPkgPath()
+	types := map[string]bool{}
+	var walk func(typ reflect.Type)
+	walk = func(typ reflect.Type) {
+		if typ.Kind() == reflect.Array && typ.Implements(myddlmakerJSON) {
+			// the accessors of JSON[T] refer to T.
+			walk(typ.Elem())
+		}
+		if typ.Name() != "" {
+			if typ.PkgPath() == pkgPath {
+				types[typ.Name()] = true
+			}
+			return
+		}
+		switch typ.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Array:
+			walk(typ.Elem())
+		}
+	}
+	for _, s := range m.structs {
+		walk(indirect(reflect.TypeOf(s)))
+	}
+	for _, table := range m.tables {
+		for _, c := range table.columns {
+			if c.fieldType != nil {
+				walk(c.fieldType)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateGoPackage checks that the Go source code in the different package can refer to the structs.
+func (m *Maker) validateGoPackage() error {
+	if !m.isForeignGoPackage() {
+		return nil
+	}
+	if m.config.GenerateValidation {
+		return fmt.Errorf("myddlmaker: GenerateValidation can't be used with GoPackagePath %q, the methods must be in the package of the structs", m.config.GoPackagePath)
+	}
+	if m.config.GenerateEnums {
+		return fmt.Errorf("myddlmaker: GenerateEnums can't be used with GoPackagePath %q, the methods must be in the package of the structs", m.config.GoPackagePath)
+	}
+	pkgPath := m.goPkgPath()
+	for _, s := range m.structs {
+		typ := indirect(reflect.TypeOf(s))
+		if typ.PkgPath() != pkgPath {
+			return fmt.Errorf("myddlmaker: struct %s is not in the package %q, the structs must be in the same package for GoPackagePath", typ, pkgPath)
+		}
+	}
+	for _, name := range m.goPkgTypes() {
+		if !token.IsExported(name) {
+			return fmt.Errorf("myddlmaker: type %s is unexported, it can't be referred from the package %q", name, m.config.GoPackagePath)
+		}
+	}
+	for _, table := range m.tables {
+		for _, c := range table.columns {
+			for _, name := range strings.Split(c.goField, ".") {
+				if !token.IsExported(name) {
+					return fmt.Errorf("myddlmaker: table %q, field %q: the unexported field can't be referred from the package %q", table.fullName(), c.goField, m.config.GoPackagePath)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// generateGoStructImport generates the import of the package of the structs.
+func (m *Maker) generateGoStructImport(w io.Writer) {
+	fmt.Fprintf(w, "%s %q\n", m.structPkgName(), m.goPkgPath())
+}
+
+// generateGoTypeAliases generates the aliases of the types in the package of the structs,
+// so the generated code refers to them in the same way as in the package of the structs.
+func (m *Maker) generateGoTypeAliases(w io.Writer) {
+	pkg := m.structPkgName()
+	fmt.Fprintf(w, "// the aliases of the types in %q.\n", m.goPkgPath())
+	fmt.Fprintf(w, "type (\n")
+	for _, name := range m.goPkgTypes() {
+		fmt.Fprintf(w, "%s = %s.%s\n", name, pkg, name)
+	}
+	fmt.Fprintf(w, ")\n\n")
+}
@@ -0,0 +1,101 @@
+package myddlmaker
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// modulePath is the path of the module of the DDL Maker.
+const modulePath = "github.com/shogo82148/myddlmaker"
+
+// moduleVersion returns the version of the DDL Maker in the build information, e.g. "v1.2.3",
+// or an empty string if it is unknown, e.g. in the development.
+func moduleVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	var version string
+	if info.Main.Path == modulePath {
+		version = info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path != modulePath {
+			continue
+		}
+		version = dep.Version
+		if dep.Replace != nil {
+			version = dep.Replace.Version
+		}
+	}
+	if version == "(devel)" {
+		return ""
+	}
+	return version
+}
+
+// headerLines returns the lines of the header comment of the generated files without the comment markers.
+func (m *Maker) headerLines() []string {
+	var lines []string
+	if m.config.FileHeader != "" {
+		lines = append(lines, strings.Split(strings.TrimRight(m.config.FileHeader, "\n"), "\n")...)
+		lines = append(lines, "")
+	}
+	banner := "Code generated by https://" + modulePath
+	if m.config.HeaderVersion && m.version != "" {
+		banner += " " + m.version
+	}
+	lines = append(lines, banner+"; DO NOT EDIT.")
+	if m.config.HeaderTimestamp {
+		lines = append(lines, "Generated at "+m.now().UTC().Format(time.RFC3339)+".")
+	}
+	return lines
+}
+
+// hasSQLHeader reports whether the SQL files have the header comment.
+// They don't have it by default for the compatibility.
+func (m *Maker) hasSQLHeader() bool {
+	return m.config.FileHeader != "" || m.config.HeaderVersion || m.config.HeaderTimestamp
+}
+
+// writeHeader writes the header comment with the comment marker, e.g. "//" and "--".
+// The header is followed by an empty line.
+func (m *Maker) writeHeader(w io.Writer, marker string) {
+	var buf bytes.Buffer
+	for _, line := range m.headerLines() {
+		buf.WriteString(marker)
+		if line != "" {
+			buf.WriteString(" ")
+			buf.WriteString(line)
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("\n")
+	buf.WriteTo(w)
+}
+
+// writeSQLHeader writes the header comment of the SQL files if they have it.
+func (m *Maker) writeSQLHeader(w io.Writer) {
+	if m.hasSQLHeader() {
+		m.writeHeader(w, "--")
+	}
+}
+
+// timestampLine matches the line of the time of the generation in the header comment.
+var timestampLine = regexp.MustCompile(`(?m)^(--|//) Generated at [^\n]*\.\n`)
+
+// removeTimestamp removes the time of the generation from the header comment,
+// so that the files generated at the different times are compared.
+func removeTimestamp(data []byte) []byte {
+	loc := timestampLine.FindIndex(data)
+	if loc == nil {
+		return data
+	}
+	ret := make([]byte, 0, len(data)-(loc[1]-loc[0]))
+	ret = append(ret, data[:loc[0]]...)
+	return append(ret, data[loc[1]:]...)
+}
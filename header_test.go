@@ -0,0 +1,114 @@
+package myddlmaker
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMaker_Header(t *testing.T) {
+	m, err := New(&Config{
+		FileHeader:      "Copyright 2024 Example Inc.\n\nLicensed under the MIT License.\n",
+		HeaderVersion:   true,
+		HeaderTimestamp: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.version = "v1.2.3"
+	m.now = func() time.Time {
+		return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	}
+	m.AddStructs(&goldenUser{})
+
+	var ddl bytes.Buffer
+	if err := m.Generate(&ddl); err != nil {
+		t.Fatal(err)
+	}
+	want := "-- Copyright 2024 Example Inc.\n" +
+		"--\n" +
+		"-- Licensed under the MIT License.\n" +
+		"--\n" +
+		"-- Code generated by https://github.com/shogo82148/myddlmaker v1.2.3; DO NOT EDIT.\n" +
+		"-- Generated at 2024-01-02T03:04:05Z.\n" +
+		"\n" +
+		"SET foreign_key_checks=0;\n"
+	if diff := cmp.Diff(want, ddl.String()[:len(want)]); diff != "" {
+		t.Errorf("(-want/+got):\n%s", diff)
+	}
+
+	var code bytes.Buffer
+	if err := m.GenerateGo(&code); err != nil {
+		t.Fatal(err)
+	}
+	want = "// Copyright 2024 Example Inc.\n" +
+		"//\n" +
+		"// Licensed under the MIT License.\n" +
+		"//\n" +
+		"// Code generated by https://github.com/shogo82148/myddlmaker v1.2.3; DO NOT EDIT.\n" +
+		"// Generated at 2024-01-02T03:04:05Z.\n" +
+		"\n" +
+		"//go:build !myddlmaker\n"
+	if diff := cmp.Diff(want, code.String()[:len(want)]); diff != "" {
+		t.Errorf("(-want/+got):\n%s", diff)
+	}
+}
+
+func TestMaker_HeaderDefault(t *testing.T) {
+	m, err := New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.AddStructs(&goldenUser{})
+
+	// the SQL files don't have the header by default.
+	var ddl bytes.Buffer
+	if err := m.Generate(&ddl); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(ddl.String(), "SET foreign_key_checks=0;\n") {
+		t.Errorf("unexpected header: %q", ddl.String())
+	}
+
+	var code bytes.Buffer
+	if err := m.GenerateGo(&code); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(code.String(), "// Code generated by https://github.com/shogo82148/myddlmaker; DO NOT EDIT.\n\n") {
+		t.Errorf("unexpected header: %q", code.String())
+	}
+}
+
+func TestTestGenerated_HeaderTimestamp(t *testing.T) {
+	dir := t.TempDir()
+	config := &Config{
+		OutFilePath:     filepath.Join(dir, "schema.sql"),
+		OutGoFilePath:   filepath.Join(dir, "schema_gen.go"),
+		HeaderTimestamp: true,
+	}
+	m, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.now = func() time.Time {
+		return time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	m.AddStructs(&goldenUser{})
+	if err := m.GenerateFile(); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.GenerateGoFile(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the time of the generation is ignored.
+	var tb recorderTB
+	TestGenerated(&tb, config, &goldenUser{})
+	if len(tb.errors) > 0 {
+		t.Errorf("unexpected failures: %q", tb.errors)
+	}
+}
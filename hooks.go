@@ -0,0 +1,146 @@
+package myddlmaker
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// hookOperations are the operations of the hooks, in the order of the fields of the hooks of the tables.
+var hookOperations = []string{"Insert", "Update", "Upsert", "Delete"}
+
+// hasHooks reports whether the generated write functions call the hooks of the tables.
+func (m *Maker) hasHooks() bool {
+	return m.config.GenerateHooks && len(m.tables) > 0
+}
+
+// generateGoHooks generates the declarations that the hooks of the tables share.
+func (m *Maker) generateGoHooks(w io.Writer) {
+	fmt.Fprintf(w, `// Execer is the connection pool, the connection or the transaction that the hooks receive,
+	// so that they can write into the other tables in the same transaction, e.g. the outbox.
+	type Execer interface {
+		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+		PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	}
+
+	type callingHooksKey struct{}
+
+	// shouldCallHooks reports whether the write function should call the hooks.
+	func shouldCallHooks[T any](ctx context.Context, before, after func(ctx context.Context, e Execer, values []*T) error) bool {
+		if ctx.Value(callingHooksKey{}) != nil {
+			// the function is called by callHooks.
+			return false
+		}
+		return before != nil || after != nil
+	}
+
+	// callHooks calls before, f and after in this order. It stops at the first error.
+	func callHooks[T any](ctx context.Context, e Execer, values []*T, before, after func(ctx context.Context, e Execer, values []*T) error, f func(ctx context.Context) error) error {
+		if before != nil {
+			if err := before(ctx, e, values); err != nil {
+				return err
+			}
+		}
+		if err := f(context.WithValue(ctx, callingHooksKey{}, true)); err != nil {
+			return err
+		}
+		if after != nil {
+			return after(ctx, e, values)
+		}
+		return nil
+	}
+
+	`)
+}
+
+// generateGoTableHooks generates the hooks of the table and its setter.
+func (m *Maker) generateGoTableHooks(w io.Writer, table *table) {
+	name := table.rawName
+	fmt.Fprintf(w, "// %sHooks are the hooks that the generated write functions of %s call before and after the statements,\n", name, table.fullName())
+	fmt.Fprintf(w, "// e.g. for the cache invalidation and the outbox. The nil hooks are not called.\n")
+	fmt.Fprintf(w, "// If a Before hook returns an error, the statement is not executed. The After hooks are called only if the statement succeeds.\n")
+	fmt.Fprintf(w, "type %sHooks struct {\n", name)
+	for i, op := range hookOperations {
+		if i > 0 {
+			fmt.Fprintf(w, "\n")
+		}
+		if funcs := m.hookFunctions(op, table); len(funcs) > 0 {
+			list := funcs[len(funcs)-1]
+			if len(funcs) > 1 {
+				list = strings.Join(funcs[:len(funcs)-1], ", ") + " and " + list
+			}
+			fmt.Fprintf(w, "// Before%[1]s and After%[1]s are called by %[2]s.\n", op, list)
+		} else {
+			fmt.Fprintf(w, "// Before%[1]s and After%[1]s are not called, %[2]s doesn't have the primary key.\n", op, table.fullName())
+		}
+		fmt.Fprintf(w, "Before%s func(ctx context.Context, e Execer, values []*%s) error\n", op, name)
+		fmt.Fprintf(w, "After%s func(ctx context.Context, e Execer, values []*%s) error\n", op, name)
+	}
+	fmt.Fprintf(w, "}\n\n")
+
+	fmt.Fprintf(w, "var %s %sHooks\n\n", hooksVar(table), name)
+
+	fmt.Fprintf(w, "// Set%[1]sHooks sets the hooks of %[2]s. The zero value disables them.\n", name, table.fullName())
+	fmt.Fprintf(w, "// It must not be called concurrently with the generated functions.\n")
+	fmt.Fprintf(w, "func Set%[1]sHooks(h %[1]sHooks) {\n %[2]s = h \n}\n\n", name, hooksVar(table))
+}
+
+// hookFunctions returns the generated functions of the table that call the hooks of the operation.
+func (m *Maker) hookFunctions(op string, table *table) []string {
+	name := table.rawName
+	var funcs []string
+	switch op {
+	case "Insert":
+		funcs = append(funcs, "Insert"+name, "InsertIgnore"+name)
+		if key, _ := m.insertReturningIDKey(table); key != nil {
+			funcs = append(funcs, "Insert"+name+"ReturningID")
+		}
+	case "Update":
+		if table.primaryKey != nil {
+			funcs = append(funcs, "Update"+name)
+		}
+	case "Upsert":
+		if table.primaryKey != nil {
+			funcs = append(funcs, "Upsert"+name, "BulkUpsert"+name)
+			if m.config.generatesReplace(table) {
+				funcs = append(funcs, "Replace"+name)
+			}
+		}
+	case "Delete":
+		if table.primaryKey != nil {
+			funcs = append(funcs, "Delete"+name)
+			if table.softDeleteColumn() != nil {
+				funcs = append(funcs, "HardDelete"+name)
+			}
+		}
+	}
+	return funcs
+}
+
+// hooksVar returns the name of the variable of the hooks of the table.
+func hooksVar(table *table) string {
+	return goParamName(table.rawName) + "Hooks"
+}
+
+// generateGoCallHooks generates the code that calls the hooks of the operation around the write function.
+// values is the expression of the slice of the values, call is the call of the function itself,
+// and result is the type of the result other than error, or empty if the function returns only error.
+func (m *Maker) generateGoCallHooks(w io.Writer, table *table, op, values, call, result string) {
+	if !m.config.GenerateHooks {
+		return
+	}
+	h := hooksVar(table)
+	fmt.Fprintf(w, "if shouldCallHooks(ctx, %[1]s.Before%[2]s, %[1]s.After%[2]s) {\n", h, op)
+	if result == "" {
+		fmt.Fprintf(w, "return callHooks(ctx, execer, %[3]s, %[1]s.Before%[2]s, %[1]s.After%[2]s, func(ctx context.Context) error {\n return %[4]s \n})\n", h, op, values, call)
+	} else {
+		fmt.Fprintf(w, "var ret %s\n", result)
+		fmt.Fprintf(w, "err := callHooks(ctx, execer, %[3]s, %[1]s.Before%[2]s, %[1]s.After%[2]s, func(ctx context.Context) error {\n", h, op, values)
+		fmt.Fprintf(w, "var err error\n")
+		fmt.Fprintf(w, "ret, err = %s\n", call)
+		fmt.Fprintf(w, "return err\n")
+		fmt.Fprintf(w, "})\n")
+		fmt.Fprintf(w, "return ret, err\n")
+	}
+	fmt.Fprintf(w, "}\n")
+}
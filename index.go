@@ -56,6 +56,17 @@ func (idx *Index) Invisible() *Index {
 	return &tmp
 }
 
+// withPrefix returns a copy of idx, but its name and columns are prefixed.
+func (idx *Index) withPrefix(prefix string) *Index {
+	if prefix == "" {
+		return idx
+	}
+	tmp := *idx // shallow copy
+	tmp.name = prefix + tmp.name
+	tmp.columns = prefixAll(prefix, tmp.columns)
+	return &tmp
+}
+
 // UniqueIndex is a unique index of a table.
 // Implement the UniqueIndexes method to define the unique indexes.
 //
@@ -100,6 +111,17 @@ func (idx *UniqueIndex) Invisible() *UniqueIndex {
 	return &tmp
 }
 
+// withPrefix returns a copy of idx, but its name and columns are prefixed.
+func (idx *UniqueIndex) withPrefix(prefix string) *UniqueIndex {
+	if prefix == "" {
+		return idx
+	}
+	tmp := *idx // shallow copy
+	tmp.name = prefix + tmp.name
+	tmp.columns = prefixAll(prefix, tmp.columns)
+	return &tmp
+}
+
 // ForeignKey is a foreign key constraint.
 // Implement the ForeignKeys method to define the foreign key constraints.
 //
@@ -119,10 +141,12 @@ func (idx *UniqueIndex) Invisible() *UniqueIndex {
 type ForeignKey struct {
 	name       string
 	columns    []string
+	schema     string
 	table      string
 	references []string
 	onUpdate   ForeignKeyOption
 	onDelete   ForeignKeyOption
+	logical    bool
 }
 
 // ForeignKeyOption is an option of a referential action.
@@ -191,6 +215,49 @@ func (fk *ForeignKey) OnDelete(opt ForeignKeyOption) *ForeignKey {
 	return &key
 }
 
+// InSchema returns a copy of fk that references the table in the schema.
+// By default, the referenced table is in the same schema as the table that has fk.
+func (fk *ForeignKey) InSchema(schema string) *ForeignKey {
+	key := *fk // shallow copy
+	key.schema = schema
+	return &key
+}
+
+// Logical returns a copy of fk that is a logical relation.
+// The DDL Maker validates logical relations like foreign key constraints,
+// but doesn't create the constraints in the database.
+// It is useful for the relations that are used in joins.
+func (fk *ForeignKey) Logical() *ForeignKey {
+	key := *fk // shallow copy
+	key.logical = true
+	return &key
+}
+
+// referencedSchema returns the schema of the referenced table.
+func (fk *ForeignKey) referencedSchema(t *table) string {
+	if fk.schema != "" {
+		return fk.schema
+	}
+	return t.schema
+}
+
+// referencedTable returns the referenced table name qualified by the schema name.
+func (fk *ForeignKey) referencedTable(t *table) string {
+	return qualifiedName(fk.referencedSchema(t), fk.table)
+}
+
+// withPrefix returns a copy of fk, but its name and columns are prefixed.
+// The referenced columns are not changed.
+func (fk *ForeignKey) withPrefix(prefix string) *ForeignKey {
+	if prefix == "" {
+		return fk
+	}
+	key := *fk // shallow copy
+	key.name = prefix + key.name
+	key.columns = prefixAll(prefix, key.columns)
+	return &key
+}
+
 type fullTextIndexes interface {
 	FullTextIndexes() []*FullTextIndex
 }
@@ -248,6 +315,17 @@ func (idx *FullTextIndex) WithParser(parser string) *FullTextIndex {
 	return &tmp
 }
 
+// withPrefix returns a copy of idx, but its name and column are prefixed.
+func (idx *FullTextIndex) withPrefix(prefix string) *FullTextIndex {
+	if prefix == "" {
+		return idx
+	}
+	tmp := *idx // shallow copy
+	tmp.name = prefix + tmp.name
+	tmp.column = prefix + tmp.column
+	return &tmp
+}
+
 type spatialIndex interface {
 	SpatialIndexes() []*SpatialIndex
 }
@@ -295,3 +373,22 @@ func (idx *SpatialIndex) Comment(comment string) *SpatialIndex {
 	tmp.comment = comment
 	return &tmp
 }
+
+// withPrefix returns a copy of idx, but its name and column are prefixed.
+func (idx *SpatialIndex) withPrefix(prefix string) *SpatialIndex {
+	if prefix == "" {
+		return idx
+	}
+	tmp := *idx // shallow copy
+	tmp.name = prefix + tmp.name
+	tmp.column = prefix + tmp.column
+	return &tmp
+}
+
+func prefixAll(prefix string, s []string) []string {
+	ret := make([]string, len(s))
+	for i, v := range s {
+		ret[i] = prefix + v
+	}
+	return ret
+}
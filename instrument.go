@@ -0,0 +1,245 @@
+package myddlmaker
+
+import (
+	"fmt"
+	"io"
+)
+
+// hasInstrumentation reports whether the generated functions instrument their statements.
+func (m *Maker) hasInstrumentation() bool {
+	return m.hasQueryHooks() || m.config.GenerateErrorHelpers
+}
+
+// hasQueryHooks reports whether the generated functions call the hooks of the queries.
+func (m *Maker) hasQueryHooks() bool {
+	return m.config.GenerateTracing || m.config.GenerateQueryLogger || m.config.GenerateMetrics || m.config.GenerateSQLCommenter
+}
+
+// generateGoInstrumentation generates the hooks of the statements and the wrappers of execer and queryer that call them.
+func (m *Maker) generateGoInstrumentation(w io.Writer) {
+	if m.config.GenerateTracing {
+		fmt.Fprintf(w, `// Tracer starts the spans of the statements that the generated functions execute.
+		// Implement it with OpenTelemetry or another tracing library, and set it by SetTracer.
+		type Tracer interface {
+			// Start starts the span of the statement of the table.
+			// The operation is the name of the generated function, e.g. "SelectUser".
+			// It returns the context of the span and the function that ends the span with the error of the statement.
+			Start(ctx context.Context, table, operation, statement string) (context.Context, func(err error))
+		}
+
+		var tracer Tracer
+
+		// SetTracer sets the tracer of the generated functions. nil disables tracing.
+		// It must not be called concurrently with the generated functions.
+		func SetTracer(t Tracer) {
+			tracer = t
+		}
+
+		`)
+	}
+	if m.config.GenerateQueryLogger {
+		fmt.Fprintf(w, `// QueryLogger logs the statements that the generated functions execute, e.g. for slow query logging.
+		type QueryLogger interface {
+			// LogQuery is called after the statement with its arguments, the duration and the error.
+			LogQuery(ctx context.Context, query string, args []any, duration time.Duration, err error)
+		}
+
+		var queryLogger QueryLogger
+
+		// SetQueryLogger sets the query logger of the generated functions. nil disables logging.
+		// It must not be called concurrently with the generated functions.
+		func SetQueryLogger(l QueryLogger) {
+			queryLogger = l
+		}
+
+		`)
+	}
+	if m.config.GenerateMetrics {
+		fmt.Fprintf(w, `// Metrics observes the statements that the generated functions execute, e.g. with Prometheus counters and histograms.
+		type Metrics interface {
+			// ObserveQuery is called after the statement of the table.
+			// The operation is the name of the generated function, e.g. "SelectUser".
+			ObserveQuery(table, operation string, duration time.Duration, err error)
+		}
+
+		var metrics Metrics
+
+		// SetMetrics sets the metrics of the generated functions. nil disables the metrics.
+		// It must not be called concurrently with the generated functions.
+		func SetMetrics(m Metrics) {
+			metrics = m
+		}
+
+		`)
+	}
+	query := "query"
+	result := "err"
+	if m.config.GenerateErrorHelpers {
+		result = "classifyError(err)"
+	}
+	if m.config.GenerateSQLCommenter {
+		query = "commentQuery(ctx, query)"
+		fmt.Fprintf(w, `// SQLCommenter returns the tags of the comment appended to the statements in the sqlcommenter format,
+		// e.g. "application", "route" and "traceparent", to correlate the slow query logs and performance_schema with the requests.
+		// See https://google.github.io/sqlcommenter/spec/ for the format.
+		type SQLCommenter interface {
+			Tags(ctx context.Context) map[string]string
+		}
+
+		var sqlCommenter SQLCommenter
+
+		// SetSQLCommenter sets the sqlcommenter of the generated functions. nil disables the comments.
+		// It must not be called concurrently with the generated functions.
+		func SetSQLCommenter(c SQLCommenter) {
+			sqlCommenter = c
+		}
+
+		// commentQuery appends the comment of the tags to the query.
+		func commentQuery(ctx context.Context, query string) string {
+			c := sqlCommenter
+			if c == nil {
+				return query
+			}
+			tags := c.Tags(ctx)
+			if len(tags) == 0 {
+				return query
+			}
+			keys := make([]string, 0, len(tags))
+			for key := range tags {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			var b strings.Builder
+			b.WriteString(query)
+			b.WriteString(" /*")
+			for i, key := range keys {
+				if i > 0 {
+					b.WriteByte(',')
+				}
+				// PathEscape escapes the quotes and the asterisks, so the tags can't close the comment.
+				b.WriteString(url.PathEscape(key))
+				b.WriteString("='")
+				b.WriteString(url.PathEscape(tags[key]))
+				b.WriteByte('\'')
+			}
+			b.WriteString("*/")
+			return b.String()
+		}
+
+		`)
+	}
+
+	fmt.Fprintf(w, `// instrumentation is the table and the operation of the statements.
+	type instrumentation struct {
+		table     string
+		operation string
+	}
+
+	// start calls the hooks before the statement, and returns the function that calls them after the statement.
+	func (in instrumentation) start(ctx context.Context, query string, args []any) (context.Context, func(err error)) {
+	`)
+	if m.config.GenerateTracing {
+		fmt.Fprintf(w, `var endSpan func(error)
+		if t := tracer; t != nil {
+			ctx, endSpan = t.Start(ctx, in.table, in.operation, query)
+		}
+		`)
+	}
+	if m.config.GenerateQueryLogger || m.config.GenerateMetrics {
+		fmt.Fprintf(w, "begin := time.Now()\n")
+	}
+	fmt.Fprintf(w, "return ctx, func(err error) {\n")
+	if m.config.GenerateTracing {
+		fmt.Fprintf(w, "if endSpan != nil {\n endSpan(err) \n}\n")
+	}
+	if m.config.GenerateQueryLogger {
+		fmt.Fprintf(w, "if l := queryLogger; l != nil {\n l.LogQuery(ctx, query, args, time.Since(begin), err) \n}\n")
+	}
+	if m.config.GenerateMetrics {
+		fmt.Fprintf(w, "if m := metrics; m != nil {\n m.ObserveQuery(in.table, in.operation, time.Since(begin), err) \n}\n")
+	}
+	fmt.Fprintf(w, `}
+	}
+
+	type instrumentedExecer struct {
+		execer
+		instrumentation
+	}
+
+	func instrumentExecer(e execer, table, operation string) execer {
+		return &instrumentedExecer{execer: e, instrumentation: instrumentation{table: table, operation: operation}}
+	}
+
+	func (e *instrumentedExecer) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+		ctx, end := e.start(ctx, query, args)
+		result, err := e.execer.ExecContext(ctx, %[1]s, args...)
+		end(err)
+		return result, %[2]s
+	}
+
+	`, query, result)
+	if m.config.GenerateSQLCommenter {
+		fmt.Fprintf(w, `// PrepareContext prepares the statement with the comment.
+		func (e *instrumentedExecer) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+			return e.execer.PrepareContext(ctx, commentQuery(ctx, query))
+		}
+
+		`)
+	}
+	fmt.Fprintf(w, `type instrumentedQueryer struct {
+		queryer
+		instrumentation
+	}
+
+	func instrumentQueryer(q queryer, table, operation string) queryer {
+		return &instrumentedQueryer{queryer: q, instrumentation: instrumentation{table: table, operation: operation}}
+	}
+
+	// QueryContext instruments the statement until the rows are returned, it doesn't include reading the rows.
+	func (q *instrumentedQueryer) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+		ctx, end := q.start(ctx, query, args)
+		rows, err := q.queryer.QueryContext(ctx, %[1]s, args...)
+		end(err)
+		return rows, err
+	}
+
+	func (q *instrumentedQueryer) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+		ctx, end := q.start(ctx, query, args)
+		row := q.queryer.QueryRowContext(ctx, %[1]s, args...)
+		end(row.Err())
+		return row
+	}
+
+	type instrumentedStmt struct {
+		preparedStmt
+		instrumentation
+		query string
+	}
+
+	func (s *instrumentedStmt) ExecContext(ctx context.Context, args ...any) (sql.Result, error) {
+		ctx, end := s.start(ctx, s.query, args)
+		result, err := s.preparedStmt.ExecContext(ctx, args...)
+		end(err)
+		return result, %[2]s
+	}
+
+	`, query, result)
+}
+
+// generateGoInstrument generates the code that instruments the statements of the generated function.
+// db is the name of the parameter, "execer" or "queryer".
+func (m *Maker) generateGoInstrument(w io.Writer, table *table, db, operation string) {
+	if !m.hasInstrumentation() {
+		return
+	}
+	if db == "queryer" && !m.hasQueryHooks() {
+		// the errors of the queries are not classified.
+		return
+	}
+	wrap := "instrumentExecer"
+	if db == "queryer" {
+		wrap = "instrumentQueryer"
+	}
+	fmt.Fprintf(w, "%[1]s = %[2]s(%[1]s, %[3]q, %[4]q)\n", db, wrap, table.fullName(), operation)
+}
@@ -0,0 +1,130 @@
+package myddlmaker
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// jsonValueType returns the Go type of the value of the column of JSON[T], i.e. T.
+// It returns an empty string if the column is not JSON[T] or T can't be referred from the package pkgPath.
+// The packages that T refers to are added to imports.
+func (c *column) jsonValueType(pkgPath string, imports map[string]bool) string {
+	if c.fieldType == nil || c.fieldType.Kind() != reflect.Array || !c.fieldType.Implements(myddlmakerJSON) {
+		return ""
+	}
+	return goTypeExpr(c.fieldType.Elem(), pkgPath, imports)
+}
+
+// hasJSONAccessors reports whether the Go source code has the accessors of the JSON[T] columns.
+func (m *Maker) hasJSONAccessors() bool {
+	pkgPath := m.goPkgPath()
+	for _, table := range m.tables {
+		if table.primaryKey == nil || finderOf(table, table.primaryKey.columns) == nil {
+			continue
+		}
+		for _, c := range table.columns {
+			if c.jsonValueType(pkgPath, map[string]bool{}) != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// generateGoJSONError generates the error of the accessors of the JSON[T] columns.
+func (m *Maker) generateGoJSONError(w io.Writer) {
+	fmt.Fprintf(w, `// JSONError is the error of marshaling or unmarshaling the value of the JSON column.
+	type JSONError struct {
+		Table  string
+		Column string
+		Err    error
+	}
+
+	func (e *JSONError) Error() string {
+		return e.Table + "." + e.Column + ": " + e.Err.Error()
+	}
+
+	func (e *JSONError) Unwrap() error {
+		return e.Err
+	}
+
+	`)
+}
+
+// generateGoTableJSONAccessors generates the typed getters and setters of the JSON[T] columns,
+// and the readers of the JSON paths with JSON_EXTRACT if GenerateJSONExtract is set.
+// They are generated only if the primary key columns are basic types.
+func (m *Maker) generateGoTableJSONAccessors(w io.Writer, table *table) {
+	finder := finderOf(table, table.primaryKey.columns)
+	if finder == nil {
+		return
+	}
+	pkgPath := m.goPkgPath()
+	params := strings.Join(finder.params, ", ")
+	args := strings.Join(finder.args, ", ")
+	for _, c := range table.columns {
+		typ := c.jsonValueType(pkgPath, map[string]bool{})
+		if typ == "" {
+			continue
+		}
+		suffix := table.rawName + c.rawName
+		jsonError := fmt.Sprintf("&JSONError{Table: %q, Column: %q, Err: err}", table.fullName(), c.name)
+		sqlSelect := fmt.Sprintf("SELECT %s FROM %s%s", quote(c.name), table.quotedName(), where(table.withNotDeleted(finder.conditions)))
+
+		fmt.Fprintf(w, "// Get%s selects the JSON column %s of the row of the primary key, and unmarshals it into %s.\n", suffix, c.name, typ)
+		fmt.Fprintf(w, "// It returns the zero value if the column is NULL, and sql.ErrNoRows if the row is not found.\n")
+		fmt.Fprintf(w, "func Get%s(ctx context.Context, queryer queryer, %s) (%s, error) {\n", suffix, params, typ)
+		m.generateGoInstrument(w, table, "queryer", "Get"+suffix)
+		fmt.Fprintf(w, "var v %s\n", typ)
+		fmt.Fprintf(w, "var data []byte\n")
+		fmt.Fprintf(w, "row := queryer.QueryRowContext(ctx, %q, %s)\n", sqlSelect, args)
+		fmt.Fprintf(w, "if err := row.Scan(&data); err != nil || data == nil {\n return v, err \n}\n")
+		fmt.Fprintf(w, "if err := json.Unmarshal(data, &v); err != nil {\n return v, %s \n}\n", jsonError)
+		fmt.Fprintf(w, "return v, nil\n")
+		fmt.Fprintf(w, "}\n\n")
+
+		fmt.Fprintf(w, "// Set%s marshals value, and updates the JSON column %s of the row of the primary key.\n", suffix, c.name)
+		fmt.Fprintf(w, "func Set%s(ctx context.Context, execer execer, %s, value %s) error {\n", suffix, params, typ)
+		m.generateGoInstrument(w, table, "execer", "Set"+suffix)
+		m.generateGoRetry(w)
+		fmt.Fprintf(w, "data, err := json.Marshal(value)\n")
+		fmt.Fprintf(w, "if err != nil {\n return %s \n}\n", jsonError)
+		fmt.Fprintf(w, "q := %q\n", "UPDATE "+table.quotedName()+" SET "+quote(c.name)+" = ?")
+		fmt.Fprintf(w, "args := []any{string(data)}\n")
+		if columns := table.autoNowColumns(false); len(columns) > 0 {
+			fmt.Fprintf(w, "now := Now()\n")
+			for _, c := range columns {
+				fmt.Fprintf(w, "q += %q\n", ", "+quote(c.name)+" = ?")
+				fmt.Fprintf(w, "args = append(args, now)\n")
+			}
+		}
+		if version := table.versionColumn(); version != nil {
+			fmt.Fprintf(w, "q += %q\n", fmt.Sprintf(", %[1]s = %[1]s + 1", quote(version.name)))
+		}
+		fmt.Fprintf(w, "q += %q\n", where(finder.conditions))
+		fmt.Fprintf(w, "args = append(args, %s)\n", args)
+		fmt.Fprintf(w, "_, err = execer.ExecContext(ctx, q, args...)\n")
+		fmt.Fprintf(w, "return err\n")
+		fmt.Fprintf(w, "}\n\n")
+
+		if !m.config.GenerateJSONExtract {
+			continue
+		}
+		extract := fmt.Sprintf("SELECT JSON_EXTRACT(%s, ?) FROM %s%s", quote(c.name), table.quotedName(), where(table.withNotDeleted(finder.conditions)))
+		fmt.Fprintf(w, "// Extract%s selects the value at the JSON path of the column %s of the row of the primary key, e.g. \"$.name\",\n", suffix, c.name)
+		fmt.Fprintf(w, "// and unmarshals it into V. It reads only the value from MySQL instead of the whole document.\n")
+		fmt.Fprintf(w, "// It returns false if the path doesn't exist, and sql.ErrNoRows if the row is not found.\n")
+		fmt.Fprintf(w, "func Extract%s[V any](ctx context.Context, queryer queryer, %s, path string) (V, bool, error) {\n", suffix, params)
+		m.generateGoInstrument(w, table, "queryer", "Extract"+suffix)
+		fmt.Fprintf(w, "var v V\n")
+		fmt.Fprintf(w, "var data []byte\n")
+		fmt.Fprintf(w, "row := queryer.QueryRowContext(ctx, %q, path, %s)\n", extract, args)
+		fmt.Fprintf(w, "if err := row.Scan(&data); err != nil {\n return v, false, err \n}\n")
+		fmt.Fprintf(w, "if data == nil {\n return v, false, nil \n}\n")
+		fmt.Fprintf(w, "if err := json.Unmarshal(data, &v); err != nil {\n return v, false, %s \n}\n", jsonError)
+		fmt.Fprintf(w, "return v, true, nil\n")
+		fmt.Fprintf(w, "}\n\n")
+	}
+}
@@ -0,0 +1,243 @@
+package myddlmaker
+
+import (
+	"strconv"
+	"strings"
+)
+
+// charsetMaxLen is the maximum length in bytes of a character in each character set.
+var charsetMaxLen = map[string]int{
+	"armscii8": 1,
+	"ascii":    1,
+	"big5":     2,
+	"binary":   1,
+	"cp1250":   1,
+	"cp1251":   1,
+	"cp1256":   1,
+	"cp1257":   1,
+	"cp850":    1,
+	"cp852":    1,
+	"cp866":    1,
+	"cp932":    2,
+	"dec8":     1,
+	"eucjpms":  3,
+	"euckr":    2,
+	"gb18030":  4,
+	"gb2312":   2,
+	"gbk":      2,
+	"geostd8":  1,
+	"greek":    1,
+	"hebrew":   1,
+	"hp8":      1,
+	"keybcs2":  1,
+	"koi8r":    1,
+	"koi8u":    1,
+	"latin1":   1,
+	"latin2":   1,
+	"latin5":   1,
+	"latin7":   1,
+	"macce":    1,
+	"macroman": 1,
+	"sjis":     2,
+	"swe7":     1,
+	"tis620":   1,
+	"ucs2":     2,
+	"ujis":     3,
+	"utf16":    4,
+	"utf16le":  4,
+	"utf32":    4,
+	"utf8":     3,
+	"utf8mb3":  3,
+	"utf8mb4":  4,
+}
+
+// defaultCharset is the default character set of MySQL 8.0.
+const defaultCharset = "utf8mb4"
+
+// charsetBytes returns the maximum length in bytes of a character in the character set.
+// It returns 4 for unknown character sets, which is the largest one.
+func charsetBytes(charset string) int {
+	if n, ok := charsetMaxLen[strings.ToLower(charset)]; ok {
+		return n
+	}
+	return 4
+}
+
+// maxBytes returns the maximum length in bytes of the values of the column.
+// tableCharset is the default character set of the table.
+// The second result is false if the length is unknown or unbounded (e.g. TEXT and BLOB).
+func (c *column) maxBytes(tableCharset string) (int, bool) {
+	typ, args := parseType(c.typ)
+	size := c.size
+	if size == 0 && len(args) > 0 {
+		size = args[0]
+	}
+	switch typ {
+	case "DECIMAL", "NUMERIC":
+		precision, scale := 10, 0
+		if len(args) > 0 {
+			precision = args[0]
+		}
+		if len(args) > 1 {
+			scale = args[1]
+		}
+		return decimalBytes(precision-scale) + decimalBytes(scale), true
+	case "BIT":
+		if len(args) > 0 {
+			return (args[0] + 7) / 8, true
+		}
+		return 1, true
+	case "ENUM":
+		return 2, true
+	case "SET":
+		return 8, true
+	case "TINYINT", "BOOL", "BOOLEAN", "YEAR":
+		return 1, true
+	case "SMALLINT":
+		return 2, true
+	case "MEDIUMINT", "DATE":
+		return 3, true
+	case "INT", "INTEGER", "FLOAT":
+		return 4, true
+	case "BIGINT", "DOUBLE":
+		return 8, true
+	case "DATETIME":
+		return 5 + fractionalBytes(size), true
+	case "TIMESTAMP":
+		return 4 + fractionalBytes(size), true
+	case "TIME":
+		return 3 + fractionalBytes(size), true
+	case "BINARY":
+		return withDefault(size, 1), true
+	case "VARBINARY":
+		return size + lengthBytes(size), true
+	case "CHAR":
+		return withDefault(size, 1) * c.charsetBytes(tableCharset), true
+	case "VARCHAR":
+		n := size * c.charsetBytes(tableCharset)
+		return n + lengthBytes(n), true
+	}
+	return 0, false
+}
+
+// isText reports whether the column is a non-binary string type.
+func (c *column) isText() bool {
+	typ, _ := parseType(c.typ)
+	switch typ {
+	case "CHAR", "VARCHAR", "TINYTEXT", "TEXT", "MEDIUMTEXT", "LONGTEXT":
+		return c.charset != "binary"
+	}
+	return false
+}
+
+// isInteger reports whether the column is an integer type.
+func (c *column) isInteger() bool {
+	typ, _ := parseType(c.typ)
+	switch typ {
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INTEGER", "INT", "BIGINT":
+		return true
+	}
+	return false
+}
+
+// isTime reports whether the column is a date and time type that holds a point in time.
+func (c *column) isTime() bool {
+	typ, _ := parseType(c.typ)
+	switch typ {
+	case "DATETIME", "TIMESTAMP":
+		return true
+	}
+	return false
+}
+
+// isGeometry reports whether the column is a spatial data type.
+func (c *column) isGeometry() bool {
+	typ, _ := parseType(c.typ)
+	switch typ {
+	case "GEOMETRY", "POINT", "LINESTRING", "POLYGON",
+		"MULTIPOINT", "MULTILINESTRING", "MULTIPOLYGON", "GEOMETRYCOLLECTION", "GEOMCOLLECTION":
+		return true
+	}
+	return false
+}
+
+// rowBytes returns the length in bytes of the column in the row size limit.
+// TEXT, BLOB and similar types are stored off-page,
+// and they contribute only 9 to 12 bytes to the row size.
+func (c *column) rowBytes(tableCharset string) (int, bool) {
+	typ, _ := parseType(c.typ)
+	switch typ {
+	case "TINYTEXT", "TEXT", "MEDIUMTEXT", "LONGTEXT",
+		"TINYBLOB", "BLOB", "MEDIUMBLOB", "LONGBLOB",
+		"JSON", "GEOMETRY", "POINT", "LINESTRING", "POLYGON",
+		"MULTIPOINT", "MULTILINESTRING", "MULTIPOLYGON", "GEOMETRYCOLLECTION":
+		return 12, true
+	}
+	return c.maxBytes(tableCharset)
+}
+
+// keyBytes returns the length in bytes of the column in index keys.
+// Unlike maxBytes, it doesn't contain the length prefix of variable-length types.
+func (c *column) keyBytes(tableCharset string) (int, bool) {
+	typ, args := parseType(c.typ)
+	size := c.size
+	if size == 0 && len(args) > 0 {
+		size = args[0]
+	}
+	switch typ {
+	case "VARBINARY":
+		return size, true
+	case "VARCHAR":
+		return size * c.charsetBytes(tableCharset), true
+	}
+	return c.maxBytes(tableCharset)
+}
+
+func (c *column) charsetBytes(tableCharset string) int {
+	charset := c.charset
+	if charset == "" {
+		charset = withDefault(tableCharset, defaultCharset)
+	}
+	return charsetBytes(charset)
+}
+
+// parseType parses the data type such as "DECIMAL(9,6)",
+// and returns the upper-case name and the numeric arguments.
+func parseType(typ string) (string, []int) {
+	name, rest, found := strings.Cut(typ, "(")
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if !found {
+		return name, nil
+	}
+	rest, _, _ = strings.Cut(rest, ")")
+	var args []int
+	for _, arg := range strings.Split(rest, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(arg))
+		if err != nil {
+			// non-numeric arguments, e.g. ENUM('a', 'b')
+			return name, nil
+		}
+		args = append(args, n)
+	}
+	return name, args
+}
+
+// decimalBytes returns the storage size of the digits of DECIMAL.
+// DECIMAL stores each multiple of nine digits in four bytes.
+func decimalBytes(digits int) int {
+	leftover := [...]int{0, 1, 1, 2, 2, 3, 3, 4, 4, 4}
+	return digits/9*4 + leftover[digits%9]
+}
+
+// fractionalBytes returns the storage size of the fractional seconds part.
+func fractionalBytes(fsp int) int {
+	return (fsp + 1) / 2
+}
+
+// lengthBytes returns the size of the length prefix of variable-length values.
+func lengthBytes(n int) int {
+	if n > 255 {
+		return 2
+	}
+	return 1
+}
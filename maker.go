@@ -2,11 +2,22 @@ package myddlmaker
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
-	"go/format"
+	"go/token"
 	"io"
+	"io/fs"
 	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strconv"
 	"strings"
+	"text/template"
+	"time"
+	"unicode"
 )
 
 // Config is a configuration of the DDL Maker.
@@ -15,12 +26,50 @@ type Config struct {
 
 	// OutFilePath is a file path for SQL generated by the DDL Maker.
 	// If it is empty, "schema.sql" is used.
+	// It is a template of text/template with OutFileData if it has "{{", e.g. "{{.Date}}_{{.Package}}.sql".
 	OutFilePath string
 
+	// OutDir is a directory for SQL generated by the DDL Maker.
+	// If it is not empty, OutFilePath is relative to it.
+	// GenerateFile creates the directory of the SQL files if it doesn't exist.
+	OutDir string
+
+	// FileHeader is the comment at the top of the generated files, e.g. the license header.
+	// Each line is written with the comment marker of SQL ("--") or Go ("//").
+	FileHeader string
+
+	// HeaderVersion adds the version of the DDL Maker into the banner of the generated files,
+	// e.g. "Code generated by https://github.com/shogo82148/myddlmaker v1.2.3; DO NOT EDIT.".
+	HeaderVersion bool
+
+	// HeaderTimestamp adds the time of the generation into the header of the generated files.
+	// It is off by default, so that the generated files are reproducible.
+	HeaderTimestamp bool
+
 	// OutGoFilePath is a file path for Go source code generated by the DDL Maker.
 	// If it is empty, "schema_gen.go" is used.
 	OutGoFilePath string
 
+	// OutGoDir is a directory for Go source code generated by the DDL Maker.
+	// If it is not empty, OutGoFilePath is relative to it, and GenerateGoFile creates it if it doesn't exist.
+	OutGoDir string
+
+	// SplitGoFiles makes GenerateGoFile write the functions of each table into separate files,
+	// in the directory of OutGoFilePath with the table name (e.g. "user_gen.go").
+	// The declarations that the tables share are written into OutGoFilePath.
+	SplitGoFiles bool
+
+	// GoBuildConstraint is the build constraint expression of Go source code generated by the DDL Maker,
+	// e.g. "linux && !nomysql". It is combined with Tag.
+	GoBuildConstraint string
+
+	// GoPackagePath is the import path of the package of Go source code generated by the DDL Maker,
+	// e.g. "example.com/app/internal/queries".
+	// If it differs from the package of the structs, Go source code imports the package of the structs
+	// and declares the aliases of the structs, so the structs can be separated from the generated functions.
+	// If it is empty, Go source code is in the package of the structs.
+	GoPackagePath string
+
 	// PackageName is a package name for Go source code generated by the DDL Maker.
 	// If it is empty, "schema" is used.
 	PackageName string
@@ -29,8 +78,284 @@ type Config struct {
 	// If it is empty, "myddlmaker" is used.
 	Tag string
 
+	// SchemaVersion is the version of the schema that SchemaVersion returns in Go source code,
+	// e.g. the number of the latest migration.
+	// If it is empty, SchemaVersion returns the first 12 characters of SchemaHash.
+	SchemaVersion string
+
 	// SkipValidationFKIndex disables index validation for foreign key constraints.
+	// It is same as setting SeverityOff to CheckFKIndex and CheckFKRefIndex in Checks.
 	SkipValidationFKIndex bool
+
+	// WarnFKIndex reports the columns of foreign key constraints that are not covered by any index
+	// as warnings instead of errors. MySQL creates the missing indexes implicitly.
+	// The referenced columns still require indexes.
+	// It is same as setting SeverityWarning to CheckFKIndex in Checks.
+	WarnFKIndex bool
+
+	// AutoFKIndex makes the DDL Maker create indexes on the columns of foreign key constraints
+	// that are not covered by any index. The created indexes have the same names as the constraints.
+	AutoFKIndex bool
+
+	// ExcludeFields is a list of patterns of fields that are not mapped to columns.
+	// The syntax of patterns is same as [path.Match].
+	// A pattern is matched against the field name (e.g. "Password"),
+	// or the struct name and the field name (e.g. "User.Password") if it contains a dot.
+	ExcludeFields []string
+
+	// TaggedFieldsOnly makes the DDL Maker map only the fields that have the ddl tag.
+	TaggedFieldsOnly bool
+
+	// TaggedUnexportedFieldsOnly makes the DDL Maker map only the unexported fields that have the ddl tag.
+	// By default, unexported fields are mapped as well as exported ones.
+	TaggedUnexportedFieldsOnly bool
+
+	// ColumnCommentsFromDoc makes the DDL Maker use the doc comments of fields as the column comments.
+	// The DDL Maker parses the Go source files of the package that declares the struct.
+	// The comment option in the ddl tag takes precedence over the doc comment.
+	ColumnCommentsFromDoc bool
+
+	// TableCommentsFromDoc makes the DDL Maker use the doc comments of structs as the table comments.
+	// The DDL Maker parses the Go source files of the package that declares the struct.
+	// The TableComment interface takes precedence over the doc comment.
+	TableCommentsFromDoc bool
+
+	// TableNamer converts struct names into table names.
+	// If it is nil, [SnakeCase] is used.
+	// The Table interface takes precedence over TableNamer.
+	TableNamer Namer
+
+	// ColumnNamer converts field names into column names.
+	// If it is nil, [SnakeCase] is used.
+	// The column name in the ddl tag takes precedence over ColumnNamer.
+	ColumnNamer Namer
+
+	// DefaultVarcharSize is the size of VARCHAR of the string fields that don't have the size option in the ddl tag,
+	// e.g. 255 for VARCHAR(255).
+	// If it is zero, 191 is used, that fits in the index key prefix of 767 bytes with utf8mb4.
+	DefaultVarcharSize int
+
+	// DefaultDatetimePrecision is the fractional seconds precision of DATETIME of the time fields
+	// that don't have the size option in the ddl tag, from 0 to 6, e.g. 3 for DATETIME(3) and 0 for DATETIME.
+	// If it is nil, 6 is used.
+	DefaultDatetimePrecision *int
+
+	// TablePrefix is the prefix of all table names (e.g. "app_").
+	// It is also applied to the tables referenced by foreign key constraints.
+	TablePrefix string
+
+	// TableSuffix is the suffix of all table names.
+	// It is also applied to the tables referenced by foreign key constraints.
+	TableSuffix string
+
+	// SplitSchemaFiles makes GenerateFile write the tables of each schema into separate files.
+	// The file path of a schema is OutFilePath with the schema name suffix (e.g. "schema_db1.sql"),
+	// unless the template of OutFilePath refers to the schema name (e.g. "{{.Schema}}.sql").
+	// The tables that don't have any schema are written into OutFilePath.
+	SplitSchemaFiles bool
+
+	// SplitTableFiles makes GenerateFile write the CREATE TABLE statement of each table into a separate file
+	// named after the table (e.g. "user.sql"), in the subdirectory of the schema if the table has a schema (e.g. "db1/user.sql").
+	// The files are in OutDir, or in the directory of OutFilePath if OutDir is empty.
+	// It is the directory layout of Skeema, and overrides SplitSchemaFiles.
+	SplitTableFiles bool
+
+	// SQLFormat is the format of the DDL, e.g. the case of the keywords and the indentation.
+	// If it is nil, the default format is used.
+	SQLFormat *SQLFormat
+
+	// SessionStatements are the session statements before and after the DDL of the tables in the SQL files.
+	// If it is nil, the SQL disables foreign_key_checks while creating the tables, unless SortTablesByForeignKeys is set.
+	// Set an empty SessionStatements to write no session statements, e.g. for the platforms that reject SET statements.
+	SessionStatements *SessionStatements
+
+	// SortTablesByForeignKeys sorts the tables so that the tables referenced by the foreign key constraints come first,
+	// and makes the SQL drop and create the tables without disabling foreign_key_checks.
+	// The generation fails if the foreign key constraints form a cycle, except for the tables that refer to themselves.
+	SortTablesByForeignKeys bool
+
+	// StrictTags makes unknown options in the ddl tag an error.
+	// By default, they are silently ignored.
+	StrictTags bool
+
+	// Checks configures the severity of each validation check.
+	// The checks that are not in the map use the default severity.
+	// The nolint option in the ddl tag suppresses the checks on the column.
+	Checks map[Check]Severity
+
+	// TableChecks configures the severity of each validation check per table.
+	// The key is the table name, qualified by the schema name if the table has a schema (e.g. "db1.users").
+	// It takes precedence over Checks.
+	TableChecks map[string]map[Check]Severity
+
+	// ReservedWords is a list of extra words that are reported when they are used as table or column names,
+	// in addition to the reserved words of MySQL 8.0.
+	// The comparison is case-insensitive.
+	ReservedWords []string
+
+	// NamingRules is a set of naming conventions that the DDL Maker checks.
+	// If it is nil, no naming conventions are checked.
+	NamingRules *NamingRules
+
+	// RequireBulkUpdateLimit makes the generated functions that update the rows by the indexes,
+	// e.g. UpdateUserColumnsByTenantID, return an error unless the limit is positive,
+	// so that a mistake doesn't update the whole rows of a large index.
+	RequireBulkUpdateLimit bool
+
+	// InsertChunkSize is the maximum number of rows that the generated Insert and BulkUpsert functions insert by one statement.
+	// Large slices are split into chunks, and the chunks are inserted sequentially.
+	// The chunk size is also limited by the maximum number of placeholders in a prepared statement.
+	// If it is zero, 32 is used.
+	InsertChunkSize int
+
+	// InsertInTransaction makes the generated Insert and BulkUpsert functions insert all the chunks in a transaction,
+	// if the execer passed to them can begin a transaction, e.g. [*sql.DB] and [*sql.Conn].
+	InsertInTransaction bool
+
+	// KeepKeyOrder makes the generated SelectByPKs functions return the rows in the order of the given keys.
+	// By default, the rows are ordered by the primary key.
+	KeepKeyOrder bool
+
+	// GenerateLockingReads makes the Go source code have the locking read variants of the functions that select a row,
+	// e.g. SelectUserForUpdate and SelectUserByEmailForShare.
+	GenerateLockingReads bool
+
+	// GenerateIterators makes the Go source code have the functions that iterate the rows,
+	// e.g. IterateAllUser and IterateAllUserByTenantID,
+	// and the functions that insert the values from iter.Seq, e.g. InsertUserFrom.
+	// GenerateGoFile writes them into the separate file with the build constraint of Go 1.23,
+	// e.g. "schema_gen_go123.go", because they use the iter package.
+	GenerateIterators bool
+
+	// GenerateQueryBuilder makes the Go source code have the query builder,
+	// e.g. QueryUser(ctx, db, And(UserWhere.TenantID.Eq(1), UserWhere.Name.In("alice", "bob"))).
+	GenerateQueryBuilder bool
+
+	// GenerateRepositories makes the Go source code have the interface of the generated functions for each table,
+	// e.g. UserQueries, and its implementations: NewUserQueries and the in-memory fake NewFakeUserQueries.
+	// They are generated only for the tables whose primary key columns are basic types.
+	GenerateRepositories bool
+
+	// GenerateGenericRepository makes the Go source code have the generic repository Repo[T, PK],
+	// the metadata of each table for it, e.g. UserMeta, and the constructor, e.g. NewUserRepo.
+	// They are generated only for the tables whose primary key columns are basic types.
+	// The Go source code requires Go 1.18 or later.
+	GenerateGenericRepository bool
+
+	// GenerateValidation makes the Go source code have the Validate methods of the structs, e.g. (*User).Validate,
+	// that check the fields satisfy the constraints of the columns before writing them:
+	// NOT NULL, the sizes of the strings, the members of ENUM and SET, and the ranges of the integers.
+	// The methods can't be generated with GoPackagePath of the different package.
+	GenerateValidation bool
+
+	// GenerateHooks makes the Go source code have the hooks of the tables, e.g. UserHooks set by SetUserHooks,
+	// that the generated write functions call before and after the statements,
+	// e.g. for the cache invalidation and the outbox.
+	GenerateHooks bool
+
+	// GenerateJSONExtract makes the Go source code have the functions that read the values at the JSON paths
+	// of the columns of [JSON] with JSON_EXTRACT, e.g. ExtractUserSettings[string](ctx, db, id, "$.theme"),
+	// in addition to the typed getters and setters of the columns, e.g. GetUserSettings and SetUserSettings.
+	GenerateJSONExtract bool
+
+	// GenerateEnums makes the Go source code have the constants of the members of the ENUM types,
+	// the string types with the method EnumValues() []string, e.g. StatusActive of Status,
+	// and their methods String, IsValid, Value and Scan.
+	// The methods can't be generated with GoPackagePath of the different package.
+	GenerateEnums bool
+
+	// GenerateFixtures makes the Go source code have the loaders of the fixtures, e.g. LoadFixturesFile and LoadUserFixtures,
+	// that insert the rows of the tables from the YAML or JSON files in the order of the foreign keys.
+	// The rows are the maps of the column names, and their maps and slices are marshaled into JSON.
+	GenerateFixtures bool
+
+	// GenerateFactories makes the Go source code have the factories of the structs for the tests, e.g. NewUserFactory,
+	// that build the values with the fake values satisfying the sizes, the members of ENUM and the NOT NULL constraints.
+	// The fields can be overridden, e.g. NewUserFactory().WithName("alice").Build().
+	GenerateFactories bool
+
+	// GenerateSeeder makes the Go source code have Seed that inserts the given numbers of the fake rows into the tables
+	// for the load tests, in the order of the foreign keys, with the generators of the columns, e.g. Seeds.User.Name.
+	// The columns of the foreign keys refer to the existing rows of the referenced tables.
+	// It generates the factories of GenerateFactories as well.
+	GenerateSeeder bool
+
+	// GenerateCSV makes the Go source code have the functions that write and read the rows in the CSV files,
+	// e.g. WriteUserCSV, ReadUserCSV and ExportUserCSV, and load the CSV files with LOAD DATA LOCAL INFILE, e.g. LoadUserCSV,
+	// for the bulk data migrations. LoadUserCSV requires local_infile of the server.
+	GenerateCSV bool
+
+	// GenerateQueryPlans makes the Go source code have ExplainQueryPlans that runs EXPLAIN on the generated queries
+	// that look up the rows by the primary keys and the indexes, and reports the queries that don't use their indexes,
+	// e.g. the full scans after an index is dropped. Run it in the tests against the database that has the tables.
+	GenerateQueryPlans bool
+
+	// GenerateTracing makes the generated functions start a span for each statement
+	// with the tracer set by SetTracer in the Go source code.
+	// The span has the table name, the name of the generated function and the statement,
+	// so that it can be adapted to OpenTelemetry without depending on it.
+	GenerateTracing bool
+
+	// GenerateQueryLogger makes the generated functions call the logger set by SetQueryLogger in the Go source code
+	// after each statement, with the statement, the arguments, the duration and the error.
+	GenerateQueryLogger bool
+
+	// GenerateMetrics makes the generated functions call the metrics set by SetMetrics in the Go source code
+	// after each statement, with the table name, the name of the generated function, the duration and the error.
+	GenerateMetrics bool
+
+	// GenerateSQLCommenter makes the generated functions append the comment of the tags returned by
+	// the sqlcommenter set by SetSQLCommenter in the Go source code to the statements, e.g. /*route='%2Fusers'*/.
+	GenerateSQLCommenter bool
+
+	// CachePreparedStatements makes the generated functions prepare the statements once for each [*sql.DB] and reuse them,
+	// instead of sending the query strings or preparing and closing the statements for each call.
+	// The functions whose statements vary for each call, e.g. Query<Table>, don't use the cache.
+	// Call ClosePreparedStatements in the Go source code before closing the [*sql.DB].
+	CachePreparedStatements bool
+
+	// PreparedStatementCacheSize is the maximum number of the cached prepared statements.
+	// The least recently used statement is closed when the cache is full.
+	// If it is zero, 128 is used.
+	PreparedStatementCacheSize int
+
+	// GenerateRetry makes the generated write functions retry each statement on deadlocks, lock wait timeouts
+	// and the connection errors before sending the statement, with the policy set by SetRetryPolicy in the Go source code.
+	// They are retried only with [*sql.DB], so use a transaction to retry the whole function.
+	GenerateRetry bool
+
+	// GenerateErrorHelpers makes the Go source code have the helpers that classify the MySQL errors,
+	// e.g. IsDuplicateEntry and IsForeignKeyViolation, and the sentinel errors of the classes.
+	// The errors returned by the generated write functions match the sentinel errors with [errors.Is],
+	// so that the callers don't need to import the MySQL driver.
+	GenerateErrorHelpers bool
+
+	// GenerateDBPair makes the Go source code have DBPair that routes the queries of the generated functions
+	// to the reader, e.g. a read replica, and the other statements to the writer.
+	// It can be passed to the generated functions instead of [*sql.DB].
+	GenerateDBPair bool
+
+	// GoFlavor is the flavor of the generated Go source code.
+	// The default is GoFlavorDatabaseSQL.
+	GoFlavor GoFlavor
+
+	// TemplateFS is the file system of the text/template files, "*.tmpl" in the root,
+	// that are executed to add the code to the Go source code, e.g. the methods with the company-specific naming.
+	// The templates named "imports", "header" and "table" are executed if they are defined:
+	// "imports" in the import declaration and "header" after the generated declarations with [TemplateData],
+	// and "table" after the generated functions of each table with [TemplateTable].
+	// The templates can use the functions "join", [strings.Join], and "param" that returns the parameter name of a field.
+	// The templates are executed only with GoFlavorDatabaseSQL.
+	TemplateFS fs.FS
+
+	// GenerateReplace is a list of patterns of struct names, e.g. "Event" or "*".
+	// The syntax of patterns is same as [path.Match].
+	// The Go source code has the Replace functions using REPLACE INTO for the matched structs.
+	GenerateReplace []string
+
+	// SensitiveComments appends the classes of the sensitive columns to the column comments,
+	// e.g. COMMENT 'the email address [pii: email]'.
+	SensitiveComments bool
 }
 
 type DBConfig struct {
@@ -42,12 +367,27 @@ type DBConfig struct {
 
 	// Collate is the default character collate for creating tables.
 	Collate string
+
+	// RowFormat is the row format for creating tables (e.g. "DYNAMIC", "COMPACT").
+	// The maximum length of index keys depends on it.
+	RowFormat string
 }
 
 type Maker struct {
-	config  *Config
-	structs []any
-	tables  []*table
+	config    *Config
+	structs   []any
+	tables    []*table
+	warnings  []string
+	templates *template.Template
+
+	// outFilePath is the template of Config.OutFilePath, or nil if it is not a template.
+	outFilePath *template.Template
+
+	// now returns the current time, used by the template of Config.OutFilePath and Config.HeaderTimestamp.
+	now func() time.Time
+
+	// version is the version of the DDL Maker, used by Config.HeaderVersion.
+	version string
 }
 
 func New(config *Config) (*Maker, error) {
@@ -58,19 +398,161 @@ func New(config *Config) (*Maker, error) {
 	if db == nil {
 		db = new(DBConfig)
 	}
+	if err := validateChecks("Checks", config.Checks); err != nil {
+		return nil, err
+	}
+	for name, checks := range config.TableChecks {
+		if err := validateChecks(fmt.Sprintf("TableChecks[%q]", name), checks); err != nil {
+			return nil, err
+		}
+	}
+	for _, pattern := range config.ExcludeFields {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("myddlmaker: invalid pattern %q in ExcludeFields: %w", pattern, err)
+		}
+	}
+	if config.DefaultVarcharSize < 0 || config.DefaultVarcharSize > 65535 {
+		return nil, fmt.Errorf("myddlmaker: invalid DefaultVarcharSize %d", config.DefaultVarcharSize)
+	}
+	var datetimePrecision *int
+	if config.DefaultDatetimePrecision != nil {
+		p := *config.DefaultDatetimePrecision
+		if p < 0 || p > 6 {
+			return nil, fmt.Errorf("myddlmaker: invalid DefaultDatetimePrecision %d", p)
+		}
+		datetimePrecision = &p
+	}
+	if config.InsertChunkSize < 0 {
+		return nil, fmt.Errorf("myddlmaker: invalid InsertChunkSize %d", config.InsertChunkSize)
+	}
+	if config.PreparedStatementCacheSize < 0 {
+		return nil, fmt.Errorf("myddlmaker: invalid PreparedStatementCacheSize %d", config.PreparedStatementCacheSize)
+	}
+	for _, pattern := range config.GenerateReplace {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("myddlmaker: invalid pattern %q in GenerateReplace: %w", pattern, err)
+		}
+	}
+	if err := parseGoBuildConstraint(config.GoBuildConstraint); err != nil {
+		return nil, err
+	}
+	switch config.GoFlavor {
+	case GoFlavorDatabaseSQL, GoFlavorSQLX:
+	default:
+		return nil, fmt.Errorf("myddlmaker: unknown GoFlavor %q", config.GoFlavor)
+	}
+	templates, err := config.parseTemplates()
+	if err != nil {
+		return nil, err
+	}
+	outFilePath, err := parseOutFilePath(withDefault(config.OutFilePath, "schema.sql"))
+	if err != nil {
+		return nil, err
+	}
+	var rules *NamingRules
+	if config.NamingRules != nil {
+		r := *config.NamingRules
+		rules = &r
+	}
+	var sqlFormat *SQLFormat
+	if config.SQLFormat != nil {
+		if err := config.SQLFormat.validate(); err != nil {
+			return nil, err
+		}
+		f := *config.SQLFormat
+		sqlFormat = &f
+	}
+	var session *SessionStatements
+	if config.SessionStatements != nil {
+		session = &SessionStatements{
+			Before: append([]string(nil), config.SessionStatements.Before...),
+			After:  append([]string(nil), config.SessionStatements.After...),
+		}
+	}
 	c := &Config{
 		DB: &DBConfig{
-			Engine:  db.Engine,
-			Charset: db.Charset,
-			Collate: db.Collate,
+			Engine:    db.Engine,
+			Charset:   db.Charset,
+			Collate:   db.Collate,
+			RowFormat: db.RowFormat,
 		},
 		OutFilePath:   withDefault(config.OutFilePath, "schema.sql"),
+		OutDir:        config.OutDir,
 		OutGoFilePath: withDefault(config.OutGoFilePath, "schema_gen.go"),
+		OutGoDir:      config.OutGoDir,
+		GoPackagePath: config.GoPackagePath,
+		SplitGoFiles:  config.SplitGoFiles,
 		PackageName:   withDefault(config.PackageName, "schema"),
 		Tag:           withDefault(config.Tag, "myddlmaker"),
+		SchemaVersion: config.SchemaVersion,
+
+		GoBuildConstraint: config.GoBuildConstraint,
+
+		SkipValidationFKIndex:      config.SkipValidationFKIndex,
+		WarnFKIndex:                config.WarnFKIndex,
+		AutoFKIndex:                config.AutoFKIndex,
+		Checks:                     config.Checks,
+		TableChecks:                config.TableChecks,
+		ReservedWords:              config.ReservedWords,
+		NamingRules:                rules,
+		ExcludeFields:              config.ExcludeFields,
+		TaggedFieldsOnly:           config.TaggedFieldsOnly,
+		TaggedUnexportedFieldsOnly: config.TaggedUnexportedFieldsOnly,
+		ColumnCommentsFromDoc:      config.ColumnCommentsFromDoc,
+		TableCommentsFromDoc:       config.TableCommentsFromDoc,
+		TableNamer:                 config.TableNamer,
+		ColumnNamer:                config.ColumnNamer,
+		TablePrefix:                config.TablePrefix,
+		TableSuffix:                config.TableSuffix,
+		SplitSchemaFiles:           config.SplitSchemaFiles,
+		SplitTableFiles:            config.SplitTableFiles,
+		SQLFormat:                  sqlFormat,
+		SortTablesByForeignKeys:    config.SortTablesByForeignKeys,
+		SessionStatements:          session,
+		DefaultVarcharSize:         config.DefaultVarcharSize,
+		DefaultDatetimePrecision:   datetimePrecision,
+		FileHeader:                 config.FileHeader,
+		HeaderVersion:              config.HeaderVersion,
+		HeaderTimestamp:            config.HeaderTimestamp,
+		StrictTags:                 config.StrictTags,
+		SensitiveComments:          config.SensitiveComments,
+		GenerateReplace:            config.GenerateReplace,
+		RequireBulkUpdateLimit:     config.RequireBulkUpdateLimit,
+		InsertChunkSize:            config.InsertChunkSize,
+		InsertInTransaction:        config.InsertInTransaction,
+		KeepKeyOrder:               config.KeepKeyOrder,
+		GenerateLockingReads:       config.GenerateLockingReads,
+		GenerateIterators:          config.GenerateIterators,
+		GenerateQueryBuilder:       config.GenerateQueryBuilder,
+		GenerateRepositories:       config.GenerateRepositories,
+		GenerateGenericRepository:  config.GenerateGenericRepository,
+		GenerateValidation:         config.GenerateValidation,
+		GenerateHooks:              config.GenerateHooks,
+		GenerateJSONExtract:        config.GenerateJSONExtract,
+		GenerateEnums:              config.GenerateEnums,
+		GenerateFixtures:           config.GenerateFixtures,
+		GenerateFactories:          config.GenerateFactories,
+		GenerateSeeder:             config.GenerateSeeder,
+		GenerateCSV:                config.GenerateCSV,
+		GenerateQueryPlans:         config.GenerateQueryPlans,
+		GenerateTracing:            config.GenerateTracing,
+		GenerateQueryLogger:        config.GenerateQueryLogger,
+		GenerateMetrics:            config.GenerateMetrics,
+		GenerateSQLCommenter:       config.GenerateSQLCommenter,
+		CachePreparedStatements:    config.CachePreparedStatements,
+		PreparedStatementCacheSize: config.PreparedStatementCacheSize,
+		GenerateRetry:              config.GenerateRetry,
+		GenerateErrorHelpers:       config.GenerateErrorHelpers,
+		GenerateDBPair:             config.GenerateDBPair,
+		GoFlavor:                   config.GoFlavor,
+		TemplateFS:                 config.TemplateFS,
 	}
 	return &Maker{
-		config: c,
+		config:      c,
+		templates:   templates,
+		outFilePath: outFilePath,
+		now:         time.Now,
+		version:     moduleVersion(),
 	}, nil
 }
 
@@ -86,11 +568,23 @@ func (m *Maker) AddStructs(structs ...any) {
 	m.structs = append(m.structs, structs...)
 }
 
-// GenerateFile opens
+// GenerateFile writes the DDL of the structs into OutFilePath,
+// or the DDL of each schema into separate files if SplitSchemaFiles is set.
 func (m *Maker) GenerateFile() error {
-	f, err := os.Create(m.config.OutFilePath)
+	if m.config.SplitSchemaFiles || m.config.SplitTableFiles {
+		return m.generateSQLFiles()
+	}
+
+	path, err := m.sqlFilePath("")
+	if err != nil {
+		return err
+	}
+	if err := mkdirFor(path); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
 	if err != nil {
-		return fmt.Errorf("myddlmaker: failed to open %q: %w", m.config.OutFilePath, err)
+		return fmt.Errorf("myddlmaker: failed to open %q: %w", path, err)
 	}
 	defer f.Close()
 
@@ -101,56 +595,252 @@ func (m *Maker) GenerateFile() error {
 	return f.Close()
 }
 
-func (m *Maker) Generate(w io.Writer) error {
-	var buf bytes.Buffer
-	if err := m.parse(); err != nil {
+// generateSQLFiles writes the tables of each schema or each table into separate files.
+func (m *Maker) generateSQLFiles() error {
+	files, err := m.sqlFiles()
+	if err != nil {
 		return err
 	}
+	for _, f := range files {
+		if err := mkdirFor(f.path); err != nil {
+			return err
+		}
+		if err := os.WriteFile(f.path, f.content, 0o644); err != nil {
+			return fmt.Errorf("myddlmaker: failed to write %q: %w", f.path, err)
+		}
+	}
+	return nil
+}
+
+// mkdirFor creates the directory of the file if it doesn't exist.
+func mkdirFor(path string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("myddlmaker: failed to create %q: %w", dir, err)
+	}
+	return nil
+}
+
+// sqlFiles returns the files that generateSQLFiles writes.
+func (m *Maker) sqlFiles() ([]generatedFile, error) {
+	if m.config.SplitTableFiles {
+		return m.tableFiles()
+	}
+	return m.schemaFiles()
+}
+
+// schemaFiles returns the SQL files of each schema.
+func (m *Maker) schemaFiles() ([]generatedFile, error) {
+	if err := m.parse(); err != nil {
+		return nil, fmt.Errorf("myddlmaker: failed to generate ddl: %w", err)
+	}
 
-	buf.WriteString("SET foreign_key_checks=0;\n")
+	var schemas []string
+	tables := map[string][]*table{}
 	for _, table := range m.tables {
-		m.generateTable(&buf, table)
+		if _, ok := tables[table.schema]; !ok {
+			schemas = append(schemas, table.schema)
+		}
+		tables[table.schema] = append(tables[table.schema], table)
+	}
+
+	files := make([]generatedFile, 0, len(schemas))
+	for _, schema := range schemas {
+		var buf bytes.Buffer
+		m.writeSQLHeader(&buf)
+		if err := m.generate(&buf, tables[schema]); err != nil {
+			return nil, fmt.Errorf("myddlmaker: failed to generate ddl: %w", err)
+		}
+		path, err := m.sqlFilePath(schema)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, generatedFile{path: path, content: buf.Bytes()})
 	}
+	return files, nil
+}
 
-	buf.WriteString("SET foreign_key_checks=1;\n")
+// schemaFilePath returns the file path for the schema.
+func schemaFilePath(path, schema string) string {
+	if schema == "" {
+		return path
+	}
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "_" + schema + ext
+}
 
-	if _, err := buf.WriteTo(w); err != nil {
+// Generate writes the DDL of the structs into w, e.g. a buffer or an HTTP response.
+// It is the same as the content of OutFilePath that GenerateFile writes.
+// The tables of all the schemas are written into w even if SplitSchemaFiles is set.
+func (m *Maker) Generate(w io.Writer) error {
+	if err := m.parse(); err != nil {
 		return err
 	}
-	return nil
+	m.writeSQLHeader(w)
+	return m.generate(w, m.tables)
+}
+
+func (m *Maker) generate(w io.Writer, tables []*table) error {
+	var before, after bytes.Buffer
+	session := m.sessionStatements()
+	writeStatements(&before, session.Before)
+	writeStatements(&after, session.After)
+
+	var buf bytes.Buffer
+	buf.Write(before.Bytes())
+	if m.config.SortTablesByForeignKeys {
+		if before.Len() > 0 {
+			buf.WriteString("\n")
+		}
+		m.generateSorted(&buf, tables)
+		if after.Len() > 0 {
+			buf.WriteString("\n")
+		}
+	} else {
+		var ddl bytes.Buffer
+		for _, table := range tables {
+			m.generateTable(&ddl, table)
+		}
+		// the empty lines separate the statements.
+		if before.Len() == 0 {
+			ddl.Next(1)
+		}
+		if after.Len() == 0 && ddl.Len() > 0 {
+			ddl.Truncate(ddl.Len() - 1)
+		}
+		buf.Write(ddl.Bytes())
+	}
+	buf.Write(after.Bytes())
+
+	_, err := io.WriteString(w, m.formatSQL(buf.String()))
+	return err
+}
+
+// generateSorted generates the DDL of the tables sorted by SortTablesByForeignKeys, without disabling foreign_key_checks.
+// The tables that refer to the others are dropped first, and the referenced tables are created first.
+func (m *Maker) generateSorted(w io.Writer, tables []*table) {
+	for i := len(tables) - 1; i >= 0; i-- {
+		fmt.Fprintf(w, "DROP TABLE IF EXISTS %s;\n", tables[i].quotedName())
+	}
+	for _, table := range tables {
+		io.WriteString(w, "\n")
+		m.generateCreateTable(w, table)
+		io.WriteString(w, ";\n")
+	}
 }
 
 func (m *Maker) parse() error {
-	m.tables = make([]*table, len(m.structs))
-	for i, s := range m.structs {
-		tbl, err := newTable(s)
+	// parse all structs and validate them before reporting errors,
+	// so that all errors are reported at once.
+	var errs []string
+	m.tables = make([]*table, 0, len(m.structs))
+	for _, s := range m.structs {
+		tbl, err := newTable(m.config, s)
 		if err != nil {
-			return fmt.Errorf("myddlmaker: failed to parse: %w", err)
+			var verr *validationError
+			if !errors.As(err, &verr) {
+				return fmt.Errorf("myddlmaker: failed to parse: %w", err)
+			}
+			errs = append(errs, verr.errs...)
+		}
+		m.tables = append(m.tables, tbl)
+	}
+	if m.config.AutoFKIndex {
+		for _, tbl := range m.tables {
+			tbl.addForeignKeyIndexes()
 		}
-		m.tables[i] = tbl
 	}
-	if err := m.validate(); err != nil {
+	if err := m.validate(errs); err != nil {
 		return err
 	}
+	if m.config.SortTablesByForeignKeys {
+		tables, err := sortTablesByForeignKeys(m.tables)
+		if err != nil {
+			return err
+		}
+		m.tables = tables
+	}
 	return nil
 }
 
-func (m *Maker) validate() error {
+// validate validates the tables.
+// parseErrs are the errors that are found while parsing the structs.
+func (m *Maker) validate(parseErrs []string) error {
 	v := newValidator(m.tables)
-	v.SkipValidationFKIndex = m.config.SkipValidationFKIndex
-	return v.Validate()
+	v.Checks = m.config.checks()
+	v.TableChecks = m.config.TableChecks
+	v.ReservedWords = m.config.ReservedWords
+	v.Charset = m.config.DB.Charset
+	v.Collate = m.config.DB.Collate
+	v.RowFormat = m.config.DB.RowFormat
+	v.NamingRules = m.config.NamingRules
+	for _, msg := range parseErrs {
+		v.SaveError(msg)
+	}
+	err := v.Validate()
+	m.warnings = v.warns
+	return err
+}
+
+// datetimePrecision returns the fractional seconds precision of DATETIME of the time fields.
+func (c *Config) datetimePrecision() int {
+	if c.DefaultDatetimePrecision == nil {
+		return 6
+	}
+	return *c.DefaultDatetimePrecision
+}
+
+// checks returns the severity configuration of the checks,
+// including the legacy options such as SkipValidationFKIndex.
+func (c *Config) checks() map[Check]Severity {
+	checks := make(map[Check]Severity, len(c.Checks)+2)
+	if c.SkipValidationFKIndex {
+		checks[CheckFKIndex] = SeverityOff
+		checks[CheckFKRefIndex] = SeverityOff
+	} else if c.WarnFKIndex {
+		checks[CheckFKIndex] = SeverityWarning
+	}
+	for check, severity := range c.Checks {
+		if severity != SeverityDefault {
+			checks[check] = severity
+		}
+	}
+	return checks
 }
 
 func (m *Maker) generateTable(w io.Writer, table *table) {
-	fmt.Fprintf(w, "\nDROP TABLE IF EXISTS %s;\n\n", quote(table.name))
-	fmt.Fprintf(w, "CREATE TABLE %s (\n", quote(table.name))
+	fmt.Fprintf(w, "\nDROP TABLE IF EXISTS %s;\n\n", table.quotedName())
+	m.generateCreateTable(w, table)
+	fmt.Fprintf(w, ";\n\n")
+}
+
+// generateCreateTable generates the CREATE TABLE statement of the table without the trailing semicolon.
+func (m *Maker) generateCreateTable(w io.Writer, table *table) {
+	var body bytes.Buffer
 	for _, col := range table.columns {
-		m.generateColumn(w, col)
+		m.generateColumn(&body, col)
+	}
+	m.generateIndex(&body, table)
+	if table.primaryKey != nil {
+		fmt.Fprintf(&body, "    PRIMARY KEY (%s)\n", strings.Join(quoteAll(table.primaryKey.columns), ", "))
+	}
+
+	// each line of the body is a definition, e.g. "    `id` INTEGER NOT NULL,".
+	lines := strings.Split(strings.TrimSuffix(body.String(), "\n"), "\n")
+	defs := make([]string, 0, len(lines))
+	for _, line := range lines {
+		defs = append(defs, strings.TrimSuffix(strings.TrimPrefix(line, "    "), ","))
 	}
-	m.generateIndex(w, table)
-	fmt.Fprintf(w, "    PRIMARY KEY (%s)\n", strings.Join(quoteAll(table.primaryKey.columns), ", "))
 
-	fmt.Fprintf(w, ")")
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "CREATE TABLE %s ", table.quotedName())
+	m.writeDefinitions(&buf, defs)
+	m.generateTableOptions(&buf, table)
+	buf.WriteTo(w)
+}
+
+// generateTableOptions generates the table options of CREATE TABLE, e.g. the comment and the engine.
+func (m *Maker) generateTableOptions(w io.Writer, table *table) {
 	if table.comment != nil {
 		fmt.Fprintf(w, " COMMENT=%s", stringQuote(*table.comment))
 	}
@@ -164,8 +854,10 @@ func (m *Maker) generateTable(w io.Writer, table *table) {
 		if collate := m.config.DB.Collate; collate != "" {
 			fmt.Fprintf(w, " DEFAULT COLLATE=%s", collate)
 		}
+		if rowFormat := m.config.DB.RowFormat; rowFormat != "" {
+			fmt.Fprintf(w, " ROW_FORMAT=%s", rowFormat)
+		}
 	}
-	fmt.Fprintf(w, ";\n\n")
 }
 
 func (m *Maker) generateColumn(w io.Writer, col *column) {
@@ -283,12 +975,15 @@ func (m *Maker) generateIndex(w io.Writer, table *table) {
 	}
 
 	for _, idx := range table.foreignKeys {
+		if idx.logical {
+			continue
+		}
 		io.WriteString(w, "    CONSTRAINT ")
 		io.WriteString(w, quote(idx.name))
 		io.WriteString(w, " FOREIGN KEY (")
 		io.WriteString(w, strings.Join(quoteAll(idx.columns), ", "))
 		io.WriteString(w, ") REFERENCES ")
-		io.WriteString(w, quote(idx.table))
+		io.WriteString(w, quoteQualified(idx.referencedSchema(table), idx.table))
 		io.WriteString(w, " (")
 		io.WriteString(w, strings.Join(quoteAll(idx.references), ", "))
 		io.WriteString(w, ")")
@@ -322,6 +1017,14 @@ func quote(s string) string {
 	return buf.String()
 }
 
+// quoteQualified quotes the name qualified by the schema name with `schema`.`name`.
+func quoteQualified(schema, name string) string {
+	if schema == "" {
+		return quote(name)
+	}
+	return quote(schema) + "." + quote(name)
+}
+
 func quoteAll(strings []string) []string {
 	ret := make([]string, len(strings))
 	for i, s := range strings {
@@ -371,32 +1074,29 @@ func NewPrimaryKey(field ...string) *PrimaryKey {
 	}
 }
 
+// GenerateGoFile writes the Go source code of the structs into OutGoFilePath in OutGoDir,
+// or the functions of each table into separate files if SplitGoFiles is set.
+// The iterators of GenerateIterators are written into a separate file with the build constraint of Go 1.23,
+// e.g. "schema_gen_go123.go".
 func (m *Maker) GenerateGoFile() error {
-	f, err := os.Create(m.config.OutGoFilePath)
-	if err != nil {
-		return fmt.Errorf("myddlmaker: failed to open %q: %w", m.config.OutGoFilePath, err)
-	}
-	defer f.Close()
-
-	if err := m.GenerateGo(f); err != nil {
-		return fmt.Errorf("myddlmaker: failed to generate go file: %w", err)
+	if m.config.OutGoDir != "" {
+		if err := os.MkdirAll(m.config.OutGoDir, 0o755); err != nil {
+			return fmt.Errorf("myddlmaker: failed to create %q: %w", m.config.OutGoDir, err)
+		}
 	}
-
-	return f.Close()
+	return m.generateGoFiles()
 }
 
+// GenerateGo writes the formatted Go source code of the structs into w, e.g. a buffer or an HTTP response.
+// It is the same as the content of OutGoFilePath that GenerateGoFile writes.
+// The functions of all the tables are written into w even if SplitGoFiles is set,
+// but the iterators of GenerateIterators are not, because they are in the separate file.
 func (m *Maker) GenerateGo(w io.Writer) error {
-	var buf bytes.Buffer
-	if err := m.parse(); err != nil {
+	src, err := m.generateGoSource()
+	if err != nil {
 		return err
 	}
-
-	m.generateGoHeader(&buf)
-	for _, table := range m.tables {
-		m.generateGoTable(&buf, table)
-	}
-
-	source, err := format.Source(buf.Bytes())
+	source, err := src.source()
 	if err != nil {
 		return err
 	}
@@ -404,16 +1104,176 @@ func (m *Maker) GenerateGo(w io.Writer) error {
 	return err
 }
 
-func (m *Maker) generateGoHeader(w io.Writer) {
-	io.WriteString(w, "// Code generated by https://github.com/shogo82148/myddlmaker; DO NOT EDIT.\n\n")
-	fmt.Fprintf(w, "//go:build !%s\n\n", m.config.Tag)
-	fmt.Fprintf(w, "package %s\n\n", m.config.PackageName)
-	fmt.Fprintf(w, `import (
-		"context"
-		"database/sql"
-	)
+// generateGoSource generates the unformatted Go source code.
+func (m *Maker) generateGoSource() (*goSource, error) {
+	if err := m.parse(); err != nil {
+		return nil, err
+	}
+	if err := m.validateGoPackage(); err != nil {
+		return nil, err
+	}
+	if err := m.validateEnums(); err != nil {
+		return nil, err
+	}
+
+	src := &goSource{
+		tables: make([][]byte, len(m.tables)),
+	}
+	var preamble, header bytes.Buffer
+	switch m.config.GoFlavor {
+	case GoFlavorSQLX:
+		if err := m.validateSQLX(); err != nil {
+			return nil, err
+		}
+		m.generateGoSQLXPreamble(&preamble)
+		if m.isForeignGoPackage() {
+			m.generateGoTypeAliases(&header)
+		}
+		for i, table := range m.tables {
+			var buf bytes.Buffer
+			m.generateGoSQLXTable(&buf, table)
+			src.tables[i] = buf.Bytes()
+		}
+	default:
+		data := m.templateData()
+		if err := m.generateGoPreamble(&preamble, data, false); err != nil {
+			return nil, err
+		}
+		if err := m.generateGoHeader(&header, data); err != nil {
+			return nil, err
+		}
+		for i, table := range m.tables {
+			var buf bytes.Buffer
+			m.generateGoTable(&buf, table)
+			if err := m.executeTemplate(&buf, "table", data.Tables[i]); err != nil {
+				return nil, err
+			}
+			src.tables[i] = buf.Bytes()
+		}
+		if m.config.GenerateIterators && len(m.tables) > 0 {
+			var buf bytes.Buffer
+			if err := m.generateGoPreamble(&buf, data, true); err != nil {
+				return nil, err
+			}
+			src.iteratorPreamble = buf.Bytes()
+			src.iterators = make([][]byte, len(m.tables))
+			for i, table := range m.tables {
+				var buf bytes.Buffer
+				m.generateGoTableInsertFrom(&buf, table)
+				m.generateGoTableIterate(&buf, table)
+				src.iterators[i] = buf.Bytes()
+			}
+		}
+	}
+	src.preamble = preamble.Bytes()
+	src.header = header.Bytes()
+	return src, nil
+}
+
+// generateGoPreamble generates the package clause and the imports.
+// iterators generates them for the file of the iterators, that imports the iter package.
+func (m *Maker) generateGoPreamble(w io.Writer, data *TemplateData, iterators bool) error {
+	m.generateGoPackageClause(w, iterators)
+	var imports []string
+	if m.config.CachePreparedStatements {
+		imports = append(imports, "container/list")
+	}
+	if m.hasCSV() {
+		imports = append(imports, "bufio")
+	}
+	if m.hasFixtures() {
+		imports = append(imports, "bytes")
+	}
+	imports = append(imports, "context", "database/sql")
+	if m.config.GenerateRetry || m.hasEnums() || m.hasCSV() {
+		imports = append(imports, "database/sql/driver")
+	}
+	if len(m.tables) > 0 || m.usesMySQLDriver() {
+		imports = append(imports, "errors")
+	}
+	if m.hasCursorFinders() {
+		imports = append(imports, "encoding/base64")
+	}
+	if m.hasCursorFinders() || m.hasJSONAccessors() || m.hasFixtures() {
+		imports = append(imports, "encoding/json")
+	}
+	if m.hasFixtures() || m.hasSeeder() || m.hasCSV() || m.hasQueryPlans() {
+		imports = append(imports, "fmt")
+	}
+	if m.hasCSV() {
+		imports = append(imports, "io")
+	}
+	if m.hasFixtures() {
+		imports = append(imports, "io/fs")
+	}
+	if iterators {
+		imports = append(imports, "iter")
+	}
+	if m.config.GenerateSQLCommenter {
+		imports = append(imports, "net/url")
+	}
+	if m.hasFixtures() {
+		imports = append(imports, "path")
+	}
+	if m.hasCSV() {
+		imports = append(imports, "reflect")
+	}
+	if m.hasRepositories() || m.config.GenerateSQLCommenter || m.hasFixtures() {
+		imports = append(imports, "sort")
+	}
+	validationImports := m.validationImports()
+	if m.hasFactories() || m.hasCSV() {
+		imports = append(imports, "strconv")
+	}
+	if m.config.GenerateSQLCommenter || validationImports["strings"] || m.hasFixtures() || m.hasCSV() || m.hasQueryPlans() {
+		imports = append(imports, "strings")
+	}
+	if m.hasRepositories() || m.config.CachePreparedStatements {
+		imports = append(imports, "sync")
+	}
+	if m.hasFactories() || m.hasCSV() {
+		imports = append(imports, "sync/atomic")
+	}
+	if m.typeImports()["time"] || m.hasAutoNowColumns() || m.config.GenerateQueryLogger || m.config.GenerateMetrics || m.config.GenerateRetry || m.hasFactories() || m.hasCSV() {
+		imports = append(imports, "time")
+	}
+	if validationImports["unicode/utf8"] {
+		imports = append(imports, "unicode/utf8")
+	}
+	io.WriteString(w, "import (\n")
+	for _, path := range imports {
+		fmt.Fprintf(w, "%q\n", path)
+	}
+	if m.usesMySQLDriver() || m.isForeignGoPackage() {
+		io.WriteString(w, "\n")
+	}
+	if m.usesMySQLDriver() {
+		fmt.Fprintf(w, "%q\n", "github.com/go-sql-driver/mysql")
+	}
+	if m.isForeignGoPackage() {
+		m.generateGoStructImport(w)
+	}
+	if m.hasTemplate("imports") {
+		// separate the imports of the templates from the generated ones.
+		io.WriteString(w, "\n")
+		if err := m.executeTemplate(w, "imports", data); err != nil {
+			return err
+		}
+	}
+	io.WriteString(w, ")\n\n")
+	return nil
+}
 
-	type execer interface {
+// generateGoHeader generates the declarations that the functions of the tables share.
+func (m *Maker) generateGoHeader(w io.Writer, data *TemplateData) error {
+	if m.isForeignGoPackage() {
+		m.generateGoTypeAliases(w)
+	}
+	if len(m.tables) > 0 {
+		m.generateGoAllDDL(w)
+		m.generateGoSchemaVersion(w)
+	}
+	fmt.Fprintf(w, `type execer interface {
 		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
 		PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
 	}
@@ -424,88 +1284,1865 @@ func (m *Maker) generateGoHeader(w io.Writer) {
 		PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
 	}
 
+	// the generated functions work with the connection pools, the connections and the transactions.
+	var (
+		_ execer  = (*sql.DB)(nil)
+		_ execer  = (*sql.Conn)(nil)
+		_ execer  = (*sql.Tx)(nil)
+		_ queryer = (*sql.DB)(nil)
+		_ queryer = (*sql.Conn)(nil)
+		_ queryer = (*sql.Tx)(nil)
+	)
+
 	`)
-}
+	if len(m.tables) > 0 {
+		fmt.Fprintf(w, `// RowScanner is the interface that scans a row, such as *sql.Row and *sql.Rows.
+		type RowScanner interface {
+			Scan(dest ...any) error
+		}
 
-func (m *Maker) generateGoTable(w io.Writer, table *table) {
-	m.generateGoTableInsert(w, table)
-	m.generateGoTableSelect(w, table)
-	m.generateGoTableSelectAll(w, table)
-	m.generateGoTableUpdate(w, table)
-}
+		`)
+		fmt.Fprintf(w, `// SelectOption is an option of the functions that select multiple rows.
+		type SelectOption func(*selectOptions)
 
-func (m *Maker) generateGoTableInsert(w io.Writer, table *table) {
-	// https://stackoverflow.com/questions/18100782/import-of-50k-records-in-mysql-gives-general-error-1390-prepared-statement-con
-	const maxPlaceholderCount = 65535
-	const maxMaxStructCount = 32
+		type selectOptions struct {
+			column string
+			desc   bool
+			limit  int
+			offset int
+		}
 
-	fmt.Fprintf(w, "func Insert%[1]s(ctx context.Context, execer execer, values ...*%[1]s) error {", table.rawName)
+		// ErrInvalidOrderBy is returned if the column passed to SelectOrderBy is not indexed.
+		var ErrInvalidOrderBy = errors.New("the column is not indexed")
 
-	columns := make([]string, 0, len(table.columns))
-	placeholders := make([]string, 0, len(table.columns))
-	values := make([]string, 0, len(table.columns))
-	for _, c := range table.columns {
-		if c.autoIncr {
-			continue
+		// SelectOrderBy sorts the rows by the column, instead of the primary key.
+		// The column must be a column of the primary key or an index.
+		func SelectOrderBy(column string) SelectOption {
+			return func(o *selectOptions) {
+				o.column = column
+			}
 		}
-		columns = append(columns, quote(c.name))
-		placeholders = append(placeholders, "?")
-		values = append(values, fmt.Sprintf("v.%s", c.rawName))
-	}
 
-	if len(placeholders) == 0 {
-		strPlaceholders := ", ()"
-		insert := "INSERT INTO " + quote(table.name) + " () VALUES ()"
-		fmt.Fprintf(w, "const q = %q+\n%q\n", insert, strings.Repeat(strPlaceholders, maxMaxStructCount-1))
-		fmt.Fprintf(w, "const maxStructCount = %d\n", maxMaxStructCount)
-		fmt.Fprintf(w, `if len(values) >= maxStructCount {
-			err := func() error {
-				stmt, err := execer.PrepareContext(ctx, q)
-				if err != nil {
-					return err
-				}
-				defer stmt.Close()
+		// SelectOrderDesc sorts the rows in descending order.
+		func SelectOrderDesc() SelectOption {
+			return func(o *selectOptions) {
+				o.desc = true
+			}
+		}
 
-				for len(values) >= maxStructCount {
-					values = values[maxStructCount:]
-					if _, err := stmt.ExecContext(ctx); err != nil {
-						return err
-					}
-				}
-				return nil
-			}()
-			if err != nil {
-				return err
+		// SelectLimit limits the number of the rows.
+		func SelectLimit(n int) SelectOption {
+			return func(o *selectOptions) {
+				o.limit = n
 			}
 		}
-		if len(values) == 0 {
-			return nil
+
+		// SelectOffset skips the first n rows.
+		func SelectOffset(n int) SelectOption {
+			return func(o *selectOptions) {
+				o.offset = n
+			}
 		}
-		if _, err := execer.ExecContext(ctx, q[:len(values)*%[1]d+%[2]d]); err != nil {
-			return err
+
+		// limitOffset appends the LIMIT and OFFSET clauses to the query.
+		func (o *selectOptions) limitOffset(q string, args []any) (string, []any) {
+			if o.limit > 0 {
+				q += " LIMIT ?"
+				args = append(args, o.limit)
+			} else if o.offset > 0 {
+				// MySQL requires LIMIT with OFFSET.
+				q += " LIMIT 18446744073709551615"
+			}
+			if o.offset > 0 {
+				q += " OFFSET ?"
+				args = append(args, o.offset)
+			}
+			return q, args
 		}
-		return nil
-	}
 
-	`, len(strPlaceholders), len(insert)-len(strPlaceholders))
-		return
+		`)
 	}
+	if m.hasCursorFinders() {
+		fmt.Fprintf(w, `// encodeCursor encodes the key of the last row into an opaque cursor.
+		func encodeCursor(key any) (string, error) {
+			data, err := json.Marshal(key)
+			if err != nil {
+				return "", err
+			}
+			return base64.RawURLEncoding.EncodeToString(data), nil
+		}
 
-	strPlaceholders := ", (" + strings.Join(placeholders, ", ") + ")"
-	maxStructCount := maxPlaceholderCount / len(placeholders)
-	if maxStructCount > maxMaxStructCount {
-		maxStructCount = maxMaxStructCount
+		// decodeCursor decodes the cursor into the key of the last row.
+		func decodeCursor(cursor string, key any) error {
+			data, err := base64.RawURLEncoding.DecodeString(cursor)
+			if err != nil {
+				return err
+			}
+			return json.Unmarshal(data, key)
+		}
+
+		`)
 	}
-	insert := "INSERT INTO " + quote(table.name) + " (" + strings.Join(columns, ", ") + ") VALUES" + " (" + strings.Join(placeholders, ", ") + ")"
+	if m.config.GenerateQueryBuilder && len(m.tables) > 0 {
+		fmt.Fprintf(w, `// Condition is a condition of the WHERE clause, built by the columns of the tables.
+		// The zero value matches all the rows.
+		type Condition struct {
+			query string
+			args  []any
+		}
+
+		// And returns the condition that matches the rows that match all the conditions.
+		func And(conds ...Condition) Condition {
+			return joinConditions(" AND ", conds)
+		}
+
+		// Or returns the condition that matches the rows that match any of the conditions.
+		func Or(conds ...Condition) Condition {
+			return joinConditions(" OR ", conds)
+		}
+
+		// Not returns the condition that matches the rows that don't match the condition.
+		func Not(cond Condition) Condition {
+			if cond.query == "" {
+				return Condition{query: "FALSE"}
+			}
+			return Condition{query: "NOT (" + cond.query + ")", args: cond.args}
+		}
+
+		func joinConditions(sep string, conds []Condition) Condition {
+			var ret Condition
+			for _, cond := range conds {
+				if cond.query == "" {
+					continue
+				}
+				if ret.query != "" {
+					ret.query += sep
+				}
+				ret.query += "(" + cond.query + ")"
+				ret.args = append(ret.args, cond.args...)
+			}
+			return ret
+		}
+
+		// Column is a column of the tables, that builds the conditions with the values of type T.
+		type Column[T any] struct {
+			name string
+		}
+
+		// Eq returns the condition "column = v".
+		func (c Column[T]) Eq(v T) Condition {
+			return Condition{query: c.name + " = ?", args: []any{v}}
+		}
+
+		// Ne returns the condition "column <> v".
+		func (c Column[T]) Ne(v T) Condition {
+			return Condition{query: c.name + " <> ?", args: []any{v}}
+		}
+
+		// Lt returns the condition "column < v".
+		func (c Column[T]) Lt(v T) Condition {
+			return Condition{query: c.name + " < ?", args: []any{v}}
+		}
+
+		// Le returns the condition "column <= v".
+		func (c Column[T]) Le(v T) Condition {
+			return Condition{query: c.name + " <= ?", args: []any{v}}
+		}
+
+		// Gt returns the condition "column > v".
+		func (c Column[T]) Gt(v T) Condition {
+			return Condition{query: c.name + " > ?", args: []any{v}}
+		}
+
+		// Ge returns the condition "column >= v".
+		func (c Column[T]) Ge(v T) Condition {
+			return Condition{query: c.name + " >= ?", args: []any{v}}
+		}
+
+		// In returns the condition "column IN (values...)".
+		// It matches no rows if values is empty.
+		func (c Column[T]) In(values ...T) Condition {
+			if len(values) == 0 {
+				return Condition{query: "FALSE"}
+			}
+			q := c.name + " IN (?"
+			args := make([]any, 0, len(values))
+			for i, v := range values {
+				if i > 0 {
+					q += ", ?"
+				}
+				args = append(args, v)
+			}
+			return Condition{query: q + ")", args: args}
+		}
+
+		// IsNull returns the condition "column IS NULL".
+		func (c Column[T]) IsNull() Condition {
+			return Condition{query: c.name + " IS NULL"}
+		}
+
+		// IsNotNull returns the condition "column IS NOT NULL".
+		func (c Column[T]) IsNotNull() Condition {
+			return Condition{query: c.name + " IS NOT NULL"}
+		}
+
+		`)
+	}
+	if m.hasRepositories() || m.hasGenericRepositories() || m.hasSeeder() {
+		fmt.Fprintf(w, `type dbtx interface {
+			execer
+			queryer
+		}
+
+		`)
+	}
+	if m.hasGenericRepositories() {
+		m.generateGoGenericRepository(w)
+	}
+	if m.hasValidation() {
+		m.generateGoValidationErrors(w)
+	}
+	if m.hasHooks() {
+		m.generateGoHooks(w)
+	}
+	if m.hasJSONAccessors() {
+		m.generateGoJSONError(w)
+	}
+	if m.hasEnums() {
+		m.generateGoEnums(w)
+	}
+	if m.hasFixtures() {
+		m.generateGoFixtures(w)
+	}
+	if m.hasFactories() {
+		m.generateGoFactoryHelpers(w)
+	}
+	if m.hasSeeder() {
+		m.generateGoSeeder(w)
+	}
+	if m.hasCSV() {
+		m.generateGoCSV(w)
+	}
+	if m.hasQueryPlans() {
+		m.generateGoQueryPlans(w)
+	}
+	if m.hasRepositories() {
+		if !m.config.GenerateErrorHelpers {
+			fmt.Fprintf(w, `// ErrDuplicateEntry is returned by the fake implementations if the primary key already exists.
+			var ErrDuplicateEntry = errors.New("duplicate entry for the primary key")
+
+			`)
+		}
+	}
+	if m.hasAutoNowColumns() {
+		fmt.Fprintf(w, `// Now returns the current time that is set to the auto_now and auto_now_add columns.
+		// Replace it to fix the time in tests.
+		var Now = time.Now
+
+		`)
+	}
+	if m.hasVersionColumns() {
+		fmt.Fprintf(w, `// ConflictError is returned when the version of the row doesn't match,
+		// i.e. the row has been updated or deleted by others since it was read.
+		type ConflictError struct {
+			Table string
+		}
+
+		func (e *ConflictError) Error() string {
+			return "the row of " + e.Table + " has been updated or deleted by others"
+		}
+
+		`)
+	}
+	if m.hasInstrumentation() {
+		m.generateGoInstrumentation(w)
+	}
+	if m.config.GenerateDBPair {
+		m.generateGoDBPair(w)
+	}
+	if m.usesAsDB() {
+		m.generateGoAsDB(w)
+	}
+	if m.usesPrepare() {
+		m.generateGoPrepare(w)
+	}
+	if m.config.GenerateErrorHelpers {
+		m.generateGoErrorHelpers(w)
+	}
+	if m.config.GenerateRetry {
+		m.generateGoRetryPolicy(w)
+	}
+	if m.config.InsertInTransaction {
+		fmt.Fprintf(w, `type beginner interface {
+			BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+		}
+
+		`)
+	}
+	return m.executeTemplate(w, "header", data)
+}
+
+func (m *Maker) generateGoTable(w io.Writer, table *table) {
+	m.generateGoTableNames(w, table)
+	m.generateGoTableCreateSQL(w, table)
+	m.generateGoTableScan(w, table)
+	if m.config.GenerateValidation {
+		m.generateGoTableValidate(w, table)
+	}
+	if m.config.GenerateHooks {
+		m.generateGoTableHooks(w, table)
+	}
+	m.generateGoTableInsert(w, table)
+	m.generateGoTableInsertIgnore(w, table)
+	m.generateGoTableInsertReturningID(w, table)
+	if table.primaryKey != nil {
+		m.generateGoTableSelect(w, table)
+		m.generateGoTableSelectColumns(w, table)
+		m.generateGoTableSelectByPKs(w, table)
+	}
+	m.generateGoTableSelectByUniqueIndexes(w, table)
+	m.generateGoTableOrderBy(w, table)
+	m.generateGoTableSelectAll(w, table)
+	m.generateGoTableSelectByIndexes(w, table)
+	m.generateGoTableSelectByIndexesAfter(w, table)
+	if table.primaryKey != nil {
+		m.generateGoTableForEach(w, table)
+	}
+	m.generateGoTableCountAndExists(w, table)
+	if m.config.GenerateQueryBuilder {
+		m.generateGoTableQuery(w, table)
+	}
+	if table.primaryKey != nil {
+		m.generateGoTableUpdate(w, table)
+		m.generateGoTableUpdateColumns(w, table)
+		m.generateGoTableJSONAccessors(w, table)
+		m.generateGoTableUpsert(w, table)
+		if m.config.generatesReplace(table) {
+			m.generateGoTableReplace(w, table)
+		}
+		m.generateGoTableDelete(w, table)
+		m.generateGoTableDeleteByPK(w, table)
+	}
+	m.generateGoTableDeleteByIndexes(w, table)
+	if table.primaryKey != nil {
+		if m.config.GenerateRepositories {
+			m.generateGoTableRepository(w, table)
+		}
+		if m.config.GenerateGenericRepository {
+			m.generateGoTableMeta(w, table)
+		}
+	}
+	if m.config.GenerateFixtures {
+		m.generateGoTableFixtures(w, table)
+	}
+	if m.hasFactories() {
+		m.generateGoTableFactory(w, table)
+	}
+	if m.config.GenerateSeeder {
+		m.generateGoTableSeed(w, table)
+	}
+	if m.config.GenerateCSV {
+		m.generateGoTableCSV(w, table)
+	}
+	if m.config.GenerateQueryPlans {
+		m.generateGoTableQueryPlans(w, table)
+	}
+}
+
+// generateGoTableNames generates the names of the table and the columns,
+// so that hand-written queries can refer them without string literals.
+func (m *Maker) generateGoTableNames(w io.Writer, table *table) {
+	fmt.Fprintf(w, "// %sTable is the name of the table for %[1]s.\n", table.rawName)
+	fmt.Fprintf(w, "const %sTable = %q\n\n", table.rawName, table.fullName())
+
+	fmt.Fprintf(w, "// %sColumns is the names of the columns for %[1]s.\n", table.rawName)
+	fmt.Fprintf(w, "var %sColumns = struct {\n", table.rawName)
+	for _, c := range table.columns {
+		fmt.Fprintf(w, "%s string\n", c.rawName)
+	}
+	fmt.Fprintf(w, "}{\n")
+	for _, c := range table.columns {
+		fmt.Fprintf(w, "%s: %q,\n", c.rawName, c.name)
+	}
+	fmt.Fprintf(w, "}\n\n")
+}
+
+// generateGoAllDDL generates AllDDL that returns the CREATE TABLE statements of all the tables.
+func (m *Maker) generateGoAllDDL(w io.Writer) {
+	if m.config.SortTablesByForeignKeys {
+		fmt.Fprintf(w, "// AllDDL returns the CREATE TABLE statements of all the tables in the order of the foreign keys,\n")
+		fmt.Fprintf(w, "// e.g. for the test fixtures. The tables referenced by the foreign keys come first.\n")
+	} else {
+		fmt.Fprintf(w, "// AllDDL returns the CREATE TABLE statements of all the tables in the order of the structs,\n")
+		fmt.Fprintf(w, "// e.g. for the test fixtures. Disable foreign_key_checks to create the tables that refer to the later ones.\n")
+	}
+	fmt.Fprintf(w, "func AllDDL() []string {\n")
+	fmt.Fprintf(w, "return []string{\n")
+	for _, table := range m.tables {
+		fmt.Fprintf(w, "%sCreateTableSQL(),\n", table.rawName)
+	}
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "}\n\n")
+}
+
+// schemaHash returns the hex-encoded SHA-256 hash of the DDL of all the tables,
+// i.e. the hash of the SQL file if the tables are in one schema.
+func (m *Maker) schemaHash() string {
+	var buf bytes.Buffer
+	m.generate(&buf, m.tables)
+	sum := sha256.Sum256(buf.Bytes())
+	return hex.EncodeToString(sum[:])
+}
+
+// generateGoSchemaVersion generates SchemaHash and SchemaVersion,
+// so that the services can report the revision of the schema that they are built against.
+func (m *Maker) generateGoSchemaVersion(w io.Writer) {
+	hash := m.schemaHash()
+	version := withDefault(m.config.SchemaVersion, hash[:12])
+	fmt.Fprintf(w, "// SchemaHash returns the SHA-256 hash of the DDL of all the tables that the code is generated from.\n")
+	fmt.Fprintf(w, "// It changes whenever the DDL changes, e.g. a column is added.\n")
+	fmt.Fprintf(w, "func SchemaHash() string {\n return %q \n}\n\n", hash)
+	fmt.Fprintf(w, "// SchemaVersion returns the version of the schema that the code is generated from.\n")
+	fmt.Fprintf(w, "func SchemaVersion() string {\n return %q \n}\n\n", version)
+}
+
+// generateGoTableCreateSQL generates the function that returns the CREATE TABLE statement of the table,
+// so that the callers can create the table without reading the SQL file.
+func (m *Maker) generateGoTableCreateSQL(w io.Writer, table *table) {
+	var buf bytes.Buffer
+	m.generateCreateTable(&buf, table)
+	fmt.Fprintf(w, "// %sCreateTableSQL returns the CREATE TABLE statement of %s.\n", table.rawName, table.fullName())
+	fmt.Fprintf(w, "func %sCreateTableSQL() string {\n", table.rawName)
+	fmt.Fprintf(w, "return %s\n", goStringLiteral(m.formatSQL(buf.String())))
+	fmt.Fprintf(w, "}\n\n")
+}
+
+// goStringLiteral returns the Go expression of the multi-line string s,
+// the concatenation of the quoted lines, because the SQL statements contain the backquotes.
+func goStringLiteral(s string) string {
+	lines := strings.SplitAfter(s, "\n")
+	quoted := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if line != "" {
+			quoted = append(quoted, strconv.Quote(line))
+		}
+	}
+	return strings.Join(quoted, " +\n")
+}
+
+// generateGoTableScan generates the functions that scan the rows,
+// so that hand-written queries can use the same mapping as the generated functions.
+func (m *Maker) generateGoTableScan(w io.Writer, table *table) {
+	fields := make([]string, 0, len(table.columns))
+	goFields := make([]string, 0, len(table.columns))
+	for _, c := range table.columns {
+		fields = append(fields, c.name)
+		goFields = append(goFields, "&v."+c.goField)
+	}
+
+	fmt.Fprintf(w, "// Scan%[1]s scans the row into %[1]s.\n", table.rawName)
+	fmt.Fprintf(w, "// The row must have the columns %s in this order.\n", strings.Join(fields, ", "))
+	fmt.Fprintf(w, "func Scan%[1]s(row RowScanner) (*%[1]s, error) {\n", table.rawName)
+	fmt.Fprintf(w, "var v %s\n", table.rawName)
+	fmt.Fprintf(w, "if err := row.Scan(%s); err != nil {\n return nil, err \n}\n", strings.Join(goFields, ", "))
+	fmt.Fprintf(w, "return &v, nil\n")
+	fmt.Fprintf(w, "}\n\n")
+
+	fmt.Fprintf(w, "// Scan%[1]sRows scans all the rows into %[1]s, and closes the rows.\n", table.rawName)
+	fmt.Fprintf(w, "// The rows must have the same columns as Scan%s.\n", table.rawName)
+	fmt.Fprintf(w, "func Scan%[1]sRows(rows *sql.Rows) ([]*%[1]s, error) {\n", table.rawName)
+	fmt.Fprintf(w, "defer rows.Close()\n")
+	fmt.Fprintf(w, "var ret []*%s\n", table.rawName)
+	fmt.Fprintf(w, "for rows.Next() {\n")
+	fmt.Fprintf(w, "v, err := Scan%s(rows)\n", table.rawName)
+	fmt.Fprintf(w, "if err != nil {\n return nil, err \n}\n")
+	fmt.Fprintf(w, "ret = append(ret, v)\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "if err := rows.Err(); err != nil {\n return nil, err \n}\n")
+	fmt.Fprintf(w, "return ret, nil\n")
+	fmt.Fprintf(w, "}\n\n")
+}
+
+// insertStructCount returns the maximum number of the values that Insert<Table> inserts by one statement.
+func (m *Maker) insertStructCount(table *table) int {
+	// https://stackoverflow.com/questions/18100782/import-of-50k-records-in-mysql-gives-general-error-1390-prepared-statement-con
+	const maxPlaceholderCount = 65535
+	count := withDefault(m.config.InsertChunkSize, 32)
+	var fieldCount int
+	for _, c := range table.columns {
+		if !c.autoIncr {
+			fieldCount++
+		}
+	}
+	if fieldCount > 0 && maxPlaceholderCount/fieldCount < count {
+		count = maxPlaceholderCount / fieldCount
+	}
+	return count
+}
+
+func (m *Maker) generateGoTableInsert(w io.Writer, table *table) {
+	maxStructCount := m.insertStructCount(table)
+
+	fmt.Fprintf(w, "func Insert%[1]s(ctx context.Context, execer execer, values ...*%[1]s) error {", table.rawName)
+
+	columns := make([]string, 0, len(table.columns))
+	placeholders := make([]string, 0, len(table.columns))
+	values := make([]string, 0, len(table.columns))
+	for _, c := range table.columns {
+		if c.autoIncr {
+			continue
+		}
+		columns = append(columns, quote(c.name))
+		placeholders = append(placeholders, "?")
+		values = append(values, fmt.Sprintf("v.%s", c.goField))
+	}
+
+	if len(placeholders) == 0 {
+		strPlaceholders := ", ()"
+		insert := "INSERT INTO " + table.quotedName() + " () VALUES ()"
+		fmt.Fprintf(w, "const q = %q+\n%q\n", insert, strings.Repeat(strPlaceholders, maxStructCount-1))
+		fmt.Fprintf(w, "const maxStructCount = %d\n", maxStructCount)
+		m.generateGoCallHooks(w, table, "Insert", "values", "Insert"+table.rawName+"(ctx, execer, values...)", "")
+		m.generateGoTableInsertTx(w, "Insert"+table.rawName)
+		m.generateGoCacheSingleInsert(w)
+		m.generateGoInstrument(w, table, "execer", "Insert"+table.rawName)
+		m.generateGoRetry(w)
+		fmt.Fprintf(w, `if len(values) >= maxStructCount {
+			err := func() error {
+				stmt, err := %[3]s
+				if err != nil {
+					return err
+				}
+				defer stmt.Close()
+
+				for len(values) >= maxStructCount {
+					values = values[maxStructCount:]
+					if _, err := stmt.ExecContext(ctx); err != nil {
+						return err
+					}
+				}
+				return nil
+			}()
+			if err != nil {
+				return err
+			}
+		}
+		if len(values) == 0 {
+			return nil
+		}
+		if _, err := execer.ExecContext(ctx, q[:len(values)*%[1]d+%[2]d]); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	`, len(strPlaceholders), len(insert)-len(strPlaceholders), m.goPrepare("q"))
+		return
+	}
+
+	strPlaceholders := ", (" + strings.Join(placeholders, ", ") + ")"
+	insert := "INSERT INTO " + table.quotedName() + " (" + strings.Join(columns, ", ") + ") VALUES" + " (" + strings.Join(placeholders, ", ") + ")"
 	fmt.Fprintf(w, "const q = %q+\n%q\n", insert, strings.Repeat(strPlaceholders, maxStructCount-1))
 	fmt.Fprintf(w, "const fieldCount = %d\n", len(placeholders))
 	fmt.Fprintf(w, "const maxStructCount = %d\n", maxStructCount)
+	m.generateGoCallHooks(w, table, "Insert", "values", "Insert"+table.rawName+"(ctx, execer, values...)", "")
+	m.generateGoTableInsertTx(w, "Insert"+table.rawName)
+	m.generateGoCacheSingleInsert(w)
+	m.generateGoInstrument(w, table, "execer", "Insert"+table.rawName)
+	m.generateGoRetry(w)
+	m.generateGoSetNow(w, table, true, "values", false)
+
+	fmt.Fprintf(w, `var args []any
+	if len(values) >= maxStructCount {
+		args = make([]any, 0, maxStructCount*fieldCount)
+		err := func() error {
+			stmt, err := %[4]s
+			if err != nil {
+				return err
+			}
+			defer stmt.Close()
+
+			for len(values) >= maxStructCount {
+				vals, rest := values[:maxStructCount], values[maxStructCount:]
+				args = args[:0]
+				for _, v := range vals {
+					args = append(args, %[1]s)
+				}
+				if _, err := stmt.ExecContext(ctx, args...); err != nil {
+					return err
+				}
+				values = rest
+			}
+			return nil
+		}()
+		if err != nil {
+			return err
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	if len(args) == 0 {
+		args = make([]any, 0, len(values)*fieldCount)
+	}
+	args = args[:0]
+	for _, v := range values {
+		args = append(args, %[1]s)
+	}
+	if _, err := execer.ExecContext(ctx, q[:len(values)*%[2]d+%[3]d], args...); err != nil {
+		return err
+	}
+	return nil
+}
+
+`, strings.Join(values, ", "), len(strPlaceholders), len(insert)-len(strPlaceholders), m.goPrepare("q"))
+}
+
+// generateGoTableInsertFrom generates the function that inserts the values from the iterator
+// in the chunks of the values that Insert<Table> inserts by one statement.
+func (m *Maker) generateGoTableInsertFrom(w io.Writer, table *table) {
+	name := table.rawName
+	fmt.Fprintf(w, "// Insert%sFrom inserts the values from seq in the chunks of %d values by Insert%[1]s,\n", name, m.insertStructCount(table))
+	fmt.Fprintf(w, "// so that the caller doesn't need to hold all the values in a slice.\n")
+	fmt.Fprintf(w, "// The chunks are inserted in separate statements, pass a transaction to insert all or nothing.\n")
+	fmt.Fprintf(w, "func Insert%[1]sFrom(ctx context.Context, execer execer, seq iter.Seq[*%[1]s]) error {\n", name)
+	fmt.Fprintf(w, "const chunkSize = %d\n", m.insertStructCount(table))
+	fmt.Fprintf(w, "chunk := make([]*%s, 0, chunkSize)\n", name)
+	fmt.Fprintf(w, "var err error\n")
+	fmt.Fprintf(w, "seq(func(v *%s) bool {\n", name)
+	fmt.Fprintf(w, "chunk = append(chunk, v)\n")
+	fmt.Fprintf(w, "if len(chunk) < chunkSize {\n return true \n}\n")
+	fmt.Fprintf(w, "err = Insert%s(ctx, execer, chunk...)\n", name)
+	fmt.Fprintf(w, "// the hooks may keep the chunk, so it is not reused.\n")
+	fmt.Fprintf(w, "chunk = make([]*%s, 0, chunkSize)\n", name)
+	fmt.Fprintf(w, "return err == nil\n")
+	fmt.Fprintf(w, "})\n")
+	fmt.Fprintf(w, "if err != nil {\n return err \n}\n")
+	fmt.Fprintf(w, "if len(chunk) == 0 {\n return nil \n}\n")
+	fmt.Fprintf(w, "return Insert%s(ctx, execer, chunk...)\n", name)
+	fmt.Fprintf(w, "}\n\n")
+}
+
+// generateGoTableInsertTx generates the code that inserts the chunks in a transaction.
+// The generated function name, e.g. "InsertUser", calls itself with the transaction,
+// which doesn't begin a nested transaction because [*sql.Tx] doesn't implement the beginner interface.
+func (m *Maker) generateGoTableInsertTx(w io.Writer, name string) {
+	if !m.config.InsertInTransaction {
+		return
+	}
+	fmt.Fprintf(w, `if db, ok := execer.(beginner); ok && len(values) > maxStructCount {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if err := %s(ctx, tx, values...); err != nil {
+			tx.Rollback()
+			return err
+		}
+		return tx.Commit()
+	}
+	`, name)
+}
+
+func (m *Maker) generateGoTableInsertIgnore(w io.Writer, table *table) {
+	columns := make([]string, 0, len(table.columns))
+	placeholders := make([]string, 0, len(table.columns))
+	values := make([]string, 0, len(table.columns))
+	for _, c := range table.columns {
+		if c.autoIncr {
+			continue
+		}
+		columns = append(columns, quote(c.name))
+		placeholders = append(placeholders, "?")
+		values = append(values, fmt.Sprintf("value.%s", c.goField))
+	}
+
+	// https://dev.mysql.com/doc/refman/8.0/en/insert.html
+	insert := fmt.Sprintf(
+		"INSERT IGNORE INTO %s (%s) VALUES (%s)",
+		table.quotedName(),
+		strings.Join(columns, ", "),
+		strings.Join(placeholders, ", "),
+	)
+	args := ""
+	if len(values) > 0 {
+		args = ", " + strings.Join(values, ", ")
+	}
+	fmt.Fprintf(w, "func InsertIgnore%[1]s(ctx context.Context, execer execer, value *%[1]s) (bool, error) {\n", table.rawName)
+	m.generateGoCallHooks(w, table, "Insert", "[]*"+table.rawName+"{value}", "InsertIgnore"+table.rawName+"(ctx, execer, value)", "bool")
+	m.generateGoCacheStatements(w, "execer")
+	m.generateGoInstrument(w, table, "execer", "InsertIgnore"+table.rawName)
+	m.generateGoRetry(w)
+	m.generateGoSetNow(w, table, true, "value", true)
+	fmt.Fprintf(w, "result, err := execer.ExecContext(ctx, %q%s)\n", insert, args)
+	fmt.Fprintf(w, "if err != nil {\n")
+	fmt.Fprintf(w, "return false, err\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "n, err := result.RowsAffected()\n")
+	fmt.Fprintf(w, "if err != nil {\n")
+	fmt.Fprintf(w, "return false, err\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "return n > 0, nil\n")
+	fmt.Fprintf(w, "}\n\n")
+}
+
+// insertReturningIDKey returns the primary key column and its Go type for the function that inserts a row and returns the ID.
+// It returns nil if the function is not generated.
+func (m *Maker) insertReturningIDKey(table *table) (*column, string) {
+	key, _ := table.primaryKeyGoType()
+	if key == nil || !key.autoIncr {
+		return nil, ""
+	}
+	switch key.fieldType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		return nil, ""
+	}
+	goType := key.goTypeExpr(m.goPkgPath(), map[string]bool{})
+	if goType == "" {
+		return nil, ""
+	}
+	return key, goType
+}
+
+// generateGoTableInsertReturningID generates the function that inserts a row,
+// and sets the auto-increment ID to the value.
+// It is generated only for the primary keys of a single auto-increment column of an integer type.
+func (m *Maker) generateGoTableInsertReturningID(w io.Writer, table *table) {
+	key, goType := m.insertReturningIDKey(table)
+	if key == nil {
+		return
+	}
+
+	columns := make([]string, 0, len(table.columns))
+	placeholders := make([]string, 0, len(table.columns))
+	values := make([]string, 0, len(table.columns))
+	for _, c := range table.columns {
+		if c.autoIncr {
+			continue
+		}
+		columns = append(columns, quote(c.name))
+		placeholders = append(placeholders, "?")
+		values = append(values, fmt.Sprintf("value.%s", c.goField))
+	}
+	insert := fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES (%s)",
+		table.quotedName(),
+		strings.Join(columns, ", "),
+		strings.Join(placeholders, ", "),
+	)
+	args := ""
+	if len(values) > 0 {
+		args = ", " + strings.Join(values, ", ")
+	}
+
+	fmt.Fprintf(w, "// Insert%[1]sReturningID inserts the value, and sets the auto-increment ID to value.%[2]s.\n", table.rawName, key.goField)
+	fmt.Fprintf(w, "func Insert%[1]sReturningID(ctx context.Context, execer execer, value *%[1]s) (%[2]s, error) {\n", table.rawName, goType)
+	m.generateGoCallHooks(w, table, "Insert", "[]*"+table.rawName+"{value}", "Insert"+table.rawName+"ReturningID(ctx, execer, value)", goType)
+	m.generateGoCacheStatements(w, "execer")
+	m.generateGoInstrument(w, table, "execer", "Insert"+table.rawName+"ReturningID")
+	m.generateGoRetry(w)
+	m.generateGoSetNow(w, table, true, "value", true)
+	fmt.Fprintf(w, "result, err := execer.ExecContext(ctx, %q%s)\n", insert, args)
+	fmt.Fprintf(w, "if err != nil {\n return 0, err \n}\n")
+	fmt.Fprintf(w, "id, err := result.LastInsertId()\n")
+	fmt.Fprintf(w, "if err != nil {\n return 0, err \n}\n")
+	fmt.Fprintf(w, "value.%s = %s(id)\n", key.goField, goType)
+	fmt.Fprintf(w, "return value.%s, nil\n", key.goField)
+	fmt.Fprintf(w, "}\n\n")
+}
+
+func (m *Maker) generateGoTableSelect(w io.Writer, table *table) {
+	fields := make([]string, 0, len(table.columns))
+	goFields := make([]string, 0, len(table.columns))
+	params := make([]string, 0, len(table.primaryKey.columns))
+	conditions := make([]string, 0, len(table.primaryKey.columns))
+	for _, c := range table.columns {
+		fields = append(fields, quote(c.name))
+		goFields = append(goFields, "&v."+c.goField)
+		for _, key := range table.primaryKey.columns {
+			if key == c.name {
+				params = append(params, fmt.Sprintf("primaryKeys.%s", c.goField))
+				conditions = append(conditions, fmt.Sprintf("%s = ?", quote(c.name)))
+			}
+		}
+	}
+
+	sqlSelect := fmt.Sprintf(
+		"SELECT %s FROM %s",
+		strings.Join(fields, ", "),
+		table.quotedName(),
+	)
+	generate := func(lock lockingRead, suffix, q string) {
+		fmt.Fprintf(w, "func Select%[1]s%[2]s(ctx context.Context, queryer queryer, primaryKeys *%[1]s) (*%[1]s, error) {\n", table.rawName, suffix)
+		m.generateGoUseWriter(w, lock)
+		m.generateGoCacheStatements(w, "queryer")
+		m.generateGoInstrument(w, table, "queryer", "Select"+table.rawName+suffix)
+		fmt.Fprintf(w, "var v %s\n", table.rawName)
+		fmt.Fprintf(w, "row := queryer.QueryRowContext(ctx, %q, %s)\n", q, strings.Join(params, ", "))
+		fmt.Fprintf(w, "if err := row.Scan(%s); err != nil {\n return nil, err \n}\n", strings.Join(goFields, ", "))
+		fmt.Fprintf(w, "return &v, nil\n")
+		fmt.Fprintf(w, "}\n\n")
+	}
+	for _, lock := range m.config.lockingReads() {
+		generate(lock, lock.suffix, sqlSelect+where(table.withNotDeleted(conditions))+lock.clause)
+	}
+	if table.softDeleteColumn() != nil {
+		generate(lockingRead{}, "WithDeleted", sqlSelect+where(conditions))
+	}
+}
+
+// generateGoTableSelectColumns generates the type of the columns and the function that selects only the given columns
+// of the row by the primary key, to avoid reading the wide columns that the caller doesn't need.
+func (m *Maker) generateGoTableSelectColumns(w io.Writer, table *table) {
+	finder := finderOf(table, table.primaryKey.columns)
+	if finder == nil {
+		return
+	}
+	name := table.rawName
+
+	fmt.Fprintf(w, "// %[1]sColumn is a column of %[1]s for Select%[1]sColumns.\n", name)
+	fmt.Fprintf(w, "type %sColumn string\n\n", name)
+	fmt.Fprintf(w, "// The columns of %s.\n", name)
+	fmt.Fprintf(w, "const (\n")
+	for _, c := range table.columns {
+		fmt.Fprintf(w, "%[1]sCol%[2]s %[1]sColumn = %[3]q\n", name, c.rawName, c.name)
+	}
+	fmt.Fprintf(w, ")\n\n")
+
+	fmt.Fprintf(w, "// Select%[1]sColumns selects the columns of the row of the primary key, and the other fields of %[1]s are left zero.\n", name)
+	fmt.Fprintf(w, "// It selects all the columns if no columns are given. It returns sql.ErrNoRows if the row is not found.\n")
+	fmt.Fprintf(w, "func Select%[1]sColumns(ctx context.Context, queryer queryer, %[2]s, columns ...%[1]sColumn) (*%[1]s, error) {\n", name, strings.Join(finder.params, ", "))
+	m.generateGoInstrument(w, table, "queryer", "Select"+name+"Columns")
+	consts := make([]string, 0, len(table.columns))
+	for _, c := range table.columns {
+		consts = append(consts, name+"Col"+c.rawName)
+	}
+	fmt.Fprintf(w, "if len(columns) == 0 {\n")
+	fmt.Fprintf(w, "columns = []%sColumn{%s}\n", name, strings.Join(consts, ", "))
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "var v %s\n", name)
+	fmt.Fprintf(w, "q := \"SELECT \"\n")
+	fmt.Fprintf(w, "dest := make([]any, 0, len(columns))\n")
+	fmt.Fprintf(w, "for i, c := range columns {\n")
+	fmt.Fprintf(w, "if i > 0 {\n q += \", \" \n}\n")
+	fmt.Fprintf(w, "switch c {\n")
+	for _, c := range table.columns {
+		fmt.Fprintf(w, "case %sCol%s:\n", name, c.rawName)
+		fmt.Fprintf(w, "q += %q\n", quote(c.name))
+		fmt.Fprintf(w, "dest = append(dest, &v.%s)\n", c.goField)
+	}
+	fmt.Fprintf(w, "default:\n")
+	fmt.Fprintf(w, "return nil, errors.New(%q + string(c))\n", "unknown column of "+table.fullName()+": ")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "q += %q\n", " FROM "+table.quotedName()+where(table.withNotDeleted(finder.conditions)))
+	fmt.Fprintf(w, "row := queryer.QueryRowContext(ctx, q, %s)\n", strings.Join(finder.args, ", "))
+	fmt.Fprintf(w, "if err := row.Scan(dest...); err != nil {\n return nil, err \n}\n")
+	fmt.Fprintf(w, "return &v, nil\n")
+	fmt.Fprintf(w, "}\n\n")
+}
+
+// where returns the WHERE clause of the conditions, or an empty string if there are no conditions.
+func where(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
+
+// lockingRead is a variant of the generated functions that select a row.
+type lockingRead struct {
+	suffix string // the suffix of the function name, e.g. "ForUpdate"
+	clause string // the locking clause, e.g. " FOR UPDATE"
+}
+
+// lockingReads returns the variants of the generated functions that select a row.
+// The first one is the non-locking read.
+func (c *Config) lockingReads() []lockingRead {
+	reads := []lockingRead{{}}
+	if c.GenerateLockingReads {
+		// https://dev.mysql.com/doc/refman/8.0/en/innodb-locking-reads.html
+		reads = append(reads,
+			lockingRead{suffix: "ForUpdate", clause: " FOR UPDATE"},
+			lockingRead{suffix: "ForShare", clause: " FOR SHARE"},
+		)
+	}
+	return reads
+}
+
+// generateGoTableSelectByPKs generates the function that selects the rows by the values of the primary key.
+// It is generated only for the primary keys of a single column of a basic type.
+func (m *Maker) generateGoTableSelectByPKs(w io.Writer, table *table) {
+	key, goType := table.primaryKeyGoType()
+	if key == nil {
+		return
+	}
+
+	fields := make([]string, 0, len(table.columns))
+	goFields := make([]string, 0, len(table.columns))
+	for _, c := range table.columns {
+		fields = append(fields, quote(c.name))
+		goFields = append(goFields, "&v."+c.goField)
+	}
+	sqlSelect := fmt.Sprintf(
+		"SELECT %s FROM %s%s",
+		strings.Join(fields, ", "),
+		table.quotedName(),
+		// the IN clause must be the last, because it is continued by the placeholders.
+		where(append(table.withNotDeleted(nil), quote(key.name)+" IN (?")),
+	)
+	sqlOrder := ")"
+	if !m.config.KeepKeyOrder {
+		sqlOrder += " ORDER BY " + quote(key.name)
+	}
+
+	fmt.Fprintf(w, "func Select%[1]sByPKs(ctx context.Context, queryer queryer, keys ...%[2]s) ([]*%[1]s, error) {\n", table.rawName, goType)
+	m.generateGoInstrument(w, table, "queryer", "Select"+table.rawName+"ByPKs")
+	fmt.Fprintf(w, "if len(keys) == 0 {\n return nil, nil \n}\n")
+	fmt.Fprintf(w, "const q1 = %q\n", sqlSelect)
+	fmt.Fprintf(w, "const q2 = %q\n", sqlOrder)
+	fmt.Fprintf(w, "q := make([]byte, 0, len(q1)+len(keys)*3+len(q2))\n")
+	fmt.Fprintf(w, "q = append(q, q1...)\n")
+	fmt.Fprintf(w, "args := make([]any, 0, len(keys))\n")
+	fmt.Fprintf(w, "for i, key := range keys {\n")
+	fmt.Fprintf(w, "if i > 0 {\n q = append(q, \", ?\"...) \n}\n")
+	fmt.Fprintf(w, "args = append(args, key)\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "q = append(q, q2...)\n")
+	fmt.Fprintf(w, "var ret []*%s\n", table.rawName)
+	fmt.Fprintf(w, "rows, err := queryer.QueryContext(ctx, string(q), args...)\n")
+	fmt.Fprintf(w, "if err != nil {\n return nil, err \n}\n")
+	fmt.Fprintf(w, "defer rows.Close()\n")
+	fmt.Fprintf(w, "for rows.Next() {\n")
+	fmt.Fprintf(w, "var v %s\n", table.rawName)
+	fmt.Fprintf(w, "if err := rows.Scan(%s); err != nil {\n return nil, err \n}\n", strings.Join(goFields, ", "))
+	fmt.Fprintf(w, "ret = append(ret, &v)\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "if err := rows.Err(); err != nil {\n return nil, err \n}\n")
+	if m.config.KeepKeyOrder {
+		fmt.Fprintf(w, "found := make(map[%s]*%s, len(ret))\n", goType, table.rawName)
+		fmt.Fprintf(w, "for _, v := range ret {\n found[%s(%s)] = v \n}\n", goType, key.keyValue("v"))
+		fmt.Fprintf(w, "ret = make([]*%s, 0, len(keys))\n", table.rawName)
+		fmt.Fprintf(w, "for _, key := range keys {\n")
+		fmt.Fprintf(w, "if v, ok := found[key]; ok {\n ret = append(ret, v) \n}\n")
+		fmt.Fprintf(w, "}\n")
+	}
+	fmt.Fprintf(w, "return ret, nil\n")
+	fmt.Fprintf(w, "}\n\n")
+}
+
+// generateGoTableSelectByUniqueIndexes generates the functions that select a row by the values of the unique indexes.
+// They are generated only for the unique indexes whose columns are basic types.
+func (m *Maker) generateGoTableSelectByUniqueIndexes(w io.Writer, table *table) {
+	fields := make([]string, 0, len(table.columns))
+	goFields := make([]string, 0, len(table.columns))
+	for _, c := range table.columns {
+		fields = append(fields, quote(c.name))
+		goFields = append(goFields, "&v."+c.goField)
+	}
+
+	generated := map[string]bool{}
+	for _, idx := range table.uniqueIndexes {
+		finder := finderOf(table, idx.columns)
+		if finder == nil || generated[finder.name] {
+			continue
+		}
+		generated[finder.name] = true
+
+		sqlSelect := fmt.Sprintf(
+			"SELECT %s FROM %s%s",
+			strings.Join(fields, ", "),
+			table.quotedName(),
+			where(table.withNotDeleted(finder.conditions)),
+		)
+		for _, lock := range m.config.lockingReads() {
+			fmt.Fprintf(w, "func Select%[1]sBy%[2]s%[3]s(ctx context.Context, queryer queryer, %[4]s) (*%[1]s, error) {\n", table.rawName, finder.name, lock.suffix, strings.Join(finder.params, ", "))
+			m.generateGoUseWriter(w, lock)
+			m.generateGoCacheStatements(w, "queryer")
+			m.generateGoInstrument(w, table, "queryer", "Select"+table.rawName+"By"+finder.name+lock.suffix)
+			fmt.Fprintf(w, "var v %s\n", table.rawName)
+			fmt.Fprintf(w, "row := queryer.QueryRowContext(ctx, %q, %s)\n", sqlSelect+lock.clause, strings.Join(finder.args, ", "))
+			fmt.Fprintf(w, "if err := row.Scan(%s); err != nil {\n return nil, err \n}\n", strings.Join(goFields, ", "))
+			fmt.Fprintf(w, "return &v, nil\n")
+			fmt.Fprintf(w, "}\n\n")
+		}
+	}
+}
+
+// generateGoTableSelectByIndexes generates the functions that select the rows by the values of the indexes.
+// They are generated only for the indexes whose columns are basic types.
+func (m *Maker) generateGoTableSelectByIndexes(w io.Writer, table *table) {
+	fields := make([]string, 0, len(table.columns))
+	goFields := make([]string, 0, len(table.columns))
+	for _, c := range table.columns {
+		fields = append(fields, quote(c.name))
+		goFields = append(goFields, "&v."+c.goField)
+	}
+	generated := map[string]bool{}
+	for _, idx := range table.indexes {
+		finder := finderOf(table, idx.columns)
+		if finder == nil || generated[finder.name] {
+			continue
+		}
+		generated[finder.name] = true
+
+		sqlSelect := fmt.Sprintf(
+			"SELECT %s FROM %s%s",
+			strings.Join(fields, ", "),
+			table.quotedName(),
+			where(table.withNotDeleted(finder.conditions)),
+		)
+		fmt.Fprintf(w, "func SelectAll%[1]sBy%[2]s(ctx context.Context, queryer queryer, %[3]s, opts ...SelectOption) ([]*%[1]s, error) {\n", table.rawName, finder.name, strings.Join(finder.params, ", "))
+		m.generateGoCacheStatements(w, "queryer")
+		m.generateGoInstrument(w, table, "queryer", "SelectAll"+table.rawName+"By"+finder.name)
+		fmt.Fprintf(w, "var o selectOptions\n")
+		fmt.Fprintf(w, "for _, opt := range opts {\n opt(&o) \n}\n")
+		fmt.Fprintf(w, "orderBy, err := o.orderBy%s()\n", table.rawName)
+		fmt.Fprintf(w, "if err != nil {\n return nil, err \n}\n")
+		fmt.Fprintf(w, "q, args := o.limitOffset(%q+orderBy, []any{%s})\n", sqlSelect, strings.Join(finder.args, ", "))
+		fmt.Fprintf(w, "var ret []*%s\n", table.rawName)
+		fmt.Fprintf(w, "rows, err := queryer.QueryContext(ctx, q, args...)\n")
+		fmt.Fprintf(w, "if err != nil {\n return nil, err \n}\n")
+		fmt.Fprintf(w, "defer rows.Close()\n")
+		fmt.Fprintf(w, "for rows.Next() {\n")
+		fmt.Fprintf(w, "var v %s\n", table.rawName)
+		fmt.Fprintf(w, "if err := rows.Scan(%s); err != nil {\n return nil, err \n}\n", strings.Join(goFields, ", "))
+		fmt.Fprintf(w, "ret = append(ret, &v)\n")
+		fmt.Fprintf(w, "}\n")
+		fmt.Fprintf(w, "if err := rows.Err(); err != nil {\n return nil, err \n}\n")
+		fmt.Fprintf(w, "return ret, nil\n")
+		fmt.Fprintf(w, "}\n\n")
+	}
+}
+
+// generateGoTableQuery generates the columns for the conditions of the query builder,
+// and the function that selects the rows by the condition.
+func (m *Maker) generateGoTableQuery(w io.Writer, table *table) {
+	columns := table.whereColumns(m.goPkgPath(), map[string]bool{})
+	fmt.Fprintf(w, "// %sWhere is the columns of %[1]s for the conditions of Query%[1]s.\n", table.rawName)
+	fmt.Fprintf(w, "var %sWhere = struct {\n", table.rawName)
+	for _, c := range columns {
+		fmt.Fprintf(w, "%s Column[%s]\n", c.column.rawName, c.typ)
+	}
+	fmt.Fprintf(w, "}{\n")
+	for _, c := range columns {
+		fmt.Fprintf(w, "%s: Column[%s]{name: %q},\n", c.column.rawName, c.typ, quote(c.column.name))
+	}
+	fmt.Fprintf(w, "}\n\n")
+
+	fields := make([]string, 0, len(table.columns))
+	goFields := make([]string, 0, len(table.columns))
+	for _, c := range table.columns {
+		fields = append(fields, quote(c.name))
+		goFields = append(goFields, "&v."+c.goField)
+	}
+	sqlSelect := fmt.Sprintf(
+		"SELECT %s FROM %s",
+		strings.Join(fields, ", "),
+		table.quotedName(),
+	)
+
+	fmt.Fprintf(w, "// Query%[1]s selects the rows that match the condition. The zero condition selects all the rows.\n", table.rawName)
+	fmt.Fprintf(w, "func Query%[1]s(ctx context.Context, queryer queryer, cond Condition, opts ...SelectOption) ([]*%[1]s, error) {\n", table.rawName)
+	m.generateGoInstrument(w, table, "queryer", "Query"+table.rawName)
+	fmt.Fprintf(w, "var o selectOptions\n")
+	fmt.Fprintf(w, "for _, opt := range opts {\n opt(&o) \n}\n")
+	fmt.Fprintf(w, "orderBy, err := o.orderBy%s()\n", table.rawName)
+	fmt.Fprintf(w, "if err != nil {\n return nil, err \n}\n")
+	if notDeleted := table.notDeleted(); notDeleted != "" {
+		fmt.Fprintf(w, "q := %q\n", sqlSelect+" WHERE "+notDeleted)
+		fmt.Fprintf(w, "if cond.query != \"\" {\n q += \" AND (\" + cond.query + \")\" \n}\n")
+	} else {
+		fmt.Fprintf(w, "q := %q\n", sqlSelect)
+		fmt.Fprintf(w, "if cond.query != \"\" {\n q += \" WHERE \" + cond.query \n}\n")
+	}
+	fmt.Fprintf(w, "q, args := o.limitOffset(q+orderBy, append([]any(nil), cond.args...))\n")
+	fmt.Fprintf(w, "var ret []*%s\n", table.rawName)
+	fmt.Fprintf(w, "rows, err := queryer.QueryContext(ctx, q, args...)\n")
+	fmt.Fprintf(w, "if err != nil {\n return nil, err \n}\n")
+	fmt.Fprintf(w, "defer rows.Close()\n")
+	fmt.Fprintf(w, "for rows.Next() {\n")
+	fmt.Fprintf(w, "var v %s\n", table.rawName)
+	fmt.Fprintf(w, "if err := rows.Scan(%s); err != nil {\n return nil, err \n}\n", strings.Join(goFields, ", "))
+	fmt.Fprintf(w, "ret = append(ret, &v)\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "if err := rows.Err(); err != nil {\n return nil, err \n}\n")
+	fmt.Fprintf(w, "return ret, nil\n")
+	fmt.Fprintf(w, "}\n\n")
+}
+
+// generateGoTableIterate generates the functions that iterate the rows of the table,
+// and the rows selected by the values of the indexes.
+func (m *Maker) generateGoTableIterate(w io.Writer, table *table) {
+	fields := make([]string, 0, len(table.columns))
+	goFields := make([]string, 0, len(table.columns))
+	for _, c := range table.columns {
+		fields = append(fields, quote(c.name))
+		goFields = append(goFields, "&v."+c.goField)
+	}
+	sqlSelect := fmt.Sprintf(
+		"SELECT %s FROM %s",
+		strings.Join(fields, ", "),
+		table.quotedName(),
+	)
+
+	generate := func(name string, params []string, conditions []string, args []string) {
+		q := sqlSelect + where(table.withNotDeleted(conditions))
+		params = append(params, "opts ...SelectOption")
+		fmt.Fprintf(w, "func IterateAll%[1]s%[2]s(ctx context.Context, queryer queryer, %[3]s) iter.Seq2[*%[1]s, error] {\n", table.rawName, name, strings.Join(params, ", "))
+		m.generateGoInstrument(w, table, "queryer", "IterateAll"+table.rawName+name)
+		fmt.Fprintf(w, "return func(yield func(*%s, error) bool) {\n", table.rawName)
+		fmt.Fprintf(w, "var o selectOptions\n")
+		fmt.Fprintf(w, "for _, opt := range opts {\n opt(&o) \n}\n")
+		fmt.Fprintf(w, "orderBy, err := o.orderBy%s()\n", table.rawName)
+		fmt.Fprintf(w, "if err != nil {\n yield(nil, err)\n return \n}\n")
+		if len(args) > 0 {
+			fmt.Fprintf(w, "q, args := o.limitOffset(%q+orderBy, []any{%s})\n", q, strings.Join(args, ", "))
+		} else {
+			fmt.Fprintf(w, "q, args := o.limitOffset(%q+orderBy, nil)\n", q)
+		}
+		fmt.Fprintf(w, "rows, err := queryer.QueryContext(ctx, q, args...)\n")
+		fmt.Fprintf(w, "if err != nil {\n yield(nil, err)\n return \n}\n")
+		fmt.Fprintf(w, "defer rows.Close()\n")
+		fmt.Fprintf(w, "for rows.Next() {\n")
+		fmt.Fprintf(w, "var v %s\n", table.rawName)
+		fmt.Fprintf(w, "if err := rows.Scan(%s); err != nil {\n yield(nil, err)\n return \n}\n", strings.Join(goFields, ", "))
+		fmt.Fprintf(w, "if !yield(&v, nil) {\n return \n}\n")
+		fmt.Fprintf(w, "}\n")
+		fmt.Fprintf(w, "if err := rows.Err(); err != nil {\n yield(nil, err) \n}\n")
+		fmt.Fprintf(w, "}\n")
+		fmt.Fprintf(w, "}\n\n")
+	}
+
+	generate("", nil, nil, nil)
+	generated := map[string]bool{}
+	for _, idx := range table.indexes {
+		finder := finderOf(table, idx.columns)
+		if finder == nil || generated[finder.name] {
+			continue
+		}
+		generated[finder.name] = true
+		generate("By"+finder.name, finder.params, finder.conditions, finder.args)
+	}
+}
+
+// generateGoTableForEach generates the function that walks the whole table in batches,
+// paging by the primary key instead of OFFSET.
+// It is generated only if the primary key columns are basic types.
+func (m *Maker) generateGoTableForEach(w io.Writer, table *table) {
+	finder := finderOf(table, table.primaryKey.columns)
+	if finder == nil {
+		return
+	}
+
+	fields := make([]string, 0, len(table.columns))
+	for _, c := range table.columns {
+		fields = append(fields, quote(c.name))
+	}
+	keys := make([]string, 0, len(table.primaryKey.columns))
+	placeholders := make([]string, 0, len(table.primaryKey.columns))
+	lastKeys := make([]string, 0, len(table.primaryKey.columns))
+	for _, name := range table.primaryKey.columns {
+		keys = append(keys, quote(name))
+		placeholders = append(placeholders, "?")
+		lastKeys = append(lastKeys, "last."+table.column(name).goField)
+	}
+	after := fmt.Sprintf("%s > ?", keys[0])
+	if len(keys) > 1 {
+		// the row constructor compares the columns in the order of the primary key.
+		after = fmt.Sprintf("(%s) > (%s)", strings.Join(keys, ", "), strings.Join(placeholders, ", "))
+	}
+	sqlSelect := fmt.Sprintf("SELECT %s FROM %s", strings.Join(fields, ", "), table.quotedName())
+	orderBy := fmt.Sprintf(" ORDER BY %s LIMIT ?", strings.Join(keys, ", "))
+
+	fmt.Fprintf(w, "// ForEach%[1]s calls fn with all the rows of %[2]s in the order of the primary key, batchSize rows at a time.\n", table.rawName, table.fullName())
+	fmt.Fprintf(w, "// Each batch is selected after the primary key of the last row of the previous batch, not by OFFSET,\n")
+	fmt.Fprintf(w, "// so it is suitable for backfills and data migrations that update the rows in fn.\n")
+	fmt.Fprintf(w, "// The rows are closed before fn is called. It stops and returns the error if fn returns an error.\n")
+	fmt.Fprintf(w, "func ForEach%[1]s(ctx context.Context, queryer queryer, batchSize int, fn func(batch []*%[1]s) error) error {\n", table.rawName)
+	m.generateGoInstrument(w, table, "queryer", "ForEach"+table.rawName)
+	fmt.Fprintf(w, "if batchSize <= 0 {\n return errors.New(\"batchSize must be positive\") \n}\n")
+	fmt.Fprintf(w, "var last *%s\n", table.rawName)
+	fmt.Fprintf(w, "for {\n")
+	fmt.Fprintf(w, "q := %q\n", sqlSelect+where(table.withNotDeleted(nil)))
+	fmt.Fprintf(w, "var args []any\n")
+	fmt.Fprintf(w, "if last != nil {\n")
+	fmt.Fprintf(w, "q = %q\n", sqlSelect+where(table.withNotDeleted([]string{after})))
+	fmt.Fprintf(w, "args = append(args, %s)\n", strings.Join(lastKeys, ", "))
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "q += %q\n", orderBy)
+	fmt.Fprintf(w, "args = append(args, batchSize)\n")
+	fmt.Fprintf(w, "rows, err := queryer.QueryContext(ctx, q, args...)\n")
+	fmt.Fprintf(w, "if err != nil {\n return err \n}\n")
+	fmt.Fprintf(w, "batch, err := Scan%sRows(rows)\n", table.rawName)
+	fmt.Fprintf(w, "if err != nil {\n return err \n}\n")
+	fmt.Fprintf(w, "if len(batch) == 0 {\n return nil \n}\n")
+	fmt.Fprintf(w, "if err := fn(batch); err != nil {\n return err \n}\n")
+	fmt.Fprintf(w, "if len(batch) < batchSize {\n return nil \n}\n")
+	fmt.Fprintf(w, "last = batch[len(batch)-1]\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "}\n\n")
+}
+
+// hasAutoNowColumns reports whether any table has the auto_now or auto_now_add columns.
+func (m *Maker) hasAutoNowColumns() bool {
+	for _, table := range m.tables {
+		if len(table.autoNowColumns(true)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// generateGoSetNow generates the code that sets the current time to the auto_now columns of the values,
+// and the auto_now_add columns too if insert is true.
+// If single is true, values is the name of a variable of a pointer to the struct, otherwise a slice of them.
+func (m *Maker) generateGoSetNow(w io.Writer, table *table, insert bool, values string, single bool) {
+	columns := table.autoNowColumns(insert)
+	if len(columns) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "now := Now()\n")
+	v := values
+	if !single {
+		v = "v"
+		fmt.Fprintf(w, "for _, v := range %s {\n", values)
+	}
+	for _, c := range columns {
+		fmt.Fprintf(w, "%s\n", c.nowAssignment(v))
+	}
+	if !single {
+		fmt.Fprintf(w, "}\n")
+	}
+}
+
+// hasRepositories reports whether any table has the generated repository.
+func (m *Maker) hasRepositories() bool {
+	if !m.config.GenerateRepositories {
+		return false
+	}
+	for _, table := range m.tables {
+		if table.primaryKey != nil && finderOf(table, table.primaryKey.columns) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// hasVersionColumns reports whether any table has the version column for optimistic locking.
+func (m *Maker) hasVersionColumns() bool {
+	for _, table := range m.tables {
+		if table.versionColumn() != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// hasCursorFinders reports whether any table has the functions that select the rows by the indexes with cursors.
+func (m *Maker) hasCursorFinders() bool {
+	for _, table := range m.tables {
+		if key, _ := table.primaryKeyGoType(); key == nil {
+			continue
+		}
+		for _, idx := range table.indexes {
+			if finderOf(table, idx.columns) != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// generateGoTableSelectByIndexesAfter generates the functions that select the rows by the values of the indexes,
+// and page them by the primary key.
+// They are generated only if the primary key is a single column of a basic type.
+func (m *Maker) generateGoTableSelectByIndexesAfter(w io.Writer, table *table) {
+	key, goType := table.primaryKeyGoType()
+	if key == nil {
+		return
+	}
+
+	fields := make([]string, 0, len(table.columns))
+	goFields := make([]string, 0, len(table.columns))
+	for _, c := range table.columns {
+		fields = append(fields, quote(c.name))
+		goFields = append(goFields, "&v."+c.goField)
+	}
+
+	generated := map[string]bool{}
+	for _, idx := range table.indexes {
+		finder := finderOf(table, idx.columns)
+		if finder == nil || generated[finder.name] {
+			continue
+		}
+		generated[finder.name] = true
+
+		sqlSelect := fmt.Sprintf(
+			"SELECT %s FROM %s%s",
+			strings.Join(fields, ", "),
+			table.quotedName(),
+			where(table.withNotDeleted(finder.conditions)),
+		)
+		fmt.Fprintf(w, "// SelectAll%[1]sBy%[2]sAfter selects the rows after the cursor, up to limit rows.\n", table.rawName, finder.name)
+		fmt.Fprintf(w, "// The empty cursor selects the first page. It returns the cursor for the next page, or the empty cursor if no rows remain.\n")
+		fmt.Fprintf(w, "func SelectAll%[1]sBy%[2]sAfter(ctx context.Context, queryer queryer, %[3]s, cursor string, limit int) ([]*%[1]s, string, error) {\n", table.rawName, finder.name, strings.Join(finder.params, ", "))
+		m.generateGoInstrument(w, table, "queryer", "SelectAll"+table.rawName+"By"+finder.name+"After")
+		fmt.Fprintf(w, "q := %q\n", sqlSelect)
+		fmt.Fprintf(w, "args := []any{%s}\n", strings.Join(finder.args, ", "))
+		fmt.Fprintf(w, "if cursor != \"\" {\n")
+		fmt.Fprintf(w, "var after %s\n", goType)
+		fmt.Fprintf(w, "if err := decodeCursor(cursor, &after); err != nil {\n return nil, \"\", err \n}\n")
+		fmt.Fprintf(w, "q += %q\n", fmt.Sprintf(" AND %s > ?", quote(key.name)))
+		fmt.Fprintf(w, "args = append(args, after)\n")
+		fmt.Fprintf(w, "}\n")
+		fmt.Fprintf(w, "q += %q\n", fmt.Sprintf(" ORDER BY %s LIMIT ?", quote(key.name)))
+		fmt.Fprintf(w, "args = append(args, limit)\n")
+		fmt.Fprintf(w, "var ret []*%s\n", table.rawName)
+		fmt.Fprintf(w, "rows, err := queryer.QueryContext(ctx, q, args...)\n")
+		fmt.Fprintf(w, "if err != nil {\n return nil, \"\", err \n}\n")
+		fmt.Fprintf(w, "defer rows.Close()\n")
+		fmt.Fprintf(w, "for rows.Next() {\n")
+		fmt.Fprintf(w, "var v %s\n", table.rawName)
+		fmt.Fprintf(w, "if err := rows.Scan(%s); err != nil {\n return nil, \"\", err \n}\n", strings.Join(goFields, ", "))
+		fmt.Fprintf(w, "ret = append(ret, &v)\n")
+		fmt.Fprintf(w, "}\n")
+		fmt.Fprintf(w, "if err := rows.Err(); err != nil {\n return nil, \"\", err \n}\n")
+		fmt.Fprintf(w, "if limit <= 0 || len(ret) < limit {\n return ret, \"\", nil \n}\n")
+		fmt.Fprintf(w, "next, err := encodeCursor(%s(%s))\n", goType, key.keyValue("ret[len(ret)-1]"))
+		fmt.Fprintf(w, "if err != nil {\n return nil, \"\", err \n}\n")
+		fmt.Fprintf(w, "return ret, next, nil\n")
+		fmt.Fprintf(w, "}\n\n")
+	}
+}
+
+// generateGoTableCountAndExists generates the functions that count the rows and check the existence of the rows
+// by the values of the primary key, the unique indexes and the indexes.
+// They are generated only for the keys whose columns are basic types.
+func (m *Maker) generateGoTableCountAndExists(w io.Writer, table *table) {
+	var keys [][]string
+	if table.primaryKey != nil {
+		keys = append(keys, table.primaryKey.columns)
+	}
+	for _, idx := range table.uniqueIndexes {
+		keys = append(keys, idx.columns)
+	}
+	for _, idx := range table.indexes {
+		keys = append(keys, idx.columns)
+	}
+
+	generated := map[string]bool{}
+	for _, key := range keys {
+		finder := finderOf(table, key)
+		if finder == nil || generated[finder.name] {
+			continue
+		}
+		generated[finder.name] = true
+
+		cond := where(table.withNotDeleted(finder.conditions))
+		count := fmt.Sprintf("SELECT COUNT(*) FROM %s%s", table.quotedName(), cond)
+		exists := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s%s)", table.quotedName(), cond)
+		params := strings.Join(finder.params, ", ")
+		args := strings.Join(finder.args, ", ")
+
+		fmt.Fprintf(w, "func Count%[1]sBy%[2]s(ctx context.Context, queryer queryer, %[3]s) (int64, error) {\n", table.rawName, finder.name, params)
+		m.generateGoCacheStatements(w, "queryer")
+		m.generateGoInstrument(w, table, "queryer", "Count"+table.rawName+"By"+finder.name)
+		fmt.Fprintf(w, "var n int64\n")
+		fmt.Fprintf(w, "row := queryer.QueryRowContext(ctx, %q, %s)\n", count, args)
+		fmt.Fprintf(w, "if err := row.Scan(&n); err != nil {\n return 0, err \n}\n")
+		fmt.Fprintf(w, "return n, nil\n")
+		fmt.Fprintf(w, "}\n\n")
+
+		fmt.Fprintf(w, "func Exists%[1]sBy%[2]s(ctx context.Context, queryer queryer, %[3]s) (bool, error) {\n", table.rawName, finder.name, params)
+		m.generateGoCacheStatements(w, "queryer")
+		m.generateGoInstrument(w, table, "queryer", "Exists"+table.rawName+"By"+finder.name)
+		fmt.Fprintf(w, "var exists bool\n")
+		fmt.Fprintf(w, "row := queryer.QueryRowContext(ctx, %q, %s)\n", exists, args)
+		fmt.Fprintf(w, "if err := row.Scan(&exists); err != nil {\n return false, err \n}\n")
+		fmt.Fprintf(w, "return exists, nil\n")
+		fmt.Fprintf(w, "}\n\n")
+	}
+}
+
+// finder is a set of the parts of the generated functions that look up rows by the columns.
+type finder struct {
+	name       string   // e.g. "TenantIDAndEmail"
+	params     []string // e.g. "tenantID int64", "email string"
+	args       []string // e.g. "tenantID", "email"
+	conditions []string // e.g. "`tenant_id` = ?", "`email` = ?"
+}
+
+// finderOf returns the finder that looks up rows by the columns.
+// It returns nil if any column is not a basic type.
+func finderOf(table *table, columns []string) *finder {
+	f := &finder{}
+	names := make([]string, 0, len(columns))
+	for _, name := range columns {
+		col := table.column(name)
+		if col == nil || col.goType() == "" {
+			return nil
+		}
+		param := goParamName(col.rawName)
+		names = append(names, col.rawName)
+		f.params = append(f.params, param+" "+col.goType())
+		f.args = append(f.args, param)
+		f.conditions = append(f.conditions, fmt.Sprintf("%s = ?", quote(col.name)))
+	}
+	f.name = strings.Join(names, "And")
+	return f
+}
+
+// goParamName converts the field name into the parameter name, e.g. "TenantID" into "tenantID".
+func goParamName(name string) string {
+	runes := []rune(name)
+	i := 0
+	for i < len(runes) && unicode.IsUpper(runes[i]) {
+		i++
+	}
+	if i > 1 && i < len(runes) {
+		// keep the first letter of the next word, e.g. "URLPath" into "urlPath".
+		i--
+	}
+	for j := 0; j < i; j++ {
+		runes[j] = unicode.ToLower(runes[j])
+	}
+	param := string(runes)
+	switch param {
+	case "ctx", "queryer", "execer", "v", "row", "rows", "err", "ret", "q", "o", "opt", "opts", "args", "n", "exists", "cursor", "limit", "after", "next", "orderBy", "yield", "changes", "now":
+		// avoid conflicts with the variables in the generated functions.
+		return param + "_"
+	}
+	if token.IsKeyword(param) {
+		return param + "_"
+	}
+	return param
+}
+
+func (m *Maker) generateGoTableSelectAll(w io.Writer, table *table) {
+	fields := make([]string, 0, len(table.columns))
+	goFields := make([]string, 0, len(table.columns))
+	for _, c := range table.columns {
+		fields = append(fields, quote(c.name))
+		goFields = append(goFields, "&v."+c.goField)
+	}
+	sqlSelect := fmt.Sprintf(
+		"SELECT %s FROM %s",
+		strings.Join(fields, ", "),
+		table.quotedName(),
+	)
+	generate := func(suffix, q string) {
+		fmt.Fprintf(w, "func SelectAll%[1]s%[2]s(ctx context.Context, queryer queryer, opts ...SelectOption) ([]*%[1]s, error) {\n", table.rawName, suffix)
+		m.generateGoCacheStatements(w, "queryer")
+		m.generateGoInstrument(w, table, "queryer", "SelectAll"+table.rawName+suffix)
+		fmt.Fprintf(w, "var o selectOptions\n")
+		fmt.Fprintf(w, "for _, opt := range opts {\n opt(&o) \n}\n")
+		fmt.Fprintf(w, "orderBy, err := o.orderBy%s()\n", table.rawName)
+		fmt.Fprintf(w, "if err != nil {\n return nil, err \n}\n")
+		fmt.Fprintf(w, "q, args := o.limitOffset(%q+orderBy, nil)\n", q)
+		fmt.Fprintf(w, "var ret []*%[1]s\n", table.rawName)
+		fmt.Fprintf(w, "rows, err := queryer.QueryContext(ctx, q, args...)\n")
+		fmt.Fprintf(w, "if err != nil {\n return nil, err \n}\n")
+		fmt.Fprintf(w, "defer rows.Close()\n")
+		fmt.Fprintf(w, "for rows.Next() {\n")
+		fmt.Fprintf(w, "var v %s\n", table.rawName)
+		fmt.Fprintf(w, "if err := rows.Scan(%s); err != nil {\n return nil, err \n}\n", strings.Join(goFields, ", "))
+		fmt.Fprintf(w, "ret = append(ret, &v)")
+		fmt.Fprintf(w, "}\n")
+		fmt.Fprintf(w, "if err := rows.Err(); err != nil {\n return nil, err \n}\n")
+		fmt.Fprintf(w, "return ret, nil\n")
+		fmt.Fprintf(w, "}\n\n")
+	}
+	generate("", sqlSelect+where(table.withNotDeleted(nil)))
+	if table.softDeleteColumn() != nil {
+		generate("WithDeleted", sqlSelect)
+	}
+}
+
+// generateGoTableOrderBy generates the method that returns the ORDER BY clause for the select options.
+// The rows are sorted by the primary key by default,
+// and only the columns of the primary key and the indexes are allowed to sort the rows.
+func (m *Maker) generateGoTableOrderBy(w io.Writer, table *table) {
+	var pk []string
+	if table.primaryKey != nil {
+		pk = table.primaryKey.columns
+	}
+	orderBy := func(columns []string, desc bool) string {
+		if len(columns) == 0 {
+			return ""
+		}
+		keys := make([]string, 0, len(columns))
+		for _, col := range columns {
+			if desc {
+				keys = append(keys, quote(col)+" DESC")
+			} else {
+				keys = append(keys, quote(col))
+			}
+		}
+		return " ORDER BY " + strings.Join(keys, ", ")
+	}
+
+	fmt.Fprintf(w, "func (o *selectOptions) orderBy%s() (string, error) {\n", table.rawName)
+	fmt.Fprintf(w, "switch o.column {\n")
+	fmt.Fprintf(w, "case \"\":\n")
+	fmt.Fprintf(w, "if o.desc {\n return %q, nil \n}\n", orderBy(pk, true))
+	fmt.Fprintf(w, "return %q, nil\n", orderBy(pk, false))
+	for _, c := range table.orderableColumns() {
+		// break ties by the primary key.
+		columns := []string{c.name}
+		for _, col := range pk {
+			if col != c.name {
+				columns = append(columns, col)
+			}
+		}
+		fmt.Fprintf(w, "case %q:\n", c.name)
+		fmt.Fprintf(w, "if o.desc {\n return %q, nil \n}\n", orderBy(columns, true))
+		fmt.Fprintf(w, "return %q, nil\n", orderBy(columns, false))
+	}
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "return \"\", ErrInvalidOrderBy\n")
+	fmt.Fprintf(w, "}\n\n")
+}
+
+func (m *Maker) generateGoTableUpdate(w io.Writer, table *table) {
+	setFields := make([]string, 0, len(table.columns))
+	goFields := make([]string, 0, len(table.columns))
+	params := make([]string, 0, len(table.primaryKey.columns))
+	conditions := make([]string, 0, len(table.primaryKey.columns))
+
+LOOP:
+	for _, c := range table.columns {
+		for _, key := range table.primaryKey.columns {
+			if key == c.name {
+				params = append(params, fmt.Sprintf("value.%s", c.goField))
+				conditions = append(conditions, fmt.Sprintf("%s = ?", quote(c.name)))
+				continue LOOP
+			}
+		}
+		if c.version {
+			continue
+		}
+		setFields = append(setFields, fmt.Sprintf("%s = ?", quote(c.name)))
+		goFields = append(goFields, "value."+c.goField)
+	}
+	version := table.versionColumn()
+	if version != nil {
+		setFields = append(setFields, fmt.Sprintf("%[1]s = %[1]s + 1", quote(version.name)))
+		params = append(params, "value."+version.goField)
+		conditions = append(conditions, fmt.Sprintf("%s = ?", quote(version.name)))
+	}
+
+	update := fmt.Sprintf(
+		"UPDATE %s SET %s WHERE %s",
+		table.quotedName(),
+		strings.Join(setFields, ", "),
+		strings.Join(conditions, " AND "),
+	)
+	fmt.Fprintf(w, "func Update%[1]s(ctx context.Context, execer execer, values ...*%[1]s) error {\n", table.rawName)
+	m.generateGoCallHooks(w, table, "Update", "values", "Update"+table.rawName+"(ctx, execer, values...)", "")
+	m.generateGoInstrument(w, table, "execer", "Update"+table.rawName)
+	m.generateGoRetry(w)
+	if len(setFields) != 0 {
+		m.generateGoSetNow(w, table, false, "values", false)
+		fmt.Fprintf(w, "stmt, err := %s\n", m.goPrepare(strconv.Quote(update)))
+		fmt.Fprintf(w, "if err != nil {\n")
+		fmt.Fprintf(w, "return err\n")
+		fmt.Fprintf(w, "}\n")
+		fmt.Fprintf(w, "defer stmt.Close()\n")
+		fmt.Fprintf(w, "for _, value := range values {\n")
+		if version != nil {
+			fmt.Fprintf(w, "result, err := stmt.ExecContext(ctx, %s)\n", strings.Join(append(goFields, params...), ", "))
+			fmt.Fprintf(w, "if err != nil {\n return err \n}\n")
+			fmt.Fprintf(w, "n, err := result.RowsAffected()\n")
+			fmt.Fprintf(w, "if err != nil {\n return err \n}\n")
+			fmt.Fprintf(w, "if n == 0 {\n return &ConflictError{Table: %q} \n}\n", table.fullName())
+			fmt.Fprintf(w, "value.%s++\n", version.goField)
+		} else {
+			fmt.Fprintf(w, "if _, err := stmt.ExecContext(ctx, %s, %s); err != nil {\n", strings.Join(goFields, ", "), strings.Join(params, ", "))
+			fmt.Fprintf(w, "return err\n")
+			fmt.Fprintf(w, "}\n")
+		}
+		fmt.Fprintf(w, "}\n")
+	}
+	fmt.Fprintf(w, "return nil\n")
+	fmt.Fprintf(w, "}\n\n")
+}
+
+// typedColumn is a column with the type expression in the generated Go source code.
+type typedColumn struct {
+	column *column
+	typ    string // the type expression of the column, e.g. "time.Time".
+}
+
+// changeColumns returns the columns that Update<Table>Columns can update.
+// The primary key columns and the columns of the types
+// that can't be referred from the package pkgPath are excluded.
+func (t *table) changeColumns(pkgPath string, imports map[string]bool) []typedColumn {
+	var ret []typedColumn
+LOOP:
+	for _, c := range t.columns {
+		for _, key := range t.primaryKey.columns {
+			if key == c.name {
+				continue LOOP
+			}
+		}
+		if c.version || c.autoNow {
+			continue
+		}
+		typ := c.goTypeExpr(pkgPath, imports)
+		if typ == "" {
+			continue
+		}
+		ret = append(ret, typedColumn{column: c, typ: typ})
+	}
+	return ret
+}
+
+// goPkgPath returns the import path of the package of the structs,
+// that the generated Go source code belongs to.
+func (m *Maker) goPkgPath() string {
+	if len(m.structs) == 0 {
+		return ""
+	}
+	return indirect(reflect.TypeOf(m.structs[0])).PkgPath()
+}
+
+// whereColumns returns the columns that the query builder can build the conditions with.
+// The columns of the types that can't be referred from the package pkgPath are excluded.
+func (t *table) whereColumns(pkgPath string, imports map[string]bool) []typedColumn {
+	var ret []typedColumn
+	for _, c := range t.columns {
+		typ := c.goTypeExpr(pkgPath, imports)
+		if typ == "" {
+			continue
+		}
+		ret = append(ret, typedColumn{column: c, typ: typ})
+	}
+	return ret
+}
+
+// typeImports returns the packages that the types of the columns in the generated Go source code refer to.
+func (m *Maker) typeImports() map[string]bool {
+	imports := map[string]bool{}
+	for _, table := range m.tables {
+		if m.config.GenerateQueryBuilder {
+			table.whereColumns(m.goPkgPath(), imports)
+		}
+		if table.primaryKey == nil || finderOf(table, table.primaryKey.columns) == nil {
+			continue
+		}
+		table.changeColumns(m.goPkgPath(), imports)
+		for _, c := range table.columns {
+			c.jsonValueType(m.goPkgPath(), imports)
+		}
+	}
+	return imports
+}
+
+// generateGoTableUpdateColumns generates the function that updates only the given columns of the row.
+func (m *Maker) generateGoTableUpdateColumns(w io.Writer, table *table) {
+	finder := finderOf(table, table.primaryKey.columns)
+	if finder == nil {
+		return
+	}
+	columns := table.changeColumns(m.goPkgPath(), map[string]bool{})
+	if len(columns) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "// %sChanges is the changes of the columns for Update%[1]sColumns.\n", table.rawName)
+	fmt.Fprintf(w, "// The nil fields are not updated.\n")
+	fmt.Fprintf(w, "type %sChanges struct {\n", table.rawName)
+	for _, c := range columns {
+		fmt.Fprintf(w, "%s *%s\n", c.column.rawName, c.typ)
+	}
+	fmt.Fprintf(w, "}\n\n")
+
+	fmt.Fprintf(w, "func Update%[1]sColumns(ctx context.Context, execer execer, %[2]s, changes %[1]sChanges) error {\n", table.rawName, strings.Join(finder.params, ", "))
+	m.generateGoInstrument(w, table, "execer", "Update"+table.rawName+"Columns")
+	m.generateGoRetry(w)
+	m.generateGoChangesSet(w, table, columns, "nil")
+	fmt.Fprintf(w, "q += %q\n", " WHERE "+strings.Join(finder.conditions, " AND "))
+	fmt.Fprintf(w, "args = append(args, %s)\n", strings.Join(finder.args, ", "))
+	fmt.Fprintf(w, "_, err := execer.ExecContext(ctx, q, args...)\n")
+	fmt.Fprintf(w, "return err\n")
+	fmt.Fprintf(w, "}\n\n")
+
+	m.generateGoTableUpdateColumnsByIndexes(w, table, columns)
+}
+
+// generateGoChangesSet generates the code that builds the UPDATE statement q and its arguments args
+// from the non-nil fields of changes. If no fields are set, the function returns zero.
+func (m *Maker) generateGoChangesSet(w io.Writer, table *table, columns []typedColumn, zero string) {
+	fmt.Fprintf(w, "q := %q\n", "UPDATE "+table.quotedName()+" SET ")
+	fmt.Fprintf(w, "var args []any\n")
+	for _, c := range columns {
+		fmt.Fprintf(w, "if changes.%s != nil {\n", c.column.rawName)
+		fmt.Fprintf(w, "if len(args) > 0 {\n q += \", \" \n}\n")
+		fmt.Fprintf(w, "q += %q\n", quote(c.column.name)+" = ?")
+		fmt.Fprintf(w, "args = append(args, *changes.%s)\n", c.column.rawName)
+		fmt.Fprintf(w, "}\n")
+	}
+	fmt.Fprintf(w, "if len(args) == 0 {\n return %s \n}\n", zero)
+	if columns := table.autoNowColumns(false); len(columns) > 0 {
+		fmt.Fprintf(w, "now := Now()\n")
+		for _, c := range columns {
+			fmt.Fprintf(w, "q += %q\n", ", "+quote(c.name)+" = ?")
+			fmt.Fprintf(w, "args = append(args, now)\n")
+		}
+	}
+	if version := table.versionColumn(); version != nil {
+		fmt.Fprintf(w, "q += %q\n", fmt.Sprintf(", %[1]s = %[1]s + 1", quote(version.name)))
+	}
+}
+
+// generateGoTableUpdateColumnsByIndexes generates the functions that update the columns set in the changes
+// of all the rows by the values of the unique indexes and the indexes, and return the number of the updated rows.
+// They are generated only for the indexes whose columns are basic types.
+func (m *Maker) generateGoTableUpdateColumnsByIndexes(w io.Writer, table *table, columns []typedColumn) {
+	var keys [][]string
+	for _, idx := range table.uniqueIndexes {
+		keys = append(keys, idx.columns)
+	}
+	for _, idx := range table.indexes {
+		keys = append(keys, idx.columns)
+	}
+
+	generated := map[string]bool{}
+	for _, key := range keys {
+		finder := finderOf(table, key)
+		if finder == nil || generated[finder.name] {
+			continue
+		}
+		generated[finder.name] = true
+
+		name := "Update" + table.rawName + "ColumnsBy" + finder.name
+		fmt.Fprintf(w, "// %s updates the columns set in changes of the rows by the values of the index,\n", name)
+		if m.config.RequireBulkUpdateLimit {
+			fmt.Fprintf(w, "// up to limit rows, and returns the number of the changed rows. The limit must be positive.\n")
+		} else {
+			fmt.Fprintf(w, "// up to limit rows if limit is positive, and returns the number of the changed rows.\n")
+		}
+		fmt.Fprintf(w, "func %s(ctx context.Context, execer execer, %s, changes %sChanges, limit int) (int64, error) {\n", name, strings.Join(finder.params, ", "), table.rawName)
+		if m.config.RequireBulkUpdateLimit {
+			fmt.Fprintf(w, "if limit <= 0 {\n return 0, errors.New(%q) \n}\n", name+": the limit must be positive")
+		}
+		m.generateGoInstrument(w, table, "execer", name)
+		m.generateGoRetry(w)
+		m.generateGoChangesSet(w, table, columns, "0, nil")
+		fmt.Fprintf(w, "q += %q\n", where(table.withNotDeleted(finder.conditions)))
+		fmt.Fprintf(w, "args = append(args, %s)\n", strings.Join(finder.args, ", "))
+		fmt.Fprintf(w, "if limit > 0 {\n")
+		fmt.Fprintf(w, "q += \" LIMIT ?\"\n")
+		fmt.Fprintf(w, "args = append(args, limit)\n")
+		fmt.Fprintf(w, "}\n")
+		fmt.Fprintf(w, "result, err := execer.ExecContext(ctx, q, args...)\n")
+		fmt.Fprintf(w, "if err != nil {\n return 0, err \n}\n")
+		fmt.Fprintf(w, "return result.RowsAffected()\n")
+		fmt.Fprintf(w, "}\n\n")
+	}
+}
+
+func (m *Maker) generateGoTableUpsert(w io.Writer, table *table) {
+	columns := make([]string, 0, len(table.columns))
+	placeholders := make([]string, 0, len(table.columns))
+	goFields := make([]string, 0, len(table.columns))
+	updates := make([]string, 0, len(table.columns))
+
+LOOP:
+	for _, c := range table.columns {
+		columns = append(columns, quote(c.name))
+		placeholders = append(placeholders, "?")
+		goFields = append(goFields, "value."+c.goField)
+		if c.noUpsert || (c.autoNowAdd && !c.autoNow) {
+			continue
+		}
+		for _, key := range table.primaryKey.columns {
+			if key == c.name {
+				continue LOOP
+			}
+		}
+		if c.version {
+			updates = append(updates, fmt.Sprintf("%[1]s = %[1]s + 1", quote(c.name)))
+			continue
+		}
+		updates = append(updates, fmt.Sprintf("%[1]s = `new`.%[1]s", quote(c.name)))
+	}
+	if len(updates) == 0 {
+		// nothing to update, but ON DUPLICATE KEY UPDATE requires at least one assignment.
+		key := quote(table.primaryKey.columns[0])
+		updates = append(updates, fmt.Sprintf("%[1]s = `new`.%[1]s", key))
+	}
+
+	// https://dev.mysql.com/doc/refman/8.0/en/insert-on-duplicate.html
+	upsert := fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES (%s) AS `new` ON DUPLICATE KEY UPDATE %s",
+		table.quotedName(),
+		strings.Join(columns, ", "),
+		strings.Join(placeholders, ", "),
+		strings.Join(updates, ", "),
+	)
+	fmt.Fprintf(w, "func Upsert%[1]s(ctx context.Context, execer execer, values ...*%[1]s) error {\n", table.rawName)
+	m.generateGoCallHooks(w, table, "Upsert", "values", "Upsert"+table.rawName+"(ctx, execer, values...)", "")
+	m.generateGoInstrument(w, table, "execer", "Upsert"+table.rawName)
+	m.generateGoRetry(w)
+	m.generateGoSetNow(w, table, true, "values", false)
+	fmt.Fprintf(w, "stmt, err := %s\n", m.goPrepare(strconv.Quote(upsert)))
+	fmt.Fprintf(w, "if err != nil {\n")
+	fmt.Fprintf(w, "return err\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "defer stmt.Close()\n")
+	fmt.Fprintf(w, "for _, value := range values {\n")
+	fmt.Fprintf(w, "if _, err := stmt.ExecContext(ctx, %s); err != nil {\n", strings.Join(goFields, ", "))
+	fmt.Fprintf(w, "return err\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "return nil\n")
+	fmt.Fprintf(w, "}\n\n")
+
+	m.generateGoTableBulkUpsert(w, table, columns, placeholders, updates)
+}
+
+// generateGoTableBulkUpsert generates the function that upserts the values by the multi-row statements,
+// splitting them into the chunks as the Insert function does.
+func (m *Maker) generateGoTableBulkUpsert(w io.Writer, table *table, columns, placeholders, updates []string) {
+	// https://stackoverflow.com/questions/18100782/import-of-50k-records-in-mysql-gives-general-error-1390-prepared-statement-con
+	const maxPlaceholderCount = 65535
+	maxStructCount := withDefault(m.config.InsertChunkSize, 32)
+	if n := maxPlaceholderCount / len(placeholders); maxStructCount > n {
+		maxStructCount = n
+	}
+
+	values := make([]string, 0, len(table.columns))
+	for _, c := range table.columns {
+		values = append(values, "v."+c.goField)
+	}
+	row := ", (" + strings.Join(placeholders, ", ") + ")"
+	insert := "INSERT INTO " + table.quotedName() + " (" + strings.Join(columns, ", ") + ") VALUES (" + strings.Join(placeholders, ", ") + ")"
+	onDuplicate := " AS `new` ON DUPLICATE KEY UPDATE " + strings.Join(updates, ", ")
+	name := "BulkUpsert" + table.rawName
+
+	fmt.Fprintf(w, "// %s inserts or updates the values by the multi-row statements, up to %d rows per statement.\n", name, maxStructCount)
+	fmt.Fprintf(w, "// It is faster than Upsert%s for many values, but the rows are not upserted atomically if they are split into multiple statements.\n", table.rawName)
+	fmt.Fprintf(w, "func %s(ctx context.Context, execer execer, values ...*%s) error {\n", name, table.rawName)
+	fmt.Fprintf(w, "const q1 = %q\n", insert)
+	fmt.Fprintf(w, "const q2 = %q\n", strings.Repeat(row, maxStructCount-1))
+	fmt.Fprintf(w, "const q3 = %q\n", onDuplicate)
+	fmt.Fprintf(w, "const fieldCount = %d\n", len(placeholders))
+	fmt.Fprintf(w, "const maxStructCount = %d\n", maxStructCount)
+	m.generateGoCallHooks(w, table, "Upsert", "values", name+"(ctx, execer, values...)", "")
+	m.generateGoTableInsertTx(w, name)
+	m.generateGoInstrument(w, table, "execer", name)
+	m.generateGoRetry(w)
+	m.generateGoSetNow(w, table, true, "values", false)
 
 	fmt.Fprintf(w, `var args []any
 	if len(values) >= maxStructCount {
 		args = make([]any, 0, maxStructCount*fieldCount)
 		err := func() error {
-			stmt, err := execer.PrepareContext(ctx, q)
+			stmt, err := %[3]s
 			if err != nil {
 				return err
 			}
@@ -538,120 +3175,390 @@ func (m *Maker) generateGoTableInsert(w io.Writer, table *table) {
 	for _, v := range values {
 		args = append(args, %[1]s)
 	}
-	if _, err := execer.ExecContext(ctx, q[:len(values)*%[2]d+%[3]d], args...); err != nil {
+	if _, err := execer.ExecContext(ctx, q1+q2[:(len(values)-1)*%[2]d]+q3, args...); err != nil {
 		return err
 	}
 	return nil
 }
 
-`, strings.Join(values, ", "), len(strPlaceholders), len(insert)-len(strPlaceholders))
+`, strings.Join(values, ", "), len(row), m.goPrepare("q1+q2+q3"))
 }
 
-func (m *Maker) generateGoTableSelect(w io.Writer, table *table) {
-	fields := make([]string, 0, len(table.columns))
+// generatesReplace reports whether the Replace function is generated for the table.
+func (c *Config) generatesReplace(table *table) bool {
+	for _, pattern := range c.GenerateReplace {
+		if ok, _ := path.Match(pattern, table.rawName); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Maker) generateGoTableReplace(w io.Writer, table *table) {
+	columns := make([]string, 0, len(table.columns))
+	placeholders := make([]string, 0, len(table.columns))
 	goFields := make([]string, 0, len(table.columns))
+	for _, c := range table.columns {
+		columns = append(columns, quote(c.name))
+		placeholders = append(placeholders, "?")
+		goFields = append(goFields, "value."+c.goField)
+	}
+
+	// https://dev.mysql.com/doc/refman/8.0/en/replace.html
+	replace := fmt.Sprintf(
+		"REPLACE INTO %s (%s) VALUES (%s)",
+		table.quotedName(),
+		strings.Join(columns, ", "),
+		strings.Join(placeholders, ", "),
+	)
+	fmt.Fprintf(w, "func Replace%[1]s(ctx context.Context, execer execer, values ...*%[1]s) error {\n", table.rawName)
+	m.generateGoCallHooks(w, table, "Upsert", "values", "Replace"+table.rawName+"(ctx, execer, values...)", "")
+	m.generateGoInstrument(w, table, "execer", "Replace"+table.rawName)
+	m.generateGoRetry(w)
+	m.generateGoSetNow(w, table, true, "values", false)
+	fmt.Fprintf(w, "stmt, err := %s\n", m.goPrepare(strconv.Quote(replace)))
+	fmt.Fprintf(w, "if err != nil {\n")
+	fmt.Fprintf(w, "return err\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "defer stmt.Close()\n")
+	fmt.Fprintf(w, "for _, value := range values {\n")
+	fmt.Fprintf(w, "if _, err := stmt.ExecContext(ctx, %s); err != nil {\n", strings.Join(goFields, ", "))
+	fmt.Fprintf(w, "return err\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "return nil\n")
+	fmt.Fprintf(w, "}\n\n")
+}
+
+func (m *Maker) generateGoTableDelete(w io.Writer, table *table) {
 	params := make([]string, 0, len(table.primaryKey.columns))
 	conditions := make([]string, 0, len(table.primaryKey.columns))
 	for _, c := range table.columns {
-		fields = append(fields, quote(c.name))
-		goFields = append(goFields, "&v."+c.rawName)
 		for _, key := range table.primaryKey.columns {
 			if key == c.name {
-				params = append(params, fmt.Sprintf("primaryKeys.%s", c.rawName))
+				params = append(params, fmt.Sprintf("value.%s", c.goField))
 				conditions = append(conditions, fmt.Sprintf("%s = ?", quote(c.name)))
 			}
 		}
 	}
 
-	sqlSelect := fmt.Sprintf(
-		"SELECT %s FROM %s WHERE %s",
-		strings.Join(fields, ", "),
-		quote(table.name),
+	del := fmt.Sprintf(
+		"DELETE FROM %s WHERE %s",
+		table.quotedName(),
 		strings.Join(conditions, " AND "),
 	)
-	fmt.Fprintf(w, "func Select%[1]s(ctx context.Context, queryer queryer, primaryKeys *%[1]s) (*%[1]s, error) {\n", table.rawName)
-	fmt.Fprintf(w, "var v %s\n", table.rawName)
-	fmt.Fprintf(w, "row := queryer.QueryRowContext(ctx, %q, %s)\n", sqlSelect, strings.Join(params, ", "))
-	fmt.Fprintf(w, "if err := row.Scan(%s); err != nil {\n return nil, err \n}\n", strings.Join(goFields, ", "))
-	fmt.Fprintf(w, "return &v, nil\n")
+	softDelete := table.softDeleteColumn()
+	if softDelete == nil {
+		m.generateGoTableDeleteFunc(w, table, "Delete", del, params)
+		return
+	}
+	update := fmt.Sprintf(
+		"UPDATE %s SET %s = %s%s",
+		table.quotedName(),
+		quote(softDelete.name),
+		softDelete.currentTimestamp(),
+		where(table.withNotDeleted(conditions)),
+	)
+	m.generateGoTableDeleteFunc(w, table, "Delete", update, params)
+	m.generateGoTableDeleteFunc(w, table, "HardDelete", del, params)
+}
+
+// generateGoTableDeleteFunc generates the function that executes the query del for each value,
+// and returns sql.ErrNoRows if no row is affected.
+func (m *Maker) generateGoTableDeleteFunc(w io.Writer, table *table, name, del string, params []string) {
+	fmt.Fprintf(w, "func %[2]s%[1]s(ctx context.Context, execer execer, values ...*%[1]s) error {\n", table.rawName, name)
+	m.generateGoCallHooks(w, table, "Delete", "values", name+table.rawName+"(ctx, execer, values...)", "")
+	m.generateGoInstrument(w, table, "execer", name+table.rawName)
+	m.generateGoRetry(w)
+	fmt.Fprintf(w, "stmt, err := %s\n", m.goPrepare(strconv.Quote(del)))
+	fmt.Fprintf(w, "if err != nil {\n")
+	fmt.Fprintf(w, "return err\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "defer stmt.Close()\n")
+	fmt.Fprintf(w, "for _, value := range values {\n")
+	fmt.Fprintf(w, "result, err := stmt.ExecContext(ctx, %s)\n", strings.Join(params, ", "))
+	fmt.Fprintf(w, "if err != nil {\n")
+	fmt.Fprintf(w, "return err\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "if n, err := result.RowsAffected(); err != nil {\n")
+	fmt.Fprintf(w, "return err\n")
+	fmt.Fprintf(w, "} else if n == 0 {\n")
+	fmt.Fprintf(w, "return sql.ErrNoRows\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "return nil\n")
 	fmt.Fprintf(w, "}\n\n")
 }
 
-func (m *Maker) generateGoTableSelectAll(w io.Writer, table *table) {
-	fields := make([]string, 0, len(table.columns))
-	goFields := make([]string, 0, len(table.columns))
-	for _, c := range table.columns {
-		fields = append(fields, quote(c.name))
-		goFields = append(goFields, "&v."+c.rawName)
-	}
-	keys := make([]string, 0, len(table.primaryKey.columns))
-	for _, key := range table.primaryKey.columns {
-		keys = append(keys, quote(key))
+// generateGoTableDeleteByPK generates the function that deletes the rows by the values of the primary key.
+// It is generated only for the primary keys of a single column of a basic type.
+func (m *Maker) generateGoTableDeleteByPK(w io.Writer, table *table) {
+	key, goType := table.primaryKeyGoType()
+	if key == nil {
+		return
 	}
 
-	sqlSelect := fmt.Sprintf(
-		"SELECT %s FROM %s ORDER BY %s",
-		strings.Join(fields, ", "),
-		quote(table.name),
-		strings.Join(keys, ", "),
+	del := fmt.Sprintf("DELETE FROM %s WHERE %s = ?", table.quotedName(), quote(key.name))
+	softDelete := table.softDeleteColumn()
+	if softDelete == nil {
+		m.generateGoTableDeleteByPKFunc(w, table, "Delete", del, goType)
+		return
+	}
+	update := fmt.Sprintf(
+		"UPDATE %s SET %s = %s%s",
+		table.quotedName(),
+		quote(softDelete.name),
+		softDelete.currentTimestamp(),
+		where(table.withNotDeleted([]string{quote(key.name) + " = ?"})),
 	)
-	fmt.Fprintf(w, "func SelectAll%[1]s(ctx context.Context, queryer queryer) ([]*%[1]s, error) {\n", table.rawName)
-	fmt.Fprintf(w, "var ret []*%[1]s\n", table.rawName)
-	fmt.Fprintf(w, "rows, err := queryer.QueryContext(ctx, %q)\n", sqlSelect)
-	fmt.Fprintf(w, "if err != nil {\n return nil, err \n}\n")
-	fmt.Fprintf(w, "defer rows.Close()\n")
-	fmt.Fprintf(w, "for rows.Next() {\n")
-	fmt.Fprintf(w, "var v %s\n", table.rawName)
-	fmt.Fprintf(w, "if err := rows.Scan(%s); err != nil {\n return nil, err \n}\n", strings.Join(goFields, ", "))
-	fmt.Fprintf(w, "ret = append(ret, &v)")
+	m.generateGoTableDeleteByPKFunc(w, table, "Delete", update, goType)
+	m.generateGoTableDeleteByPKFunc(w, table, "HardDelete", del, goType)
+}
+
+// generateGoTableDeleteByPKFunc generates the function that executes the query del for each key,
+// and returns sql.ErrNoRows if no row is affected.
+func (m *Maker) generateGoTableDeleteByPKFunc(w io.Writer, table *table, name, del, goType string) {
+	fmt.Fprintf(w, "func %s%sByPK(ctx context.Context, execer execer, keys ...%s) error {\n", name, table.rawName, goType)
+	m.generateGoInstrument(w, table, "execer", name+table.rawName+"ByPK")
+	m.generateGoRetry(w)
+	fmt.Fprintf(w, "stmt, err := %s\n", m.goPrepare(strconv.Quote(del)))
+	fmt.Fprintf(w, "if err != nil {\n")
+	fmt.Fprintf(w, "return err\n")
 	fmt.Fprintf(w, "}\n")
-	fmt.Fprintf(w, "if err := rows.Err(); err != nil {\n return nil, err \n}\n")
-	fmt.Fprintf(w, "return ret, nil\n")
+	fmt.Fprintf(w, "defer stmt.Close()\n")
+	fmt.Fprintf(w, "for _, key := range keys {\n")
+	fmt.Fprintf(w, "result, err := stmt.ExecContext(ctx, key)\n")
+	fmt.Fprintf(w, "if err != nil {\n")
+	fmt.Fprintf(w, "return err\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "if n, err := result.RowsAffected(); err != nil {\n")
+	fmt.Fprintf(w, "return err\n")
+	fmt.Fprintf(w, "} else if n == 0 {\n")
+	fmt.Fprintf(w, "return sql.ErrNoRows\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "return nil\n")
 	fmt.Fprintf(w, "}\n\n")
 }
 
-func (m *Maker) generateGoTableUpdate(w io.Writer, table *table) {
-	setFields := make([]string, 0, len(table.columns))
-	goFields := make([]string, 0, len(table.columns))
-	params := make([]string, 0, len(table.primaryKey.columns))
-	conditions := make([]string, 0, len(table.primaryKey.columns))
+// generateGoTableDeleteByIndexes generates the functions that delete the rows by the values of the unique indexes and the indexes,
+// and return the number of the deleted rows, e.g. for the cleanup jobs.
+// They are generated only for the indexes whose columns are basic types.
+func (m *Maker) generateGoTableDeleteByIndexes(w io.Writer, table *table) {
+	var keys [][]string
+	for _, idx := range table.uniqueIndexes {
+		keys = append(keys, idx.columns)
+	}
+	for _, idx := range table.indexes {
+		keys = append(keys, idx.columns)
+	}
 
-LOOP:
-	for _, c := range table.columns {
-		for _, key := range table.primaryKey.columns {
-			if key == c.name {
-				params = append(params, fmt.Sprintf("value.%s", c.rawName))
-				conditions = append(conditions, fmt.Sprintf("%s = ?", quote(c.name)))
-				continue LOOP
-			}
+	softDelete := table.softDeleteColumn()
+	generated := map[string]bool{}
+	for _, key := range keys {
+		finder := finderOf(table, key)
+		if finder == nil || generated[finder.name] {
+			continue
 		}
-		setFields = append(setFields, fmt.Sprintf("%s = ?", quote(c.name)))
-		goFields = append(goFields, "value."+c.rawName)
+		generated[finder.name] = true
+
+		del := fmt.Sprintf("DELETE FROM %s%s", table.quotedName(), where(finder.conditions))
+		if softDelete == nil {
+			m.generateGoTableDeleteByIndexFunc(w, table, finder, "Delete", del)
+			continue
+		}
+		update := fmt.Sprintf(
+			"UPDATE %s SET %s = %s%s",
+			table.quotedName(),
+			quote(softDelete.name),
+			softDelete.currentTimestamp(),
+			where(table.withNotDeleted(finder.conditions)),
+		)
+		m.generateGoTableDeleteByIndexFunc(w, table, finder, "Delete", update)
+		m.generateGoTableDeleteByIndexFunc(w, table, finder, "HardDelete", del)
 	}
+}
 
-	update := fmt.Sprintf(
-		"UPDATE %s SET %s WHERE %s",
-		quote(table.name),
-		strings.Join(setFields, ", "),
-		strings.Join(conditions, " AND "),
-	)
-	fmt.Fprintf(w, "func Update%[1]s(ctx context.Context, execer execer, values ...*%[1]s) error {\n", table.rawName)
-	if len(setFields) != 0 {
-		fmt.Fprintf(w, "stmt, err := execer.PrepareContext(ctx, %q)\n", update)
-		fmt.Fprintf(w, "if err != nil {\n")
-		fmt.Fprintf(w, "return err\n")
-		fmt.Fprintf(w, "}\n")
-		fmt.Fprintf(w, "defer stmt.Close()\n")
-		fmt.Fprintf(w, "for _, value := range values {\n")
-		fmt.Fprintf(w, "if _, err := stmt.ExecContext(ctx, %s, %s); err != nil {\n", strings.Join(goFields, ", "), strings.Join(params, ", "))
-		fmt.Fprintf(w, "return err\n")
-		fmt.Fprintf(w, "}\n")
-		fmt.Fprintf(w, "}\n")
+// generateGoTableDeleteByIndexFunc generates the function that executes the query del with the values of the index,
+// and returns the number of the affected rows.
+func (m *Maker) generateGoTableDeleteByIndexFunc(w io.Writer, table *table, finder *finder, name, del string) {
+	funcName := name + table.rawName + "By" + finder.name
+	fmt.Fprintf(w, "// %s deletes the rows by the values of the index, and returns the number of the deleted rows.\n", funcName)
+	fmt.Fprintf(w, "func %s(ctx context.Context, execer execer, %s) (int64, error) {\n", funcName, strings.Join(finder.params, ", "))
+	m.generateGoCacheStatements(w, "execer")
+	m.generateGoInstrument(w, table, "execer", funcName)
+	m.generateGoRetry(w)
+	fmt.Fprintf(w, "result, err := execer.ExecContext(ctx, %q, %s)\n", del, strings.Join(finder.args, ", "))
+	fmt.Fprintf(w, "if err != nil {\n return 0, err \n}\n")
+	fmt.Fprintf(w, "return result.RowsAffected()\n")
+	fmt.Fprintf(w, "}\n\n")
+}
+
+// generateGoTableRepository generates the interface of the generated functions for the table,
+// the implementation that calls them, and the in-memory fake implementation for unit tests.
+func (m *Maker) generateGoTableRepository(w io.Writer, table *table) {
+	pk := finderOf(table, table.primaryKey.columns)
+	if pk == nil {
+		return
+	}
+	name := table.rawName
+
+	fmt.Fprintf(w, "// %sQueries is the interface of the generated functions for %[1]s.\n", name)
+	fmt.Fprintf(w, "type %sQueries interface {\n", name)
+	fmt.Fprintf(w, "Insert(ctx context.Context, values ...*%s) error\n", name)
+	fmt.Fprintf(w, "Select(ctx context.Context, primaryKeys *%[1]s) (*%[1]s, error)\n", name)
+	fmt.Fprintf(w, "SelectAll(ctx context.Context, opts ...SelectOption) ([]*%s, error)\n", name)
+	fmt.Fprintf(w, "Update(ctx context.Context, values ...*%s) error\n", name)
+	fmt.Fprintf(w, "Delete(ctx context.Context, values ...*%s) error\n", name)
+	fmt.Fprintf(w, "}\n\n")
+
+	// the implementation with the generated functions.
+	lower := goParamName(name)
+	fmt.Fprintf(w, "type %sQueries struct {\n db dbtx \n}\n\n", lower)
+	fmt.Fprintf(w, "// New%[1]sQueries returns %[1]sQueries that calls the generated functions with db,\n", name)
+	fmt.Fprintf(w, "// such as *sql.DB, *sql.Conn and *sql.Tx.\n")
+	fmt.Fprintf(w, "func New%[1]sQueries(db dbtx) %[1]sQueries {\n return &%[2]sQueries{db: db} \n}\n\n", name, lower)
+	fmt.Fprintf(w, "func (q *%[2]sQueries) Insert(ctx context.Context, values ...*%[1]s) error {\n return Insert%[1]s(ctx, q.db, values...) \n}\n\n", name, lower)
+	fmt.Fprintf(w, "func (q *%[2]sQueries) Select(ctx context.Context, primaryKeys *%[1]s) (*%[1]s, error) {\n return Select%[1]s(ctx, q.db, primaryKeys) \n}\n\n", name, lower)
+	fmt.Fprintf(w, "func (q *%[2]sQueries) SelectAll(ctx context.Context, opts ...SelectOption) ([]*%[1]s, error) {\n return SelectAll%[1]s(ctx, q.db, opts...) \n}\n\n", name, lower)
+	fmt.Fprintf(w, "func (q *%[2]sQueries) Update(ctx context.Context, values ...*%[1]s) error {\n return Update%[1]s(ctx, q.db, values...) \n}\n\n", name, lower)
+	fmt.Fprintf(w, "func (q *%[2]sQueries) Delete(ctx context.Context, values ...*%[1]s) error {\n return Delete%[1]s(ctx, q.db, values...) \n}\n\n", name, lower)
+
+	// the in-memory fake implementation.
+	keyFields := make([]string, 0, len(table.primaryKey.columns))
+	for _, col := range table.primaryKey.columns {
+		keyFields = append(keyFields, "v."+table.column(col).goField)
+	}
+	keyType := fmt.Sprintf("[%d]any", len(keyFields))
+	auto, _ := table.primaryKeyGoType()
+	if auto != nil && (!auto.autoIncr || auto.goType() == "") {
+		auto = nil
+	}
+
+	fmt.Fprintf(w, "// Fake%[1]sQueries is an in-memory implementation of %[1]sQueries for unit tests.\n", name)
+	fmt.Fprintf(w, "// It is safe for concurrent use, but it doesn't emulate the features of MySQL other than the primary key.\n")
+	fmt.Fprintf(w, "type Fake%sQueries struct {\n", name)
+	fmt.Fprintf(w, "mu sync.Mutex\n")
+	fmt.Fprintf(w, "rows map[%s]%s\n", keyType, name)
+	if auto != nil {
+		fmt.Fprintf(w, "lastID %s\n", auto.goType())
+	}
+	fmt.Fprintf(w, "}\n\n")
+
+	fmt.Fprintf(w, "var _ %[1]sQueries = (*Fake%[1]sQueries)(nil)\n\n", name)
+
+	fmt.Fprintf(w, "// NewFake%[1]sQueries returns a new empty Fake%[1]sQueries.\n", name)
+	fmt.Fprintf(w, "func NewFake%[1]sQueries() *Fake%[1]sQueries {\n return &Fake%[1]sQueries{rows: map[%[2]s]%[1]s{}} \n}\n\n", name, keyType)
+
+	fmt.Fprintf(w, "func (f *Fake%[1]sQueries) key(v *%[1]s) %[2]s {\n return %[2]s{%[3]s} \n}\n\n", name, keyType, strings.Join(keyFields, ", "))
+
+	fmt.Fprintf(w, "func (f *Fake%[1]sQueries) Insert(ctx context.Context, values ...*%[1]s) error {\n", name)
+	fmt.Fprintf(w, "f.mu.Lock()\n defer f.mu.Unlock()\n")
+	fmt.Fprintf(w, "for _, value := range values {\n")
+	fmt.Fprintf(w, "v := *value\n")
+	if auto != nil {
+		fmt.Fprintf(w, "// the auto-increment column is assigned, like the generated Insert%s ignores its value.\n", name)
+		fmt.Fprintf(w, "f.lastID++\n")
+		fmt.Fprintf(w, "v.%s = %s(f.lastID)\n", auto.goField, auto.goTypeExpr(m.goPkgPath(), map[string]bool{}))
+	}
+	fmt.Fprintf(w, "key := f.key(&v)\n")
+	fmt.Fprintf(w, "if _, ok := f.rows[key]; ok {\n return ErrDuplicateEntry \n}\n")
+	fmt.Fprintf(w, "f.rows[key] = v\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "return nil\n")
+	fmt.Fprintf(w, "}\n\n")
+
+	fmt.Fprintf(w, "func (f *Fake%[1]sQueries) Select(ctx context.Context, primaryKeys *%[1]s) (*%[1]s, error) {\n", name)
+	fmt.Fprintf(w, "f.mu.Lock()\n defer f.mu.Unlock()\n")
+	fmt.Fprintf(w, "v, ok := f.rows[f.key(primaryKeys)]\n")
+	fmt.Fprintf(w, "if !ok {\n return nil, sql.ErrNoRows \n}\n")
+	fmt.Fprintf(w, "return &v, nil\n")
+	fmt.Fprintf(w, "}\n\n")
+
+	fmt.Fprintf(w, "func (f *Fake%[1]sQueries) SelectAll(ctx context.Context, opts ...SelectOption) ([]*%[1]s, error) {\n", name)
+	fmt.Fprintf(w, "var o selectOptions\n")
+	fmt.Fprintf(w, "for _, opt := range opts {\n opt(&o) \n}\n")
+	fmt.Fprintf(w, "var less func(a, b *%s) bool\n", name)
+	fmt.Fprintf(w, "switch o.column {\n")
+	fmt.Fprintf(w, "case \"\":\n")
+	fmt.Fprintf(w, "less = func(a, b *%s) bool {\n%s}\n", name, fakeLess(table, nil))
+	for _, c := range table.orderableColumns() {
+		body := fakeLess(table, c)
+		if body == "" {
+			continue
+		}
+		fmt.Fprintf(w, "case %q:\n", c.name)
+		fmt.Fprintf(w, "less = func(a, b *%s) bool {\n%s}\n", name, body)
 	}
+	fmt.Fprintf(w, "default:\n return nil, ErrInvalidOrderBy \n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "f.mu.Lock()\n")
+	fmt.Fprintf(w, "ret := make([]*%s, 0, len(f.rows))\n", name)
+	fmt.Fprintf(w, "for _, v := range f.rows {\n v := v \n ret = append(ret, &v) \n}\n")
+	fmt.Fprintf(w, "f.mu.Unlock()\n")
+	fmt.Fprintf(w, "sort.Slice(ret, func(i, j int) bool {\n")
+	fmt.Fprintf(w, "if o.desc {\n return less(ret[j], ret[i]) \n}\n")
+	fmt.Fprintf(w, "return less(ret[i], ret[j])\n")
+	fmt.Fprintf(w, "})\n")
+	fmt.Fprintf(w, "if o.offset > 0 {\n if o.offset >= len(ret) {\n return nil, nil \n}\n ret = ret[o.offset:] \n}\n")
+	fmt.Fprintf(w, "if o.limit > 0 && o.limit < len(ret) {\n ret = ret[:o.limit] \n}\n")
+	fmt.Fprintf(w, "if len(ret) == 0 {\n return nil, nil \n}\n")
+	fmt.Fprintf(w, "return ret, nil\n")
+	fmt.Fprintf(w, "}\n\n")
+
+	fmt.Fprintf(w, "func (f *Fake%[1]sQueries) Update(ctx context.Context, values ...*%[1]s) error {\n", name)
+	fmt.Fprintf(w, "f.mu.Lock()\n defer f.mu.Unlock()\n")
+	fmt.Fprintf(w, "for _, v := range values {\n")
+	fmt.Fprintf(w, "key := f.key(v)\n")
+	fmt.Fprintf(w, "if _, ok := f.rows[key]; ok {\n f.rows[key] = *v \n}\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "return nil\n")
+	fmt.Fprintf(w, "}\n\n")
+
+	fmt.Fprintf(w, "func (f *Fake%[1]sQueries) Delete(ctx context.Context, values ...*%[1]s) error {\n", name)
+	fmt.Fprintf(w, "f.mu.Lock()\n defer f.mu.Unlock()\n")
+	fmt.Fprintf(w, "for _, v := range values {\n")
+	fmt.Fprintf(w, "key := f.key(v)\n")
+	fmt.Fprintf(w, "if _, ok := f.rows[key]; !ok {\n return sql.ErrNoRows \n}\n")
+	fmt.Fprintf(w, "delete(f.rows, key)\n")
+	fmt.Fprintf(w, "}\n")
 	fmt.Fprintf(w, "return nil\n")
 	fmt.Fprintf(w, "}\n\n")
 }
 
+// fakeLess returns the body of the function that compares a and b by the column,
+// and breaks ties by the primary key.
+// If the column is nil, they are compared only by the primary key.
+// It returns an empty string if the column can't be compared.
+func fakeLess(table *table, c *column) string {
+	columns := []*column{}
+	if c != nil {
+		columns = append(columns, c)
+	}
+	for _, name := range table.primaryKey.columns {
+		if c == nil || name != c.name {
+			columns = append(columns, table.column(name))
+		}
+	}
+
+	var buf strings.Builder
+	for _, col := range columns {
+		a, b := "a."+col.goField, "b."+col.goField
+		switch {
+		case col.goType() == "bool":
+			fmt.Fprintf(&buf, "if %[1]s != %[2]s {\n return !%[1]s \n}\n", a, b)
+		case col.goType() != "":
+			fmt.Fprintf(&buf, "if %[1]s != %[2]s {\n return %[1]s < %[2]s \n}\n", a, b)
+		case col.fieldType == timeType:
+			fmt.Fprintf(&buf, "if !%[1]s.Equal(%[2]s) {\n return %[1]s.Before(%[2]s) \n}\n", a, b)
+		default:
+			return ""
+		}
+	}
+	buf.WriteString("return false\n")
+	return buf.String()
+}
+
 // ptrInt returns a pointer to int value.
 func ptrInt(v int) *int {
 	return &v
@@ -10,8 +10,11 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"strings"
 	"testing"
+	"testing/fstest"
 	"time"
 
 	"github.com/go-sql-driver/mysql"
@@ -349,6 +352,51 @@ func (*Foo23) PrimaryKey() *PrimaryKey {
 	return NewPrimaryKey("id")
 }
 
+type SoftDelete struct {
+	DeletedAt sql.NullTime `ddl:",null"`
+}
+
+func (*SoftDelete) Indexes() []*Index {
+	return []*Index{
+		NewIndex("idx_deleted_at", "deleted_at"),
+	}
+}
+
+type Foo25 struct {
+	ID   int32 `ddl:",auto"`
+	Name string
+	SoftDelete
+}
+
+func (*Foo25) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+type Foo26 struct {
+	ID   int32 `ddl:",auto"`
+	Name string
+	SoftDelete
+}
+
+func (*Foo26) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+func (*Foo26) Indexes() []*Index {
+	return []*Index{
+		NewIndex("idx_name", "name"),
+	}
+}
+
+type Foo27 struct {
+	ID         int32 `ddl:",auto"`
+	SoftDelete `ddl:",prefix=user_"`
+}
+
+func (*Foo27) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
 type Fkp1 struct {
 	ID string
 }
@@ -621,18 +669,52 @@ func (*Fkc8) ForeignKeys() []*ForeignKey {
 	}
 }
 
+type Fkp9 struct {
+	ID string
+}
+
+func (*Fkp9) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+type Fkc9 struct {
+	ID       string
+	ParentID string `ddl:",size=64,charset=ascii,collate=ascii_bin"`
+}
+
+func (*Fkc9) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+func (*Fkc9) Indexes() []*Index {
+	return []*Index{
+		NewIndex("idx_parent_id", "parent_id"),
+	}
+}
+
+func (*Fkc9) ForeignKeys() []*ForeignKey {
+	return []*ForeignKey{
+		NewForeignKey("fk_fkc9_parent_id", []string{"parent_id"}, "fkp9", []string{"id"}),
+	}
+}
+
 func testMaker(t *testing.T, structs []any, ddl string) {
 	t.Helper()
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	m, err := New(&Config{
+	testMakerWithConfig(t, &Config{
 		DB: &DBConfig{
 			Engine:  "InnoDB",
 			Charset: "utf8mb4",
 			Collate: "utf8mb4_bin",
 		},
-	})
+	}, structs, ddl)
+}
+
+func testMakerWithConfig(t *testing.T, config *Config, structs []any, ddl string) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	m, err := New(config)
 	if err != nil {
 		t.Fatalf("failed to initialize Maker: %v", err)
 	}
@@ -707,14 +789,19 @@ func setupDatabase(ctx context.Context, t testing.TB) (db *sql.DB, ok bool) {
 
 func testMakerError(t *testing.T, structs []any, wantErr []string) {
 	t.Helper()
-
-	m, err := New(&Config{
+	testMakerErrorWithConfig(t, &Config{
 		DB: &DBConfig{
 			Engine:  "InnoDB",
 			Charset: "utf8mb4",
 			Collate: "utf8mb4_bin",
 		},
-	})
+	}, structs, wantErr)
+}
+
+func testMakerErrorWithConfig(t *testing.T, config *Config, structs []any, wantErr []string) {
+	t.Helper()
+
+	m, err := New(config)
 	if err != nil {
 		t.Fatalf("failed to initialize Maker: %v", err)
 	}
@@ -738,6 +825,26 @@ func testMakerError(t *testing.T, structs []any, wantErr []string) {
 	}
 }
 
+func testMakerWarning(t *testing.T, config *Config, structs []any, wantWarnings []string) {
+	t.Helper()
+
+	m, err := New(config)
+	if err != nil {
+		t.Fatalf("failed to initialize Maker: %v", err)
+	}
+
+	m.AddStructs(structs...)
+
+	var buf bytes.Buffer
+	if err := m.Generate(&buf); err != nil {
+		t.Fatalf("failed to generate ddl: %v", err)
+	}
+
+	if diff := cmp.Diff(wantWarnings, m.warnings); diff != "" {
+		t.Errorf("unexpected warnings (-want/+got):\n%s", diff)
+	}
+}
+
 func TestMaker_Generate(t *testing.T) {
 	testMaker(t, []any{&Foo1{}}, "SET foreign_key_checks=0;\n\n"+
 		"DROP TABLE IF EXISTS `foo1`;\n\n"+
@@ -902,6 +1009,42 @@ func TestMaker_Generate(t *testing.T) {
 		") COMMENT='test comment' ENGINE=InnoDB DEFAULT CHARACTER SET=utf8mb4 DEFAULT COLLATE=utf8mb4_bin;\n\n"+
 		"SET foreign_key_checks=1;\n")
 
+	// indexes from an embedded struct
+	testMaker(t, []any{&Foo25{}}, "SET foreign_key_checks=0;\n\n"+
+		"DROP TABLE IF EXISTS `foo25`;\n\n"+
+		"CREATE TABLE `foo25` (\n"+
+		"    `id` INTEGER NOT NULL AUTO_INCREMENT,\n"+
+		"    `name` VARCHAR(191) NOT NULL,\n"+
+		"    `deleted_at` DATETIME(6) NULL,\n"+
+		"    INDEX `idx_deleted_at` (`deleted_at`),\n"+
+		"    PRIMARY KEY (`id`)\n"+
+		") ENGINE=InnoDB DEFAULT CHARACTER SET=utf8mb4 DEFAULT COLLATE=utf8mb4_bin;\n\n"+
+		"SET foreign_key_checks=1;\n")
+
+	// merge indexes of the table and its embedded struct
+	testMaker(t, []any{&Foo26{}}, "SET foreign_key_checks=0;\n\n"+
+		"DROP TABLE IF EXISTS `foo26`;\n\n"+
+		"CREATE TABLE `foo26` (\n"+
+		"    `id` INTEGER NOT NULL AUTO_INCREMENT,\n"+
+		"    `name` VARCHAR(191) NOT NULL,\n"+
+		"    `deleted_at` DATETIME(6) NULL,\n"+
+		"    INDEX `idx_name` (`name`),\n"+
+		"    INDEX `idx_deleted_at` (`deleted_at`),\n"+
+		"    PRIMARY KEY (`id`)\n"+
+		") ENGINE=InnoDB DEFAULT CHARACTER SET=utf8mb4 DEFAULT COLLATE=utf8mb4_bin;\n\n"+
+		"SET foreign_key_checks=1;\n")
+
+	// indexes from a prefixed embedded struct
+	testMaker(t, []any{&Foo27{}}, "SET foreign_key_checks=0;\n\n"+
+		"DROP TABLE IF EXISTS `foo27`;\n\n"+
+		"CREATE TABLE `foo27` (\n"+
+		"    `id` INTEGER NOT NULL AUTO_INCREMENT,\n"+
+		"    `user_deleted_at` DATETIME(6) NULL,\n"+
+		"    INDEX `user_idx_deleted_at` (`user_deleted_at`),\n"+
+		"    PRIMARY KEY (`id`)\n"+
+		") ENGINE=InnoDB DEFAULT CHARACTER SET=utf8mb4 DEFAULT COLLATE=utf8mb4_bin;\n\n"+
+		"SET foreign_key_checks=1;\n")
+
 	// nullable string foreign key
 	testMaker(t, []any{&Fkp1{}, &Fkc1{}}, "SET foreign_key_checks=0;\n\n"+
 		"DROP TABLE IF EXISTS `fkp1`;\n\n"+
@@ -1056,6 +1199,7 @@ func TestMaker_Generate(t *testing.T) {
 		`table "foo15": duplicated name of index: "idx_name"`,
 		`table "foo15": duplicated name of index: "idx_name"`,
 		`table "foo15": duplicated name of index: "idx_name"`,
+		`table "foo15", spatial index "idx_name": column "name" is VARCHAR(191), want a spatial data type such as GEOMETRY`,
 	})
 
 	testMakerError(t, []any{&Foo16{}}, []string{
@@ -1067,134 +1211,1501 @@ func TestMaker_Generate(t *testing.T) {
 		`table "foo17", foreign key "fk_foo17": referenced table "unknown_table" not found`,
 	})
 
+	// all errors of all tables are reported at once.
+	testMakerError(t, []any{&Foo28{}, &Foo14{}}, []string{
+		`source_test.go:12: table "foo28", field "Foo28.Size": failed to parse size param in tag: strconv.ParseInt: parsing "large": invalid syntax`,
+		`source_test.go:13: table "foo28", field "Foo28.Unknown": unknown type: myddlmaker.customType`,
+		`table "foo14", primary key: column "unknown_column" not found`,
+		`table "foo14", index "idx": column "unknown_column" not found`,
+		`table "foo14", unique index "uniq": column "unknown_column" not found`,
+	})
+
 	testMakerError(t, []any{&Foo18{}, &Foo19{}}, []string{
 		`table "foo18", foreign key "fk_foo19": index required on table "foo18"`,
-		`table "foo18", foreign key "fk_foo19": column "foo19_id" and referenced column "foo19"."id" type mismatch`,
+		`table "foo18", foreign key "fk_foo19": column "foo19_id" and referenced column "foo19"."id" type mismatch: INTEGER != BIGINT`,
 	})
-}
 
-func TestMaker_GenerateGo(t *testing.T) {
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	testMakerError(t, []any{&Fkp9{}, &Fkc9{}}, []string{
+		`table "fkc9", foreign key "fk_fkc9_parent_id": column "parent_id" and referenced column "fkp9"."id" type mismatch: VARCHAR(64) != VARCHAR(191)`,
+		`table "fkc9", foreign key "fk_fkc9_parent_id": column "parent_id" and referenced column "fkp9"."id" character set mismatch: ascii != utf8mb4`,
+		`table "fkc9", foreign key "fk_fkc9_parent_id": column "parent_id" and referenced column "fkp9"."id" collate mismatch: ascii_bin != utf8mb4_bin`,
+	})
+}
 
-	fn := func(dir string) func(t *testing.T) {
-		// gen generates 'scheme.sql' and 'scheme_gen.go'
-		gen := func(t *testing.T) error {
-			ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
-			defer cancel()
+func TestMaker_TablePrefix(t *testing.T) {
+	testMakerWithConfig(t, &Config{
+		DB: &DBConfig{
+			Engine:  "InnoDB",
+			Charset: "utf8mb4",
+			Collate: "utf8mb4_bin",
+		},
+		TablePrefix: "app_",
+		TableSuffix: "_v1",
+	}, []any{&Foo1{}, &Foo4{}}, "SET foreign_key_checks=0;\n\n"+
+		"DROP TABLE IF EXISTS `app_foo1_v1`;\n\n"+
+		"CREATE TABLE `app_foo1_v1` (\n"+
+		"    `id` INTEGER NOT NULL,\n"+
+		"    PRIMARY KEY (`id`)\n"+
+		") ENGINE=InnoDB DEFAULT CHARACTER SET=utf8mb4 DEFAULT COLLATE=utf8mb4_bin;\n\n\n"+
+		"DROP TABLE IF EXISTS `app_foo4_v1`;\n\n"+
+		"CREATE TABLE `app_foo4_v1` (\n"+
+		"    `id` INTEGER NOT NULL,\n"+
+		"    `name` VARCHAR(191) NOT NULL,\n"+
+		"    CONSTRAINT `fk_foo1` FOREIGN KEY (`id`) REFERENCES `app_foo1_v1` (`id`),\n"+
+		"    PRIMARY KEY (`id`)\n"+
+		") ENGINE=InnoDB DEFAULT CHARACTER SET=utf8mb4 DEFAULT COLLATE=utf8mb4_bin;\n\n"+
+		"SET foreign_key_checks=1;\n")
+}
 
-			var buf bytes.Buffer
-			args := []string{"run", "-tags", "myddlmaker", filepath.Join("gen", "main.go")}
-			cmd := exec.CommandContext(ctx, goTool(), args...)
-			cmd.Stdout = &buf
-			cmd.Stderr = &buf
-			cmd.Dir = dir
-			if err := cmd.Run(); err != nil {
-				t.Errorf("failed to generate: %v, output:\n%s", err, buf.String())
-				return err
-			}
-			return nil
-		}
+type Sc1 struct {
+	ID int32
+}
 
-		runTests := func(t *testing.T) error {
-			ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
-			defer cancel()
+func (*Sc1) Schema() string {
+	return "db1"
+}
 
-			var buf bytes.Buffer
-			args := []string{"test"}
-			cmd := exec.CommandContext(ctx, goTool(), args...)
-			cmd.Stdout = &buf
-			cmd.Stderr = &buf
-			cmd.Dir = dir
-			if err := cmd.Run(); err != nil {
-				t.Errorf("failed to run test: %v, output:\n%s", err, buf.String())
-				return err
-			}
-			return nil
-		}
-		return func(t *testing.T) {
-			if err := gen(t); err != nil {
-				return
-			}
+func (*Sc1) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
 
-			// check the ddl syntax with MySQL Server
-			user := os.Getenv("MYSQL_TEST_USER")
-			pass := os.Getenv("MYSQL_TEST_PASS")
-			addr := os.Getenv("MYSQL_TEST_ADDR")
-			if user != "" && pass != "" && addr != "" {
-				ddl, err := os.ReadFile(filepath.Join(dir, "schema.sql"))
-				if err != nil {
-					t.Errorf("failed read schema.sql: %v", err)
-					return
-				}
+type Sc2 struct {
+	ID    int32
+	Sc1ID int32
+}
 
-				// connect to the server
-				cfg := mysql.NewConfig()
-				cfg.User = user
-				cfg.Passwd = pass
-				cfg.Addr = addr
-				db0, err := sql.Open("mysql", cfg.FormatDSN())
-				if err != nil {
-					t.Fatalf("failed to open db: %v", err)
-				}
-				defer db0.Close()
+func (*Sc2) Schema() string {
+	return "db2"
+}
 
-				// create a new database
-				var buf2 [4]byte
-				_, err = rand.Read(buf2[:])
-				if err != nil {
-					t.Fatal(err)
-				}
-				dbName := fmt.Sprintf("myddlmaker_%x", buf2[:])
-				_, err = db0.ExecContext(ctx, "CREATE DATABASE "+dbName)
-				if err != nil {
-					t.Fatalf("failed to create database %q: %v", dbName, err)
-				}
-				defer db0.ExecContext(ctx, "DROP DATABASE "+dbName)
-				t.Setenv("MYSQL_TEST_DB", dbName)
+func (*Sc2) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
 
-				// apply the ddl
-				cfg.DBName = dbName
-				cfg.MultiStatements = true
-				db, err := sql.Open("mysql", cfg.FormatDSN())
-				if err != nil {
-					t.Fatalf("failed to open db: %v", err)
-				}
-				defer db.Close()
-				if _, err := db.ExecContext(ctx, string(ddl)); err != nil {
-					t.Errorf("failed to execute %q: %v", string(ddl), err)
-				}
-			}
+func (*Sc2) Indexes() []*Index {
+	return []*Index{
+		NewIndex("idx_sc1_id", "sc1_id"),
+	}
+}
 
-			if err := runTests(t); err != nil {
-				return
-			}
-		}
+func (*Sc2) ForeignKeys() []*ForeignKey {
+	return []*ForeignKey{
+		NewForeignKey("fk_sc1", []string{"sc1_id"}, "sc1", []string{"id"}).InSchema("db1"),
 	}
-	dirs, err := filepath.Glob("./testdata/*")
+}
+
+func TestMaker_Schema(t *testing.T) {
+	want1 := "SET foreign_key_checks=0;\n\n" +
+		"DROP TABLE IF EXISTS `db1`.`sc1`;\n\n" +
+		"CREATE TABLE `db1`.`sc1` (\n" +
+		"    `id` INTEGER NOT NULL,\n" +
+		"    PRIMARY KEY (`id`)\n" +
+		");\n\n"
+	want2 := "\nDROP TABLE IF EXISTS `db2`.`sc2`;\n\n" +
+		"CREATE TABLE `db2`.`sc2` (\n" +
+		"    `id` INTEGER NOT NULL,\n" +
+		"    `sc1_id` INTEGER NOT NULL,\n" +
+		"    INDEX `idx_sc1_id` (`sc1_id`),\n" +
+		"    CONSTRAINT `fk_sc1` FOREIGN KEY (`sc1_id`) REFERENCES `db1`.`sc1` (`id`),\n" +
+		"    PRIMARY KEY (`id`)\n" +
+		");\n\n" +
+		"SET foreign_key_checks=1;\n"
+
+	m, err := New(nil)
 	if err != nil {
 		t.Fatal(err)
 	}
-	for _, dir := range dirs {
-		stat, err := os.Stat(dir)
-		if err != nil {
-			t.Error(err)
-			continue
-		}
-		if !stat.IsDir() {
-			continue
-		}
-		t.Run(dir, fn(dir))
+	m.AddStructs(&Sc1{}, &Sc2{})
+	var buf bytes.Buffer
+	if err := m.Generate(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want1+want2, buf.String()); diff != "" {
+		t.Errorf("ddl is not match: (-want/+got)\n%s", diff)
 	}
-}
 
-// goTool reports the path of the go tool to use to run the tests.
-// If possible, use the same Go used to run run.go, otherwise
-// fallback to the go version found in the PATH.
-func goTool() string {
-	var exeSuffix string
-	if runtime.GOOS == "windows" {
-		exeSuffix = ".exe"
+	// split the files by schema
+	dir := t.TempDir()
+	m, err = New(&Config{
+		OutFilePath:      filepath.Join(dir, "schema.sql"),
+		SplitSchemaFiles: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.AddStructs(&Sc1{}, &Sc2{})
+	if err := m.GenerateFile(); err != nil {
+		t.Fatal(err)
+	}
+	got1, err := os.ReadFile(filepath.Join(dir, "schema_db1.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want1+"SET foreign_key_checks=1;\n", string(got1)); diff != "" {
+		t.Errorf("ddl is not match: (-want/+got)\n%s", diff)
+	}
+	got2, err := os.ReadFile(filepath.Join(dir, "schema_db2.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("SET foreign_key_checks=0;\n"+want2, string(got2)); diff != "" {
+		t.Errorf("ddl is not match: (-want/+got)\n%s", diff)
+	}
+}
+
+type Fkp10 struct {
+	ID int32
+}
+
+func (*Fkp10) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+type Fkc10 struct {
+	ID       int32
+	ParentID int32
+}
+
+func (*Fkc10) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+func (*Fkc10) ForeignKeys() []*ForeignKey {
+	return []*ForeignKey{
+		// no index on parent_id
+		NewForeignKey("fk_fkc10_parent_id", []string{"parent_id"}, "fkp10", []string{"id"}),
+	}
+}
+
+func TestMaker_FKIndex(t *testing.T) {
+	testMakerError(t, []any{&Fkp10{}, &Fkc10{}}, []string{
+		`table "fkc10", foreign key "fk_fkc10_parent_id": index required on table "fkc10"`,
+	})
+
+	// warn missing indexes
+	testMakerWarning(t, &Config{
+		WarnFKIndex: true,
+	}, []any{&Fkp10{}, &Fkc10{}}, []string{
+		`table "fkc10", foreign key "fk_fkc10_parent_id": index required on table "fkc10"`,
+	})
+
+	// create missing indexes
+	testMakerWithConfig(t, &Config{
+		DB: &DBConfig{
+			Engine:  "InnoDB",
+			Charset: "utf8mb4",
+			Collate: "utf8mb4_bin",
+		},
+		AutoFKIndex: true,
+	}, []any{&Fkp10{}, &Fkc10{}}, "SET foreign_key_checks=0;\n\n"+
+		"DROP TABLE IF EXISTS `fkp10`;\n\n"+
+		"CREATE TABLE `fkp10` (\n"+
+		"    `id` INTEGER NOT NULL,\n"+
+		"    PRIMARY KEY (`id`)\n"+
+		") ENGINE=InnoDB DEFAULT CHARACTER SET=utf8mb4 DEFAULT COLLATE=utf8mb4_bin;\n\n\n"+
+		"DROP TABLE IF EXISTS `fkc10`;\n\n"+
+		"CREATE TABLE `fkc10` (\n"+
+		"    `id` INTEGER NOT NULL,\n"+
+		"    `parent_id` INTEGER NOT NULL,\n"+
+		"    INDEX `fk_fkc10_parent_id` (`parent_id`),\n"+
+		"    CONSTRAINT `fk_fkc10_parent_id` FOREIGN KEY (`parent_id`) REFERENCES `fkp10` (`id`),\n"+
+		"    PRIMARY KEY (`id`)\n"+
+		") ENGINE=InnoDB DEFAULT CHARACTER SET=utf8mb4 DEFAULT COLLATE=utf8mb4_bin;\n\n"+
+		"SET foreign_key_checks=1;\n")
+}
+
+type Rw1 struct {
+	ID     int32
+	Order  int32
+	Status string
+}
+
+func (*Rw1) Table() string {
+	return "group"
+}
+
+func (*Rw1) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+func TestMaker_ReservedWords(t *testing.T) {
+	testMakerWarning(t, nil, []any{&Rw1{}}, []string{
+		`table "group": table name "group" is a reserved word`,
+		`table "group": column name "order" is a reserved word`,
+	})
+
+	testMakerWarning(t, &Config{
+		ReservedWords: []string{"STATUS"},
+	}, []any{&Rw1{}}, []string{
+		`table "group": table name "group" is a reserved word`,
+		`table "group": column name "order" is a reserved word`,
+		`table "group": column name "status" is a reserved word`,
+	})
+}
+
+type Kl1 struct {
+	ID          int32
+	Name        string `ddl:",size=255"`
+	Code        string `ddl:",size=255,charset=ascii"`
+	Description string `ddl:",size=800"`
+}
+
+func (*Kl1) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+func (*Kl1) Indexes() []*Index {
+	return []*Index{
+		NewIndex("idx_name", "name"),
+		NewIndex("idx_code", "code"),
+		NewIndex("idx_description", "description"),
+		NewIndex("idx_name_code_description", "name", "code", "description"),
+	}
+}
+
+func TestMaker_KeyLength(t *testing.T) {
+	testMakerError(t, []any{&Kl1{}}, []string{
+		`table "kl1", index "idx_description": column "description" is 3200 bytes, exceeds the maximum key length 3072 bytes`,
+		`table "kl1", index "idx_description": key is 3200 bytes, exceeds the maximum key length 3072 bytes`,
+		`table "kl1", index "idx_name_code_description": column "description" is 3200 bytes, exceeds the maximum key length 3072 bytes`,
+		`table "kl1", index "idx_name_code_description": key is 4475 bytes, exceeds the maximum key length 3072 bytes`,
+	})
+
+	testMakerErrorWithConfig(t, &Config{
+		DB: &DBConfig{
+			Charset:   "utf8mb4",
+			RowFormat: "COMPACT",
+		},
+	}, []any{&Kl1{}}, []string{
+		`table "kl1", index "idx_name": column "name" is 1020 bytes, exceeds the maximum key length 767 bytes`,
+		`table "kl1", index "idx_description": column "description" is 3200 bytes, exceeds the maximum key length 767 bytes`,
+		`table "kl1", index "idx_description": key is 3200 bytes, exceeds the maximum key length 3072 bytes`,
+		`table "kl1", index "idx_name_code_description": column "name" is 1020 bytes, exceeds the maximum key length 767 bytes`,
+		`table "kl1", index "idx_name_code_description": column "description" is 3200 bytes, exceeds the maximum key length 767 bytes`,
+		`table "kl1", index "idx_name_code_description": key is 4475 bytes, exceeds the maximum key length 3072 bytes`,
+	})
+
+	testMakerWithConfig(t, &Config{
+		DB: &DBConfig{
+			Engine:    "InnoDB",
+			RowFormat: "DYNAMIC",
+		},
+	}, []any{&Foo1{}}, "SET foreign_key_checks=0;\n\n"+
+		"DROP TABLE IF EXISTS `foo1`;\n\n"+
+		"CREATE TABLE `foo1` (\n"+
+		"    `id` INTEGER NOT NULL,\n"+
+		"    PRIMARY KEY (`id`)\n"+
+		") ENGINE=InnoDB ROW_FORMAT=DYNAMIC;\n\n"+
+		"SET foreign_key_checks=1;\n")
+
+	// latin1 is a single-byte character set.
+	testMakerErrorWithConfig(t, &Config{
+		DB: &DBConfig{
+			Charset:   "latin1",
+			RowFormat: "COMPACT",
+		},
+	}, []any{&Kl1{}}, []string{
+		`table "kl1", index "idx_description": column "description" is 800 bytes, exceeds the maximum key length 767 bytes`,
+		`table "kl1", index "idx_name_code_description": column "description" is 800 bytes, exceeds the maximum key length 767 bytes`,
+	})
+}
+
+type Rs1 struct {
+	ID    int32
+	Title string `ddl:",size=5000"`
+	Body  string `ddl:",size=10000"`
+}
+
+func (*Rs1) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+type Rs2 struct {
+	ID      int32
+	Title   string         `ddl:",size=5000"`
+	Body    string         `ddl:",size=10000"`
+	Note    sql.NullString `ddl:",size=2000,null"`
+	Content string         `ddl:",type=TEXT"`
+}
+
+func (*Rs2) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+func TestMaker_RowSize(t *testing.T) {
+	testMakerWarning(t, nil, []any{&Rs1{}}, []string{
+		`table "rs1": row size is 60008 bytes, close to the maximum row size 65535 bytes, consider converting "body" (40002 bytes), "title" (20002 bytes) to TEXT or BLOB`,
+	})
+
+	testMakerError(t, []any{&Rs2{}}, []string{
+		`table "rs2": row size is 68023 bytes, exceeds the maximum row size 65535 bytes, consider converting "body" (40002 bytes), "title" (20002 bytes), "note" (8002 bytes) to TEXT or BLOB`,
+	})
+}
+
+type Lim1 struct {
+	C1, C2, C3, C4, C5, C6, C7, C8, C9, C10, C11, C12, C13, C14, C15, C16, C17 int32
+}
+
+func (*Lim1) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("c1")
+}
+
+func (*Lim1) Indexes() []*Index {
+	cols := []string{
+		"c1", "c2", "c3", "c4", "c5", "c6", "c7", "c8", "c9",
+		"c10", "c11", "c12", "c13", "c14", "c15", "c16", "c17",
+	}
+	indexes := []*Index{
+		NewIndex("idx_all", cols...),
+	}
+	for i := 0; i < 64; i++ {
+		indexes = append(indexes, NewIndex(fmt.Sprintf("idx_%d", i), "c2"))
+	}
+	return indexes
+}
+
+func (*Lim1) ForeignKeys() []*ForeignKey {
+	return []*ForeignKey{
+		NewForeignKey(
+			"fk_lim1_c2_references_foo1_id_with_a_very_long_name_over_64_chars",
+			[]string{"c2"},
+			"foo1",
+			[]string{"id"},
+		),
+	}
+}
+
+func TestMaker_Limits(t *testing.T) {
+	testMakerError(t, []any{&Foo1{}, &Lim1{}}, []string{
+		`table "lim1": 65 secondary indexes, exceeds the maximum number of secondary indexes 64`,
+		`table "lim1", index "idx_all": 17 columns, exceeds the maximum number of columns in a key 16`,
+		`table "lim1", foreign key "fk_lim1_c2_references_foo1_id_with_a_very_long_name_over_64_chars": the name is 65 characters, exceeds the maximum length 64`,
+	})
+
+	columns := make([]*column, 1018)
+	for i := range columns {
+		columns[i] = &column{name: fmt.Sprintf("c%d", i), typ: "TINYINT"}
+	}
+	v := newValidator([]*table{
+		{
+			name:       "wide",
+			columns:    columns,
+			primaryKey: NewPrimaryKey("c0"),
+		},
+	})
+	err := v.Validate()
+	var errs *validationError
+	if !errors.As(err, &errs) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		`table "wide": 1018 columns, exceeds the maximum number of columns 1017`,
+	}
+	if diff := cmp.Diff(want, errs.errs); diff != "" {
+		t.Errorf("unexpected errors (-want/+got):\n%s", diff)
+	}
+}
+
+type Pk1 struct {
+	UserID  int32
+	GroupID sql.NullInt32 `ddl:",null"`
+}
+
+func (*Pk1) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("user_di", "group_id")
+}
+
+func TestMaker_PrimaryKey(t *testing.T) {
+	testMakerError(t, []any{&Pk1{}}, []string{
+		`table "pk1", primary key: column "user_di" not found, did you mean "user_id"?`,
+		`table "pk1", primary key: column "group_id" is nullable, the columns of the primary key must be NOT NULL`,
+	})
+}
+
+type Dx1 struct {
+	ID int32
+	A  int32
+	B  int32
+	C  int32
+}
+
+func (*Dx1) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+func (*Dx1) Indexes() []*Index {
+	return []*Index{
+		NewIndex("idx_a", "a"),
+		NewIndex("idx_a_b", "a", "b"),
+		NewIndex("idx_a_b_2", "a", "b"),
+		NewIndex("idx_id", "id"),
+		NewIndex("idx_c", "c"),
+	}
+}
+
+func (*Dx1) UniqueIndexes() []*UniqueIndex {
+	return []*UniqueIndex{
+		NewUniqueIndex("uniq_c", "c"),
+		NewUniqueIndex("uniq_b", "b"),
+		NewUniqueIndex("uniq_b_c", "b", "c"),
+	}
+}
+
+func TestMaker_RedundantIndexes(t *testing.T) {
+	testMakerWarning(t, nil, []any{&Dx1{}}, []string{
+		`table "dx1": index "idx_a" is redundant, index "idx_a_b" covers it`,
+		`table "dx1": index "idx_a_b_2" is a duplicate of index "idx_a_b"`,
+		`table "dx1": index "idx_id" is a duplicate of primary key`,
+		`table "dx1": index "idx_c" is a duplicate of unique index "uniq_c"`,
+	})
+}
+
+type Df1 struct {
+	ID     int32
+	Status string `ddl:",type=ENUM('active','deleted'),default='unknown'"`
+	Count  uint32 `ddl:",default=-1"`
+}
+
+func (*Df1) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+func TestMaker_Default(t *testing.T) {
+	testMakerError(t, []any{&Df1{}}, []string{
+		`table "df1", column "status": invalid default value 'unknown' for ENUM('active','deleted'): "unknown" is not a member of ENUM('active','deleted')`,
+		`table "df1", column "count": invalid default value -1 for INTEGER UNSIGNED: not an unsigned integer`,
+	})
+}
+
+type NmUser struct {
+	ID int32
+}
+
+func (*NmUser) Table() string {
+	return "users"
+}
+
+func (*NmUser) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+type NmPost struct {
+	ID     int32
+	Author int32 `ddl:"Author"`
+}
+
+func (*NmPost) Table() string {
+	return "posts"
+}
+
+func (*NmPost) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+func (*NmPost) Indexes() []*Index {
+	return []*Index{
+		NewIndex("idx_author_of_the_post", "Author"),
+	}
+}
+
+func (*NmPost) ForeignKeys() []*ForeignKey {
+	return []*ForeignKey{
+		NewForeignKey("fk_author", []string{"Author"}, "users", []string{"id"}),
+	}
+}
+
+type enumStatus string
+
+func (enumStatus) EnumValues() []string {
+	return []string{"active", "in-progress", "it's done"}
+}
+
+type enumConflict string
+
+func (enumConflict) EnumValues() []string {
+	return []string{"in-progress", "in_progress"}
+}
+
+type En1 struct {
+	ID       int32
+	Status   enumStatus  `ddl:",default='active'"`
+	Previous *enumStatus `ddl:",null"`
+}
+
+func (*En1) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+type En2 struct {
+	ID     int32
+	Status enumConflict
+}
+
+func (*En2) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+func TestMaker_Enum(t *testing.T) {
+	testMaker(t, []any{&En1{}}, "SET foreign_key_checks=0;\n\n"+
+		"DROP TABLE IF EXISTS `en1`;\n\n"+
+		"CREATE TABLE `en1` (\n"+
+		"    `id` INTEGER NOT NULL,\n"+
+		"    `status` ENUM('active','in-progress','it''s done') NOT NULL DEFAULT 'active',\n"+
+		"    `previous` ENUM('active','in-progress','it''s done') NULL,\n"+
+		"    PRIMARY KEY (`id`)\n"+
+		") ENGINE=InnoDB DEFAULT CHARACTER SET=utf8mb4 DEFAULT COLLATE=utf8mb4_bin;\n\n"+
+		"SET foreign_key_checks=1;\n")
+
+	generate := func(s any) (string, error) {
+		m, err := New(&Config{GenerateEnums: true})
+		if err != nil {
+			return "", err
+		}
+		m.AddStructs(s)
+		var buf bytes.Buffer
+		if err := m.GenerateGo(&buf); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+
+	got, err := generate(&En1{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"enumStatusActive     enumStatus = \"active\"\n",
+		"enumStatusInProgress enumStatus = \"in-progress\"\n",
+		"enumStatusItSDone    enumStatus = \"it's done\"\n",
+		"func (v *enumStatus) Scan(src any) error {\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("%s is not generated:\n%s", want, got)
+		}
+	}
+	if n := strings.Count(got, "func (v enumStatus) Value() (driver.Value, error) {\n"); n != 1 {
+		t.Errorf("want the methods of enumStatus once, got %d times", n)
+	}
+
+	_, err = generate(&En2{})
+	want := `myddlmaker: type enumConflict: the members "in-progress" and "in_progress" have the same constant name enumConflictInProgress`
+	if err == nil || err.Error() != want {
+		t.Errorf("want error %q, got %v", want, err)
+	}
+}
+
+func TestMaker_NamingRules(t *testing.T) {
+	testMakerErrorWithConfig(t, &Config{
+		NamingRules: &NamingRules{
+			SnakeCase:              true,
+			ForeignKeyColumnSuffix: "_id",
+			TableNameNumber:        TableNameSingular,
+			MaxIdentifierLength:    16,
+		},
+	}, []any{&NmUser{}, &NmPost{}}, []string{
+		`table "users": table name "users" is not singular`,
+		`table "posts": column name "Author" is not snake case`,
+		`table "posts": index name "idx_author_of_the_post" is 22 characters, exceeds the maximum length 16`,
+		`table "posts": table name "posts" is not singular`,
+		`table "posts", foreign key "fk_author": column "Author" doesn't have the suffix "_id"`,
+	})
+
+	// plural table names are allowed.
+	testMakerErrorWithConfig(t, &Config{
+		NamingRules: &NamingRules{
+			TableNameNumber: TableNamePlural,
+		},
+	}, []any{&NmUser{}, &NmPost{}, &Foo1{}}, []string{
+		`table "foo1": table name "foo1" is not plural`,
+	})
+}
+
+type Nl1 struct {
+	ID    int32
+	Order int32 `ddl:",nolint=reserved-word"`
+	Key   int32 `ddl:",nolint"`
+	Group int32
+}
+
+func (*Nl1) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+func TestMaker_Checks(t *testing.T) {
+	// change the severity
+	testMakerErrorWithConfig(t, &Config{
+		Checks: map[Check]Severity{
+			CheckReservedWord: SeverityError,
+		},
+	}, []any{&Rw1{}}, []string{
+		`table "group": table name "group" is a reserved word`,
+		`table "group": column name "order" is a reserved word`,
+	})
+
+	// disable the check
+	testMakerWarning(t, &Config{
+		Checks: map[Check]Severity{
+			CheckReservedWord: SeverityOff,
+		},
+	}, []any{&Rw1{}}, nil)
+
+	// suppress the checks on the columns
+	testMakerWarning(t, nil, []any{&Nl1{}}, []string{
+		`table "nl1": column name "group" is a reserved word`,
+	})
+
+	// unknown checks
+	if _, err := New(&Config{
+		Checks: map[Check]Severity{
+			"unknown": SeverityOff,
+		},
+	}); err == nil {
+		t.Error("want some error, but not")
+	}
+	type UnknownNolint struct {
+		ID int32 `ddl:",nolint=unknown"`
+	}
+	if _, err := newTable(nil, &UnknownNolint{}); err == nil {
+		t.Error("want some error, but not")
+	}
+}
+
+type AccessLog struct {
+	Path      string
+	CreatedAt time.Time
+}
+
+func (*AccessLog) Indexes() []*Index {
+	return []*Index{
+		NewIndex("idx_created_at", "created_at"),
+	}
+}
+
+func TestMaker_MissingPrimaryKey(t *testing.T) {
+	testMakerError(t, []any{&AccessLog{}}, []string{
+		`table "access_log": primary key not found, implement the PrimaryKey method`,
+	})
+
+	testMakerWarning(t, &Config{
+		Checks: map[Check]Severity{
+			CheckMissingPK: SeverityWarning,
+		},
+	}, []any{&AccessLog{}}, []string{
+		`table "access_log": primary key not found, implement the PrimaryKey method`,
+	})
+
+	config := &Config{
+		DB: &DBConfig{
+			Engine:  "InnoDB",
+			Charset: "utf8mb4",
+			Collate: "utf8mb4_bin",
+		},
+		TableChecks: map[string]map[Check]Severity{
+			"access_log": {
+				CheckMissingPK: SeverityOff,
+			},
+		},
+	}
+	testMakerWithConfig(t, config, []any{&AccessLog{}}, "SET foreign_key_checks=0;\n\n"+
+		"DROP TABLE IF EXISTS `access_log`;\n\n"+
+		"CREATE TABLE `access_log` (\n"+
+		"    `path` VARCHAR(191) NOT NULL,\n"+
+		"    `created_at` DATETIME(6) NOT NULL,\n"+
+		"    INDEX `idx_created_at` (`created_at`)\n"+
+		") ENGINE=InnoDB DEFAULT CHARACTER SET=utf8mb4 DEFAULT COLLATE=utf8mb4_bin;\n\n"+
+		"SET foreign_key_checks=1;\n")
+
+	// the functions that need the primary key are not generated.
+	m, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.AddStructs(&AccessLog{})
+	var buf bytes.Buffer
+	if err := m.GenerateGo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	if !strings.Contains(got, "func InsertAccessLog(") || !strings.Contains(got, "func SelectAllAccessLog(") {
+		t.Errorf("InsertAccessLog or SelectAllAccessLog is not generated:\n%s", got)
+	}
+	if strings.Contains(got, "func SelectAccessLog(") || strings.Contains(got, "func UpdateAccessLog(") {
+		t.Errorf("SelectAccessLog or UpdateAccessLog is generated:\n%s", got)
+	}
+}
+
+type Sp1 struct {
+	ID       int32
+	Location []byte `ddl:",type=POINT,null"`
+	Area     []byte `ddl:",type=POLYGON,srid=4326"`
+}
+
+func (*Sp1) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+func (*Sp1) SpatialIndexes() []*SpatialIndex {
+	return []*SpatialIndex{
+		NewSpatialIndex("idx_location", "location"),
+		NewSpatialIndex("idx_area", "area"),
+		NewSpatialIndex("idx_id", "id"),
+		NewSpatialIndex("idx_unknown", "unknown"),
+	}
+}
+
+func TestMaker_SpatialIndex(t *testing.T) {
+	testMakerError(t, []any{&Sp1{}}, []string{
+		`table "sp1", spatial index "idx_location": column "location" is nullable, spatial indexes require NOT NULL columns`,
+		`table "sp1", spatial index "idx_location": column "location" has no SRID, the optimizer doesn't use spatial indexes on columns without SRID`,
+		`table "sp1", spatial index "idx_id": column "id" is INTEGER, want a spatial data type such as GEOMETRY`,
+		`table "sp1", spatial index "idx_unknown": column "unknown" not found`,
+	})
+}
+
+type Lr1 struct {
+	ID   string
+	Code string `ddl:",charset=ascii,collate=ascii_bin"`
+}
+
+func (*Lr1) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+func (*Lr1) UniqueIndexes() []*UniqueIndex {
+	return []*UniqueIndex{
+		NewUniqueIndex("uniq_code", "code"),
+	}
+}
+
+type Lr2 struct {
+	ID       int32
+	ParentID string `ddl:",charset=utf8mb4,collate=utf8mb4_bin"`
+	Code     string
+}
+
+func (*Lr2) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+func (*Lr2) Indexes() []*Index {
+	return []*Index{
+		NewIndex("idx_parent_id", "parent_id"),
+	}
+}
+
+func (*Lr2) ForeignKeys() []*ForeignKey {
+	return []*ForeignKey{
+		// the charset and the collation are same as the default of the table.
+		NewForeignKey("fk_lr2_parent_id", []string{"parent_id"}, "lr1", []string{"id"}),
+
+		// logical relations are not created in the database.
+		NewForeignKey("rel_lr2_code", []string{"code"}, "lr1", []string{"code"}).Logical(),
+	}
+}
+
+func TestMaker_FKCollation(t *testing.T) {
+	config := &Config{
+		DB: &DBConfig{
+			Engine:  "InnoDB",
+			Charset: "utf8mb4",
+			Collate: "utf8mb4_bin",
+		},
+	}
+	testMakerWarning(t, config, []any{&Lr1{}, &Lr2{}}, []string{
+		`table "lr2", foreign key "rel_lr2_code": index required on table "lr2"`,
+		`table "lr2", foreign key "rel_lr2_code": column "code" and referenced column "lr1"."code" character set mismatch: utf8mb4 != ascii`,
+		`table "lr2", foreign key "rel_lr2_code": column "code" and referenced column "lr1"."code" collate mismatch: utf8mb4_bin != ascii_bin`,
+	})
+
+	testMakerWithConfig(t, config, []any{&Lr1{}, &Lr2{}}, "SET foreign_key_checks=0;\n\n"+
+		"DROP TABLE IF EXISTS `lr1`;\n\n"+
+		"CREATE TABLE `lr1` (\n"+
+		"    `id` VARCHAR(191) NOT NULL,\n"+
+		"    `code` VARCHAR(191) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,\n"+
+		"    UNIQUE `uniq_code` (`code`),\n"+
+		"    PRIMARY KEY (`id`)\n"+
+		") ENGINE=InnoDB DEFAULT CHARACTER SET=utf8mb4 DEFAULT COLLATE=utf8mb4_bin;\n\n\n"+
+		"DROP TABLE IF EXISTS `lr2`;\n\n"+
+		"CREATE TABLE `lr2` (\n"+
+		"    `id` INTEGER NOT NULL,\n"+
+		"    `parent_id` VARCHAR(191) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL,\n"+
+		"    `code` VARCHAR(191) NOT NULL,\n"+
+		"    INDEX `idx_parent_id` (`parent_id`),\n"+
+		"    CONSTRAINT `fk_lr2_parent_id` FOREIGN KEY (`parent_id`) REFERENCES `lr1` (`id`),\n"+
+		"    PRIMARY KEY (`id`)\n"+
+		") ENGINE=InnoDB DEFAULT CHARACTER SET=utf8mb4 DEFAULT COLLATE=utf8mb4_bin;\n\n"+
+		"SET foreign_key_checks=1;\n")
+}
+
+type Ai1 struct {
+	TenantID int32
+	ID       int64 `ddl:",auto"`
+	Seq      int64 `ddl:",auto"`
+}
+
+func (*Ai1) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("tenant_id", "id")
+}
+
+func (*Ai1) Indexes() []*Index {
+	return []*Index{
+		NewIndex("idx_seq", "seq"),
+	}
+}
+
+func TestMaker_AutoIncrement(t *testing.T) {
+	testMakerError(t, []any{&Ai1{}}, []string{
+		`table "ai1": multiple auto-increment columns "id", "seq", a table can have only one`,
+		`table "ai1": auto-increment column "id" must be the first column of the primary key or an index`,
+	})
+}
+
+type Ft1 struct {
+	ID      int32
+	Title   string
+	Body    string `ddl:",type=MEDIUMTEXT"`
+	Payload []byte
+}
+
+func (*Ft1) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+func (*Ft1) FullTextIndexes() []*FullTextIndex {
+	return []*FullTextIndex{
+		NewFullTextIndex("idx_title", "title"),
+		NewFullTextIndex("idx_body", "body"),
+		NewFullTextIndex("idx_id", "id"),
+		NewFullTextIndex("idx_payload", "payload"),
+		NewFullTextIndex("idx_unknown", "unknown"),
+	}
+}
+
+func TestMaker_FullTextIndex(t *testing.T) {
+	testMakerError(t, []any{&Ft1{}}, []string{
+		`table "ft1", fulltext index "idx_id": column "id" is INTEGER, want CHAR, VARCHAR or TEXT`,
+		`table "ft1", fulltext index "idx_payload": column "payload" is VARBINARY(767), want CHAR, VARCHAR or TEXT`,
+		`table "ft1", fulltext index "idx_unknown": column "unknown" not found`,
+	})
+}
+
+type Cl1 struct {
+	ID   int32
+	Name string
+}
+
+func (*Cl1) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+func (*Cl1) TableComment() string {
+	return strings.Repeat("あ", 2049)
+}
+
+func (*Cl1) Indexes() []*Index {
+	return []*Index{
+		NewIndex("idx_name", "name").Comment(strings.Repeat("a", 1025)),
+	}
+}
+
+type Cl2 struct {
+	ID   int32
+	Name string
+}
+
+func (*Cl2) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+func (*Cl2) TableComment() string {
+	return strings.Repeat("あ", 2048)
+}
+
+func (*Cl2) Indexes() []*Index {
+	return []*Index{
+		NewIndex("idx_name", "name").Comment(strings.Repeat("a", 1024)),
+	}
+}
+
+func TestMaker_CommentLength(t *testing.T) {
+	testMakerError(t, []any{&Cl1{}}, []string{
+		`table "cl1": the comment is 2049 characters, exceeds the maximum length 2048`,
+		`table "cl1", index "idx_name": the comment is 1025 characters, exceeds the maximum length 1024`,
+	})
+
+	m, err := New(&Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.AddStructs(&Cl2{})
+	var buf bytes.Buffer
+	if err := m.Generate(&buf); err != nil {
+		t.Errorf("want no error, got %v", err)
+	}
+}
+
+type Il1 struct {
+	ID    int32
+	Name  string `ddl:"column_name_that_is_much_longer_than_the_limit_of_identifiers_xyz"`
+	FooID int32
+}
+
+func (*Il1) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+func (*Il1) Indexes() []*Index {
+	return []*Index{
+		NewIndex("idx_il1_id_foo_id_with_a_very_long_name_over_sixty_four_character", "id", "foo_id"),
+	}
+}
+
+func (*Il1) ForeignKeys() []*ForeignKey {
+	return []*ForeignKey{
+		NewForeignKey("fk_il1_foo_id_references_foo1_id_with_a_long_name_over_64_chars_x", []string{"foo_id"}, "foo1", []string{"id"}),
+	}
+}
+
+func TestMaker_IdentifierLength(t *testing.T) {
+	testMakerErrorWithConfig(t, &Config{
+		DB: &DBConfig{
+			Engine:  "InnoDB",
+			Charset: "utf8mb4",
+			Collate: "utf8mb4_bin",
+		},
+		AutoFKIndex: true,
+	}, []any{&Foo1{}, &Il1{}}, []string{
+		`table "il1", column "column_name_that_is_much_longer_than_the_limit_of_identifiers_xyz": the name is 65 characters, exceeds the maximum length 64`,
+		`table "il1", index "idx_il1_id_foo_id_with_a_very_long_name_over_sixty_four_character": the name is 65 characters, exceeds the maximum length 64`,
+		`table "il1", index "fk_il1_foo_id_references_foo1_id_with_a_long_name_over_64_chars_x": the name is 65 characters, exceeds the maximum length 64`,
+		`table "il1", foreign key "fk_il1_foo_id_references_foo1_id_with_a_long_name_over_64_chars_x": the name is 65 characters, exceeds the maximum length 64`,
+	})
+}
+
+func TestGoParamName(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "Email", want: "email"},
+		{in: "TenantID", want: "tenantID"},
+		{in: "ID", want: "id"},
+		{in: "URLPath", want: "urlPath"},
+		{in: "Type", want: "type_"},
+		{in: "V", want: "v_"},
+	}
+	for _, tt := range tests {
+		if got := goParamName(tt.in); got != tt.want {
+			t.Errorf("goParamName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+type Ver1 struct {
+	ID       int32  `ddl:",version"`
+	Revision string `ddl:",version"`
+	Count    int32  `ddl:",null,version"`
+}
+
+func (*Ver1) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+type Ver2 struct {
+	ID      int32
+	Version int64 `ddl:",version"`
+}
+
+func TestMaker_Version(t *testing.T) {
+	testMakerError(t, []any{&Ver1{}, &Ver2{}}, []string{
+		`table "ver1": multiple version columns "id", "revision", "count", a table can have only one`,
+		`table "ver1": version column "id" must not be a primary key column`,
+		`table "ver1": version column "revision" is VARCHAR(191), want a NOT NULL integer type`,
+		`table "ver1": version column "count" is INTEGER, want a NOT NULL integer type`,
+		`table "ver2": primary key not found, implement the PrimaryKey method`,
+		`table "ver2": version column "version" requires the primary key`,
+	})
+}
+
+type Sd1 struct {
+	ID        int32
+	DeletedAt time.Time `ddl:",softdelete"`
+	RemovedAt string    `ddl:",null,softdelete"`
+}
+
+func (*Sd1) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+func TestMaker_SoftDelete(t *testing.T) {
+	testMakerError(t, []any{&Sd1{}}, []string{
+		`table "sd1": multiple soft delete columns "deleted_at", "removed_at", a table can have only one`,
+		`table "sd1": soft delete column "deleted_at" is DATETIME(6), want a NULL DATETIME or TIMESTAMP`,
+		`table "sd1": soft delete column "removed_at" is VARCHAR(191), want a NULL DATETIME or TIMESTAMP`,
+	})
+}
+
+type Sx1 struct {
+	ID   int32  `db:"id"`
+	Name string `db:"name"`
+}
+
+func (*Sx1) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+type Sx2 struct {
+	ID   int32 `db:"id"`
+	Name string
+}
+
+func (*Sx2) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+func TestMaker_GoFlavorSQLX(t *testing.T) {
+	generate := func(s any) (string, error) {
+		m, err := New(&Config{
+			DB: &DBConfig{
+				Engine:  "InnoDB",
+				Charset: "utf8mb4",
+				Collate: "utf8mb4_bin",
+			},
+			GoFlavor: GoFlavorSQLX,
+		})
+		if err != nil {
+			return "", err
+		}
+		m.AddStructs(s)
+		var buf bytes.Buffer
+		if err := m.GenerateGo(&buf); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+
+	got, err := generate(&Sx1{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`"github.com/jmoiron/sqlx"`,
+		"sqlx.NamedExecContext(ctx, db, \"INSERT INTO `sx1` (`id`, `name`) VALUES (:id, :name)\", values)",
+		"sqlx.GetContext(ctx, db, &v, \"SELECT `id`, `name` FROM `sx1` WHERE `id` = ?\", primaryKeys.ID)",
+		"sqlx.NamedExecContext(ctx, db, \"UPDATE `sx1` SET `name` = :name WHERE `id` = :id\", value)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("%s is not generated:\n%s", want, got)
+		}
+	}
+
+	_, err = generate(&Sx2{})
+	want := `myddlmaker: table "sx2", field "Name": db tag "" doesn't match the column name "name"`
+	if err == nil || err.Error() != want {
+		t.Errorf("want error %q, got %v", want, err)
+	}
+
+	_, err = New(&Config{GoFlavor: "gorm"})
+	if err == nil {
+		t.Error("want error for the unknown flavor, got nil")
+	}
+}
+
+type goPkgStatus string
+
+type GoPkg1 struct {
+	ID     int32
+	Status goPkgStatus `ddl:",size=16"`
+}
+
+func (*GoPkg1) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+func TestMaker_GoPackagePath(t *testing.T) {
+	generate := func(s any) (string, error) {
+		m, err := New(&Config{
+			DB: &DBConfig{
+				Engine:  "InnoDB",
+				Charset: "utf8mb4",
+				Collate: "utf8mb4_bin",
+			},
+			PackageName:   "queries",
+			GoPackagePath: "example.com/queries",
+		})
+		if err != nil {
+			return "", err
+		}
+		m.AddStructs(s)
+		var buf bytes.Buffer
+		if err := m.GenerateGo(&buf); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+
+	got, err := generate(&Foo1{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"package queries\n",
+		`myddlmaker "github.com/shogo82148/myddlmaker"`,
+		"type (\n\tFoo1 = myddlmaker.Foo1\n)\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("%s is not generated:\n%s", want, got)
+		}
+	}
+
+	_, err = generate(&GoPkg1{})
+	want := `myddlmaker: type goPkgStatus is unexported, it can't be referred from the package "example.com/queries"`
+	if err == nil || err.Error() != want {
+		t.Errorf("want error %q, got %v", want, err)
+	}
+
+	m, err := New(&Config{
+		PackageName:        "queries",
+		GoPackagePath:      "example.com/queries",
+		GenerateValidation: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.AddStructs(&Foo1{})
+	err = m.GenerateGo(&bytes.Buffer{})
+	want = `myddlmaker: GenerateValidation can't be used with GoPackagePath "example.com/queries", the methods must be in the package of the structs`
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("want error %q, got %v", want, err)
+	}
+}
+
+func TestMaker_GoBuildConstraint(t *testing.T) {
+	m, err := New(&Config{
+		DB: &DBConfig{
+			Engine:  "InnoDB",
+			Charset: "utf8mb4",
+			Collate: "utf8mb4_bin",
+		},
+		GoBuildConstraint: "linux || darwin",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.AddStructs(&Foo1{})
+	var buf bytes.Buffer
+	if err := m.GenerateGo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if want := "//go:build !myddlmaker && (linux || darwin)\n"; !strings.Contains(buf.String(), want) {
+		t.Errorf("%s is not generated:\n%s", want, buf.String())
+	}
+
+	_, err = New(&Config{GoBuildConstraint: "linux &&"})
+	if err == nil {
+		t.Error("want error for the invalid constraint, got nil")
+	}
+}
+
+func TestMaker_GenerateIterators(t *testing.T) {
+	m, err := New(&Config{
+		DB: &DBConfig{
+			Engine:  "InnoDB",
+			Charset: "utf8mb4",
+			Collate: "utf8mb4_bin",
+		},
+		OutGoFilePath:     "foo_gen.go",
+		GoBuildConstraint: "linux || darwin",
+		GenerateIterators: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.AddStructs(&Foo1{})
+	files, err := m.goFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("want 2 files, got %d", len(files))
+	}
+
+	// the iterators are separated from the file that older Go can build.
+	if files[0].path != "foo_gen.go" {
+		t.Errorf("unexpected path: %s", files[0].path)
+	}
+	if got := string(files[0].content); strings.Contains(got, "iter.") {
+		t.Errorf("the iterators are generated in %s:\n%s", files[0].path, got)
+	}
+
+	if files[1].path != "foo_gen_go123.go" {
+		t.Errorf("unexpected path: %s", files[1].path)
+	}
+	got := string(files[1].content)
+	for _, want := range []string{
+		"//go:build !myddlmaker && (linux || darwin) && go1.23\n",
+		"\t\"iter\"\n",
+		"func IterateAllFoo1(ctx context.Context, queryer queryer, opts ...SelectOption) iter.Seq2[*Foo1, error] {\n",
+		"func InsertFoo1From(ctx context.Context, execer execer, seq iter.Seq[*Foo1]) error {\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("%s is not generated:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, `"database/sql"`) {
+		t.Errorf("the unused imports are not removed:\n%s", got)
+	}
+}
+
+func TestMaker_SchemaVersion(t *testing.T) {
+	generate := func(version string, s any) string {
+		t.Helper()
+		m, err := New(&Config{SchemaVersion: version})
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.AddStructs(s)
+		var buf bytes.Buffer
+		if err := m.GenerateGo(&buf); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+
+	got := generate("20240102", &Foo1{})
+	if want := "func SchemaVersion() string {\n\treturn \"20240102\"\n}\n"; !strings.Contains(got, want) {
+		t.Errorf("%s is not generated:\n%s", want, got)
+	}
+
+	// the hash depends only on the DDL.
+	hash := regexp.MustCompile(`return "([0-9a-f]{64})"`)
+	foo1 := hash.FindStringSubmatch(got)
+	if foo1 == nil {
+		t.Fatalf("SchemaHash is not generated:\n%s", got)
+	}
+	if again := hash.FindStringSubmatch(generate("", &Foo1{})); again == nil || again[1] != foo1[1] {
+		t.Errorf("want the same hash %s, got %v", foo1[1], again)
+	}
+	if foo3 := hash.FindStringSubmatch(generate("", &Foo3{})); foo3 == nil || foo3[1] == foo1[1] {
+		t.Errorf("want the different hash from %s, got %v", foo1[1], foo3)
+	}
+}
+
+func TestMaker_FixtureOrder(t *testing.T) {
+	m, err := New(&Config{GenerateFixtures: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the child table is added before the parent table.
+	m.AddStructs(&Fkc1{}, &Foo1{}, &Fkp1{})
+	var buf bytes.Buffer
+	if err := m.GenerateGo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	if want := "// The tables referenced by the foreign keys are inserted first: fkp1, fkc1, foo1.\n"; !strings.Contains(got, want) {
+		t.Errorf("%s is not generated:\n%s", want, got)
+	}
+}
+
+func TestMaker_RequireBulkUpdateLimit(t *testing.T) {
+	m, err := New(&Config{
+		DB: &DBConfig{
+			Engine:  "InnoDB",
+			Charset: "utf8mb4",
+			Collate: "utf8mb4_bin",
+		},
+		RequireBulkUpdateLimit: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.AddStructs(&Foo2{})
+	var buf bytes.Buffer
+	if err := m.GenerateGo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := "if limit <= 0 {\n\t\treturn 0, errors.New(\"UpdateFoo2ColumnsByName: the limit must be positive\")\n\t}\n"
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("the limit is not checked:\n%s", buf.String())
+	}
+}
+
+func TestMaker_TemplateFS(t *testing.T) {
+	generate := func(fsys fstest.MapFS) (string, error) {
+		m, err := New(&Config{
+			DB: &DBConfig{
+				Engine:  "InnoDB",
+				Charset: "utf8mb4",
+				Collate: "utf8mb4_bin",
+			},
+			TemplateFS: fsys,
+		})
+		if err != nil {
+			return "", err
+		}
+		m.AddStructs(&Foo1{})
+		var buf bytes.Buffer
+		if err := m.GenerateGo(&buf); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+
+	got, err := generate(fstest.MapFS{
+		"table.tmpl": &fstest.MapFile{
+			Data: []byte(`{{define "table"}}func Get{{.GoName}}ByID(ctx context.Context, db *sql.DB{{range .PrimaryKey}}, {{.GoParam}} {{.GoType}}{{end}}) (*{{.GoName}}, error) {
+	return Select{{.GoName}}(ctx, db, &{{.GoName}}{ {{- range .PrimaryKey}}{{.GoName}}: {{.GoParam}}{{end}} })
+}{{end}}`),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "func GetFoo1ByID(ctx context.Context, db *sql.DB, id int32) (*Foo1, error) {\n\treturn SelectFoo1(ctx, db, &Foo1{ID: id})\n}\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("%s is not generated:\n%s", want, got)
+	}
+
+	_, err = New(&Config{
+		TemplateFS: fstest.MapFS{
+			"broken.tmpl": &fstest.MapFile{Data: []byte(`{{define "table"}}`)},
+		},
+	})
+	if err == nil {
+		t.Error("want error for the broken template, got nil")
+	}
+
+	_, err = generate(fstest.MapFS{
+		"table.tmpl": &fstest.MapFile{Data: []byte(`{{define "table"}}{{.Unknown}}{{end}}`)},
+	})
+	if err == nil || !strings.Contains(err.Error(), `myddlmaker: failed to execute the template "table"`) {
+		t.Errorf("want error for the unknown field, got %v", err)
+	}
+}
+
+func TestMaker_GenerateGo(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fn := func(dir string) func(t *testing.T) {
+		// gen generates 'scheme.sql' and 'scheme_gen.go'
+		gen := func(t *testing.T) error {
+			ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+			defer cancel()
+
+			var buf bytes.Buffer
+			args := []string{"run", "-tags", "myddlmaker", filepath.Join("gen", "main.go")}
+			cmd := exec.CommandContext(ctx, goTool(), args...)
+			cmd.Stdout = &buf
+			cmd.Stderr = &buf
+			cmd.Dir = dir
+			if err := cmd.Run(); err != nil {
+				t.Errorf("failed to generate: %v, output:\n%s", err, buf.String())
+				return err
+			}
+			return nil
+		}
+
+		runTests := func(t *testing.T) error {
+			ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+			defer cancel()
+
+			var buf bytes.Buffer
+			args := []string{"test"}
+			cmd := exec.CommandContext(ctx, goTool(), args...)
+			cmd.Stdout = &buf
+			cmd.Stderr = &buf
+			cmd.Dir = dir
+			if err := cmd.Run(); err != nil {
+				t.Errorf("failed to run test: %v, output:\n%s", err, buf.String())
+				return err
+			}
+			return nil
+		}
+		return func(t *testing.T) {
+			if err := gen(t); err != nil {
+				return
+			}
+
+			// check the ddl syntax with MySQL Server
+			user := os.Getenv("MYSQL_TEST_USER")
+			pass := os.Getenv("MYSQL_TEST_PASS")
+			addr := os.Getenv("MYSQL_TEST_ADDR")
+			if user != "" && pass != "" && addr != "" {
+				ddl, err := os.ReadFile(filepath.Join(dir, "schema.sql"))
+				if err != nil {
+					t.Errorf("failed read schema.sql: %v", err)
+					return
+				}
+
+				// connect to the server
+				cfg := mysql.NewConfig()
+				cfg.User = user
+				cfg.Passwd = pass
+				cfg.Addr = addr
+				db0, err := sql.Open("mysql", cfg.FormatDSN())
+				if err != nil {
+					t.Fatalf("failed to open db: %v", err)
+				}
+				defer db0.Close()
+
+				// create a new database
+				var buf2 [4]byte
+				_, err = rand.Read(buf2[:])
+				if err != nil {
+					t.Fatal(err)
+				}
+				dbName := fmt.Sprintf("myddlmaker_%x", buf2[:])
+				_, err = db0.ExecContext(ctx, "CREATE DATABASE "+dbName)
+				if err != nil {
+					t.Fatalf("failed to create database %q: %v", dbName, err)
+				}
+				defer db0.ExecContext(ctx, "DROP DATABASE "+dbName)
+				t.Setenv("MYSQL_TEST_DB", dbName)
+
+				// apply the ddl
+				cfg.DBName = dbName
+				cfg.MultiStatements = true
+				db, err := sql.Open("mysql", cfg.FormatDSN())
+				if err != nil {
+					t.Fatalf("failed to open db: %v", err)
+				}
+				defer db.Close()
+				if _, err := db.ExecContext(ctx, string(ddl)); err != nil {
+					t.Errorf("failed to execute %q: %v", string(ddl), err)
+				}
+			}
+
+			if err := runTests(t); err != nil {
+				return
+			}
+		}
+	}
+	dirs, err := filepath.Glob("./testdata/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range dirs {
+		stat, err := os.Stat(dir)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if !stat.IsDir() {
+			continue
+		}
+		t.Run(dir, fn(dir))
+	}
+}
+
+// goTool reports the path of the go tool to use to run the tests.
+// If possible, use the same Go used to run run.go, otherwise
+// fallback to the go version found in the PATH.
+func goTool() string {
+	var exeSuffix string
+	if runtime.GOOS == "windows" {
+		exeSuffix = ".exe"
 	}
 	path := filepath.Join(runtime.GOROOT(), "bin", "go"+exeSuffix)
 	if _, err := os.Stat(path); err == nil {
@@ -1203,3 +2714,41 @@ func goTool() string {
 	// Just run "go" from PATH
 	return "go"
 }
+
+func TestMaker_GenerateWriter(t *testing.T) {
+	dir := t.TempDir()
+	m, err := New(&Config{
+		DB:            &DBConfig{Engine: "InnoDB", Charset: "utf8mb4", Collate: "utf8mb4_bin"},
+		OutFilePath:   filepath.Join(dir, "schema.sql"),
+		OutGoFilePath: filepath.Join(dir, "schema_gen.go"),
+		PackageName:   "schema",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.AddStructs(&goldenUser{})
+	if err := m.GenerateFile(); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.GenerateGoFile(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the writers get the same content as the files.
+	var ddl, code bytes.Buffer
+	if err := m.Generate(&ddl); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.GenerateGo(&code); err != nil {
+		t.Fatal(err)
+	}
+	for name, got := range map[string][]byte{"schema.sql": ddl.Bytes(), "schema_gen.go": code.Bytes()} {
+		want, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(string(want), string(got)); diff != "" {
+			t.Errorf("%s (-file/+writer):\n%s", name, diff)
+		}
+	}
+}
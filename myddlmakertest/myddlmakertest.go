@@ -0,0 +1,215 @@
+// Package myddlmakertest provides the MySQL databases for the tests of the code generated by myddlmaker.
+//
+// Open connects to the MySQL server of the DSN in the environment variable TEST_DSN,
+// or starts the MySQL container with Testcontainers if it is empty.
+// Each call creates a new database and applies the DDL to it, so the tests don't share the rows.
+//
+//	func TestUser(t *testing.T) {
+//		db := myddlmakertest.Open(t, schema.AllDDL()...)
+//		if err := schema.InsertUser(context.Background(), db, &schema.User{Name: "Alice"}); err != nil {
+//			t.Fatal(err)
+//		}
+//	}
+package myddlmakertest
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/shogo82148/myddlmaker"
+	"github.com/testcontainers/testcontainers-go"
+	tcmysql "github.com/testcontainers/testcontainers-go/modules/mysql"
+)
+
+// DSNEnv is the name of the environment variable of the DSN of the MySQL server that Open connects to.
+// The user of the DSN must be able to create and drop the databases.
+const DSNEnv = "TEST_DSN"
+
+// Image is the Docker image of the MySQL container that Open starts if DSNEnv is empty.
+// It must be set before the first call of Open.
+var Image = "mysql:8.0"
+
+// the container is shared by all the tests in the package, and it is removed by Testcontainers after the tests.
+var container struct {
+	once sync.Once
+	dsn  string
+	err  error
+}
+
+// Open returns the new database that the statements ddl are applied to,
+// e.g. schema.AllDDL() or the content of schema.sql.
+// The statements are applied with foreign_key_checks=0, so the tables can refer to the later ones.
+// The database is dropped when the test finishes.
+// The returned database parses DATETIME into time.Time, i.e. parseTime=true, for the generated functions.
+// If DSNEnv is empty and Docker is not available, the test is skipped.
+func Open(t testing.TB, ddl ...string) *sql.DB {
+	t.Helper()
+	ctx := context.Background()
+
+	dsn, err := serverDSN(ctx)
+	if err != nil {
+		t.Fatalf("myddlmakertest: failed to start MySQL: %v", err)
+	}
+	if dsn == "" {
+		t.Skip("myddlmakertest: Docker is not available, set " + DSNEnv + " to run the test")
+	}
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		t.Fatalf("myddlmakertest: invalid DSN: %v", err)
+	}
+
+	// create the database of the test.
+	admin, err := openConfig(cfg, "")
+	if err != nil {
+		t.Fatalf("myddlmakertest: failed to connect to MySQL: %v", err)
+	}
+	defer admin.Close()
+	name, err := databaseName()
+	if err != nil {
+		t.Fatalf("myddlmakertest: failed to create the name of the database: %v", err)
+	}
+	if _, err := admin.ExecContext(ctx, "CREATE DATABASE "+name); err != nil {
+		t.Fatalf("myddlmakertest: failed to create the database: %v", err)
+	}
+	t.Cleanup(func() {
+		admin, err := openConfig(cfg, "")
+		if err != nil {
+			t.Errorf("myddlmakertest: failed to connect to MySQL: %v", err)
+			return
+		}
+		defer admin.Close()
+		if _, err := admin.ExecContext(context.Background(), "DROP DATABASE "+name); err != nil {
+			t.Errorf("myddlmakertest: failed to drop the database: %v", err)
+		}
+	})
+
+	if err := apply(ctx, cfg, name, ddl); err != nil {
+		t.Fatalf("myddlmakertest: failed to apply the DDL: %v", err)
+	}
+
+	db, err := openConfig(cfg, name)
+	if err != nil {
+		t.Fatalf("myddlmakertest: failed to connect to MySQL: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// AssertSchema compares the tables in the database db with the structs registered to m,
+// and fails the test with the differences, e.g. the migration that is applied to db but not reflected in the structs.
+//
+//	func TestSchema(t *testing.T) {
+//		db := myddlmakertest.Open(t, migrations...)
+//		m, err := myddlmaker.New(&myddlmaker.Config{})
+//		if err != nil {
+//			t.Fatal(err)
+//		}
+//		m.AddStructs(&schema.User{})
+//		myddlmakertest.AssertSchema(t, db, m)
+//	}
+func AssertSchema(t testing.TB, db *sql.DB, m *myddlmaker.Maker) {
+	t.Helper()
+	diffs, err := m.DiffDatabase(context.Background(), db)
+	if err != nil {
+		t.Fatalf("myddlmakertest: failed to compare the schema: %v", err)
+	}
+	if len(diffs) > 0 {
+		t.Errorf("myddlmakertest: the database doesn't match the structs:\n\t%s", strings.Join(diffs, "\n\t"))
+	}
+}
+
+// serverDSN returns the DSN of the MySQL server.
+// It returns an empty string if DSNEnv is empty and Docker is not available.
+func serverDSN(ctx context.Context) (string, error) {
+	if dsn := os.Getenv(DSNEnv); dsn != "" {
+		return dsn, nil
+	}
+	container.once.Do(func() {
+		if !dockerAvailable(ctx) {
+			return
+		}
+		c, err := tcmysql.Run(ctx, Image,
+			tcmysql.WithUsername("root"),
+			tcmysql.WithPassword("myddlmakertest"),
+		)
+		if err != nil {
+			container.err = err
+			return
+		}
+		container.dsn, container.err = c.ConnectionString(ctx)
+	})
+	return container.dsn, container.err
+}
+
+// dockerAvailable reports whether Testcontainers can start the containers.
+func dockerAvailable(ctx context.Context) (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			// Testcontainers panics if Docker is not running.
+			ok = false
+		}
+	}()
+	provider, err := testcontainers.ProviderDocker.GetProvider()
+	if err != nil {
+		return false
+	}
+	defer provider.Close()
+	return provider.Health(ctx) == nil
+}
+
+// databaseName returns the random name of the database of a test.
+func databaseName() (string, error) {
+	var buf [8]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", err
+	}
+	return "myddlmakertest_" + hex.EncodeToString(buf[:]), nil
+}
+
+// openConfig opens the database name of the server cfg.
+func openConfig(cfg *mysql.Config, name string) (*sql.DB, error) {
+	cfg = cfg.Clone()
+	cfg.DBName = name
+	cfg.ParseTime = true
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(connector), nil
+}
+
+// apply executes the statements ddl in the database name.
+func apply(ctx context.Context, cfg *mysql.Config, name string, ddl []string) error {
+	cfg = cfg.Clone()
+	// the content of schema.sql has multiple statements.
+	cfg.MultiStatements = true
+	db, err := openConfig(cfg, name)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// SET affects only the connection, so all the statements must be executed in the same connection.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, "SET foreign_key_checks=0"); err != nil {
+		return err
+	}
+	for i, stmt := range ddl {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("statement #%d: %w", i, err)
+		}
+	}
+	return nil
+}
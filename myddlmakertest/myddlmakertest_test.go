@@ -0,0 +1,125 @@
+package myddlmakertest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/shogo82148/myddlmaker"
+)
+
+// the child table refers to the later parent table.
+var ddl = []string{
+	"CREATE TABLE `child` (\n" +
+		"    `id` INTEGER NOT NULL,\n" +
+		"    `parent_id` INTEGER NOT NULL,\n" +
+		"    CONSTRAINT `fk_parent` FOREIGN KEY (`parent_id`) REFERENCES `parent` (`id`),\n" +
+		"    PRIMARY KEY (`id`)\n" +
+		")",
+	"CREATE TABLE `parent` (\n" +
+		"    `id` INTEGER NOT NULL,\n" +
+		"    PRIMARY KEY (`id`)\n" +
+		")",
+}
+
+func TestDatabaseName(t *testing.T) {
+	a, err := databaseName()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := databaseName()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(a, "myddlmakertest_") || a == b {
+		t.Errorf("want the random names, got %q and %q", a, b)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	ctx := context.Background()
+	db := Open(t, ddl...)
+	if _, err := db.ExecContext(ctx, "INSERT INTO `parent` (`id`) VALUES (1)"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ExecContext(ctx, "INSERT INTO `child` (`id`, `parent_id`) VALUES (1, 1)"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the foreign key checks are enabled after the DDL is applied.
+	if _, err := db.ExecContext(ctx, "INSERT INTO `child` (`id`, `parent_id`) VALUES (2, 2)"); err == nil {
+		t.Error("want the foreign key error, got nil")
+	}
+
+	// each call has its own database.
+	other := Open(t, ddl...)
+	var n int
+	if err := other.QueryRowContext(ctx, "SELECT COUNT(*) FROM `parent`").Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Errorf("want the empty table, got %d rows", n)
+	}
+}
+
+func TestOpen_SQLFile(t *testing.T) {
+	// the content of schema.sql has multiple statements.
+	db := Open(t, "SET foreign_key_checks=0;\n\nDROP TABLE IF EXISTS `parent`;\n\n"+ddl[1]+";\n\nSET foreign_key_checks=1;\n")
+	if _, err := db.ExecContext(context.Background(), "INSERT INTO `parent` (`id`) VALUES (1)"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type Child struct {
+	ID       int32
+	ParentID int32
+}
+
+func (*Child) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
+
+func (*Child) ForeignKeys() []*myddlmaker.ForeignKey {
+	return []*myddlmaker.ForeignKey{
+		myddlmaker.NewForeignKey("fk_parent", []string{"parent_id"}, "parent", []string{"id"}),
+	}
+}
+
+type Parent struct {
+	ID int32
+}
+
+func (*Parent) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
+
+// recorder records the errors of AssertSchema.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertSchema(t *testing.T) {
+	db := Open(t, ddl...)
+	m, err := myddlmaker.New(&myddlmaker.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.AddStructs(&Child{}, &Parent{})
+	AssertSchema(t, db, m)
+
+	// the migration that is not reflected in the structs.
+	if _, err := db.ExecContext(context.Background(), "ALTER TABLE `parent` ADD COLUMN `name` VARCHAR(255) NOT NULL"); err != nil {
+		t.Fatal(err)
+	}
+	r := &recorder{TB: t}
+	AssertSchema(r, db, m)
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], `table "parent", column "name": the column is in the database, but not in the structs`) {
+		t.Errorf("unexpected errors: %q", r.errors)
+	}
+}
@@ -0,0 +1,128 @@
+package myddlmaker
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Namer converts Go identifiers into SQL identifiers.
+type Namer func(name string) string
+
+var (
+	_ Namer = SnakeCase
+	_ Namer = PluralSnakeCase
+	_ Namer = LowerCase
+)
+
+// SnakeCase converts name into snake case.
+// It is the default naming strategy.
+//
+//	SnakeCase("UserID") // "user_id"
+func SnakeCase(name string) string {
+	return camelToSnake(name)
+}
+
+// PluralSnakeCase converts name into snake case, and pluralizes the last word.
+//
+//	PluralSnakeCase("User")        // "users"
+//	PluralSnakeCase("UserCompany") // "user_companies"
+func PluralSnakeCase(name string) string {
+	return pluralize(camelToSnake(name))
+}
+
+// LowerCase converts name into lower case.
+//
+//	LowerCase("UserID") // "userid"
+func LowerCase(name string) string {
+	return strings.ToLower(name)
+}
+
+// pluralize returns the plural form of the English word s.
+// It handles only regular plurals.
+func pluralize(s string) string {
+	switch {
+	case s == "":
+		return s
+	case strings.HasSuffix(s, "s"), strings.HasSuffix(s, "x"), strings.HasSuffix(s, "z"),
+		strings.HasSuffix(s, "ch"), strings.HasSuffix(s, "sh"):
+		return s + "es"
+	case strings.HasSuffix(s, "y") && len(s) >= 2 && !strings.ContainsRune("aeiou", rune(s[len(s)-2])):
+		return s[:len(s)-1] + "ies"
+	default:
+		return s + "s"
+	}
+}
+
+// tableName returns the table name for the struct name.
+func (c *Config) tableName(name string) string {
+	if c.TableNamer != nil {
+		return c.TableNamer(name)
+	}
+	return camelToSnake(name)
+}
+
+// columnName returns the column name for the field name.
+func (c *Config) columnName(name string) string {
+	if c.ColumnNamer != nil {
+		return c.ColumnNamer(name)
+	}
+	return camelToSnake(name)
+}
+
+// TableNameNumber is the grammatical number of table names.
+type TableNameNumber int
+
+const (
+	// TableNameAny allows both singular and plural table names.
+	TableNameAny TableNameNumber = iota
+
+	// TableNameSingular requires singular table names, e.g. "user".
+	TableNameSingular
+
+	// TableNamePlural requires plural table names, e.g. "users".
+	TableNamePlural
+)
+
+// NamingRules is a set of naming conventions that the DDL Maker checks.
+// The zero value checks nothing.
+type NamingRules struct {
+	// SnakeCase requires the names of tables, columns, indexes and constraints to be snake case.
+	SnakeCase bool
+
+	// ForeignKeyColumnSuffix is the suffix that the columns of foreign key constraints must have (e.g. "_id").
+	ForeignKeyColumnSuffix string
+
+	// TableNameNumber is the grammatical number of table names.
+	// The last word of the table name is checked.
+	TableNameNumber TableNameNumber
+
+	// MaxIdentifierLength is the maximum length of the names of tables, columns, indexes and constraints.
+	// If it is zero, the length is not checked.
+	MaxIdentifierLength int
+}
+
+var snakeCasePattern = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+// isSnakeCase reports whether s is snake case.
+func isSnakeCase(s string) bool {
+	return snakeCasePattern.MatchString(s)
+}
+
+// isPlural reports whether the last word of the snake case name s looks like a plural.
+// It handles only regular plurals.
+func isPlural(s string) bool {
+	if i := strings.LastIndexByte(s, '_'); i >= 0 {
+		s = s[i+1:]
+	}
+	s = strings.ToLower(s)
+	if !strings.HasSuffix(s, "s") {
+		return false
+	}
+	// e.g. "class", "status", "analysis"
+	for _, suffix := range []string{"ss", "us", "is"} {
+		if strings.HasSuffix(s, suffix) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,84 @@
+package myddlmaker
+
+import "testing"
+
+func TestPluralSnakeCase(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"User", "users"},
+		{"UserCompany", "user_companies"},
+		{"Day", "days"},
+		{"Box", "boxes"},
+		{"Status", "statuses"},
+		{"Match", "matches"},
+		{"Wish", "wishes"},
+		{"APIKey", "api_keys"},
+	}
+	for _, tt := range tests {
+		got := PluralSnakeCase(tt.in)
+		if got != tt.want {
+			t.Errorf("PluralSnakeCase(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLowerCase(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"User", "user"},
+		{"UserID", "userid"},
+	}
+	for _, tt := range tests {
+		got := LowerCase(tt.in)
+		if got != tt.want {
+			t.Errorf("LowerCase(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTable_Namer(t *testing.T) {
+	got, err := newTable(&Config{
+		TableNamer:  PluralSnakeCase,
+		ColumnNamer: LowerCase,
+	}, &Shop{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.name != "shops" {
+		t.Errorf("unexpected table name: %q", got.name)
+	}
+	want := []string{"id", "address_city", "address_zip", "createdat", "updatedat"}
+	if len(got.columns) != len(want) {
+		t.Fatalf("unexpected columns: %v", got.columns)
+	}
+	for i, col := range got.columns {
+		if col.name != want[i] {
+			t.Errorf("%d: unexpected column name: want %q, got %q", i, want[i], col.name)
+		}
+	}
+}
+
+func TestIsPlural(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"user", false},
+		{"users", true},
+		{"user_companies", true},
+		{"user_company", false},
+		{"status", false},
+		{"class", false},
+		{"analysis", false},
+	}
+	for _, tt := range tests {
+		got := isPlural(tt.in)
+		if got != tt.want {
+			t.Errorf("isPlural(%q) = %t, want %t", tt.in, got, tt.want)
+		}
+	}
+}
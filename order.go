@@ -0,0 +1,70 @@
+package myddlmaker
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sortTablesByForeignKeys returns the tables in the order that the tables referenced by the foreign key constraints come first,
+// so that they can be created without disabling foreign_key_checks.
+// The other tables keep the order of the structs.
+// It returns an error if the foreign key constraints form a cycle.
+// The tables that refer to themselves and the logical relations are allowed.
+func sortTablesByForeignKeys(tables []*table) ([]*table, error) {
+	byName := make(map[string]*table, len(tables))
+	for _, t := range tables {
+		byName[t.fullName()] = t
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[*table]int, len(tables))
+	ret := make([]*table, 0, len(tables))
+	var path []*table
+	var visit func(t *table) error
+	visit = func(t *table) error {
+		switch state[t] {
+		case visited:
+			return nil
+		case visiting:
+			// the path from t to the end is the cycle.
+			var names []string
+			for i := len(path) - 1; i >= 0; i-- {
+				names = append([]string{path[i].fullName()}, names...)
+				if path[i] == t {
+					break
+				}
+			}
+			names = append(names, t.fullName())
+			return fmt.Errorf("myddlmaker: the foreign key constraints form a cycle: %s", strings.Join(names, " -> "))
+		}
+
+		state[t] = visiting
+		path = append(path, t)
+		for _, fk := range t.foreignKeys {
+			if fk.logical {
+				continue
+			}
+			ref, ok := byName[fk.referencedTable(t)]
+			if !ok || ref == t {
+				continue
+			}
+			if err := visit(ref); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[t] = visited
+		ret = append(ret, t)
+		return nil
+	}
+	for _, t := range tables {
+		if err := visit(t); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
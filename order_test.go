@@ -0,0 +1,150 @@
+package myddlmaker
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type SortComment struct {
+	ID     int32
+	PostID int32
+}
+
+func (*SortComment) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+func (*SortComment) Indexes() []*Index {
+	return []*Index{NewIndex("idx_post_id", "post_id")}
+}
+
+func (*SortComment) ForeignKeys() []*ForeignKey {
+	return []*ForeignKey{
+		NewForeignKey("fk_post", []string{"post_id"}, "sort_post", []string{"id"}),
+	}
+}
+
+type SortPost struct {
+	ID       int32
+	UserID   int32
+	ParentID int32
+}
+
+func (*SortPost) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+func (*SortPost) Indexes() []*Index {
+	return []*Index{
+		NewIndex("idx_user_id", "user_id"),
+		NewIndex("idx_parent_id", "parent_id"),
+	}
+}
+
+func (*SortPost) ForeignKeys() []*ForeignKey {
+	return []*ForeignKey{
+		NewForeignKey("fk_user", []string{"user_id"}, "sort_user", []string{"id"}),
+		// the self reference is allowed.
+		NewForeignKey("fk_parent", []string{"parent_id"}, "sort_post", []string{"id"}),
+	}
+}
+
+type SortUser struct {
+	ID     int32
+	PostID int32
+}
+
+func (*SortUser) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+func (*SortUser) Indexes() []*Index {
+	return []*Index{NewIndex("idx_post_id", "post_id")}
+}
+
+func TestMaker_SortTablesByForeignKeys(t *testing.T) {
+	m, err := New(&Config{SortTablesByForeignKeys: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.AddStructs(&SortComment{}, &SortPost{}, &SortUser{})
+	var buf bytes.Buffer
+	if err := m.Generate(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := "DROP TABLE IF EXISTS `sort_comment`;\n" +
+		"DROP TABLE IF EXISTS `sort_post`;\n" +
+		"DROP TABLE IF EXISTS `sort_user`;\n" +
+		"\n" +
+		"CREATE TABLE `sort_user` (\n" +
+		"    `id` INTEGER NOT NULL,\n" +
+		"    `post_id` INTEGER NOT NULL,\n" +
+		"    INDEX `idx_post_id` (`post_id`),\n" +
+		"    PRIMARY KEY (`id`)\n" +
+		");\n" +
+		"\n" +
+		"CREATE TABLE `sort_post` (\n" +
+		"    `id` INTEGER NOT NULL,\n" +
+		"    `user_id` INTEGER NOT NULL,\n" +
+		"    `parent_id` INTEGER NOT NULL,\n" +
+		"    INDEX `idx_user_id` (`user_id`),\n" +
+		"    INDEX `idx_parent_id` (`parent_id`),\n" +
+		"    CONSTRAINT `fk_user` FOREIGN KEY (`user_id`) REFERENCES `sort_user` (`id`),\n" +
+		"    CONSTRAINT `fk_parent` FOREIGN KEY (`parent_id`) REFERENCES `sort_post` (`id`),\n" +
+		"    PRIMARY KEY (`id`)\n" +
+		");\n" +
+		"\n" +
+		"CREATE TABLE `sort_comment` (\n" +
+		"    `id` INTEGER NOT NULL,\n" +
+		"    `post_id` INTEGER NOT NULL,\n" +
+		"    INDEX `idx_post_id` (`post_id`),\n" +
+		"    CONSTRAINT `fk_post` FOREIGN KEY (`post_id`) REFERENCES `sort_post` (`id`),\n" +
+		"    PRIMARY KEY (`id`)\n" +
+		");\n"
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("ddl is not match: (-want/+got)\n%s", diff)
+	}
+}
+
+type SortCycleUser struct {
+	ID     int32
+	PostID int32
+}
+
+func (*SortCycleUser) Table() string {
+	return "sort_user"
+}
+
+func (*SortCycleUser) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+func (*SortCycleUser) Indexes() []*Index {
+	return []*Index{NewIndex("idx_post_id", "post_id")}
+}
+
+func (*SortCycleUser) ForeignKeys() []*ForeignKey {
+	return []*ForeignKey{
+		NewForeignKey("fk_latest_post", []string{"post_id"}, "sort_post", []string{"id"}),
+	}
+}
+
+func TestMaker_SortTablesByForeignKeysCycle(t *testing.T) {
+	m, err := New(&Config{SortTablesByForeignKeys: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.AddStructs(&SortComment{}, &SortPost{}, &SortCycleUser{})
+	var buf bytes.Buffer
+	err = m.Generate(&buf)
+	if err == nil {
+		t.Fatal("want an error, got nil")
+	}
+	want := "the foreign key constraints form a cycle: sort_post -> sort_user -> sort_post"
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("want %q, got %q", want, err.Error())
+	}
+}
@@ -0,0 +1,122 @@
+package myddlmaker
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// OutFileData is the data of the template of Config.OutFilePath.
+type OutFileData struct {
+	// Schema is the schema name of the tables in the file,
+	// or an empty string for the tables that don't have any schema or if SplitSchemaFiles is not set.
+	Schema string
+
+	// Package is the package name of the Go source code, i.e. Config.PackageName.
+	Package string
+
+	// Date is the date of the generation in UTC, e.g. "20240102".
+	Date string
+}
+
+// parseOutFilePath parses Config.OutFilePath as a template if it has "{{".
+func parseOutFilePath(path string) (*template.Template, error) {
+	if !strings.Contains(path, "{{") {
+		return nil, nil
+	}
+	tmpl, err := template.New("OutFilePath").Option("missingkey=error").Parse(path)
+	if err != nil {
+		return nil, fmt.Errorf("myddlmaker: invalid OutFilePath %q: %w", path, err)
+	}
+	return tmpl, nil
+}
+
+// sqlFilePath returns the file path of the SQL of the tables in the schema.
+func (m *Maker) sqlFilePath(schema string) (string, error) {
+	path := m.config.OutFilePath
+	if m.outFilePath != nil {
+		var err error
+		path, err = m.executeOutFilePath(schema)
+		if err != nil {
+			return "", err
+		}
+		if schema != "" {
+			// add the suffix if the template doesn't refer to the schema.
+			base, err := m.executeOutFilePath("")
+			if err != nil {
+				return "", err
+			}
+			if path == base {
+				path = schemaFilePath(path, schema)
+			}
+		}
+	} else {
+		path = schemaFilePath(path, schema)
+	}
+	if m.config.OutDir != "" {
+		path = filepath.Join(m.config.OutDir, path)
+	}
+	return path, nil
+}
+
+// executeOutFilePath executes the template of Config.OutFilePath.
+func (m *Maker) executeOutFilePath(schema string) (string, error) {
+	var buf strings.Builder
+	data := &OutFileData{
+		Schema:  schema,
+		Package: m.config.PackageName,
+		Date:    m.now().UTC().Format("20060102"),
+	}
+	if err := m.outFilePath.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("myddlmaker: failed to execute OutFilePath %q: %w", m.config.OutFilePath, err)
+	}
+	return buf.String(), nil
+}
+
+// tableFilePath returns the file path of the SQL of the table for SplitTableFiles, e.g. "db1/user.sql".
+func (m *Maker) tableFilePath(table *table) (string, error) {
+	dir := m.config.OutDir
+	if dir == "" {
+		path, err := m.sqlFilePath("")
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Dir(path)
+	}
+	return filepath.Join(dir, table.schema, table.name+".sql"), nil
+}
+
+// tableFiles returns the SQL files of each table for SplitTableFiles.
+// The tables are not qualified by the schema names, because the directories are the schemas.
+func (m *Maker) tableFiles() ([]generatedFile, error) {
+	if err := m.parse(); err != nil {
+		return nil, fmt.Errorf("myddlmaker: failed to generate ddl: %w", err)
+	}
+
+	files := make([]generatedFile, 0, len(m.tables))
+	paths := make(map[string]string, len(m.tables))
+	for _, table := range m.tables {
+		path, err := m.tableFilePath(table)
+		if err != nil {
+			return nil, err
+		}
+		// the file systems may be case-insensitive.
+		if name, ok := paths[strings.ToLower(path)]; ok {
+			return nil, fmt.Errorf("myddlmaker: table %q: the file %q is already generated by table %q", table.fullName(), path, name)
+		}
+		paths[strings.ToLower(path)] = table.fullName()
+
+		unqualified := *table // shallow copy
+		unqualified.schema = ""
+		var ddl strings.Builder
+		m.generateCreateTable(&ddl, &unqualified)
+		var buf bytes.Buffer
+		m.writeSQLHeader(&buf)
+		buf.WriteString(m.formatSQL(ddl.String()))
+		buf.WriteString(";\n")
+		files = append(files, generatedFile{path: path, content: buf.Bytes()})
+	}
+	return files, nil
+}
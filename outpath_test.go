@@ -0,0 +1,126 @@
+package myddlmaker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMaker_sqlFilePath(t *testing.T) {
+	tests := []struct {
+		outFilePath string
+		outDir      string
+		schema      string
+		want        string
+	}{
+		{"", "", "", "schema.sql"},
+		{"", "", "db1", "schema_db1.sql"},
+		{"ddl.sql", "sql", "", filepath.Join("sql", "ddl.sql")},
+		{"ddl.sql", "sql", "db1", filepath.Join("sql", "ddl_db1.sql")},
+		{"{{.Date}}_{{.Package}}.sql", "", "", "20240103_models.sql"},
+		{"{{.Date}}_{{.Package}}.sql", "", "db1", "20240103_models_db1.sql"},
+		{"{{if .Schema}}{{.Schema}}{{else}}default{{end}}/schema.sql", "sql", "", filepath.Join("sql", "default", "schema.sql")},
+		{"{{if .Schema}}{{.Schema}}{{else}}default{{end}}/schema.sql", "sql", "db1", filepath.Join("sql", "db1", "schema.sql")},
+	}
+	for _, tt := range tests {
+		m, err := New(&Config{
+			OutFilePath: tt.outFilePath,
+			OutDir:      tt.outDir,
+			PackageName: "models",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the date is in UTC.
+		m.now = func() time.Time {
+			return time.Date(2024, 1, 2, 23, 0, 0, 0, time.FixedZone("UTC-3", -3*60*60))
+		}
+		got, err := m.sqlFilePath(tt.schema)
+		if err != nil {
+			t.Errorf("OutFilePath %q, schema %q: %v", tt.outFilePath, tt.schema, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("OutFilePath %q, schema %q: want %q, got %q", tt.outFilePath, tt.schema, tt.want, got)
+		}
+	}
+}
+
+func TestMaker_sqlFilePathError(t *testing.T) {
+	if _, err := New(&Config{OutFilePath: "{{.Schema"}); err == nil {
+		t.Error("want an error for the invalid template, got nil")
+	}
+
+	m, err := New(&Config{OutFilePath: "{{.Unknown}}.sql"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.sqlFilePath(""); err == nil {
+		t.Error("want an error for the unknown field, got nil")
+	}
+}
+
+func TestMaker_GenerateFileOutDir(t *testing.T) {
+	dir := t.TempDir()
+	m, err := New(&Config{
+		OutFilePath:      "{{.Schema}}/schema.sql",
+		OutDir:           filepath.Join(dir, "sql"),
+		SplitSchemaFiles: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.AddStructs(&Sc1{}, &Sc2{})
+	if err := m.GenerateFile(); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"db1", "db2"} {
+		if _, err := os.Stat(filepath.Join(dir, "sql", name, "schema.sql")); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestMaker_SplitTableFiles(t *testing.T) {
+	dir := t.TempDir()
+	m, err := New(&Config{
+		OutFilePath:     filepath.Join(dir, "schema.sql"),
+		SplitTableFiles: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.AddStructs(&Sc1{}, &Sc2{})
+	if err := m.GenerateFile(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		filepath.Join("db1", "sc1.sql"): "CREATE TABLE `sc1` (\n" +
+			"    `id` INTEGER NOT NULL,\n" +
+			"    PRIMARY KEY (`id`)\n" +
+			");\n",
+		filepath.Join("db2", "sc2.sql"): "CREATE TABLE `sc2` (\n" +
+			"    `id` INTEGER NOT NULL,\n" +
+			"    `sc1_id` INTEGER NOT NULL,\n" +
+			"    INDEX `idx_sc1_id` (`sc1_id`),\n" +
+			"    CONSTRAINT `fk_sc1` FOREIGN KEY (`sc1_id`) REFERENCES `db1`.`sc1` (`id`),\n" +
+			"    PRIMARY KEY (`id`)\n" +
+			");\n",
+	}
+	for name, ddl := range want {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(ddl, string(got)); diff != "" {
+			t.Errorf("%s (-want/+got):\n%s", name, diff)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "schema.sql")); !os.IsNotExist(err) {
+		t.Errorf("schema.sql is generated: %v", err)
+	}
+}
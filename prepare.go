@@ -0,0 +1,343 @@
+package myddlmaker
+
+import (
+	"fmt"
+	"io"
+)
+
+// usesPrepare reports whether the generated functions prepare the statements with the prepare function,
+// instead of calling PrepareContext directly.
+func (m *Maker) usesPrepare() bool {
+	return m.hasInstrumentation() || m.config.CachePreparedStatements || m.config.GenerateRetry
+}
+
+// goPrepare returns the Go expression that prepares the statement q with the execer.
+func (m *Maker) goPrepare(q string) string {
+	if m.usesPrepare() {
+		return fmt.Sprintf("prepare(ctx, execer, %s)", q)
+	}
+	return fmt.Sprintf("execer.PrepareContext(ctx, %s)", q)
+}
+
+// usesAsDB reports whether the Go source code has the asDB function.
+func (m *Maker) usesAsDB() bool {
+	return m.config.CachePreparedStatements || m.config.GenerateRetry
+}
+
+// generateGoAsDB generates the function that returns the connection pool of the execer.
+func (m *Maker) generateGoAsDB(w io.Writer) {
+	fmt.Fprintf(w, "// asDB returns the connection pool of the execer, or false if it is a connection or a transaction.\n")
+	fmt.Fprintf(w, "func asDB(e execer) (*sql.DB, bool) {\n")
+	fmt.Fprintf(w, "switch e := e.(type) {\n")
+	fmt.Fprintf(w, "case *sql.DB:\n return e, true \n")
+	if m.config.GenerateDBPair {
+		fmt.Fprintf(w, "case DBPair:\n return e.Writer, true \n")
+	}
+	if m.hasInstrumentation() {
+		fmt.Fprintf(w, "case *instrumentedExecer:\n return asDB(e.execer) \n")
+	}
+	if m.config.CachePreparedStatements {
+		fmt.Fprintf(w, "case cachedDB:\n return e.DB, true \n")
+	}
+	if m.config.GenerateRetry {
+		fmt.Fprintf(w, "case *retryingExecer:\n return asDB(e.execer) \n")
+	}
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "return nil, false\n")
+	fmt.Fprintf(w, "}\n\n")
+}
+
+// generateGoCacheStatements generates the code that executes the statements of the generated function
+// with the cached prepared statements, if configured.
+// It is used only by the functions whose statements are fixed, or have a few variations,
+// so that the dynamic statements don't evict the others from the cache.
+// db is the name of the parameter, "execer" or "queryer".
+func (m *Maker) generateGoCacheStatements(w io.Writer, db string) {
+	if !m.config.CachePreparedStatements {
+		return
+	}
+	if db == "queryer" {
+		fmt.Fprintf(w, "queryer = cacheQueryer(ctx, queryer)\n")
+		return
+	}
+	fmt.Fprintf(w, "execer = cacheExecer(execer)\n")
+}
+
+// generateGoCacheSingleInsert generates the code that inserts a single value with the cached prepared statement, if configured.
+// The statements of the other numbers of the values are not cached except for the chunks of maxStructCount,
+// so that they don't evict the others from the cache.
+func (m *Maker) generateGoCacheSingleInsert(w io.Writer) {
+	if !m.config.CachePreparedStatements {
+		return
+	}
+	fmt.Fprintf(w, "if len(values) == 1 {\n execer = cacheExecer(execer) \n}\n")
+}
+
+// generateGoPrepare generates the prepare function, and the cache of the prepared statements if configured.
+func (m *Maker) generateGoPrepare(w io.Writer) {
+	fmt.Fprintf(w, `// preparedStmt is a prepared statement.
+	type preparedStmt interface {
+		ExecContext(ctx context.Context, args ...any) (sql.Result, error)
+		Close() error
+	}
+
+	`)
+
+	fmt.Fprintf(w, "// prepare prepares the statement.\n")
+	if m.config.CachePreparedStatements {
+		fmt.Fprintf(w, "// It reuses the cached statement if the execer is a connection pool.\n")
+	}
+	if m.hasInstrumentation() {
+		fmt.Fprintf(w, "// The statement is instrumented if the execer is instrumented.\n")
+	}
+	if m.config.GenerateRetry {
+		fmt.Fprintf(w, "// The statement is retried if the execer retries.\n")
+	}
+	fmt.Fprintf(w, "func prepare(ctx context.Context, e execer, query string) (preparedStmt, error) {\n")
+	if m.config.GenerateRetry {
+		fmt.Fprintf(w, "if r, ok := e.(*retryingExecer); ok {\n")
+		fmt.Fprintf(w, "stmt, err := prepare(ctx, r.execer, query)\n")
+		fmt.Fprintf(w, "if err != nil {\n return nil, err \n}\n")
+		fmt.Fprintf(w, "return &retryingStmt{preparedStmt: stmt}, nil\n")
+		fmt.Fprintf(w, "}\n")
+	}
+	if m.hasInstrumentation() {
+		fmt.Fprintf(w, "ie, instrumented := e.(*instrumentedExecer)\n")
+	}
+	fmt.Fprintf(w, "var stmt preparedStmt\n")
+	if m.config.CachePreparedStatements {
+		fmt.Fprintf(w, "db, cacheable := asDB(e)\n")
+		q := "query"
+		if m.config.GenerateSQLCommenter {
+			q = "commentQuery(ctx, query)"
+		}
+		fmt.Fprintf(w, "if cacheable {\n")
+		fmt.Fprintf(w, "s, err := preparedStmts.prepare(ctx, db, %s)\n", q)
+		fmt.Fprintf(w, "if err != nil {\n return nil, err \n}\n")
+		fmt.Fprintf(w, "stmt = s\n")
+		fmt.Fprintf(w, "} else {\n")
+	}
+	fmt.Fprintf(w, "s, err := e.PrepareContext(ctx, query)\n")
+	fmt.Fprintf(w, "if err != nil {\n return nil, err \n}\n")
+	fmt.Fprintf(w, "stmt = s\n")
+	if m.config.CachePreparedStatements {
+		fmt.Fprintf(w, "}\n")
+	}
+	if m.hasInstrumentation() {
+		fmt.Fprintf(w, "if instrumented {\n return &instrumentedStmt{preparedStmt: stmt, instrumentation: ie.instrumentation, query: query}, nil \n}\n")
+	}
+	fmt.Fprintf(w, "return stmt, nil\n")
+	fmt.Fprintf(w, "}\n\n")
+
+	if !m.config.CachePreparedStatements {
+		return
+	}
+	dbPairReader := ""
+	if m.config.GenerateDBPair {
+		dbPairReader = "case DBPair:\n return cachedDB{q.reader(ctx)}\n"
+	}
+	fmt.Fprintf(w, `// PreparedStatementCacheSize is the maximum number of the prepared statements that the generated functions cache.
+	// The least recently used statement is closed when the cache is full.
+	// It must not be changed concurrently with the generated functions.
+	var PreparedStatementCacheSize = %[1]d
+
+	// ClosePreparedStatements closes the prepared statements of db that the generated functions cache.
+	// Call it before closing db.
+	// The statements in use are closed when they are released.
+	func ClosePreparedStatements(db *sql.DB) error {
+		return preparedStmts.close(db)
+	}
+
+	// cachedDB executes the statements with the prepared statements cached for the connection pool.
+	type cachedDB struct {
+		*sql.DB
+	}
+
+	// cacheExecer returns the execer that executes the statements with the cached prepared statements
+	// if e is a connection pool, otherwise e.
+	func cacheExecer(e execer) execer {
+		if db, ok := asDB(e); ok {
+			return cachedDB{db}
+		}
+		return e
+	}
+
+	// cacheQueryer returns the queryer that executes the queries with the cached prepared statements
+	// if q is a connection pool, otherwise q.
+	func cacheQueryer(ctx context.Context, q queryer) queryer {
+		switch q := q.(type) {
+		case *sql.DB:
+			return cachedDB{q}
+		%[2]s}
+		return q
+	}
+
+	func (db cachedDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+		stmt, err := preparedStmts.prepare(ctx, db.DB, query)
+		if err != nil {
+			return nil, err
+		}
+		defer stmt.Close()
+		return stmt.ExecContext(ctx, args...)
+	}
+
+	// QueryContext releases the statement before the rows are read.
+	// It is safe, because *sql.Stmt closes the statement after closing the rows.
+	func (db cachedDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+		stmt, err := preparedStmts.prepare(ctx, db.DB, query)
+		if err != nil {
+			return nil, err
+		}
+		defer stmt.Close()
+		return stmt.QueryContext(ctx, args...)
+	}
+
+	func (db cachedDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+		stmt, err := preparedStmts.prepare(ctx, db.DB, query)
+		if err != nil {
+			// *sql.Row can't be created with the error, so the query reports it without the statement.
+			return db.DB.QueryRowContext(ctx, query, args...)
+		}
+		defer stmt.Close()
+		return stmt.QueryRowContext(ctx, args...)
+	}
+
+	var preparedStmts = &stmtCache{
+		stmts: map[stmtCacheKey]*list.Element{},
+		lru:   list.New(),
+	}
+
+	type stmtCacheKey struct {
+		db    *sql.DB
+		query string
+	}
+
+	// stmtCache is the LRU cache of the prepared statements.
+	type stmtCache struct {
+		mu    sync.Mutex
+		stmts map[stmtCacheKey]*list.Element
+		lru   *list.List
+	}
+
+	// cachedStmt is a prepared statement in the cache.
+	// It is closed when it has been evicted from the cache and no one uses it.
+	type cachedStmt struct {
+		stmt    *sql.Stmt
+		key     stmtCacheKey
+		refs    int
+		evicted bool
+	}
+
+	func (c *stmtCache) prepare(ctx context.Context, db *sql.DB, query string) (*stmtRef, error) {
+		key := stmtCacheKey{db: db, query: query}
+		if ref := c.get(key); ref != nil {
+			return ref, nil
+		}
+
+		stmt, err := db.PrepareContext(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if elem, ok := c.stmts[key]; ok {
+			// another goroutine has prepared the same statement.
+			stmt.Close()
+			return c.ref(elem), nil
+		}
+		c.stmts[key] = c.lru.PushFront(&cachedStmt{stmt: stmt, key: key})
+		ref := c.ref(c.stmts[key])
+		for c.lru.Len() > PreparedStatementCacheSize {
+			c.evict(c.lru.Back())
+		}
+		return ref, nil
+	}
+
+	func (c *stmtCache) get(key stmtCacheKey) *stmtRef {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if elem, ok := c.stmts[key]; ok {
+			return c.ref(elem)
+		}
+		return nil
+	}
+
+	// ref returns the reference to the statement, and marks it as recently used.
+	// c.mu must be held.
+	func (c *stmtCache) ref(elem *list.Element) *stmtRef {
+		c.lru.MoveToFront(elem)
+		s := elem.Value.(*cachedStmt)
+		s.refs++
+		return &stmtRef{cache: c, stmt: s}
+	}
+
+	// evict removes the statement from the cache, and closes it if no one uses it.
+	// c.mu must be held.
+	func (c *stmtCache) evict(elem *list.Element) error {
+		s := c.lru.Remove(elem).(*cachedStmt)
+		delete(c.stmts, s.key)
+		s.evicted = true
+		if s.refs == 0 {
+			return s.stmt.Close()
+		}
+		return nil
+	}
+
+	// release releases the reference to the statement, and closes it if it has been evicted and no one uses it.
+	func (c *stmtCache) release(s *cachedStmt) error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		s.refs--
+		if s.evicted && s.refs == 0 {
+			return s.stmt.Close()
+		}
+		return nil
+	}
+
+	func (c *stmtCache) close(db *sql.DB) error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		var firstErr error
+		for elem := c.lru.Front(); elem != nil; {
+			next := elem.Next()
+			if elem.Value.(*cachedStmt).key.db == db {
+				if err := c.evict(elem); err != nil && firstErr == nil {
+					firstErr = err
+				}
+			}
+			elem = next
+		}
+		return firstErr
+	}
+
+	// stmtRef is a reference to the cached statement.
+	// Close releases the reference, it doesn't close the statement in the cache.
+	type stmtRef struct {
+		cache    *stmtCache
+		stmt     *cachedStmt
+		released bool
+	}
+
+	func (r *stmtRef) ExecContext(ctx context.Context, args ...any) (sql.Result, error) {
+		return r.stmt.stmt.ExecContext(ctx, args...)
+	}
+
+	func (r *stmtRef) QueryContext(ctx context.Context, args ...any) (*sql.Rows, error) {
+		return r.stmt.stmt.QueryContext(ctx, args...)
+	}
+
+	func (r *stmtRef) QueryRowContext(ctx context.Context, args ...any) *sql.Row {
+		return r.stmt.stmt.QueryRowContext(ctx, args...)
+	}
+
+	func (r *stmtRef) Close() error {
+		if r.released {
+			return nil
+		}
+		r.released = true
+		return r.cache.release(r.stmt)
+	}
+
+	`, withDefault(m.config.PreparedStatementCacheSize, 128), dbPairReader)
+}
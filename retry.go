@@ -0,0 +1,128 @@
+package myddlmaker
+
+import (
+	"fmt"
+	"io"
+)
+
+// generateGoRetryPolicy generates the retry policy of the write functions.
+func (m *Maker) generateGoRetryPolicy(w io.Writer) {
+	fmt.Fprintf(w, `// RetryPolicy is the policy of retrying the generated write functions
+	// on deadlocks, lock wait timeouts and connection errors.
+	// They are retried only with the connection pools, because MySQL rolls back the whole transaction on deadlocks.
+	type RetryPolicy struct {
+		// MaxAttempts is the maximum number of attempts including the first one.
+		// Zero or one disables retrying.
+		MaxAttempts int
+
+		// Backoff returns the delay before the n-th retry, starting from 1.
+		// If it is nil, the functions are retried without delay.
+		Backoff func(n int) time.Duration
+	}
+
+	var retryPolicy = RetryPolicy{
+		MaxAttempts: 3,
+		Backoff: func(n int) time.Duration {
+			return 10 * time.Millisecond << (n - 1)
+		},
+	}
+
+	// SetRetryPolicy sets the retry policy of the generated write functions.
+	// It must not be called concurrently with the generated functions.
+	func SetRetryPolicy(p RetryPolicy) {
+		retryPolicy = p
+	}
+
+	// IsRetryable reports whether err is a deadlock, a lock wait timeout or a connection error.
+	// Note that the write statements may have been applied before the connection errors except driver.ErrBadConn.
+	func IsRetryable(err error) bool {
+		var myErr *mysql.MySQLError
+		if errors.As(err, &myErr) {
+			// ER_LOCK_DEADLOCK and ER_LOCK_WAIT_TIMEOUT
+			return myErr.Number == 1213 || myErr.Number == 1205
+		}
+		return errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn)
+	}
+
+	// canRetryWrite reports whether err guarantees that the write statement was not applied:
+	// MySQL rolls back the statement on deadlocks and lock wait timeouts,
+	// and driver.ErrBadConn is returned only if the statement was not sent.
+	// mysql.ErrInvalidConn is not the case, because the connection may be lost after the statement is applied.
+	func canRetryWrite(err error) bool {
+		var myErr *mysql.MySQLError
+		if errors.As(err, &myErr) {
+			return myErr.Number == 1213 || myErr.Number == 1205
+		}
+		return errors.Is(err, driver.ErrBadConn)
+	}
+
+	// retry calls f until it succeeds, it returns the error that is not retryable, or the attempts run out.
+	func retry(ctx context.Context, f func() error) error {
+		p := retryPolicy
+		for n := 1; ; n++ {
+			err := f()
+			if err == nil || n >= p.MaxAttempts || !canRetryWrite(err) {
+				return err
+			}
+			if p.Backoff != nil {
+				timer := time.NewTimer(p.Backoff(n))
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return err
+				case <-timer.C:
+				}
+			}
+		}
+	}
+
+	// retryingExecer retries each statement, instead of the whole function,
+	// so that the statements that have succeeded are not executed again.
+	type retryingExecer struct {
+		execer
+	}
+
+	// retryExecer returns the execer that retries the statements if e is a connection pool, otherwise e.
+	func retryExecer(e execer) execer {
+		if _, ok := asDB(e); !ok || retryPolicy.MaxAttempts <= 1 {
+			return e
+		}
+		return &retryingExecer{execer: e}
+	}
+
+	func (e *retryingExecer) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+		var result sql.Result
+		err := retry(ctx, func() error {
+			var err error
+			result, err = e.execer.ExecContext(ctx, query, args...)
+			return err
+		})
+		return result, err
+	}
+
+	// retryingStmt is the prepared statement of retryingExecer.
+	type retryingStmt struct {
+		preparedStmt
+	}
+
+	func (s *retryingStmt) ExecContext(ctx context.Context, args ...any) (sql.Result, error) {
+		var result sql.Result
+		err := retry(ctx, func() error {
+			var err error
+			result, err = s.preparedStmt.ExecContext(ctx, args...)
+			return err
+		})
+		return result, err
+	}
+
+	`)
+}
+
+// generateGoRetry generates the code that retries the statements of the write function.
+// It must be called after generateGoInstrument, so that each attempt is instrumented.
+func (m *Maker) generateGoRetry(w io.Writer) {
+	if !m.config.GenerateRetry {
+		return
+	}
+	fmt.Fprintf(w, "execer = retryExecer(execer)\n")
+}
@@ -0,0 +1,170 @@
+package myddlmaker
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// hasSeeder reports whether the Go source code has the seeder of the tables.
+func (m *Maker) hasSeeder() bool {
+	return m.config.GenerateSeeder && len(m.tables) > 0
+}
+
+// generateGoSeeder generates Seed, the seeds of all the tables and the helpers of the seeder.
+func (m *Maker) generateGoSeeder(w io.Writer) {
+	tables := m.insertOrder()
+	names := make([]string, 0, len(tables))
+	for _, t := range tables {
+		names = append(names, t.fullName())
+	}
+
+	fmt.Fprintf(w, "// Seeds are the numbers of the rows and the generators of the columns of the tables that Seed inserts.\n")
+	fmt.Fprintf(w, "type Seeds struct {\n")
+	for _, t := range m.tables {
+		fmt.Fprintf(w, "%[1]s %[1]sSeed\n", t.rawName)
+	}
+	fmt.Fprintf(w, "}\n\n")
+
+	fmt.Fprintf(w, "// Seed inserts the fake rows into the tables for the load tests.\n")
+	fmt.Fprintf(w, "// The tables referenced by the foreign keys are inserted first: %s.\n", strings.Join(names, ", "))
+	fmt.Fprintf(w, "func Seed(ctx context.Context, db dbtx, seeds *Seeds) error {\n")
+	for _, t := range tables {
+		fmt.Fprintf(w, "if err := seed%[1]s(ctx, db, &seeds.%[1]s); err != nil {\n return err \n}\n", t.rawName)
+	}
+	fmt.Fprintf(w, "return nil\n")
+	fmt.Fprintf(w, "}\n\n")
+
+	fmt.Fprintf(w, `// seedBatchSize is the number of the rows that Seed builds and inserts at once.
+	const seedBatchSize = 1000
+
+	// seedKeysLimit is the maximum number of the rows of the referenced table that the foreign keys refer to.
+	const seedKeysLimit = 100000
+
+	// seedKeys selects the rows of the referenced table, and scans their columns into the fields of the foreign key.
+	func seedKeys[T any](ctx context.Context, queryer queryer, query string, scan func(rows *sql.Rows, v *T) error) ([]*T, error) {
+		rows, err := queryer.QueryContext(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		var ret []*T
+		for rows.Next() {
+			v := new(T)
+			if err := scan(rows, v); err != nil {
+				return nil, err
+			}
+			ret = append(ret, v)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return ret, nil
+	}
+
+	`)
+}
+
+// generateGoTableSeed generates the seed of the table and the function that inserts it.
+func (m *Maker) generateGoTableSeed(w io.Writer, table *table) {
+	name := table.rawName
+	pkgPath := m.goPkgPath()
+	byName := make(map[string]*column, len(table.columns))
+	for _, c := range table.columns {
+		byName[c.name] = c
+	}
+
+	fmt.Fprintf(w, "// %sSeed is the number of the rows and the generators of the columns of %s that Seed inserts.\n", name, table.fullName())
+	fmt.Fprintf(w, "// The nil generators leave the fake values of New%sFactory,\n", name)
+	fmt.Fprintf(w, "// and the columns of the foreign keys refer to the existing rows of the referenced tables in turn.\n")
+	fmt.Fprintf(w, "// The generators of the unique columns must return the distinct values if the fake values may collide.\n")
+	fmt.Fprintf(w, "type %sSeed struct {\n", name)
+	fmt.Fprintf(w, "// Rows is the number of the rows.\n")
+	fmt.Fprintf(w, "Rows int\n")
+	var generators []*column
+	for _, c := range table.columns {
+		if c.fieldType == nil {
+			continue
+		}
+		typ := goTypeExpr(c.fieldType, pkgPath, map[string]bool{})
+		if typ == "" {
+			continue
+		}
+		generators = append(generators, c)
+		fmt.Fprintf(w, "\n// %s returns the value of the column %s of the i-th row.\n", c.rawName, c.name)
+		fmt.Fprintf(w, "%s func(i int) %s\n", c.rawName, typ)
+	}
+	fmt.Fprintf(w, "}\n\n")
+
+	fmt.Fprintf(w, "func seed%[1]s(ctx context.Context, db dbtx, seed *%[1]sSeed) error {\n", name)
+	fmt.Fprintf(w, "if seed.Rows <= 0 {\n return nil \n}\n")
+
+	// the rows of the referenced tables.
+	type reference struct {
+		keys    string
+		columns []*column
+	}
+	var refs []reference
+	for i, fk := range table.foreignKeys {
+		if fk.referencedTable(table) == table.fullName() {
+			// the rows that refer to themselves are not seeded.
+			continue
+		}
+		columns := make([]*column, 0, len(fk.columns))
+		for _, col := range fk.columns {
+			if c := byName[col]; c != nil && c.fieldType != nil {
+				columns = append(columns, c)
+			}
+		}
+		if len(columns) != len(fk.columns) {
+			continue
+		}
+		ref := reference{
+			keys:    fmt.Sprintf("keys%d", i),
+			columns: columns,
+		}
+		refs = append(refs, ref)
+
+		unset := make([]string, 0, len(columns))
+		fields := make([]string, 0, len(columns))
+		for _, c := range columns {
+			unset = append(unset, "seed."+c.rawName+" == nil")
+			fields = append(fields, "&v."+c.goField)
+		}
+		query := fmt.Sprintf("SELECT %s FROM %s LIMIT ", strings.Join(quoteAll(fk.references), ", "), quoteQualified(fk.referencedSchema(table), fk.table))
+		fmt.Fprintf(w, "var %s []*%s\n", ref.keys, name)
+		fmt.Fprintf(w, "if %s {\n", strings.Join(unset, " && "))
+		fmt.Fprintf(w, "var err error\n")
+		fmt.Fprintf(w, "%s, err = seedKeys(ctx, db, %q+strconv.Itoa(seedKeysLimit), func(rows *sql.Rows, v *%s) error {\n", ref.keys, query, name)
+		fmt.Fprintf(w, "return rows.Scan(%s)\n", strings.Join(fields, ", "))
+		fmt.Fprintf(w, "})\n")
+		fmt.Fprintf(w, "if err != nil {\n return fmt.Errorf(\"%s: failed to select the rows of %s: %%w\", err) \n}\n", table.fullName(), fk.referencedTable(table))
+		fmt.Fprintf(w, "if len(%s) == 0 {\n return errors.New(%q) \n}\n", ref.keys, fmt.Sprintf("%s: no rows of %s to refer by the foreign key %s", table.fullName(), fk.referencedTable(table), fk.name))
+		fmt.Fprintf(w, "}\n")
+	}
+
+	fmt.Fprintf(w, "factory := New%sFactory()\n", name)
+	fmt.Fprintf(w, "batch := make([]*%s, 0, seedBatchSize)\n", name)
+	fmt.Fprintf(w, "for i := 0; i < seed.Rows; i++ {\n")
+	fmt.Fprintf(w, "v := factory.Build()\n")
+	for _, ref := range refs {
+		fmt.Fprintf(w, "if len(%[1]s) > 0 {\n key := %[1]s[i%%len(%[1]s)]\n", ref.keys)
+		for _, c := range ref.columns {
+			fmt.Fprintf(w, "v.%[1]s = key.%[1]s\n", c.goField)
+		}
+		fmt.Fprintf(w, "}\n")
+	}
+	for _, c := range generators {
+		fmt.Fprintf(w, "if seed.%[1]s != nil {\n v.%[2]s = seed.%[1]s(i) \n}\n", c.rawName, c.goField)
+	}
+	fmt.Fprintf(w, "batch = append(batch, v)\n")
+	fmt.Fprintf(w, "if len(batch) == cap(batch) || i == seed.Rows-1 {\n")
+	fmt.Fprintf(w, "if err := Insert%s(ctx, db, batch...); err != nil {\n", name)
+	fmt.Fprintf(w, "return fmt.Errorf(\"%s: %%w\", err)\n", table.fullName())
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "batch = batch[:0]\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "return nil\n")
+	fmt.Fprintf(w, "}\n\n")
+}
@@ -0,0 +1,118 @@
+package myddlmaker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ReportFormat is a format of the reports that the DDL Maker generates.
+type ReportFormat string
+
+const (
+	// ReportFormatJSON is a JSON array of the objects.
+	ReportFormatJSON ReportFormat = "json"
+
+	// ReportFormatMarkdown is a Markdown table.
+	ReportFormatMarkdown ReportFormat = "markdown"
+)
+
+// SensitiveColumn is a column that is marked with the sensitive or pii option.
+type SensitiveColumn struct {
+	// Table is the table name qualified by the schema name.
+	Table string `json:"table"`
+
+	// Column is the column name.
+	Column string `json:"column"`
+
+	// Type is the type of the column, e.g. "VARCHAR(191)".
+	Type string `json:"type"`
+
+	// Class is the class of the personally identifiable information.
+	// It is empty if the column is marked with the sensitive option only.
+	Class string `json:"class,omitempty"`
+
+	// Comment is the comment of the column.
+	Comment string `json:"comment,omitempty"`
+}
+
+// SensitiveColumns returns the columns that hold sensitive data,
+// in the order of the tables and the columns.
+func (m *Maker) SensitiveColumns() ([]SensitiveColumn, error) {
+	if err := m.parse(); err != nil {
+		return nil, err
+	}
+
+	var ret []SensitiveColumn
+	for _, table := range m.tables {
+		for _, col := range table.columns {
+			if !col.sensitive {
+				continue
+			}
+			ret = append(ret, SensitiveColumn{
+				Table:   table.fullName(),
+				Column:  col.name,
+				Type:    col.sqlType(),
+				Class:   col.pii,
+				Comment: col.comment,
+			})
+		}
+	}
+	return ret, nil
+}
+
+// GenerateSensitiveReport writes the report of the sensitive columns in the format.
+func (m *Maker) GenerateSensitiveReport(w io.Writer, format ReportFormat) error {
+	cols, err := m.SensitiveColumns()
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case ReportFormatJSON:
+		if cols == nil {
+			cols = []SensitiveColumn{}
+		}
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(cols); err != nil {
+			return err
+		}
+	case ReportFormatMarkdown:
+		buf.WriteString("| Table | Column | Type | Class | Comment |\n")
+		buf.WriteString("| --- | --- | --- | --- | --- |\n")
+		for _, col := range cols {
+			fmt.Fprintf(&buf, "| %s | %s | %s | %s | %s |\n",
+				markdownEscape(col.Table), markdownEscape(col.Column), markdownEscape(col.Type),
+				markdownEscape(col.Class), markdownEscape(col.Comment))
+		}
+	default:
+		return fmt.Errorf("myddlmaker: unknown report format %q", format)
+	}
+
+	if _, err := buf.WriteTo(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+// sensitiveComment returns the comment of the column followed by its class.
+func (c *column) sensitiveComment() string {
+	label := "[sensitive]"
+	if c.pii != "" {
+		label = "[pii: " + c.pii + "]"
+	}
+	if c.comment == "" {
+		return label
+	}
+	return c.comment + " " + label
+}
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ")
+
+func markdownEscape(s string) string {
+	return markdownEscaper.Replace(s)
+}
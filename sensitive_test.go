@@ -0,0 +1,90 @@
+package myddlmaker
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type Customer struct {
+	ID       int32
+	Name     string `ddl:",pii=name"`
+	Email    string `ddl:",pii=email,comment=the email address"`
+	Password []byte `ddl:",sensitive"`
+	Note     string `ddl:",sensitive,comment=a|b"`
+}
+
+func (*Customer) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+func TestMaker_SensitiveColumns(t *testing.T) {
+	m, err := New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.AddStructs(&Customer{})
+
+	got, err := m.SensitiveColumns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SensitiveColumn{
+		{Table: "customer", Column: "name", Type: "VARCHAR(191)", Class: "name"},
+		{Table: "customer", Column: "email", Type: "VARCHAR(191)", Class: "email", Comment: "the email address"},
+		{Table: "customer", Column: "password", Type: "VARBINARY(767)"},
+		{Table: "customer", Column: "note", Type: "VARCHAR(191)", Comment: "a|b"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("sensitive columns are not match (-want/+got):\n%s", diff)
+	}
+
+	var buf bytes.Buffer
+	if err := m.GenerateSensitiveReport(&buf, ReportFormatMarkdown); err != nil {
+		t.Fatal(err)
+	}
+	wantMarkdown := "| Table | Column | Type | Class | Comment |\n" +
+		"| --- | --- | --- | --- | --- |\n" +
+		"| customer | name | VARCHAR(191) | name |  |\n" +
+		"| customer | email | VARCHAR(191) | email | the email address |\n" +
+		"| customer | password | VARBINARY(767) |  |  |\n" +
+		"| customer | note | VARCHAR(191) |  | a\\|b |\n"
+	if diff := cmp.Diff(wantMarkdown, buf.String()); diff != "" {
+		t.Errorf("markdown report is not match (-want/+got):\n%s", diff)
+	}
+
+	buf.Reset()
+	if err := m.GenerateSensitiveReport(&buf, ReportFormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"class": "email"`) {
+		t.Errorf("unexpected json report: %s", buf.String())
+	}
+
+	if err := m.GenerateSensitiveReport(&buf, "xml"); err == nil {
+		t.Error("want some error, got nil")
+	}
+}
+
+func TestMaker_SensitiveComments(t *testing.T) {
+	testMakerWithConfig(t, &Config{
+		DB: &DBConfig{
+			Engine:  "InnoDB",
+			Charset: "utf8mb4",
+			Collate: "utf8mb4_bin",
+		},
+		SensitiveComments: true,
+	}, []any{&Customer{}}, "SET foreign_key_checks=0;\n\n"+
+		"DROP TABLE IF EXISTS `customer`;\n\n"+
+		"CREATE TABLE `customer` (\n"+
+		"    `id` INTEGER NOT NULL,\n"+
+		"    `name` VARCHAR(191) NOT NULL COMMENT '[pii: name]',\n"+
+		"    `email` VARCHAR(191) NOT NULL COMMENT 'the email address [pii: email]',\n"+
+		"    `password` VARBINARY(767) NOT NULL COMMENT '[sensitive]',\n"+
+		"    `note` VARCHAR(191) NOT NULL COMMENT 'a|b [sensitive]',\n"+
+		"    PRIMARY KEY (`id`)\n"+
+		") ENGINE=InnoDB DEFAULT CHARACTER SET=utf8mb4 DEFAULT COLLATE=utf8mb4_bin;\n\n"+
+		"SET foreign_key_checks=1;\n")
+}
@@ -0,0 +1,46 @@
+package myddlmaker
+
+import (
+	"io"
+	"strings"
+)
+
+// SessionStatements are the session statements that the SQL files run around the DDL of the tables.
+type SessionStatements struct {
+	// Before are the statements before the DDL, e.g. "SET foreign_key_checks=0".
+	Before []string
+
+	// After are the statements after the DDL, e.g. "SET foreign_key_checks=1".
+	After []string
+}
+
+// defaultSessionStatements disables foreign_key_checks while creating the tables,
+// so that the tables can refer to the later ones.
+var defaultSessionStatements = SessionStatements{
+	Before: []string{"SET foreign_key_checks=0"},
+	After:  []string{"SET foreign_key_checks=1"},
+}
+
+// sessionStatements returns the session statements of the SQL files.
+func (m *Maker) sessionStatements() SessionStatements {
+	if m.config.SessionStatements != nil {
+		return *m.config.SessionStatements
+	}
+	if m.config.SortTablesByForeignKeys {
+		// the tables are created in the order of the foreign keys.
+		return SessionStatements{}
+	}
+	return defaultSessionStatements
+}
+
+// writeStatements writes the statements, one per line, with the trailing semicolons.
+func writeStatements(w io.Writer, stmts []string) {
+	for _, stmt := range stmts {
+		stmt = strings.TrimSpace(stmt)
+		if stmt == "" {
+			continue
+		}
+		io.WriteString(w, strings.TrimSuffix(stmt, ";"))
+		io.WriteString(w, ";\n")
+	}
+}
@@ -0,0 +1,85 @@
+package myddlmaker
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMaker_SessionStatements(t *testing.T) {
+	createTable := "CREATE TABLE `format_user` (\n" +
+		"    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,\n" +
+		"    `name` VARCHAR(191) NOT NULL COMMENT '\\'NOT NULL\\' name',\n" +
+		"    PRIMARY KEY (`id`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARACTER SET=utf8mb4;\n"
+	tests := []struct {
+		name    string
+		session *SessionStatements
+		sorted  bool
+		want    string
+	}{
+		{
+			name: "default",
+			want: "SET foreign_key_checks=0;\n\n" +
+				"DROP TABLE IF EXISTS `format_user`;\n\n" +
+				createTable + "\n" +
+				"SET foreign_key_checks=1;\n",
+		},
+		{
+			name:    "off",
+			session: &SessionStatements{},
+			want: "DROP TABLE IF EXISTS `format_user`;\n\n" +
+				createTable,
+		},
+		{
+			name: "custom",
+			session: &SessionStatements{
+				Before: []string{"SET NAMES utf8mb4;", "SET sql_mode='STRICT_ALL_TABLES'"},
+				After:  []string{""},
+			},
+			want: "SET NAMES utf8mb4;\n" +
+				"SET sql_mode='STRICT_ALL_TABLES';\n\n" +
+				"DROP TABLE IF EXISTS `format_user`;\n\n" +
+				createTable,
+		},
+		{
+			name:   "sorted",
+			sorted: true,
+			want: "DROP TABLE IF EXISTS `format_user`;\n\n" +
+				createTable,
+		},
+		{
+			name:   "sorted custom",
+			sorted: true,
+			session: &SessionStatements{
+				Before: []string{"SET foreign_key_checks=0"},
+				After:  []string{"SET foreign_key_checks=1"},
+			},
+			want: "SET foreign_key_checks=0;\n\n" +
+				"DROP TABLE IF EXISTS `format_user`;\n\n" +
+				createTable + "\n" +
+				"SET foreign_key_checks=1;\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := New(&Config{
+				DB:                      &DBConfig{Engine: "InnoDB", Charset: "utf8mb4"},
+				SessionStatements:       tt.session,
+				SortTablesByForeignKeys: tt.sorted,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			m.AddStructs(&FormatUser{})
+			var buf bytes.Buffer
+			if err := m.Generate(&buf); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, buf.String()); diff != "" {
+				t.Errorf("ddl is not match: (-want/+got)\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package myddlmaker
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// sourcePackage is the information of a package parsed from Go source files.
+type sourcePackage struct {
+	// key: struct name
+	structs map[string]*sourceStruct
+}
+
+// sourceStruct is the information of a struct parsed from Go source files.
+type sourceStruct struct {
+	doc string
+	pos token.Position
+
+	// key: field name
+	fields map[string]*sourceField
+}
+
+// sourceField is the information of a field parsed from Go source files.
+type sourceField struct {
+	doc string
+	pos token.Position
+}
+
+var sourceCache sync.Map // key: package path and build tags, value: *sourcePackage
+
+// loadSourcePackage parses the Go source files of the package, selected with the build tags.
+func loadSourcePackage(pkgPath string, tags []string) (*sourcePackage, error) {
+	key := pkgPath + " " + strings.Join(tags, ",")
+	if v, ok := sourceCache.Load(key); ok {
+		return v.(*sourcePackage), nil
+	}
+
+	ctx := build.Default
+	ctx.BuildTags = append(append([]string{}, ctx.BuildTags...), tags...)
+	bp, err := ctx.Import(pkgPath, ".", 0)
+	if err != nil {
+		return nil, fmt.Errorf("myddlmaker: failed to find the package %q: %w", pkgPath, err)
+	}
+
+	pkg := &sourcePackage{
+		structs: map[string]*sourceStruct{},
+	}
+	fset := token.NewFileSet()
+	files := append(append([]string{}, bp.GoFiles...), bp.TestGoFiles...)
+	for _, name := range files {
+		f, err := parser.ParseFile(fset, filepath.Join(bp.Dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("myddlmaker: failed to parse the package %q: %w", pkgPath, err)
+		}
+		pkg.addFile(fset, f)
+	}
+
+	v, _ := sourceCache.LoadOrStore(key, pkg)
+	return v.(*sourcePackage), nil
+}
+
+func (pkg *sourcePackage) addFile(fset *token.FileSet, f *ast.File) {
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+
+			doc := ts.Doc
+			if doc == nil && len(gen.Specs) == 1 {
+				doc = gen.Doc
+			}
+			s := &sourceStruct{
+				doc:    docText(doc),
+				pos:    fset.Position(ts.Name.Pos()),
+				fields: map[string]*sourceField{},
+			}
+			for _, field := range st.Fields.List {
+				// the line comments are not used, because they are often incidental notes, e.g. "// TODO".
+				for _, name := range fieldNames(field) {
+					s.fields[name.Name] = &sourceField{
+						doc: docText(field.Doc),
+						pos: fset.Position(name.Pos()),
+					}
+				}
+			}
+			pkg.structs[ts.Name.Name] = s
+		}
+	}
+}
+
+// fieldNames returns the names of the field.
+// The name of an embedded field is its type name.
+func fieldNames(field *ast.Field) []*ast.Ident {
+	if len(field.Names) > 0 {
+		return field.Names
+	}
+
+	typ := field.Type
+	for {
+		switch t := typ.(type) {
+		case *ast.StarExpr:
+			typ = t.X
+		case *ast.SelectorExpr:
+			return []*ast.Ident{t.Sel}
+		case *ast.IndexExpr:
+			typ = t.X
+		case *ast.Ident:
+			return []*ast.Ident{t}
+		default:
+			return nil
+		}
+	}
+}
+
+// docText returns the text of the comment group as a single line.
+func docText(doc *ast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(doc.Text()), " ")
+}
+
+// lookupSourceStruct returns the source information of the struct typ.
+// The source files are selected with the build tags.
+func lookupSourceStruct(typ reflect.Type, tags []string) (*sourceStruct, error) {
+	if typ.PkgPath() == "" || typ.Name() == "" {
+		return nil, nil
+	}
+	pkg, err := loadSourcePackage(typ.PkgPath(), tags)
+	if err != nil {
+		return nil, err
+	}
+	return pkg.structs[typ.Name()], nil
+}
+
+// lookupSourceField returns the source information of the field f in the struct typ.
+// f may be a field promoted from embedded structs.
+func lookupSourceField(typ reflect.Type, f reflect.StructField, tags []string) (*sourceField, error) {
+	owner := typ
+	if len(f.Index) > 1 {
+		owner = indirect(typ.FieldByIndex(f.Index[:len(f.Index)-1]).Type)
+	}
+	s, err := lookupSourceStruct(owner, tags)
+	if err != nil || s == nil {
+		return nil, err
+	}
+	return s.fields[f.Name], nil
+}
+
+// fieldPosition returns the position of the field f in the struct typ, e.g. "schema.go:12".
+// It returns an empty string if the position is not available.
+func fieldPosition(typ reflect.Type, f reflect.StructField, tags []string) string {
+	src, err := lookupSourceField(typ, f, tags)
+	if err != nil || src == nil {
+		return ""
+	}
+
+	pos := src.pos
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, pos.Filename); err == nil && !strings.HasPrefix(rel, "..") {
+			pos.Filename = rel
+		}
+	}
+	return fmt.Sprintf("%s:%d", pos.Filename, pos.Line)
+}
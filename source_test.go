@@ -0,0 +1,48 @@
+package myddlmaker
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Foo28 has some invalid fields.
+// Don't move it; the tests depend on the positions of the fields.
+type Foo28 struct {
+	ID      int32      `ddl:",auto"`
+	Size    string     `ddl:",size=large"`
+	Unknown customType // unknown type
+}
+
+func (*Foo28) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+type Foo29 struct {
+	ID int32 `ddl:",auto"`
+	Foo28
+}
+
+func TestFieldPosition(t *testing.T) {
+	typ := reflect.TypeOf(Foo28{})
+	f, _ := typ.FieldByName("Size")
+	if got, want := fieldPosition(typ, f, nil), "source_test.go:12"; got != want {
+		t.Errorf("unexpected position: want %q, got %q", want, got)
+	}
+
+	// promoted fields
+	typ = reflect.TypeOf(Foo29{})
+	f, _ = typ.FieldByName("Unknown")
+	if got, want := fieldPosition(typ, f, nil), "source_test.go:13"; got != want {
+		t.Errorf("unexpected position: want %q, got %q", want, got)
+	}
+
+	// the position of local types is not available.
+	type Local struct {
+		ID int32
+	}
+	typ = reflect.TypeOf(Local{})
+	f, _ = typ.FieldByName("ID")
+	if got, want := fieldPosition(typ, f, nil), ""; got != want {
+		t.Errorf("unexpected position: want %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,244 @@
+package myddlmaker
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// QuoteStyle is the style of the quotes of the identifiers in the DDL.
+type QuoteStyle string
+
+const (
+	// QuoteBacktick quotes the identifiers with the backticks, e.g. `user`. It is the default.
+	QuoteBacktick QuoteStyle = ""
+
+	// QuoteANSI quotes the identifiers with the double quotes, e.g. "user",
+	// for the servers running with the ANSI_QUOTES SQL mode and the other databases.
+	QuoteANSI QuoteStyle = "ansi"
+
+	// QuoteNone doesn't quote the identifiers, e.g. user.
+	// The identifiers that are the reserved words or have the special characters are quoted with the backticks.
+	QuoteNone QuoteStyle = "none"
+)
+
+// SQLFormat is the format of the generated DDL,
+// so that it matches the style of the existing hand-written schema files.
+// The zero value is the default format.
+type SQLFormat struct {
+	// LowerKeywords writes the keywords and the types in lower case, e.g. "create table" and "varchar(191)".
+	// The identifiers, the strings and the engine names are not changed.
+	LowerKeywords bool
+
+	// Indent is the indentation of the definitions in CREATE TABLE.
+	// If it is empty, four spaces are used.
+	Indent string
+
+	// Compact writes all the definitions of CREATE TABLE in one line.
+	Compact bool
+
+	// LeadingComma puts the commas at the beginning of the definitions instead of the end, e.g.
+	//
+	//	CREATE TABLE `user` (
+	//	      `id` BIGINT NOT NULL
+	//	    , `name` VARCHAR(191) NOT NULL
+	//	    , PRIMARY KEY (`id`)
+	//	)
+	LeadingComma bool
+
+	// Quote is the style of the quotes of the identifiers.
+	// The queries of the Go source code always use the backticks, that MySQL accepts in any SQL mode.
+	Quote QuoteStyle
+}
+
+// validate validates the format.
+func (f *SQLFormat) validate() error {
+	switch f.Quote {
+	case QuoteBacktick, QuoteANSI, QuoteNone:
+	default:
+		return fmt.Errorf("myddlmaker: unknown SQLFormat.Quote %q", f.Quote)
+	}
+	return nil
+}
+
+// sqlFormat returns the format of the DDL.
+func (m *Maker) sqlFormat() SQLFormat {
+	if m.config == nil || m.config.SQLFormat == nil {
+		return SQLFormat{}
+	}
+	return *m.config.SQLFormat
+}
+
+// writeDefinitions writes the definitions of CREATE TABLE in the format, including the parentheses.
+func (m *Maker) writeDefinitions(w io.Writer, defs []string) {
+	format := m.sqlFormat()
+	if format.Compact {
+		io.WriteString(w, "(")
+		io.WriteString(w, strings.Join(defs, ", "))
+		io.WriteString(w, ")")
+		return
+	}
+
+	indent := withDefault(format.Indent, "    ")
+	io.WriteString(w, "(\n")
+	for i, def := range defs {
+		io.WriteString(w, indent)
+		if format.LeadingComma {
+			if i == 0 {
+				io.WriteString(w, "  ")
+			} else {
+				io.WriteString(w, ", ")
+			}
+		}
+		io.WriteString(w, def)
+		if !format.LeadingComma && i < len(defs)-1 {
+			io.WriteString(w, ",")
+		}
+		io.WriteString(w, "\n")
+	}
+	io.WriteString(w, ")")
+}
+
+// formatSQL converts the case of the keywords and the quotes of the identifiers in the DDL for SQLFormat.
+func (m *Maker) formatSQL(ddl string) string {
+	format := m.sqlFormat()
+	if format.LowerKeywords {
+		ddl = lowerKeywords(ddl)
+	}
+	if format.Quote != QuoteBacktick {
+		ddl = convertQuotes(ddl, format.Quote, m.config.ReservedWords)
+	}
+	return ddl
+}
+
+// lowerKeywords converts the words that have no lower case letters into lower case,
+// except the quoted identifiers and strings.
+func lowerKeywords(ddl string) string {
+	var buf strings.Builder
+	buf.Grow(len(ddl))
+	for i := 0; i < len(ddl); {
+		switch ch := ddl[i]; {
+		case ch == '`' || ch == '\'' || ch == '"':
+			// skip the quoted identifier or string.
+			j := i + 1
+			for j < len(ddl) && ddl[j] != ch {
+				if ch != '`' && ddl[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j < len(ddl) {
+				j++ // the closing quote
+			} else {
+				j = len(ddl)
+			}
+			buf.WriteString(ddl[i:j])
+			i = j
+		case isWordChar(ch):
+			j := i
+			for j < len(ddl) && isWordChar(ddl[j]) {
+				j++
+			}
+			word := ddl[i:j]
+			if strings.ToUpper(word) == word {
+				word = strings.ToLower(word)
+			}
+			buf.WriteString(word)
+			i = j
+		default:
+			buf.WriteByte(ch)
+			i++
+		}
+	}
+	return buf.String()
+}
+
+func isWordChar(ch byte) bool {
+	return ch == '_' || ('0' <= ch && ch <= '9') || ('a' <= ch && ch <= 'z') || ('A' <= ch && ch <= 'Z')
+}
+
+// convertQuotes converts the backticks of the identifiers into the quote style.
+// reserved are the extra reserved words that QuoteNone quotes.
+func convertQuotes(ddl string, style QuoteStyle, reserved []string) string {
+	var buf strings.Builder
+	buf.Grow(len(ddl))
+	for i := 0; i < len(ddl); {
+		switch ch := ddl[i]; ch {
+		case '\'', '"':
+			// copy the string.
+			j := i + 1
+			for j < len(ddl) && ddl[j] != ch {
+				if ddl[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j < len(ddl) {
+				j++ // the closing quote
+			} else {
+				j = len(ddl)
+			}
+			buf.WriteString(ddl[i:j])
+			i = j
+		case '`':
+			// parse the identifier, the doubled backticks are the backticks in it.
+			var ident strings.Builder
+			j := i + 1
+			for j < len(ddl) {
+				if ddl[j] == '`' {
+					if j+1 < len(ddl) && ddl[j+1] == '`' {
+						ident.WriteByte('`')
+						j += 2
+						continue
+					}
+					break
+				}
+				ident.WriteByte(ddl[j])
+				j++
+			}
+			if j < len(ddl) {
+				j++ // the closing backtick
+			} else {
+				j = len(ddl)
+			}
+			buf.WriteString(quoteIdentifier(ident.String(), ddl[i:j], style, reserved))
+			i = j
+		default:
+			buf.WriteByte(ch)
+			i++
+		}
+	}
+	return buf.String()
+}
+
+// quoteIdentifier quotes the identifier in the style. quoted is the identifier quoted with the backticks.
+func quoteIdentifier(ident, quoted string, style QuoteStyle, reserved []string) string {
+	switch style {
+	case QuoteANSI:
+		return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
+	case QuoteNone:
+		if isPlainIdentifier(ident) && !isReservedWord(ident, reserved) {
+			return ident
+		}
+	}
+	return quoted
+}
+
+// isPlainIdentifier reports whether the identifier can be written without the quotes,
+// i.e. it consists of the letters, the digits, '_' and '$', and it isn't a number.
+func isPlainIdentifier(ident string) bool {
+	if ident == "" {
+		return false
+	}
+	digits := true
+	for i := 0; i < len(ident); i++ {
+		ch := ident[i]
+		if !isWordChar(ch) && ch != '$' {
+			return false
+		}
+		if ch < '0' || '9' < ch {
+			digits = false
+		}
+	}
+	return !digits
+}
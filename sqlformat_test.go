@@ -0,0 +1,178 @@
+package myddlmaker
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type FormatUser struct {
+	ID   uint64 `ddl:",auto"`
+	Name string `ddl:",comment='NOT NULL' name"`
+}
+
+func (*FormatUser) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+func TestMaker_SQLFormat(t *testing.T) {
+	tests := []struct {
+		format *SQLFormat
+		want   string
+	}{
+		{
+			format: &SQLFormat{LowerKeywords: true, Indent: "  "},
+			want: "create table `format_user` (\n" +
+				"  `id` bigint unsigned not null auto_increment,\n" +
+				"  `name` varchar(191) not null comment '\\'NOT NULL\\' name',\n" +
+				"  primary key (`id`)\n" +
+				") engine=InnoDB default character set=utf8mb4",
+		},
+		{
+			format: &SQLFormat{Compact: true},
+			want: "CREATE TABLE `format_user` (" +
+				"`id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT, " +
+				"`name` VARCHAR(191) NOT NULL COMMENT '\\'NOT NULL\\' name', " +
+				"PRIMARY KEY (`id`)" +
+				") ENGINE=InnoDB DEFAULT CHARACTER SET=utf8mb4",
+		},
+		{
+			format: &SQLFormat{LeadingComma: true},
+			want: "CREATE TABLE `format_user` (\n" +
+				"      `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT\n" +
+				"    , `name` VARCHAR(191) NOT NULL COMMENT '\\'NOT NULL\\' name'\n" +
+				"    , PRIMARY KEY (`id`)\n" +
+				") ENGINE=InnoDB DEFAULT CHARACTER SET=utf8mb4",
+		},
+		{
+			format: &SQLFormat{Quote: QuoteANSI},
+			want: "CREATE TABLE \"format_user\" (\n" +
+				"    \"id\" BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,\n" +
+				"    \"name\" VARCHAR(191) NOT NULL COMMENT '\\'NOT NULL\\' name',\n" +
+				"    PRIMARY KEY (\"id\")\n" +
+				") ENGINE=InnoDB DEFAULT CHARACTER SET=utf8mb4",
+		},
+		{
+			format: &SQLFormat{Quote: QuoteNone, LowerKeywords: true},
+			want: "create table format_user (\n" +
+				"    id bigint unsigned not null auto_increment,\n" +
+				"    name varchar(191) not null comment '\\'NOT NULL\\' name',\n" +
+				"    primary key (id)\n" +
+				") engine=InnoDB default character set=utf8mb4",
+		},
+	}
+	for _, tt := range tests {
+		m, err := New(&Config{
+			DB:        &DBConfig{Engine: "InnoDB", Charset: "utf8mb4"},
+			SQLFormat: tt.format,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.AddStructs(&FormatUser{})
+		var buf bytes.Buffer
+		if err := m.Generate(&buf); err != nil {
+			t.Fatal(err)
+		}
+		start := bytes.Index(buf.Bytes(), []byte("CREATE TABLE"))
+		if tt.format.LowerKeywords {
+			start = bytes.Index(buf.Bytes(), []byte("create table"))
+		}
+		end := bytes.LastIndex(buf.Bytes(), []byte(";\n\n"))
+		if start < 0 || end < 0 {
+			t.Fatalf("unexpected ddl: %s", buf.String())
+		}
+		if diff := cmp.Diff(tt.want, buf.String()[start:end]); diff != "" {
+			t.Errorf("%+v (-want/+got):\n%s", *tt.format, diff)
+		}
+	}
+}
+
+type FormatAccount struct {
+	ID    uint64 `ddl:"ID,auto"`
+	Email string `ddl:"Email"`
+}
+
+func (*FormatAccount) Table() string {
+	return "USERS"
+}
+
+func (*FormatAccount) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("ID")
+}
+
+func TestMaker_SQLFormatMixedCase(t *testing.T) {
+	m, err := New(&Config{
+		DB:                &DBConfig{Engine: "InnoDB", Charset: "utf8mb4"},
+		SQLFormat:         &SQLFormat{LowerKeywords: true, Quote: QuoteNone},
+		SessionStatements: &SessionStatements{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.AddStructs(&FormatAccount{})
+	var buf bytes.Buffer
+	if err := m.Generate(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := "drop table if exists USERS;\n\n" +
+		"create table USERS (\n" +
+		"    ID bigint unsigned not null auto_increment,\n" +
+		"    Email varchar(191) not null,\n" +
+		"    primary key (ID)\n" +
+		") engine=InnoDB default character set=utf8mb4;\n"
+	start := bytes.Index(buf.Bytes(), []byte("drop table"))
+	if start < 0 {
+		t.Fatalf("unexpected ddl: %s", buf.String())
+	}
+	if diff := cmp.Diff(want, buf.String()[start:]); diff != "" {
+		t.Errorf("ddl is not match: (-want/+got)\n%s", diff)
+	}
+}
+
+func TestLowerKeywords(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"SET foreign_key_checks=0;", "set foreign_key_checks=0;"},
+		{"DROP TABLE IF EXISTS `USER`;", "drop table if exists `USER`;"},
+		{"`ID` INTEGER DEFAULT 'ABC' COMMENT 'IT\\'S OK'", "`ID` integer default 'ABC' comment 'IT\\'S OK'"},
+		{"ENGINE=InnoDB DEFAULT COLLATE=utf8mb4_bin", "engine=InnoDB default collate=utf8mb4_bin"},
+		{"DEFAULT 'UNTERMINATED\\", "default 'UNTERMINATED\\"},
+	}
+	for _, tt := range tests {
+		if got := lowerKeywords(tt.in); got != tt.want {
+			t.Errorf("lowerKeywords(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestConvertQuotes(t *testing.T) {
+	tests := []struct {
+		in    string
+		style QuoteStyle
+		want  string
+	}{
+		{"`user`.`id` = 'it''s `id`'", QuoteANSI, "\"user\".\"id\" = 'it''s `id`'"},
+		{"`a\"b` `c``d`", QuoteANSI, "\"a\"\"b\" \"c`d\""},
+		{"`user`.`id` = 'it''s `id`'", QuoteNone, "user.id = 'it''s `id`'"},
+		{"`order` `my column` `123` `c``d` `ok$1`", QuoteNone, "`order` `my column` `123` `c``d` ok$1"},
+		{"`user`", QuoteBacktick, "`user`"},
+	}
+	for _, tt := range tests {
+		if got := convertQuotes(tt.in, tt.style, nil); got != tt.want {
+			t.Errorf("convertQuotes(%q, %q) = %q, want %q", tt.in, tt.style, got, tt.want)
+		}
+	}
+	if got := convertQuotes("`account`", QuoteNone, []string{"ACCOUNT"}); got != "`account`" {
+		t.Errorf("the extra reserved word is not quoted: %q", got)
+	}
+}
+
+func TestSQLFormat_Validate(t *testing.T) {
+	if _, err := New(&Config{SQLFormat: &SQLFormat{Quote: "single"}}); err == nil {
+		t.Error("want an error, got nil")
+	}
+}
@@ -0,0 +1,169 @@
+package myddlmaker
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// GoFlavor is a flavor of the Go source code that the DDL Maker generates.
+type GoFlavor string
+
+const (
+	// GoFlavorDatabaseSQL generates the Go source code with the database/sql package.
+	GoFlavorDatabaseSQL GoFlavor = ""
+
+	// GoFlavorSQLX generates the Go source code with github.com/jmoiron/sqlx.
+	// The fields of the structs must have the db tags of the column names.
+	GoFlavorSQLX GoFlavor = "sqlx"
+)
+
+// validateSQLX checks that the tables can be generated with the sqlx flavor.
+func (m *Maker) validateSQLX() error {
+	for _, table := range m.tables {
+		if err := validateSQLXTable(table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generateGoSQLXPreamble generates the package clause and the imports of the sqlx flavor.
+func (m *Maker) generateGoSQLXPreamble(w io.Writer) {
+	m.generateGoPackageClause(w, false)
+	io.WriteString(w, "import (\n")
+	fmt.Fprintf(w, "%q\n", "context")
+	for _, table := range m.tables {
+		if table.primaryKey != nil {
+			// Delete functions return sql.ErrNoRows.
+			fmt.Fprintf(w, "%q\n", "database/sql")
+			break
+		}
+	}
+	fmt.Fprintf(w, "\n%q\n", "github.com/jmoiron/sqlx")
+	if m.isForeignGoPackage() {
+		m.generateGoStructImport(w)
+	}
+	io.WriteString(w, ")\n\n")
+}
+
+// validateSQLXTable checks that sqlx can map the columns to the fields,
+// and that the table doesn't use the options that the sqlx flavor doesn't support.
+func validateSQLXTable(table *table) error {
+	for _, c := range table.columns {
+		if c.dbTag != c.name {
+			return fmt.Errorf("myddlmaker: table %q, field %q: db tag %q doesn't match the column name %q", table.fullName(), c.rawName, c.dbTag, c.name)
+		}
+		if c.version || c.autoNow || c.autoNowAdd {
+			return fmt.Errorf("myddlmaker: table %q, field %q: the sqlx flavor doesn't support version, auto_now and auto_now_add", table.fullName(), c.rawName)
+		}
+	}
+	return nil
+}
+
+func (m *Maker) generateGoSQLXTable(w io.Writer, table *table) {
+	name := table.rawName
+	fields := make([]string, 0, len(table.columns))
+	columns := make([]string, 0, len(table.columns))
+	named := make([]string, 0, len(table.columns))
+	for _, c := range table.columns {
+		fields = append(fields, quote(c.name))
+		if c.autoIncr {
+			continue
+		}
+		columns = append(columns, quote(c.name))
+		named = append(named, ":"+c.name)
+	}
+
+	// sqlx expands the named query for the slice into a bulk insert.
+	fmt.Fprintf(w, "func Insert%[1]s(ctx context.Context, db sqlx.ExtContext, values ...*%[1]s) error {\n", name)
+	fmt.Fprintf(w, "if len(values) == 0 {\n return nil \n}\n")
+	if len(columns) == 0 {
+		fmt.Fprintf(w, "for range values {\n")
+		fmt.Fprintf(w, "if _, err := db.ExecContext(ctx, %q); err != nil {\n return err \n}\n", "INSERT INTO "+table.quotedName()+" () VALUES ()")
+		fmt.Fprintf(w, "}\n")
+		fmt.Fprintf(w, "return nil\n")
+	} else {
+		insert := fmt.Sprintf(
+			"INSERT INTO %s (%s) VALUES (%s)",
+			table.quotedName(),
+			strings.Join(columns, ", "),
+			strings.Join(named, ", "),
+		)
+		fmt.Fprintf(w, "_, err := sqlx.NamedExecContext(ctx, db, %q, values)\n", insert)
+		fmt.Fprintf(w, "return err\n")
+	}
+	fmt.Fprintf(w, "}\n\n")
+
+	sqlSelect := fmt.Sprintf("SELECT %s FROM %s", strings.Join(fields, ", "), table.quotedName())
+	var orderBy string
+	if table.primaryKey != nil {
+		keys := make([]string, 0, len(table.primaryKey.columns))
+		for _, col := range table.primaryKey.columns {
+			keys = append(keys, quote(col))
+		}
+		orderBy = " ORDER BY " + strings.Join(keys, ", ")
+	}
+	fmt.Fprintf(w, "func SelectAll%[1]s(ctx context.Context, db sqlx.QueryerContext) ([]*%[1]s, error) {\n", name)
+	fmt.Fprintf(w, "var ret []*%s\n", name)
+	fmt.Fprintf(w, "if err := sqlx.SelectContext(ctx, db, &ret, %q); err != nil {\n return nil, err \n}\n", sqlSelect+where(table.withNotDeleted(nil))+orderBy)
+	fmt.Fprintf(w, "return ret, nil\n")
+	fmt.Fprintf(w, "}\n\n")
+
+	if table.primaryKey == nil {
+		return
+	}
+
+	params := make([]string, 0, len(table.primaryKey.columns))
+	conditions := make([]string, 0, len(table.primaryKey.columns))
+	namedConditions := make([]string, 0, len(table.primaryKey.columns))
+	isKey := map[string]bool{}
+	for _, col := range table.primaryKey.columns {
+		isKey[col] = true
+		params = append(params, "primaryKeys."+table.column(col).goField)
+		conditions = append(conditions, quote(col)+" = ?")
+		namedConditions = append(namedConditions, quote(col)+" = :"+col)
+	}
+
+	fmt.Fprintf(w, "func Select%[1]s(ctx context.Context, db sqlx.QueryerContext, primaryKeys *%[1]s) (*%[1]s, error) {\n", name)
+	fmt.Fprintf(w, "var v %s\n", name)
+	fmt.Fprintf(w, "if err := sqlx.GetContext(ctx, db, &v, %q, %s); err != nil {\n return nil, err \n}\n", sqlSelect+where(table.withNotDeleted(conditions)), strings.Join(params, ", "))
+	fmt.Fprintf(w, "return &v, nil\n")
+	fmt.Fprintf(w, "}\n\n")
+
+	var sets []string
+	for _, c := range table.columns {
+		if isKey[c.name] {
+			continue
+		}
+		sets = append(sets, quote(c.name)+" = :"+c.name)
+	}
+	fmt.Fprintf(w, "func Update%[1]s(ctx context.Context, db sqlx.ExtContext, values ...*%[1]s) error {\n", name)
+	if len(sets) > 0 {
+		update := fmt.Sprintf("UPDATE %s SET %s%s", table.quotedName(), strings.Join(sets, ", "), where(namedConditions))
+		fmt.Fprintf(w, "for _, value := range values {\n")
+		fmt.Fprintf(w, "if _, err := sqlx.NamedExecContext(ctx, db, %q, value); err != nil {\n return err \n}\n", update)
+		fmt.Fprintf(w, "}\n")
+	}
+	fmt.Fprintf(w, "return nil\n")
+	fmt.Fprintf(w, "}\n\n")
+
+	del := fmt.Sprintf("DELETE FROM %s%s", table.quotedName(), where(namedConditions))
+	if softDelete := table.softDeleteColumn(); softDelete != nil {
+		del = fmt.Sprintf(
+			"UPDATE %s SET %s = %s%s",
+			table.quotedName(),
+			quote(softDelete.name),
+			softDelete.currentTimestamp(),
+			where(table.withNotDeleted(namedConditions)),
+		)
+	}
+	fmt.Fprintf(w, "func Delete%[1]s(ctx context.Context, db sqlx.ExtContext, values ...*%[1]s) error {\n", name)
+	fmt.Fprintf(w, "for _, value := range values {\n")
+	fmt.Fprintf(w, "result, err := sqlx.NamedExecContext(ctx, db, %q, value)\n", del)
+	fmt.Fprintf(w, "if err != nil {\n return err \n}\n")
+	fmt.Fprintf(w, "if n, err := result.RowsAffected(); err != nil {\n return err \n} else if n == 0 {\n return sql.ErrNoRows \n}\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "return nil\n")
+	fmt.Fprintf(w, "}\n\n")
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"path"
 	"reflect"
 	"strconv"
 	"strings"
@@ -41,7 +42,19 @@ type TableComment interface {
 	TableComment() string
 }
 
+// Schema is used for assigning the table to a named schema (database).
+// It is an optional interface that may be implemented by a table.
+//
+//	// it generates CREATE TABLE `db1`.`user` ...
+//	func (*User) Schema() string {
+//	    return "db1"
+//	}
+type Schema interface {
+	Schema() string
+}
+
 type table struct {
+	schema          string
 	name            string
 	rawName         string
 	columns         []*column
@@ -54,7 +67,11 @@ type table struct {
 	spatialIndexes  []*SpatialIndex
 }
 
-func newTable(s any) (*table, error) {
+func newTable(cfg *Config, s any) (*table, error) {
+	if cfg == nil {
+		cfg = new(Config)
+	}
+
 	val := reflect.ValueOf(s)
 	typ := indirect(val.Type())
 	iface := val.Interface()
@@ -67,7 +84,11 @@ func newTable(s any) (*table, error) {
 	if t, ok := iface.(Table); ok {
 		tbl.name = t.Table()
 	} else {
-		tbl.name = camelToSnake(typ.Name())
+		tbl.name = cfg.tableName(typ.Name())
+	}
+	tbl.name = cfg.TablePrefix + tbl.name + cfg.TableSuffix
+	if t, ok := iface.(Schema); ok {
+		tbl.schema = t.Schema()
 	}
 
 	if t, ok := iface.(TableComment); ok {
@@ -76,16 +97,105 @@ func newTable(s any) (*table, error) {
 			tbl.comment = &comment
 		}
 	}
+	if cfg.TableCommentsFromDoc && tbl.comment == nil {
+		src, err := lookupSourceStruct(typ, cfg.sourceTags())
+		if err != nil {
+			return nil, err
+		}
+		if src != nil && src.doc != "" {
+			comment := src.doc
+			tbl.comment = &comment
+		}
+	}
 
-	fields := reflect.VisibleFields(typ)
+	fields := structFields(typ)
 	tbl.columns = make([]*column, 0, len(fields))
+
+	// depths is the depth of the shallowest field of each name.
+	// The deeper fields of the same name are hidden in Go, e.g. overridden by the outer struct.
+	depths := map[string]int{}
+	for _, f := range fields {
+		if d, ok := depths[f.Name]; !ok || len(f.Index) < d {
+			depths[f.Name] = len(f.Index)
+		}
+	}
+
+	// key: the index sequence of an embedded struct
+	// value: the column name prefix for the fields of the embedded struct
+	prefixes := map[string]string{}
+	var mixins []*mixin
+
+	// key: the index sequence of an embedded struct excluded by the config
+	excluded := map[string]bool{}
+
+	// errs is the errors of the fields.
+	// All of them are reported at once.
+	var errs []string
 	for _, f := range fields {
-		col, err := newColumn(f)
+		parent := indexKey(f.Index[:len(f.Index)-1])
+		if excluded[parent] {
+			// the fields of the excluded embedded struct are excluded too.
+			excluded[indexKey(f.Index)] = true
+			continue
+		}
+		prefix := prefixes[parent]
+		if isEmbeddedStruct(f) {
+			if cfg.excludesField(typ, f) {
+				excluded[indexKey(f.Index)] = true
+				mixins = append(mixins, &mixin{
+					index:    f.Index,
+					value:    reflect.New(indirect(f.Type)).Interface(),
+					excluded: true,
+				})
+				continue
+			}
+			if f.Type.Kind() == reflect.Pointer {
+				// the generated code would read and scan the fields through the nil pointer.
+				err := fmt.Errorf("myddlmaker: the embedded struct must not be a pointer, embed %s instead", f.Type.Elem().Name())
+				errs = append(errs, fieldErrorMessage(cfg, &tbl, typ, f, err))
+				continue
+			}
+			p, err := embeddedPrefix(cfg, f)
+			if err != nil {
+				errs = append(errs, fieldErrorMessage(cfg, &tbl, typ, f, err))
+			}
+			prefixes[indexKey(f.Index)] = prefix + p
+			mixins = append(mixins, &mixin{
+				index:  f.Index,
+				prefix: prefixes[indexKey(f.Index)],
+				value:  reflect.New(f.Type).Interface(),
+			})
+			continue
+		}
+		if prefix == "" && len(f.Index) > depths[f.Name] {
+			// the field is hidden by the shallower field.
+			// the fields of the embedded structs with the prefixes are not hidden, because their columns don't conflict.
+			continue
+		}
+		if cfg.excludesField(typ, f) {
+			continue
+		}
+
+		col, err := newColumn(cfg, f)
 		if err != nil {
 			if !errors.Is(err, errSkipColumn) {
-				return nil, err
+				errs = append(errs, fieldErrorMessage(cfg, &tbl, typ, f, err))
 			}
 		} else {
+			col.name = prefix + col.name
+			col.rawName, col.goField = goFieldNames(typ, f)
+			if cfg.ColumnCommentsFromDoc && col.comment == "" {
+				src, err := lookupSourceField(typ, f, cfg.sourceTags())
+				if err != nil {
+					return nil, err
+				}
+				if src != nil {
+					col.comment = src.doc
+				}
+			}
+			if cfg.SensitiveComments && col.sensitive {
+				col.comment = col.sensitiveComment()
+			}
 			tbl.columns = append(tbl.columns, col)
 		}
 	}
@@ -93,23 +203,182 @@ func newTable(s any) (*table, error) {
 	if pk, ok := iface.(primaryKey); ok {
 		tbl.primaryKey = pk.PrimaryKey()
 	}
-	if idx, ok := iface.(indexes); ok {
-		tbl.indexes = idx.Indexes()
+	tbl.indexes = mergeMixins(iface, mixins, func(v any) []*Index {
+		if idx, ok := v.(indexes); ok {
+			return idx.Indexes()
+		}
+		return nil
+	}, (*Index).withPrefix)
+	tbl.uniqueIndexes = mergeMixins(iface, mixins, func(v any) []*UniqueIndex {
+		if idx, ok := v.(uniqueIndexes); ok {
+			return idx.UniqueIndexes()
+		}
+		return nil
+	}, (*UniqueIndex).withPrefix)
+	tbl.foreignKeys = mergeMixins(iface, mixins, func(v any) []*ForeignKey {
+		if idx, ok := v.(foreignKeys); ok {
+			return idx.ForeignKeys()
+		}
+		return nil
+	}, (*ForeignKey).withPrefix)
+	if cfg.TablePrefix != "" || cfg.TableSuffix != "" {
+		for i, fk := range tbl.foreignKeys {
+			key := *fk // shallow copy
+			key.table = cfg.TablePrefix + key.table + cfg.TableSuffix
+			tbl.foreignKeys[i] = &key
+		}
 	}
-	if idx, ok := iface.(uniqueIndexes); ok {
-		tbl.uniqueIndexes = idx.UniqueIndexes()
+	tbl.fullTextIndexes = mergeMixins(iface, mixins, func(v any) []*FullTextIndex {
+		if idx, ok := v.(fullTextIndexes); ok {
+			return idx.FullTextIndexes()
+		}
+		return nil
+	}, (*FullTextIndex).withPrefix)
+	tbl.spatialIndexes = mergeMixins(iface, mixins, func(v any) []*SpatialIndex {
+		if idx, ok := v.(spatialIndex); ok {
+			return idx.SpatialIndexes()
+		}
+		return nil
+	}, (*SpatialIndex).withPrefix)
+
+	if len(errs) > 0 {
+		// return the table with valid columns, so that the other errors can be validated.
+		return &tbl, &validationError{errs: errs}
 	}
-	if idx, ok := iface.(foreignKeys); ok {
-		tbl.foreignKeys = idx.ForeignKeys()
+	return &tbl, nil
+}
+
+// structFields returns the fields of the struct type and the fields of the embedded structs,
+// in the same order as [reflect.VisibleFields]: the embedded structs are followed by their fields.
+// Unlike reflect.VisibleFields, it returns the fields that are ambiguous or hidden in Go,
+// e.g. the fields of the same names in two embedded structs with the prefixes.
+func structFields(typ reflect.Type) []reflect.StructField {
+	var fields []reflect.StructField
+	var walk func(typ reflect.Type, index []int)
+	walk = func(typ reflect.Type, index []int) {
+		for i := 0; i < typ.NumField(); i++ {
+			f := typ.Field(i)
+			f.Index = append(append(make([]int, 0, len(index)+1), index...), i)
+			fields = append(fields, f)
+			if !isEmbeddedStruct(f) || f.Type.Kind() == reflect.Pointer {
+				// the embedded pointers are rejected by newTable.
+				continue
+			}
+			walk(f.Type, f.Index)
+		}
 	}
-	if idx, ok := iface.(fullTextIndexes); ok {
-		tbl.fullTextIndexes = idx.FullTextIndexes()
+	walk(typ, nil)
+	return fields
+}
+
+// goFieldNames returns the name and the selector of the field in Go codes.
+// They are the name of the field if Go promotes it to the struct, e.g. "City",
+// or the names of the path if it is ambiguous, e.g. "HomeAddressCity" and "HomeAddress.City".
+func goFieldNames(typ reflect.Type, f reflect.StructField) (name, selector string) {
+	if promoted, ok := typ.FieldByName(f.Name); ok && indexKey(promoted.Index) == indexKey(f.Index) {
+		return f.Name, f.Name
 	}
-	if idx, ok := iface.(spatialIndex); ok {
-		tbl.spatialIndexes = idx.SpatialIndexes()
+	path := make([]string, 0, len(f.Index))
+	for i := range f.Index {
+		path = append(path, typ.FieldByIndex(f.Index[:i+1]).Name)
 	}
+	return strings.Join(path, ""), strings.Join(path, ".")
+}
 
-	return &tbl, nil
+// fieldErrorMessage returns the error message of err with the location of the field f.
+func fieldErrorMessage(cfg *Config, tbl *table, typ reflect.Type, f reflect.StructField, err error) string {
+	owner := typ
+	if len(f.Index) > 1 {
+		owner = indirect(typ.FieldByIndex(f.Index[:len(f.Index)-1]).Type)
+	}
+	msg := fmt.Sprintf("table %q, field %q: %s", tbl.fullName(), owner.Name()+"."+f.Name, strings.TrimPrefix(err.Error(), "myddlmaker: "))
+	if pos := fieldPosition(typ, f, cfg.sourceTags()); pos != "" {
+		msg = pos + ": " + msg
+	}
+	return msg
+}
+
+// mixin is an embedded struct that may contribute its own indexes and constraints.
+type mixin struct {
+	// index is the index sequence of the embedded struct.
+	index []int
+
+	// prefix is the column name prefix of the embedded struct.
+	prefix string
+
+	// value is a pointer to the zero value of the embedded struct.
+	value any
+
+	// excluded marks the embedded struct excluded by the config.
+	// Its indexes and constraints are not merged, but their promoted methods are still detected.
+	excluded bool
+}
+
+// mergeMixins merges the indexes defined by the table and its embedded structs.
+// The methods of embedded structs are promoted to the table by Go.
+// The promoted methods are detected and ignored in order to avoid duplication.
+func mergeMixins[T any](iface any, mixins []*mixin, get func(v any) []T, withPrefix func(v T, prefix string) T) []T {
+	raws := make([][]T, len(mixins))
+	for i, m := range mixins {
+		raws[i] = get(m.value)
+	}
+
+	// isPromoted reports whether raw is returned by a method promoted from
+	// the embedded structs under the index sequence.
+	isPromoted := func(index []int, raw []T) bool {
+		if len(raw) == 0 {
+			return false
+		}
+		for i, m := range mixins {
+			if len(m.index) > len(index) && hasPrefixInt(m.index, index) && reflect.DeepEqual(raw, raws[i]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var ret []T
+	if raw := get(iface); !isPromoted(nil, raw) {
+		ret = append(ret, raw...)
+	}
+	for i, m := range mixins {
+		if m.excluded || isPromoted(m.index, raws[i]) {
+			continue
+		}
+		for _, v := range raws[i] {
+			ret = append(ret, withPrefix(v, m.prefix))
+		}
+	}
+	return ret
+}
+
+func hasPrefixInt(s, prefix []int) bool {
+	if len(s) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if s[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// fullName returns the table name qualified by the schema name.
+func (t *table) fullName() string {
+	return qualifiedName(t.schema, t.name)
+}
+
+// quotedName returns the quoted table name qualified by the schema name.
+func (t *table) quotedName() string {
+	return quoteQualified(t.schema, t.name)
+}
+
+func qualifiedName(schema, name string) string {
+	if schema == "" {
+		return name
+	}
+	return schema + "." + name
 }
 
 type column struct {
@@ -117,14 +386,22 @@ type column struct {
 	name string
 
 	// rawName is the name in Go codes.
+	// It is the names of the path joined if the field is ambiguous in Go, e.g. "HomeAddressCity".
 	rawName string
 
+	// goField is the selector of the field in Go codes, e.g. "City",
+	// or the full path if the field is ambiguous in Go, e.g. "HomeAddress.City".
+	goField string
+
 	// typ is the type name in SQL queries
 	typ string
 
 	// rawType is the type name in Go codes.
 	rawType reflect.Type
 
+	// fieldType is the type of the field, which may be a pointer to rawType.
+	fieldType reflect.Type
+
 	size int
 
 	// autoIncr marks the column an auto increment column.
@@ -153,6 +430,335 @@ type column struct {
 
 	// srid is the id of spatial reference systems
 	srid *int
+
+	// nolint is the list of the checks that are suppressed on the column.
+	// An empty string suppresses all checks.
+	nolint []Check
+
+	// sensitive marks the columns that hold sensitive data.
+	sensitive bool
+
+	// pii is the class of the personally identifiable information, e.g. "email".
+	pii string
+
+	// noUpsert excludes the column from the update clause of the generated upsert function.
+	noUpsert bool
+
+	// version marks the column for optimistic locking in the generated update functions.
+	version bool
+
+	// softDelete marks the column that holds the deletion time of soft-deleted rows.
+	softDelete bool
+
+	// autoNowAdd makes the generated insert functions set the current time to the column.
+	autoNowAdd bool
+
+	// autoNow makes the generated insert and update functions set the current time to the column.
+	autoNow bool
+
+	// dbTag is the value of the db tag, which sqlx uses for mapping the column to the field.
+	dbTag string
+}
+
+// versionColumn returns the column for optimistic locking, or nil if the table doesn't have it.
+func (t *table) versionColumn() *column {
+	for _, c := range t.columns {
+		if c.version {
+			return c
+		}
+	}
+	return nil
+}
+
+// softDeleteColumn returns the column for soft deletion, or nil if the table doesn't have it.
+func (t *table) softDeleteColumn() *column {
+	for _, c := range t.columns {
+		if c.softDelete {
+			return c
+		}
+	}
+	return nil
+}
+
+// notDeleted returns the condition that excludes the soft-deleted rows,
+// or an empty string if the table doesn't have the column for soft deletion.
+func (t *table) notDeleted() string {
+	if c := t.softDeleteColumn(); c != nil {
+		return quote(c.name) + " IS NULL"
+	}
+	return ""
+}
+
+// withNotDeleted returns the conditions followed by the condition that excludes the soft-deleted rows.
+func (t *table) withNotDeleted(conditions []string) []string {
+	cond := t.notDeleted()
+	if cond == "" {
+		return conditions
+	}
+	ret := make([]string, 0, len(conditions)+1)
+	ret = append(ret, conditions...)
+	return append(ret, cond)
+}
+
+// currentTimestamp returns the SQL expression of the current time in the precision of the column.
+func (c *column) currentTimestamp() string {
+	if c.size > 0 {
+		return fmt.Sprintf("CURRENT_TIMESTAMP(%d)", c.size)
+	}
+	return "CURRENT_TIMESTAMP"
+}
+
+// nowAssignment returns the statement that sets the current time in the variable now to the field of v.
+func (c *column) nowAssignment(v string) string {
+	if c.fieldType == nullTimeType {
+		return fmt.Sprintf("%s.%s = sql.NullTime{Time: now, Valid: true}", v, c.goField)
+	}
+	return fmt.Sprintf("%s.%s = now", v, c.goField)
+}
+
+// autoNowColumns returns the columns that the generated functions set the current time to.
+// If insert is false, the columns for the update functions are returned.
+func (t *table) autoNowColumns(insert bool) []*column {
+	var ret []*column
+	for _, c := range t.columns {
+		if c.autoNow || (insert && c.autoNowAdd) {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+// orderableColumns returns the columns of the primary key and the indexes in the order of the columns,
+// which the generated select functions can sort the rows by.
+func (t *table) orderableColumns() []*column {
+	indexed := map[string]bool{}
+	if t.primaryKey != nil {
+		for _, col := range t.primaryKey.columns {
+			indexed[col] = true
+		}
+	}
+	for _, idx := range t.uniqueIndexes {
+		for _, col := range idx.columns {
+			indexed[col] = true
+		}
+	}
+	for _, idx := range t.indexes {
+		for _, col := range idx.columns {
+			indexed[col] = true
+		}
+	}
+
+	var ret []*column
+	for _, c := range t.columns {
+		if indexed[c.name] {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+// hasIndex reports whether the columns are covered by the primary key or any index.
+func (t *table) hasIndex(cols []string) bool {
+	if t.primaryKey != nil && hasPrefix(t.primaryKey.columns, cols) {
+		return true
+	}
+
+	for _, idx := range t.indexes {
+		if hasPrefix(idx.columns, cols) {
+			return true
+		}
+	}
+
+	for _, idx := range t.uniqueIndexes {
+		if hasPrefix(idx.columns, cols) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addForeignKeyIndexes adds indexes on the columns of foreign key constraints
+// that are not covered by any index.
+func (t *table) addForeignKeyIndexes() {
+	for _, fk := range t.foreignKeys {
+		if t.hasIndex(fk.columns) || !t.hasColumns(fk.columns) {
+			continue
+		}
+		t.indexes = append(t.indexes, NewIndex(fk.name, fk.columns...))
+	}
+}
+
+// primaryKeyGoType returns the column of the primary key and the Go type for its values,
+// if the primary key is a single column of a basic type, such as integers and strings, or a pointer to it.
+// Otherwise, it returns nil.
+func (t *table) primaryKeyGoType() (*column, string) {
+	if t.primaryKey == nil || len(t.primaryKey.columns) != 1 {
+		return nil, ""
+	}
+	key := t.column(t.primaryKey.columns[0])
+	if key == nil || basicGoType(key.rawType) == "" {
+		return nil, ""
+	}
+	return key, basicGoType(key.rawType)
+}
+
+// column returns the column that has the name, or nil if not found.
+func (t *table) column(name string) *column {
+	for _, c := range t.columns {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// identifier is a name of the object that belongs to the table.
+type identifier struct {
+	kind string // e.g. "column", "index", "foreign key"
+	name string
+	col  *column
+}
+
+// identifiers returns the names of the table, its columns, indexes and constraints.
+// The indexes that are generated for the foreign keys are also included.
+func (t *table) identifiers() []identifier {
+	ids := []identifier{{kind: "table", name: t.name}}
+	for _, col := range t.columns {
+		ids = append(ids, identifier{kind: "column", name: col.name, col: col})
+	}
+	for _, idx := range t.indexes {
+		ids = append(ids, identifier{kind: "index", name: idx.name})
+	}
+	for _, idx := range t.uniqueIndexes {
+		ids = append(ids, identifier{kind: "unique index", name: idx.name})
+	}
+	for _, idx := range t.fullTextIndexes {
+		ids = append(ids, identifier{kind: "fulltext index", name: idx.name})
+	}
+	for _, idx := range t.spatialIndexes {
+		ids = append(ids, identifier{kind: "spatial index", name: idx.name})
+	}
+	for _, fk := range t.foreignKeys {
+		ids = append(ids, identifier{kind: "foreign key", name: fk.name})
+	}
+	return ids
+}
+
+// hasColumns reports whether the table has all the columns.
+func (t *table) hasColumns(cols []string) bool {
+	for _, name := range cols {
+		found := false
+		for _, col := range t.columns {
+			if col.name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func hasPrefix(s []string, prefix []string) bool {
+	if len(s) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if s[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// goType returns the Go type for the values of the column in the generated functions,
+// if the field is a basic type, such as integers and strings.
+// The named types are converted into their underlying basic types.
+// Otherwise, it returns an empty string.
+func (c *column) goType() string {
+	return basicGoType(c.fieldType)
+}
+
+// basicGoType returns the basic type of typ, or an empty string if typ is not a basic type.
+func basicGoType(typ reflect.Type) string {
+	if typ == nil {
+		return ""
+	}
+	switch kind := typ.Kind(); kind {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return kind.String()
+	}
+	return ""
+}
+
+// goTypeExpr returns the type expression of the column in Go codes of the package pkgPath,
+// e.g. "*string" and "time.Time".
+// It adds the packages that the expression refers to into imports.
+// It returns an empty string if the type can't be referred from the package.
+func (c *column) goTypeExpr(pkgPath string, imports map[string]bool) string {
+	if c.fieldType == nil {
+		return ""
+	}
+	return goTypeExpr(c.fieldType, pkgPath, imports)
+}
+
+// keyValue returns the expression of the value of the column of the struct v,
+// dereferencing the field if it is a pointer.
+func (c *column) keyValue(v string) string {
+	if c.fieldType.Kind() == reflect.Pointer {
+		return "*" + v + "." + c.goField
+	}
+	return v + "." + c.goField
+}
+
+func goTypeExpr(typ reflect.Type, pkgPath string, imports map[string]bool) string {
+	if name := typ.Name(); name != "" {
+		switch typ.PkgPath() {
+		case "", pkgPath:
+			return name
+		case "time":
+			imports["time"] = true
+			return "time." + name
+		case "database/sql":
+			imports["database/sql"] = true
+			return "sql." + name
+		}
+		return ""
+	}
+
+	switch typ.Kind() {
+	case reflect.Pointer:
+		if elem := goTypeExpr(typ.Elem(), pkgPath, imports); elem != "" {
+			return "*" + elem
+		}
+	case reflect.Slice:
+		if elem := goTypeExpr(typ.Elem(), pkgPath, imports); elem != "" {
+			return "[]" + elem
+		}
+	case reflect.Array:
+		if elem := goTypeExpr(typ.Elem(), pkgPath, imports); elem != "" {
+			return fmt.Sprintf("[%d]%s", typ.Len(), elem)
+		}
+	}
+	return ""
+}
+
+// sqlType returns the data type of the column with its size and signedness, e.g. "INTEGER UNSIGNED".
+func (c *column) sqlType() string {
+	typ := c.typ
+	if c.size != 0 {
+		typ += fmt.Sprintf("(%d)", c.size)
+	}
+	if c.unsigned {
+		typ += " UNSIGNED"
+	}
+	return typ
 }
 
 var errSkipColumn = errors.New("myddlmaker: skip this column")
@@ -168,12 +774,13 @@ var nullInt64Type = reflect.TypeOf(sql.NullInt64{})
 var jsonRawMessageType = reflect.TypeOf(json.RawMessage{})
 var myddlmakerJSON = reflect.TypeOf((*jsonMarker)(nil)).Elem()
 
-func newColumn(f reflect.StructField) (*column, error) {
+func newColumn(cfg *Config, f reflect.StructField) (*column, error) {
 	var invalidType bool
 
 	typ := indirect(f.Type)
 	col := &column{
-		rawType: typ,
+		rawType:   typ,
+		fieldType: f.Type,
 	}
 
 	switch typ.Kind() {
@@ -206,7 +813,7 @@ func newColumn(f reflect.StructField) (*column, error) {
 		col.typ = "DOUBLE"
 	case reflect.String:
 		col.typ = "VARCHAR"
-		col.size = 191
+		col.size = withDefault(cfg.DefaultVarcharSize, 191)
 	case reflect.Slice:
 		if typ == jsonRawMessageType {
 			col.typ = "JSON"
@@ -227,13 +834,13 @@ func newColumn(f reflect.StructField) (*column, error) {
 		switch typ {
 		case timeType:
 			col.typ = "DATETIME"
-			col.size = 6
+			col.size = cfg.datetimePrecision()
 		case nullTimeType:
 			col.typ = "DATETIME"
-			col.size = 6
+			col.size = cfg.datetimePrecision()
 		case nullStringType:
 			col.typ = "VARCHAR"
-			col.size = 191
+			col.size = withDefault(cfg.DefaultVarcharSize, 191)
 		case nullBoolType:
 			col.typ = "TINYINT"
 			col.size = 1
@@ -259,12 +866,22 @@ func newColumn(f reflect.StructField) (*column, error) {
 		col.typ = "JSON"
 		invalidType = false
 	}
+	if isEnumType(typ) {
+		values := enumValues(typ)
+		if len(values) == 0 {
+			return nil, fmt.Errorf("myddlmaker: ENUM type %s has no members", typ.String())
+		}
+		col.typ = enumSQLType(values)
+		col.size = 0
+	}
 
 	// parse the tag of the field.
 	col.rawName = f.Name
+	col.goField = f.Name
+	col.dbTag = f.Tag.Get("db")
 	name, remain, _ := strings.Cut(f.Tag.Get(StructTagName), ",")
 	if name == "" {
-		name = camelToSnake(f.Name)
+		name = cfg.columnName(f.Name)
 	} else if name == "-" {
 		return nil, errSkipColumn
 	}
@@ -323,16 +940,158 @@ func newColumn(f reflect.StructField) (*column, error) {
 			col.collate = val
 		case "comment":
 			col.comment = val
+		case "sensitive":
+			v, err := parseBool("sensitive", val, ok)
+			if err != nil {
+				return nil, err
+			}
+			col.sensitive = v
+		case "pii":
+			if val == "" {
+				return nil, errors.New("myddlmaker: pii class is missing in tag")
+			}
+			col.pii = val
+			col.sensitive = true
+		case "noupsert":
+			v, err := parseBool("noupsert", val, ok)
+			if err != nil {
+				return nil, err
+			}
+			col.noUpsert = v
+		case "version":
+			v, err := parseBool("version", val, ok)
+			if err != nil {
+				return nil, err
+			}
+			col.version = v
+		case "softdelete":
+			v, err := parseBool("softdelete", val, ok)
+			if err != nil {
+				return nil, err
+			}
+			col.softDelete = v
+		case "auto_now_add":
+			v, err := parseBool("auto_now_add", val, ok)
+			if err != nil {
+				return nil, err
+			}
+			col.autoNowAdd = v
+		case "auto_now":
+			v, err := parseBool("auto_now", val, ok)
+			if err != nil {
+				return nil, err
+			}
+			col.autoNow = v
+		case "nolint":
+			check := Check(val)
+			if _, known := checks[check]; ok && !known {
+				return nil, fmt.Errorf("myddlmaker: unknown check %q in nolint tag", val)
+			}
+			col.nolint = append(col.nolint, check)
+		default:
+			if cfg.StrictTags {
+				return nil, fmt.Errorf("myddlmaker: unknown option %q in tag", opt)
+			}
 		}
 	}
 
 	if invalidType {
 		return nil, fmt.Errorf("myddlmaker: unknown type: %s", typ.String())
 	}
+	if (col.autoNowAdd || col.autoNow) && f.Type != timeType && f.Type != nullTimeType {
+		return nil, fmt.Errorf("myddlmaker: auto_now and auto_now_add are available only for time.Time and sql.NullTime, got %s", f.Type.String())
+	}
 
 	return col, nil
 }
 
+// sourceTags returns the build tags for selecting the Go source files of the structs,
+// i.e. the tag that the program running the DDL Maker is built with.
+func (c *Config) sourceTags() []string {
+	return []string{withDefault(c.Tag, "myddlmaker")}
+}
+
+// excludesField reports whether the field f of the struct typ is excluded by the config.
+func (c *Config) excludesField(typ reflect.Type, f reflect.StructField) bool {
+	if c.TaggedUnexportedFieldsOnly && !f.IsExported() {
+		if _, ok := f.Tag.Lookup(StructTagName); !ok {
+			return true
+		}
+	}
+	if c.TaggedFieldsOnly {
+		if _, ok := f.Tag.Lookup(StructTagName); !ok {
+			return true
+		}
+	}
+	for _, pattern := range c.ExcludeFields {
+		name := f.Name
+		if strings.Contains(pattern, ".") {
+			name = typ.Name() + "." + f.Name
+		}
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmbeddedStruct reports whether f is an embedded struct
+// whose fields are flattened into the table.
+func isEmbeddedStruct(f reflect.StructField) bool {
+	if !f.Anonymous {
+		return false
+	}
+	typ := indirect(f.Type)
+	if typ.Kind() != reflect.Struct || typ.Implements(myddlmakerJSON) {
+		return false
+	}
+	switch typ {
+	case timeType, nullTimeType, nullStringType, nullBoolType, nullByteType,
+		nullFloat64Type, nullInt16Type, nullInt32Type, nullInt64Type:
+		return false
+	}
+
+	// the embedded struct with the type option is a column.
+	_, remain, _ := strings.Cut(f.Tag.Get(StructTagName), ",")
+	for len(remain) > 0 {
+		var opt string
+		opt, remain, _ = cutComma(remain)
+		if name, _, _ := strings.Cut(opt, "="); name == "type" {
+			return false
+		}
+	}
+	return true
+}
+
+// embeddedPrefix returns the column name prefix of the embedded struct f.
+// If the prefix option is specified without any value, the column name of the field is used.
+func embeddedPrefix(cfg *Config, f reflect.StructField) (string, error) {
+	var prefix string
+	_, remain, _ := strings.Cut(f.Tag.Get(StructTagName), ",")
+	for len(remain) > 0 {
+		var opt string
+		opt, remain, _ = cutComma(remain)
+		name, val, ok := strings.Cut(opt, "=")
+		switch name {
+		case "prefix":
+			if ok && val != "" {
+				prefix = val
+			} else {
+				prefix = cfg.columnName(f.Name) + "_"
+			}
+		default:
+			if cfg.StrictTags {
+				return "", fmt.Errorf("myddlmaker: unknown option %q in tag", opt)
+			}
+		}
+	}
+	return prefix, nil
+}
+
+func indexKey(index []int) string {
+	return fmt.Sprint(index)
+}
+
 func parseBool(name, val string, ok bool) (bool, error) {
 	if !ok {
 		return true, nil
@@ -3,6 +3,8 @@ package myddlmaker
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -119,12 +121,12 @@ func TestTable(t *testing.T) {
 			{name: "default_value", rawName: "DefaultValue", typ: "BIGINT", def: "123"},
 		},
 	}
-	got, err := newTable(&FooBar{})
+	got, err := newTable(nil, &FooBar{})
 	if err != nil {
 		t.Fatal(err)
 	}
 	opt1 := cmp.AllowUnexported(table{}, column{}, PrimaryKey{}, Index{}, UniqueIndex{}, ForeignKey{})
-	opt2 := cmpopts.IgnoreFields(column{}, "rawType")
+	opt2 := cmpopts.IgnoreFields(column{}, "rawType", "fieldType", "goField")
 	if diff := cmp.Diff(want, got, opt1, opt2); diff != "" {
 		t.Errorf("table structures are not match (-want/+got):\n%s", diff)
 	}
@@ -137,12 +139,547 @@ func TestTable_UnknownType(t *testing.T) {
 		Foo customType
 	}
 
-	_, err := newTable(&FooBar{})
+	_, err := newTable(nil, &FooBar{})
 	if err == nil {
 		t.Error("want some errors, got nil")
 	}
 }
 
+func TestTable_AutoNowType(t *testing.T) {
+	type Post struct {
+		ID        int64  `ddl:",auto"`
+		CreatedAt string `ddl:",auto_now_add"`
+	}
+	type Comment struct {
+		ID        int64      `ddl:",auto"`
+		UpdatedAt *time.Time `ddl:",null,auto_now"`
+	}
+
+	if _, err := newTable(nil, &Post{}); err == nil {
+		t.Error("want some errors, got nil")
+	}
+	if _, err := newTable(nil, &Comment{}); err == nil {
+		t.Error("want some errors, got nil")
+	}
+}
+
+type Address struct {
+	City string
+	Zip  string `ddl:",size=16"`
+}
+
+type Timestamps struct {
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type Shop struct {
+	ID      int64 `ddl:",auto"`
+	Address `ddl:",prefix"`
+	Timestamps
+}
+
+type PointerShop struct {
+	ID int64 `ddl:",auto"`
+	*Timestamps
+}
+
+type Office struct {
+	ID      int64 `ddl:",auto"`
+	Address `ddl:",prefix=office_"`
+}
+
+func TestTable_Embedded(t *testing.T) {
+	opt1 := cmp.AllowUnexported(table{}, column{}, PrimaryKey{}, Index{}, UniqueIndex{}, ForeignKey{})
+	opt2 := cmpopts.IgnoreFields(column{}, "rawType", "fieldType", "goField")
+
+	got, err := newTable(nil, &Shop{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &table{
+		name:    "shop",
+		rawName: "Shop",
+		columns: []*column{
+			{name: "id", rawName: "ID", typ: "BIGINT", autoIncr: true},
+			{name: "address_city", rawName: "City", typ: "VARCHAR", size: 191},
+			{name: "address_zip", rawName: "Zip", typ: "VARCHAR", size: 16},
+			{name: "created_at", rawName: "CreatedAt", typ: "DATETIME", size: 6},
+			{name: "updated_at", rawName: "UpdatedAt", typ: "DATETIME", size: 6},
+		},
+	}
+	if diff := cmp.Diff(want, got, opt1, opt2); diff != "" {
+		t.Errorf("table structures are not match (-want/+got):\n%s", diff)
+	}
+
+	got, err = newTable(nil, &Office{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = &table{
+		name:    "office",
+		rawName: "Office",
+		columns: []*column{
+			{name: "id", rawName: "ID", typ: "BIGINT", autoIncr: true},
+			{name: "office_city", rawName: "City", typ: "VARCHAR", size: 191},
+			{name: "office_zip", rawName: "Zip", typ: "VARCHAR", size: 16},
+		},
+	}
+	if diff := cmp.Diff(want, got, opt1, opt2); diff != "" {
+		t.Errorf("table structures are not match (-want/+got):\n%s", diff)
+	}
+
+	// the generated code can't set the fields through the nil pointer.
+	_, err = newTable(nil, &PointerShop{})
+	var errs *validationError
+	if !errors.As(err, &errs) || len(errs.errs) != 1 ||
+		!strings.HasSuffix(errs.errs[0], `table "pointer_shop", field "PointerShop.Timestamps": the embedded struct must not be a pointer, embed Timestamps instead`) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+type HomeAddress Address
+type WorkAddress Address
+
+type Contact struct {
+	ID          int64 `ddl:",auto"`
+	City        string
+	HomeAddress `ddl:",prefix=home_"`
+	WorkAddress `ddl:",prefix=work_"`
+}
+
+type OverriddenTimestamps struct {
+	Timestamps
+	CreatedAt time.Time `ddl:"created"`
+}
+
+func TestTable_EmbeddedAmbiguous(t *testing.T) {
+	opt1 := cmp.AllowUnexported(table{}, column{}, PrimaryKey{}, Index{}, UniqueIndex{}, ForeignKey{})
+	opt2 := cmpopts.IgnoreFields(column{}, "rawType", "fieldType")
+
+	got, err := newTable(nil, &Contact{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &table{
+		name:    "contact",
+		rawName: "Contact",
+		columns: []*column{
+			{name: "id", rawName: "ID", goField: "ID", typ: "BIGINT", autoIncr: true},
+			{name: "city", rawName: "City", goField: "City", typ: "VARCHAR", size: 191},
+			{name: "home_city", rawName: "HomeAddressCity", goField: "HomeAddress.City", typ: "VARCHAR", size: 191},
+			{name: "home_zip", rawName: "HomeAddressZip", goField: "HomeAddress.Zip", typ: "VARCHAR", size: 16},
+			{name: "work_city", rawName: "WorkAddressCity", goField: "WorkAddress.City", typ: "VARCHAR", size: 191},
+			{name: "work_zip", rawName: "WorkAddressZip", goField: "WorkAddress.Zip", typ: "VARCHAR", size: 16},
+		},
+	}
+	if diff := cmp.Diff(want, got, opt1, opt2); diff != "" {
+		t.Errorf("table structures are not match (-want/+got):\n%s", diff)
+	}
+
+	// the field of the embedded struct without the prefix is overridden by the outer field.
+	got, err = newTable(nil, &OverriddenTimestamps{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = &table{
+		name:    "overridden_timestamps",
+		rawName: "OverriddenTimestamps",
+		columns: []*column{
+			{name: "updated_at", rawName: "UpdatedAt", goField: "UpdatedAt", typ: "DATETIME", size: 6},
+			{name: "created", rawName: "CreatedAt", goField: "CreatedAt", typ: "DATETIME", size: 6},
+		},
+	}
+	if diff := cmp.Diff(want, got, opt1, opt2); diff != "" {
+		t.Errorf("table structures are not match (-want/+got):\n%s", diff)
+	}
+}
+
+type SignUpRequest struct {
+	ID        int64  `ddl:",auto"`
+	Name      string `ddl:""`
+	Password  string
+	CSRFToken string
+}
+
+func TestTable_ExcludeFields(t *testing.T) {
+	opt1 := cmp.AllowUnexported(table{}, column{}, PrimaryKey{}, Index{}, UniqueIndex{}, ForeignKey{})
+	opt2 := cmpopts.IgnoreFields(column{}, "rawType", "fieldType", "goField")
+
+	got, err := newTable(&Config{
+		ExcludeFields: []string{"SignUpRequest.Password", "*Token"},
+	}, &SignUpRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &table{
+		name:    "sign_up_request",
+		rawName: "SignUpRequest",
+		columns: []*column{
+			{name: "id", rawName: "ID", typ: "BIGINT", autoIncr: true},
+			{name: "name", rawName: "Name", typ: "VARCHAR", size: 191},
+		},
+	}
+	if diff := cmp.Diff(want, got, opt1, opt2); diff != "" {
+		t.Errorf("table structures are not match (-want/+got):\n%s", diff)
+	}
+
+	got, err = newTable(&Config{
+		TaggedFieldsOnly: true,
+	}, &SignUpRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got, opt1, opt2); diff != "" {
+		t.Errorf("table structures are not match (-want/+got):\n%s", diff)
+	}
+}
+
+type RequestMeta struct {
+	TraceID   string
+	UserAgent string
+}
+
+func (*RequestMeta) Indexes() []*Index {
+	return []*Index{NewIndex("idx_trace_id", "trace_id")}
+}
+
+type CreateOrderRequest struct {
+	ID     int64 `ddl:",auto"`
+	Amount int64 `ddl:""`
+	RequestMeta
+	Address `ddl:",prefix"`
+}
+
+func TestTable_ExcludeEmbeddedFields(t *testing.T) {
+	opt1 := cmp.AllowUnexported(table{}, column{}, PrimaryKey{}, Index{}, UniqueIndex{}, ForeignKey{})
+	opt2 := cmpopts.IgnoreFields(column{}, "rawType", "fieldType", "goField")
+
+	// the embedded struct is excluded with its fields and indexes.
+	want := &table{
+		name:    "create_order_request",
+		rawName: "CreateOrderRequest",
+		columns: []*column{
+			{name: "id", rawName: "ID", typ: "BIGINT", autoIncr: true},
+			{name: "amount", rawName: "Amount", typ: "BIGINT"},
+			{name: "address_city", rawName: "City", typ: "VARCHAR", size: 191},
+			{name: "address_zip", rawName: "Zip", typ: "VARCHAR", size: 16},
+		},
+	}
+	for _, cfg := range []*Config{
+		{ExcludeFields: []string{"RequestMeta"}},
+		{ExcludeFields: []string{"*Meta"}},
+		{ExcludeFields: []string{"CreateOrderRequest.RequestMeta"}},
+	} {
+		got, err := newTable(cfg, &CreateOrderRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got, opt1, opt2); diff != "" {
+			t.Errorf("%v: table structures are not match (-want/+got):\n%s", cfg.ExcludeFields, diff)
+		}
+	}
+
+	// the untagged embedded struct is excluded, and so are the untagged fields of the tagged one.
+	got, err := newTable(&Config{TaggedFieldsOnly: true}, &CreateOrderRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = &table{
+		name:    "create_order_request",
+		rawName: "CreateOrderRequest",
+		columns: []*column{
+			{name: "id", rawName: "ID", typ: "BIGINT", autoIncr: true},
+			{name: "amount", rawName: "Amount", typ: "BIGINT"},
+			{name: "address_zip", rawName: "Zip", typ: "VARCHAR", size: 16},
+		},
+	}
+	if diff := cmp.Diff(want, got, opt1, opt2); diff != "" {
+		t.Errorf("table structures are not match (-want/+got):\n%s", diff)
+	}
+
+	// the fields of the embedded struct are excluded by their names.
+	got, err = newTable(&Config{ExcludeFields: []string{"UserAgent", "Address"}}, &CreateOrderRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = &table{
+		name:    "create_order_request",
+		rawName: "CreateOrderRequest",
+		columns: []*column{
+			{name: "id", rawName: "ID", typ: "BIGINT", autoIncr: true},
+			{name: "amount", rawName: "Amount", typ: "BIGINT"},
+			{name: "trace_id", rawName: "TraceID", typ: "VARCHAR", size: 191},
+		},
+		indexes: []*Index{NewIndex("idx_trace_id", "trace_id")},
+	}
+	if diff := cmp.Diff(want, got, opt1, opt2); diff != "" {
+		t.Errorf("table structures are not match (-want/+got):\n%s", diff)
+	}
+}
+
+type VarcharUser struct {
+	ID       int64 `ddl:",auto"`
+	Name     string
+	Nickname sql.NullString
+	Code     string `ddl:",size=8"`
+}
+
+func TestTable_DefaultVarcharSize(t *testing.T) {
+	opt1 := cmp.AllowUnexported(table{}, column{}, PrimaryKey{}, Index{}, UniqueIndex{}, ForeignKey{})
+	opt2 := cmpopts.IgnoreFields(column{}, "rawType", "fieldType", "goField")
+
+	got, err := newTable(&Config{
+		DefaultVarcharSize: 255,
+	}, &VarcharUser{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &table{
+		name:    "varchar_user",
+		rawName: "VarcharUser",
+		columns: []*column{
+			{name: "id", rawName: "ID", typ: "BIGINT", autoIncr: true},
+			{name: "name", rawName: "Name", typ: "VARCHAR", size: 255},
+			{name: "nickname", rawName: "Nickname", typ: "VARCHAR", size: 255},
+			{name: "code", rawName: "Code", typ: "VARCHAR", size: 8},
+		},
+	}
+	if diff := cmp.Diff(want, got, opt1, opt2); diff != "" {
+		t.Errorf("table structures are not match (-want/+got):\n%s", diff)
+	}
+
+	if _, err := New(&Config{DefaultVarcharSize: 65536}); err == nil {
+		t.Error("want an error, got nil")
+	}
+}
+
+type DatetimeUser struct {
+	ID        int64 `ddl:",auto"`
+	CreatedAt time.Time
+	DeletedAt sql.NullTime
+	UpdatedAt time.Time `ddl:",size=6"`
+}
+
+func TestTable_DefaultDatetimePrecision(t *testing.T) {
+	opt1 := cmp.AllowUnexported(table{}, column{}, PrimaryKey{}, Index{}, UniqueIndex{}, ForeignKey{})
+	opt2 := cmpopts.IgnoreFields(column{}, "rawType", "fieldType", "goField")
+
+	for _, precision := range []int{0, 3} {
+		precision := precision
+		got, err := newTable(&Config{
+			DefaultDatetimePrecision: &precision,
+		}, &DatetimeUser{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &table{
+			name:    "datetime_user",
+			rawName: "DatetimeUser",
+			columns: []*column{
+				{name: "id", rawName: "ID", typ: "BIGINT", autoIncr: true},
+				{name: "created_at", rawName: "CreatedAt", typ: "DATETIME", size: precision},
+				{name: "deleted_at", rawName: "DeletedAt", typ: "DATETIME", size: precision},
+				{name: "updated_at", rawName: "UpdatedAt", typ: "DATETIME", size: 6},
+			},
+		}
+		if diff := cmp.Diff(want, got, opt1, opt2); diff != "" {
+			t.Errorf("precision %d: table structures are not match (-want/+got):\n%s", precision, diff)
+		}
+	}
+
+	invalid := 7
+	if _, err := New(&Config{DefaultDatetimePrecision: &invalid}); err == nil {
+		t.Error("want an error, got nil")
+	}
+}
+
+type Account struct {
+	ID           int64  `ddl:",auto"`
+	passwordHash []byte `ddl:",size=64"`
+	cache        string
+}
+
+func TestTable_UnexportedFields(t *testing.T) {
+	opt1 := cmp.AllowUnexported(table{}, column{}, PrimaryKey{}, Index{}, UniqueIndex{}, ForeignKey{})
+	opt2 := cmpopts.IgnoreFields(column{}, "rawType", "fieldType", "goField")
+
+	// unexported fields are mapped by default.
+	got, err := newTable(nil, &Account{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &table{
+		name:    "account",
+		rawName: "Account",
+		columns: []*column{
+			{name: "id", rawName: "ID", typ: "BIGINT", autoIncr: true},
+			{name: "password_hash", rawName: "passwordHash", typ: "VARBINARY", size: 64},
+			{name: "cache", rawName: "cache", typ: "VARCHAR", size: 191},
+		},
+	}
+	if diff := cmp.Diff(want, got, opt1, opt2); diff != "" {
+		t.Errorf("table structures are not match (-want/+got):\n%s", diff)
+	}
+
+	// only unexported fields with the ddl tag are mapped.
+	got, err = newTable(&Config{
+		TaggedUnexportedFieldsOnly: true,
+	}, &Account{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = &table{
+		name:    "account",
+		rawName: "Account",
+		columns: []*column{
+			{name: "id", rawName: "ID", typ: "BIGINT", autoIncr: true},
+			{name: "password_hash", rawName: "passwordHash", typ: "VARBINARY", size: 64},
+		},
+	}
+	if diff := cmp.Diff(want, got, opt1, opt2); diff != "" {
+		t.Errorf("table structures are not match (-want/+got):\n%s", diff)
+	}
+}
+
+type PointerKey struct {
+	ID   *int64
+	Name *string
+}
+
+func (*PointerKey) PrimaryKey() *PrimaryKey {
+	return NewPrimaryKey("id")
+}
+
+func TestTable_PrimaryKeyGoType(t *testing.T) {
+	tbl, err := newTable(nil, &PointerKey{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the pointer is dereferenced.
+	key, goType := tbl.primaryKeyGoType()
+	if key == nil || key.name != "id" || goType != "int64" {
+		t.Errorf("unexpected primary key: %v, %q", key, goType)
+	}
+	if got, want := key.keyValue("v"), "*v.ID"; got != want {
+		t.Errorf("unexpected key value: want %q, got %q", want, got)
+	}
+	if got, want := key.goTypeExpr("", map[string]bool{}), "*int64"; got != want {
+		t.Errorf("unexpected type: want %q, got %q", want, got)
+	}
+
+	// the pointer fields are not the basic types.
+	if got := tbl.column("name").goType(); got != "" {
+		t.Errorf("unexpected Go type: %q", got)
+	}
+}
+
+// Book is a book.
+type Book struct {
+	// ID is the identifier of the book.
+	ID int64 `ddl:",auto"`
+
+	// Title is the title
+	// of the book.
+	Title string
+
+	Author string // Author is the author of the book.
+
+	// Code is overwritten by the tag.
+	Code string `ddl:",comment=International Standard Book Number"`
+
+	Price int32
+}
+
+// Magazine is a magazine.
+type Magazine struct {
+	ID int64 `ddl:",auto"`
+}
+
+func (*Magazine) TableComment() string {
+	return "magazines"
+}
+
+func TestTable_ColumnCommentsFromDoc(t *testing.T) {
+	opt1 := cmp.AllowUnexported(table{}, column{}, PrimaryKey{}, Index{}, UniqueIndex{}, ForeignKey{})
+	opt2 := cmpopts.IgnoreFields(column{}, "rawType", "fieldType", "goField")
+
+	got, err := newTable(&Config{
+		ColumnCommentsFromDoc: true,
+	}, &Book{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &table{
+		name:    "book",
+		rawName: "Book",
+		columns: []*column{
+			{name: "id", rawName: "ID", typ: "BIGINT", autoIncr: true, comment: "ID is the identifier of the book."},
+			{name: "title", rawName: "Title", typ: "VARCHAR", size: 191, comment: "Title is the title of the book."},
+			{name: "author", rawName: "Author", typ: "VARCHAR", size: 191},
+			{name: "code", rawName: "Code", typ: "VARCHAR", size: 191, comment: "International Standard Book Number"},
+			{name: "price", rawName: "Price", typ: "INTEGER"},
+		},
+	}
+	if diff := cmp.Diff(want, got, opt1, opt2); diff != "" {
+		t.Errorf("table structures are not match (-want/+got):\n%s", diff)
+	}
+}
+
+func TestTable_TableCommentsFromDoc(t *testing.T) {
+	got, err := newTable(&Config{
+		TableCommentsFromDoc: true,
+	}, &Book{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.comment == nil || *got.comment != "Book is a book." {
+		t.Errorf("unexpected table comment: %v", got.comment)
+	}
+
+	// the TableComment interface takes precedence.
+	got, err = newTable(&Config{
+		TableCommentsFromDoc: true,
+	}, &Magazine{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.comment == nil || *got.comment != "magazines" {
+		t.Errorf("unexpected table comment: %v", got.comment)
+	}
+}
+
+func TestTable_StrictTags(t *testing.T) {
+	type Typo struct {
+		ID   int64  `ddl:",auto"`
+		Name string `ddl:",nulll"`
+	}
+	type EmbeddedTypo struct {
+		ID      int64 `ddl:",auto"`
+		Address `ddl:",prefx"`
+	}
+
+	// unknown options are ignored by default.
+	if _, err := newTable(nil, &Typo{}); err != nil {
+		t.Errorf("want no error, got %v", err)
+	}
+	if _, err := newTable(nil, &EmbeddedTypo{}); err != nil {
+		t.Errorf("want no error, got %v", err)
+	}
+
+	cfg := &Config{StrictTags: true}
+	if _, err := newTable(cfg, &Typo{}); err == nil {
+		t.Error("want some errors, got nil")
+	}
+	if _, err := newTable(cfg, &EmbeddedTypo{}); err == nil {
+		t.Error("want some errors, got nil")
+	}
+	if _, err := newTable(cfg, &FooBar{}); err != nil {
+		t.Errorf("want no error, got %v", err)
+	}
+}
+
 func TestCutComma(t *testing.T) {
 	tests := []struct {
 		in     string
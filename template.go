@@ -0,0 +1,137 @@
+package myddlmaker
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// TemplateData is the data of the "header" template in [Config.TemplateFS].
+type TemplateData struct {
+	// PackageName is the package name of the generated Go source code.
+	PackageName string
+
+	// Tables are the tables in the order of the structs.
+	Tables []*TemplateTable
+}
+
+// TemplateTable is the data of the "table" template in [Config.TemplateFS].
+type TemplateTable struct {
+	// Name is the name of the table, e.g. "user".
+	Name string
+
+	// GoName is the name of the struct, e.g. "User".
+	GoName string
+
+	// Columns are the columns of the table.
+	Columns []*TemplateColumn
+
+	// PrimaryKey are the columns of the primary key.
+	// It is nil if the table doesn't have the primary key.
+	PrimaryKey []*TemplateColumn
+}
+
+// TemplateColumn is a column in the templates.
+type TemplateColumn struct {
+	// Name is the name of the column, e.g. "tenant_id".
+	Name string
+
+	// GoName is the name of the field, e.g. "TenantID".
+	// It is the names of the path joined if the field of the embedded struct is ambiguous in Go, e.g. "HomeAddressCity".
+	GoName string
+
+	// GoField is the selector of the field, e.g. "TenantID",
+	// or the full path if the field of the embedded struct is ambiguous in Go, e.g. "HomeAddress.City".
+	GoField string
+
+	// GoParam is the name of the parameter of the column in the generated functions, e.g. "tenantID".
+	GoParam string
+
+	// GoType is the type of the field in the generated Go source code, e.g. "int64" and "time.Time".
+	// It is empty if the type can't be referred from the package.
+	GoType string
+
+	// SQLType is the type of the column, e.g. "BIGINT".
+	SQLType string
+
+	// Null reports whether the column accepts NULL.
+	Null bool
+
+	// AutoIncrement reports whether the column is an auto increment column.
+	AutoIncrement bool
+}
+
+// templateFuncs are the functions available in the templates.
+var templateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"param": goParamName,
+}
+
+// parseTemplates parses the templates in Config.TemplateFS.
+func (c *Config) parseTemplates() (*template.Template, error) {
+	if c.TemplateFS == nil {
+		return nil, nil
+	}
+	tmpl, err := template.New("").Funcs(templateFuncs).ParseFS(c.TemplateFS, "*.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("myddlmaker: failed to parse the templates: %w", err)
+	}
+	return tmpl, nil
+}
+
+// hasTemplate reports whether the template name is defined in Config.TemplateFS.
+func (m *Maker) hasTemplate(name string) bool {
+	return m.templates != nil && m.templates.Lookup(name) != nil
+}
+
+// executeTemplate executes the template name if it is defined.
+func (m *Maker) executeTemplate(w io.Writer, name string, data any) error {
+	if !m.hasTemplate(name) {
+		return nil
+	}
+	if err := m.templates.ExecuteTemplate(w, name, data); err != nil {
+		return fmt.Errorf("myddlmaker: failed to execute the template %q: %w", name, err)
+	}
+	io.WriteString(w, "\n")
+	return nil
+}
+
+func (m *Maker) templateData() *TemplateData {
+	data := &TemplateData{
+		PackageName: m.config.PackageName,
+	}
+	pkgPath := m.goPkgPath()
+	for _, table := range m.tables {
+		data.Tables = append(data.Tables, templateTableOf(table, pkgPath))
+	}
+	return data
+}
+
+func templateTableOf(table *table, pkgPath string) *TemplateTable {
+	t := &TemplateTable{
+		Name:   table.fullName(),
+		GoName: table.rawName,
+	}
+	columns := make(map[string]*TemplateColumn, len(table.columns))
+	for _, c := range table.columns {
+		col := &TemplateColumn{
+			Name:          c.name,
+			GoName:        c.rawName,
+			GoField:       c.goField,
+			GoParam:       goParamName(c.rawName),
+			GoType:        c.goTypeExpr(pkgPath, map[string]bool{}),
+			SQLType:       c.sqlType(),
+			Null:          c.null,
+			AutoIncrement: c.autoIncr,
+		}
+		t.Columns = append(t.Columns, col)
+		columns[c.name] = col
+	}
+	if table.primaryKey != nil {
+		for _, name := range table.primaryKey.columns {
+			t.PrimaryKey = append(t.PrimaryKey, columns[name])
+		}
+	}
+	return t
+}
@@ -0,0 +1,19 @@
+package schema
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/shogo82148/myddlmaker"
+)
+
+type Post struct {
+	ID        int32 `ddl:",auto"`
+	Title     string
+	CreatedAt time.Time    `ddl:",auto_now_add"`
+	UpdatedAt sql.NullTime `ddl:",null,auto_now"`
+}
+
+func (*Post) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
@@ -0,0 +1,87 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return nil
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestAutoNow(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	t1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	t3 := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+	t.Cleanup(func() { Now = time.Now })
+
+	Now = func() time.Time { return t1 }
+	post := &Post{ID: 1, Title: "hello"}
+	if err := InsertPost(ctx, db, post); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+	if !post.CreatedAt.Equal(t1) || !post.UpdatedAt.Valid || !post.UpdatedAt.Time.Equal(t1) {
+		t.Errorf("unexpected post: %v", post)
+	}
+
+	Now = func() time.Time { return t2 }
+	post.Title = "world"
+	if err := UpdatePost(ctx, db, post); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	got, err := SelectPost(ctx, db, &Post{ID: 1})
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if !got.CreatedAt.Equal(t1) || !got.UpdatedAt.Time.Equal(t2) {
+		t.Errorf("unexpected post: %v", got)
+	}
+
+	Now = func() time.Time { return t3 }
+	title := "partial"
+	if err := UpdatePostColumns(ctx, db, 1, PostChanges{Title: &title}); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	if err := UpsertPost(ctx, db, &Post{ID: 1, Title: "upsert"}); err != nil {
+		t.Fatalf("failed to upsert: %v", err)
+	}
+	got, err = SelectPost(ctx, db, &Post{ID: 1})
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	// the upsert keeps the auto_now_add column.
+	if got.Title != "upsert" || !got.CreatedAt.Equal(t1) || !got.UpdatedAt.Time.Equal(t3) {
+		t.Errorf("unexpected post: %v", got)
+	}
+}
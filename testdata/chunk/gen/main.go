@@ -0,0 +1,28 @@
+package main
+
+import (
+	"log"
+
+	"github.com/shogo82148/myddlmaker"
+	schema "github.com/shogo82148/myddlmaker/testdata/chunk"
+)
+
+func main() {
+	m, err := myddlmaker.New(&myddlmaker.Config{
+		InsertChunkSize:     3,
+		InsertInTransaction: true,
+		KeepKeyOrder:        true,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	m.AddStructs(&schema.Event{}, &schema.Label{})
+
+	if err := m.GenerateFile(); err != nil {
+		log.Fatal(err)
+	}
+	if err := m.GenerateGoFile(); err != nil {
+		log.Fatal(err)
+	}
+}
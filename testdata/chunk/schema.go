@@ -0,0 +1,23 @@
+package schema
+
+import (
+	"github.com/shogo82148/myddlmaker"
+)
+
+type Event struct {
+	ID   int32 `ddl:",auto"`
+	Name string
+}
+
+func (*Event) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
+
+type Label struct {
+	ID   *int64
+	Name string
+}
+
+func (*Label) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
@@ -0,0 +1,127 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestInsertChunks(t *testing.T) {
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	// 10 rows are split into 4 chunks.
+	events := make([]*Event, 10)
+	for i := range events {
+		events[i] = &Event{Name: fmt.Sprintf("event%d", i)}
+	}
+	if err := InsertEvent(ctx, db, events...); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+
+	got, err := SelectAllEvent(ctx, db)
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if len(got) != len(events) {
+		t.Fatalf("unexpected count: want %d, got %d", len(events), len(got))
+	}
+	for i, e := range got {
+		if want := fmt.Sprintf("event%d", i); e.Name != want {
+			t.Errorf("unexpected name: want %s, got %s", want, e.Name)
+		}
+	}
+	// the rows are returned in the order of the keys, and the missing keys are skipped.
+	selected, err := SelectEventByPKs(ctx, db, got[5].ID, got[2].ID, -1, got[7].ID)
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if len(selected) != 3 {
+		t.Fatalf("unexpected count: want 3, got %d", len(selected))
+	}
+	for i, want := range []string{"event5", "event2", "event7"} {
+		if selected[i].Name != want {
+			t.Errorf("unexpected name: want %s, got %s", want, selected[i].Name)
+		}
+	}
+}
+
+func TestLabelByPKs(t *testing.T) {
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	// the primary key of the pointer type.
+	labels := make([]*Label, 5)
+	for i := range labels {
+		id := int64(i + 1)
+		labels[i] = &Label{ID: &id, Name: fmt.Sprintf("label%d", i+1)}
+	}
+	if err := InsertLabel(ctx, db, labels...); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+
+	selected, err := SelectLabelByPKs(ctx, db, 4, 1, 6)
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if len(selected) != 2 {
+		t.Fatalf("unexpected count: want 2, got %d", len(selected))
+	}
+	for i, want := range []string{"label4", "label1"} {
+		if selected[i].Name != want {
+			t.Errorf("unexpected name: want %s, got %s", want, selected[i].Name)
+		}
+	}
+
+	if err := DeleteLabelByPK(ctx, db, 4); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	got, err := SelectAllLabel(ctx, db)
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if len(got) != 4 {
+		t.Errorf("unexpected count: want 4, got %d", len(got))
+	}
+}
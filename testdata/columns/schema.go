@@ -0,0 +1,18 @@
+package schema
+
+import (
+	"encoding/json"
+
+	"github.com/shogo82148/myddlmaker"
+)
+
+type Article struct {
+	ID       int64 `ddl:",auto"`
+	Title    string
+	Body     string          `ddl:",type=TEXT"`
+	Metadata json.RawMessage `ddl:",null"`
+}
+
+func (*Article) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
@@ -0,0 +1,146 @@
+package schema
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return nil
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestAllDDL(t *testing.T) {
+	data, err := os.ReadFile("schema.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ddl := AllDDL()
+	if len(ddl) != 1 || ddl[0] != ArticleCreateTableSQL() {
+		t.Errorf("unexpected AllDDL: %q", ddl)
+	}
+	if !strings.Contains(string(data), ArticleCreateTableSQL()+";\n") {
+		t.Errorf("ArticleCreateTableSQL doesn't match schema.sql:\n%s", ArticleCreateTableSQL())
+	}
+}
+
+func TestSchemaHash(t *testing.T) {
+	data, err := os.ReadFile("schema.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(data)
+	if got, want := SchemaHash(), hex.EncodeToString(sum[:]); got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+	if got, want := SchemaVersion(), SchemaHash()[:12]; got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+}
+
+func TestCreateTableSQL(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// the temporary table hides the table of schema.sql only in this connection.
+	q := strings.Replace(ArticleCreateTableSQL(), "CREATE TABLE", "CREATE TEMPORARY TABLE", 1)
+	if _, err := conn.ExecContext(ctx, q); err != nil {
+		t.Fatal(err)
+	}
+	defer conn.ExecContext(ctx, "DROP TEMPORARY TABLE `article`")
+
+	article := &Article{Title: "Hello", Body: "temporary"}
+	id, err := InsertArticleReturningID(ctx, conn, article)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 1 {
+		t.Errorf("want the first row of the new table, got id %d", id)
+	}
+}
+
+func TestSelectArticleColumns_UnknownColumn(t *testing.T) {
+	_, err := SelectArticleColumns(context.Background(), nil, 1, ArticleColTitle, ArticleColumn("author"))
+	if err == nil || err.Error() != "unknown column of article: author" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestSelectArticleColumns(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	article := &Article{
+		Title:    "Hello",
+		Body:     "a long body",
+		Metadata: json.RawMessage(`{"tags":["go"]}`),
+	}
+	id, err := InsertArticleReturningID(ctx, db, article)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := SelectArticleColumns(ctx, db, id, ArticleColID, ArticleColTitle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&Article{ID: id, Title: "Hello"}, got); diff != "" {
+		t.Errorf("SelectArticleColumns mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err = SelectArticleColumns(ctx, db, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Body != article.Body || got.Title != article.Title {
+		t.Errorf("want all the columns, got %+v", got)
+	}
+
+	if _, err := SelectArticleColumns(ctx, db, id+1, ArticleColTitle); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("want sql.ErrNoRows, got %v", err)
+	}
+}
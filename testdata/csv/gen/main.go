@@ -0,0 +1,26 @@
+package main
+
+import (
+	"log"
+
+	"github.com/shogo82148/myddlmaker"
+	schema "github.com/shogo82148/myddlmaker/testdata/csv"
+)
+
+func main() {
+	m, err := myddlmaker.New(&myddlmaker.Config{
+		GenerateCSV: true,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	m.AddStructs(&schema.Record{})
+
+	if err := m.GenerateFile(); err != nil {
+		log.Fatal(err)
+	}
+	if err := m.GenerateGoFile(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,29 @@
+package schema
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/shogo82148/myddlmaker"
+)
+
+type Settings struct {
+	Theme string `json:"theme"`
+}
+
+type Record struct {
+	ID        uint64
+	Name      string
+	Note      *string        `ddl:",null"`
+	Email     sql.NullString `ddl:",null"`
+	Score     float64        `ddl:",type=DOUBLE"`
+	Active    bool
+	Data      []byte `ddl:",size=16"`
+	Settings  myddlmaker.JSON[Settings]
+	ExpiresAt sql.NullTime `ddl:",null"`
+	CreatedAt time.Time
+}
+
+func (*Record) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
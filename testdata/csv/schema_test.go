@@ -0,0 +1,163 @@
+package schema
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/go-cmp/cmp"
+	"github.com/shogo82148/myddlmaker"
+)
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return nil
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	cfg.ParseTime = true
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func records() []*Record {
+	var settings myddlmaker.JSON[Settings]
+	settings.Set(Settings{Theme: "dark"})
+	return []*Record{
+		{
+			ID:        1,
+			Name:      `comma, "quote" and` + "\nnewline",
+			Note:      ptr("NULL"),
+			Email:     sql.NullString{String: "alice@example.com", Valid: true},
+			Score:     1.5,
+			Active:    true,
+			Data:      []byte{0x00, 0xff},
+			Settings:  settings,
+			ExpiresAt: sql.NullTime{Time: time.Date(2024, 1, 2, 3, 4, 5, 600000000, time.UTC), Valid: true},
+			CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		{
+			ID:        2,
+			Name:      "",
+			Data:      []byte{},
+			Settings:  settings,
+			CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+	}
+}
+
+func TestWriteRecordCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteRecordCSV(&buf, records()...); err != nil {
+		t.Fatal(err)
+	}
+	want := `"id","name","note","email","score","active","data","settings","expires_at","created_at"` + "\n" +
+		`"1","comma, ""quote"" and` + "\n" + `newline","NULL","alice@example.com","1.5","1","` + "\x00\xff" + `","{""theme"":""dark""}","2024-01-02 03:04:05.6","2024-01-02 03:04:05"` + "\n" +
+		`"2","",NULL,NULL,"0","0","","{""theme"":""dark""}",NULL,"2024-01-02 03:04:05"` + "\n"
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("(-want/+got):\n%s", diff)
+	}
+
+	got, err := ReadRecordCSV(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(records(), got); diff != "" {
+		t.Errorf("(-want/+got):\n%s", diff)
+	}
+}
+
+func TestReadRecordCSV_Subset(t *testing.T) {
+	got, err := ReadRecordCSV(strings.NewReader("name,id\r\nalice,1\r\n\"bob\",2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Record{{ID: 1, Name: "alice"}, {ID: 2, Name: "bob"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want/+got):\n%s", diff)
+	}
+}
+
+func TestReadRecordCSV_Error(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", "the header of the columns is missing"},
+		{"id,age\n", `unknown column "age" in the header`},
+		{"id,id\n", `duplicated column "id" in the header`},
+		{"id,name\n1\n", "line 2: 1 fields, want 2"},
+		{"id,name\n\"x\",\"alice\"\n", "line 2, field 1: "},
+		{"id,name\nNULL,\"alice\"\n", "line 2, field 1: unexpected NULL"},
+		{"id,name\n\"1\",\"alice\n", "line 2: unexpected EOF"},
+	}
+	for _, tt := range tests {
+		_, err := ReadRecordCSV(strings.NewReader(tt.in))
+		if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+			t.Errorf("%q: want %q, got %v", tt.in, tt.want, err)
+		}
+	}
+}
+
+func TestLoadRecordCSV(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+	var localInfile bool
+	if err := db.QueryRowContext(ctx, "SELECT @@local_infile").Scan(&localInfile); err != nil {
+		t.Fatal(err)
+	}
+	if !localInfile {
+		t.Skip("local_infile is disabled")
+	}
+	if _, err := db.ExecContext(ctx, "DELETE FROM `record`"); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteRecordCSV(&buf, records()...); err != nil {
+		t.Fatal(err)
+	}
+	n, err := LoadRecordCSV(ctx, db, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("want 2 rows, got %d", n)
+	}
+
+	var exported bytes.Buffer
+	if err := ExportRecordCSV(ctx, db, &exported); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadRecordCSV(&exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(records(), got); diff != "" {
+		t.Errorf("(-want/+got):\n%s", diff)
+	}
+}
@@ -0,0 +1,14 @@
+package schema
+
+import (
+	"github.com/shogo82148/myddlmaker"
+)
+
+type Account struct {
+	ID      int32
+	Balance int64
+}
+
+func (*Account) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
@@ -0,0 +1,112 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// recordingConnector records the statements executed on the connections.
+type recordingConnector struct {
+	mu    sync.Mutex
+	execs []string
+	reads []string
+}
+
+func (c *recordingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return &recordingConn{c: c}, nil
+}
+
+func (c *recordingConnector) Driver() driver.Driver {
+	return nil
+}
+
+type recordingConn struct {
+	c *recordingConnector
+}
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return &recordingStmt{c: c.c, query: query}, nil
+}
+
+func (c *recordingConn) Close() error { return nil }
+
+func (c *recordingConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+type recordingStmt struct {
+	c     *recordingConnector
+	query string
+}
+
+func (s *recordingStmt) Close() error { return nil }
+
+func (s *recordingStmt) NumInput() int { return -1 }
+
+func (s *recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.c.mu.Lock()
+	defer s.c.mu.Unlock()
+	s.c.execs = append(s.c.execs, s.query)
+	return driver.RowsAffected(1), nil
+}
+
+func (s *recordingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.c.mu.Lock()
+	defer s.c.mu.Unlock()
+	s.c.reads = append(s.c.reads, s.query)
+	return emptyRows{}, nil
+}
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string { return []string{"id", "balance"} }
+
+func (emptyRows) Close() error { return nil }
+
+func (emptyRows) Next(dest []driver.Value) error { return io.EOF }
+
+func TestDBPair(t *testing.T) {
+	writer, reader := &recordingConnector{}, &recordingConnector{}
+	db := DBPair{
+		Writer: sql.OpenDB(writer),
+		Reader: sql.OpenDB(reader),
+	}
+	defer db.Writer.Close()
+	defer db.Reader.Close()
+	ctx := context.Background()
+
+	if err := InsertAccount(ctx, db, &Account{ID: 1, Balance: 100}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SelectAccount(ctx, db, &Account{ID: 1}); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("want sql.ErrNoRows, got %v", err)
+	}
+	if _, err := SelectAccountForUpdate(ctx, db, &Account{ID: 1}); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("want sql.ErrNoRows, got %v", err)
+	}
+	if _, err := SelectAllAccount(UseWriter(ctx), db); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(writer.execs) != 1 || len(reader.execs) != 0 {
+		t.Errorf("want the insert on the writer, got writer %q, reader %q", writer.execs, reader.execs)
+	}
+	wantReader := []string{
+		"SELECT `id`, `balance` FROM `account` WHERE `id` = ?",
+	}
+	wantWriter := []string{
+		"SELECT `id`, `balance` FROM `account` WHERE `id` = ? FOR UPDATE",
+		"SELECT `id`, `balance` FROM `account` ORDER BY `id`",
+	}
+	if diff := cmp.Diff(wantReader, reader.reads); diff != "" {
+		t.Errorf("reader mismatch (-want/+got):\n%s", diff)
+	}
+	if diff := cmp.Diff(wantWriter, writer.reads); diff != "" {
+		t.Errorf("writer mismatch (-want/+got):\n%s", diff)
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"log"
+
+	"github.com/shogo82148/myddlmaker"
+	schema "github.com/shogo82148/myddlmaker/testdata/doccomment"
+)
+
+func main() {
+	m, err := myddlmaker.New(&myddlmaker.Config{
+		ColumnCommentsFromDoc: true,
+		TableCommentsFromDoc:  true,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	m.AddStructs(&schema.Article{}, &schema.Draft{})
+
+	// the Go source code isn't generated, because Draft is declared only with the myddlmaker tag.
+	if err := m.GenerateFile(); err != nil {
+		log.Fatal(err)
+	}
+}
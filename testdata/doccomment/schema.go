@@ -0,0 +1,20 @@
+package schema
+
+import (
+	"github.com/shogo82148/myddlmaker"
+)
+
+// Article is an article.
+type Article struct {
+	// ID is the identifier of the article.
+	ID int64 `ddl:",auto"`
+
+	Title string // TODO: limit the length of the title
+
+	// Body is the body of the article.
+	Body string `ddl:",type=TEXT"`
+}
+
+func (*Article) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
@@ -0,0 +1,18 @@
+//go:build myddlmaker
+
+package schema
+
+import (
+	"github.com/shogo82148/myddlmaker"
+)
+
+// Draft is a draft of an article.
+// It is declared only in the files for the DDL maker.
+type Draft struct {
+	// ID is the identifier of the draft.
+	ID int64 `ddl:",auto"`
+}
+
+func (*Draft) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
@@ -0,0 +1,34 @@
+package schema
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestComments(t *testing.T) {
+	data, err := os.ReadFile("schema.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ddl := string(data)
+
+	for _, want := range []string{
+		"COMMENT 'ID is the identifier of the article.'",
+		"COMMENT 'Body is the body of the article.'",
+		"COMMENT='Article is an article.'",
+
+		// the files with the myddlmaker tag are parsed.
+		"COMMENT 'ID is the identifier of the draft.'",
+		"COMMENT='Draft is a draft of an article. It is declared only in the files for the DDL maker.'",
+	} {
+		if !strings.Contains(ddl, want) {
+			t.Errorf("%s is not found in schema.sql:\n%s", want, ddl)
+		}
+	}
+
+	// the line comments are not the column comments.
+	if strings.Contains(ddl, "TODO") {
+		t.Errorf("the line comment is used:\n%s", ddl)
+	}
+}
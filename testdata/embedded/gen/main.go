@@ -0,0 +1,31 @@
+package main
+
+import (
+	"log"
+
+	"github.com/shogo82148/myddlmaker"
+	schema "github.com/shogo82148/myddlmaker/testdata/embedded"
+)
+
+func main() {
+	m, err := myddlmaker.New(&myddlmaker.Config{
+		GenerateQueryBuilder:      true,
+		GenerateRepositories:      true,
+		GenerateGenericRepository: true,
+		GenerateValidation:        true,
+		GenerateFactories:         true,
+		GenerateSeeder:            true,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	m.AddStructs(&schema.Customer{})
+
+	if err := m.GenerateFile(); err != nil {
+		log.Fatal(err)
+	}
+	if err := m.GenerateGoFile(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,40 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/shogo82148/myddlmaker"
+)
+
+type Address struct {
+	City string
+	Zip  string `ddl:",size=16"`
+}
+
+type HomeAddress Address
+
+type WorkAddress Address
+
+type Timestamps struct {
+	CreatedAt time.Time `ddl:",auto_now_add"`
+	UpdatedAt time.Time `ddl:",auto_now"`
+}
+
+// Customer has two addresses whose fields have the same names.
+type Customer struct {
+	ID          int64 `ddl:",auto"`
+	Name        string
+	HomeAddress `ddl:",prefix=home_"`
+	WorkAddress `ddl:",prefix=work_"`
+	Timestamps
+}
+
+func (*Customer) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
+
+func (*Customer) Indexes() []*myddlmaker.Index {
+	return []*myddlmaker.Index{
+		myddlmaker.NewIndex("idx_home_city", "home_city"),
+	}
+}
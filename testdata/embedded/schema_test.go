@@ -0,0 +1,85 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return nil
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestFactory(t *testing.T) {
+	v := NewCustomerFactory().WithHomeAddressCity("Tokyo").WithWorkAddressCity("Osaka").Build()
+	if v.HomeAddress.City != "Tokyo" || v.WorkAddress.City != "Osaka" {
+		t.Errorf("unexpected addresses: %+v, %+v", v.HomeAddress, v.WorkAddress)
+	}
+	if v.HomeAddress.Zip == "" || v.WorkAddress.Zip == "" {
+		t.Errorf("the fake values are not set: %+v, %+v", v.HomeAddress, v.WorkAddress)
+	}
+	if err := v.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCustomer(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	customer := &Customer{
+		Name:        "alice",
+		HomeAddress: HomeAddress{City: "Tokyo", Zip: "100-0001"},
+		WorkAddress: WorkAddress{City: "Osaka", Zip: "530-0001"},
+	}
+	id, err := InsertCustomerReturningID(ctx, db, customer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := SelectCustomer(ctx, db, &Customer{ID: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(customer.HomeAddress, got.HomeAddress); diff != "" {
+		t.Errorf("HomeAddress (-want/+got):\n%s", diff)
+	}
+	if diff := cmp.Diff(customer.WorkAddress, got.WorkAddress); diff != "" {
+		t.Errorf("WorkAddress (-want/+got):\n%s", diff)
+	}
+
+	list, err := SelectAllCustomerByHomeAddressCity(ctx, db, "Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].WorkAddress.City != "Osaka" {
+		t.Errorf("unexpected customers: %v", list)
+	}
+}
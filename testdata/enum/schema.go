@@ -0,0 +1,22 @@
+package schema
+
+import (
+	"github.com/shogo82148/myddlmaker"
+)
+
+type Status string
+
+func (Status) EnumValues() []string {
+	return []string{"todo", "in-progress", "done"}
+}
+
+type Task struct {
+	ID       int64 `ddl:",auto"`
+	Title    string
+	Status   Status  `ddl:",default='todo'"`
+	Previous *Status `ddl:",null"`
+}
+
+func (*Task) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
@@ -0,0 +1,111 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return nil
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestStatus(t *testing.T) {
+	if StatusInProgress.String() != "in-progress" {
+		t.Errorf("want in-progress, got %s", StatusInProgress)
+	}
+	if !StatusDone.IsValid() {
+		t.Error("StatusDone must be valid")
+	}
+	if Status("unknown").IsValid() {
+		t.Error("unknown must not be valid")
+	}
+
+	v, err := StatusTodo.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "todo" {
+		t.Errorf("want todo, got %v", v)
+	}
+	if _, err := Status("unknown").Value(); err == nil || err.Error() != "invalid value of Status: unknown" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	var s Status
+	if err := s.Scan([]byte("done")); err != nil {
+		t.Fatal(err)
+	}
+	if s != StatusDone {
+		t.Errorf("want done, got %s", s)
+	}
+	if err := s.Scan("unknown"); err == nil || err.Error() != "invalid value of Status: unknown" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if s != StatusDone {
+		t.Errorf("Scan must not change the value on errors, got %s", s)
+	}
+	if err := s.Scan(int64(1)); err == nil {
+		t.Error("want error, got nil")
+	}
+}
+
+func TestTask(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	previous := StatusTodo
+	task := &Task{Title: "write the docs", Status: StatusInProgress, Previous: &previous}
+	id, err := InsertTaskReturningID(ctx, db, task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := SelectTask(ctx, db, &Task{ID: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(task, got); diff != "" {
+		t.Errorf("SelectTask (-want/+got):\n%s", diff)
+	}
+
+	task.Status = Status("unknown")
+	if err := UpdateTask(ctx, db, task); err == nil {
+		t.Error("want error, got nil")
+	}
+
+	var status *Status
+	if err := db.QueryRowContext(ctx, "SELECT `previous` FROM `task` WHERE `id` = ?", id).Scan(&status); err != nil {
+		t.Fatal(err)
+	}
+	if status == nil || *status != StatusTodo {
+		t.Errorf("want todo, got %v", status)
+	}
+}
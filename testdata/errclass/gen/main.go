@@ -0,0 +1,27 @@
+package main
+
+import (
+	"log"
+
+	"github.com/shogo82148/myddlmaker"
+	schema "github.com/shogo82148/myddlmaker/testdata/errclass"
+)
+
+func main() {
+	m, err := myddlmaker.New(&myddlmaker.Config{
+		GenerateErrorHelpers: true,
+		GenerateRepositories: true,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	m.AddStructs(&schema.Author{}, &schema.Book{})
+
+	if err := m.GenerateFile(); err != nil {
+		log.Fatal(err)
+	}
+	if err := m.GenerateGoFile(); err != nil {
+		log.Fatal(err)
+	}
+}
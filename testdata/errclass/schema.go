@@ -0,0 +1,42 @@
+package schema
+
+import (
+	"github.com/shogo82148/myddlmaker"
+)
+
+type Author struct {
+	ID    int32
+	Email string
+}
+
+func (*Author) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
+
+func (*Author) UniqueIndexes() []*myddlmaker.UniqueIndex {
+	return []*myddlmaker.UniqueIndex{
+		myddlmaker.NewUniqueIndex("uniq_email", "email"),
+	}
+}
+
+type Book struct {
+	ID       int32
+	AuthorID int32
+	Title    string
+}
+
+func (*Book) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
+
+func (*Book) Indexes() []*myddlmaker.Index {
+	return []*myddlmaker.Index{
+		myddlmaker.NewIndex("idx_author_id", "author_id"),
+	}
+}
+
+func (*Book) ForeignKeys() []*myddlmaker.ForeignKey {
+	return []*myddlmaker.ForeignKey{
+		myddlmaker.NewForeignKey("fk_book_author_id", []string{"author_id"}, "author", []string{"id"}),
+	}
+}
@@ -0,0 +1,165 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return nil
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestErrorHelpers(t *testing.T) {
+	tests := []struct {
+		err       error
+		duplicate bool
+		fk        bool
+	}{
+		{&mysql.MySQLError{Number: 1062}, true, false},
+		{fmt.Errorf("wrapped: %w", &mysql.MySQLError{Number: 1062}), true, false},
+		{classifyError(&mysql.MySQLError{Number: 1062}), true, false},
+		{ErrDuplicateEntry, true, false},
+		{&mysql.MySQLError{Number: 1451}, false, true},
+		{&mysql.MySQLError{Number: 1452}, false, true},
+		{&mysql.MySQLError{Number: 1213}, false, false},
+		{sql.ErrNoRows, false, false},
+		{nil, false, false},
+	}
+	for _, tt := range tests {
+		if got := IsDuplicateEntry(tt.err); got != tt.duplicate {
+			t.Errorf("IsDuplicateEntry(%v) = %v, want %v", tt.err, got, tt.duplicate)
+		}
+		if got := IsForeignKeyViolation(tt.err); got != tt.fk {
+			t.Errorf("IsForeignKeyViolation(%v) = %v, want %v", tt.err, got, tt.fk)
+		}
+	}
+
+	// the classified error keeps the original error.
+	err := classifyError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '1' for key 'PRIMARY'"})
+	var myErr *mysql.MySQLError
+	if !errors.As(err, &myErr) || myErr.Number != 1062 {
+		t.Errorf("want the MySQL error, got %v", err)
+	}
+	if !errors.Is(err, ErrDuplicateEntry) || errors.Is(err, ErrForeignKeyViolation) {
+		t.Errorf("unexpected class: %v", err)
+	}
+}
+
+func TestFakeDuplicateEntry(t *testing.T) {
+	ctx := context.Background()
+	q := NewFakeAuthorQueries()
+	if err := q.Insert(ctx, &Author{ID: 1, Email: "alice@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Insert(ctx, &Author{ID: 1, Email: "bob@example.com"}); !IsDuplicateEntry(err) {
+		t.Errorf("want the duplicate entry error, got %v", err)
+	}
+}
+
+// failingConnector opens the connections whose statements fail with err.
+type failingConnector struct {
+	err error
+}
+
+func (c failingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return failingConn(c), nil
+}
+
+func (c failingConnector) Driver() driver.Driver {
+	return nil
+}
+
+type failingConn struct {
+	err error
+}
+
+func (c failingConn) Prepare(query string) (driver.Stmt, error) {
+	return failingStmt(c), nil
+}
+
+func (c failingConn) Close() error { return nil }
+
+func (c failingConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+type failingStmt struct {
+	err error
+}
+
+func (s failingStmt) Close() error { return nil }
+
+func (s failingStmt) NumInput() int { return -1 }
+
+func (s failingStmt) Exec(args []driver.Value) (driver.Result, error) { return nil, s.err }
+
+func (s failingStmt) Query(args []driver.Value) (driver.Rows, error) { return nil, s.err }
+
+func TestClassifyWriteErrors(t *testing.T) {
+	ctx := context.Background()
+
+	db := sql.OpenDB(failingConnector{err: &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}})
+	defer db.Close()
+	if err := InsertAuthor(ctx, db, &Author{ID: 1}); !errors.Is(err, ErrDuplicateEntry) {
+		t.Errorf("InsertAuthor: want ErrDuplicateEntry, got %v", err)
+	}
+	if err := UpsertAuthor(ctx, db, &Author{ID: 1}); !errors.Is(err, ErrDuplicateEntry) {
+		t.Errorf("UpsertAuthor: want ErrDuplicateEntry, got %v", err)
+	}
+
+	db = sql.OpenDB(failingConnector{err: &mysql.MySQLError{Number: 1451, Message: "Cannot delete or update a parent row"}})
+	defer db.Close()
+	if err := DeleteAuthorByPK(ctx, db, 1); !errors.Is(err, ErrForeignKeyViolation) {
+		t.Errorf("DeleteAuthorByPK: want ErrForeignKeyViolation, got %v", err)
+	}
+}
+
+func TestErrorClasses(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	if err := InsertAuthor(ctx, db, &Author{ID: 1, Email: "alice@example.com"}); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+	if err := InsertAuthor(ctx, db, &Author{ID: 2, Email: "alice@example.com"}); !errors.Is(err, ErrDuplicateEntry) {
+		t.Errorf("want ErrDuplicateEntry, got %v", err)
+	}
+	if err := InsertBook(ctx, db, &Book{ID: 1, AuthorID: 3, Title: "no author"}); !errors.Is(err, ErrForeignKeyViolation) {
+		t.Errorf("want ErrForeignKeyViolation, got %v", err)
+	}
+	if err := InsertBook(ctx, db, &Book{ID: 1, AuthorID: 1, Title: "Go"}); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+	if err := DeleteAuthorByPK(ctx, db, 1); !errors.Is(err, ErrForeignKeyViolation) {
+		t.Errorf("want ErrForeignKeyViolation, got %v", err)
+	}
+}
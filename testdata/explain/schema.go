@@ -0,0 +1,29 @@
+package schema
+
+import (
+	"github.com/shogo82148/myddlmaker"
+)
+
+type User struct {
+	ID       int32 `ddl:",auto"`
+	TenantID int32
+	Email    string
+	Name     string
+}
+
+func (*User) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
+
+func (*User) UniqueIndexes() []*myddlmaker.UniqueIndex {
+	return []*myddlmaker.UniqueIndex{
+		myddlmaker.NewUniqueIndex("uniq_email", "email"),
+	}
+}
+
+func (*User) Indexes() []*myddlmaker.Index {
+	return []*myddlmaker.Index{
+		myddlmaker.NewIndex("idx_tenant_id_name", "tenant_id", "name"),
+		myddlmaker.NewIndex("idx_name", "name").Invisible(),
+	}
+}
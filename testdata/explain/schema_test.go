@@ -0,0 +1,82 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return nil
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestAllQueryPlans(t *testing.T) {
+	var funcs []string
+	for _, plan := range AllQueryPlans() {
+		funcs = append(funcs, plan.Func)
+	}
+	want := []string{
+		"SelectUser",
+		"SelectUserByEmail",
+		"SelectAllUserByTenantIDAndName",
+		"SelectAllUserByName",
+		"DeleteUserByEmail",
+		"DeleteUserByTenantIDAndName",
+		"DeleteUserByName",
+	}
+	if diff := cmp.Diff(want, funcs); diff != "" {
+		t.Errorf("(-want/+got):\n%s", diff)
+	}
+}
+
+func TestExplainQueryPlans(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+	if _, err := db.ExecContext(ctx, "DROP TABLE IF EXISTS `user`"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ExecContext(ctx, UserCreateTableSQL()); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ExplainQueryPlans(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// MySQL doesn't use the invisible index idx_name.
+	if len(got) != 2 {
+		t.Fatalf("want the queries of idx_name, got %q", got)
+	}
+	for _, s := range got {
+		if !strings.Contains(s, "ByName: the query uses no index") || !strings.Contains(s, "want a visible index") {
+			t.Errorf("unexpected result: %q", s)
+		}
+	}
+}
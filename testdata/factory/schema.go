@@ -0,0 +1,44 @@
+package schema
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/shogo82148/myddlmaker"
+)
+
+type Status string
+
+func (Status) EnumValues() []string {
+	return []string{"active", "suspended"}
+}
+
+type Nickname string
+
+type Profile struct {
+	ID        int64    `ddl:",auto"`
+	Name      string   `ddl:",size=8"`
+	Nickname  Nickname `ddl:",size=4"`
+	Bio       *string  `ddl:",type=TINYTEXT"`
+	Email     sql.NullString
+	Website   *string `ddl:",null,size=16"`
+	Status    Status
+	Tags      string  `ddl:",type=SET('go','mysql')"`
+	Age       int16   `ddl:",type=TINYINT,unsigned"`
+	Score     int32   `ddl:",type=SMALLINT"`
+	Rate      float64 `ddl:",type=DOUBLE"`
+	Active    bool
+	Avatar    []byte `ddl:",size=4"`
+	BornAt    time.Time
+	CreatedAt time.Time `ddl:",auto_now_add"`
+}
+
+func (*Profile) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
+
+func (*Profile) UniqueIndexes() []*myddlmaker.UniqueIndex {
+	return []*myddlmaker.UniqueIndex{
+		myddlmaker.NewUniqueIndex("uniq_name", "name"),
+	}
+}
@@ -0,0 +1,78 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return nil
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestProfileFactory(t *testing.T) {
+	profiles := NewProfileFactory().BuildList(300)
+	names := map[string]bool{}
+	for _, p := range profiles {
+		if err := p.Validate(); err != nil {
+			t.Errorf("%#v: %v", p, err)
+		}
+		if names[p.Name] {
+			t.Errorf("duplicated name %q", p.Name)
+		}
+		names[p.Name] = true
+		if !p.Status.IsValid() {
+			t.Errorf("invalid status %q", p.Status)
+		}
+		if p.ID != 0 || p.Website != nil || !p.CreatedAt.IsZero() {
+			t.Errorf("the auto increment, NULL and auto_now_add columns must be zero: %#v", p)
+		}
+	}
+}
+
+func TestProfileFactory_Overrides(t *testing.T) {
+	base := NewProfileFactory().WithStatus(StatusSuspended)
+	alice := base.WithName("alice").With(func(v *Profile) {
+		v.Age = 20
+	}).Build()
+	if alice.Name != "alice" || alice.Status != StatusSuspended || alice.Age != 20 {
+		t.Errorf("unexpected profile: %#v", alice)
+	}
+
+	// the overrides of the derived factories don't affect the base.
+	if p := base.Build(); p.Name == "alice" || p.Status != StatusSuspended {
+		t.Errorf("unexpected profile: %#v", p)
+	}
+}
+
+func TestProfileFactory_Insert(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+	if err := InsertProfile(ctx, db, NewProfileFactory().BuildList(10)...); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,29 @@
+package schema
+
+import (
+	"github.com/shogo82148/myddlmaker"
+)
+
+type User struct {
+	ID       int32 `ddl:",auto"`
+	TenantID int32
+	Email    string
+	Name     string
+}
+
+func (*User) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
+
+func (*User) UniqueIndexes() []*myddlmaker.UniqueIndex {
+	return []*myddlmaker.UniqueIndex{
+		myddlmaker.NewUniqueIndex("uniq_email", "email"),
+		myddlmaker.NewUniqueIndex("uniq_name_tenant_id", "name", "tenant_id"),
+	}
+}
+
+func (*User) Indexes() []*myddlmaker.Index {
+	return []*myddlmaker.Index{
+		myddlmaker.NewIndex("idx_tenant_id", "tenant_id"),
+	}
+}
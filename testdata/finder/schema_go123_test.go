@@ -0,0 +1,108 @@
+//go:build go1.23
+// +build go1.23
+
+package schema
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestIterate(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	if err := InsertUser(ctx, db,
+		&User{TenantID: 20, Email: "grace@example.com", Name: "grace"},
+		&User{TenantID: 20, Email: "heidi@example.com", Name: "heidi"},
+		&User{TenantID: 20, Email: "ivan@example.com", Name: "ivan"},
+	); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+
+	var names []string
+	for u, err := range IterateAllUserByTenantID(ctx, db, 20) {
+		if err != nil {
+			t.Fatalf("failed to iterate: %v", err)
+		}
+		names = append(names, u.Name)
+	}
+	if len(names) != 3 || names[0] != "grace" || names[1] != "heidi" || names[2] != "ivan" {
+		t.Errorf("unexpected names: %v", names)
+	}
+
+	// stop the iteration early.
+	names = names[:0]
+	for u, err := range IterateAllUserByTenantID(ctx, db, 20, SelectOrderDesc()) {
+		if err != nil {
+			t.Fatalf("failed to iterate: %v", err)
+		}
+		names = append(names, u.Name)
+		break
+	}
+	if len(names) != 1 || names[0] != "ivan" {
+		t.Errorf("unexpected names: %v", names)
+	}
+
+	for _, err := range IterateAllUser(ctx, db, SelectOrderBy("unknown")) {
+		if err != ErrInvalidOrderBy {
+			t.Errorf("want ErrInvalidOrderBy, got %v", err)
+		}
+	}
+}
+
+func TestInsertFrom_Empty(t *testing.T) {
+	// the empty iterator doesn't execute any statement, so the execer is never used.
+	if err := InsertUserFrom(context.Background(), nil, func(yield func(*User) bool) {}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInsertFrom(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	// more than two chunks.
+	const count = 70
+	var users []*User
+	seq := func(yield func(*User) bool) {
+		for i := 0; i < count; i++ {
+			u := &User{TenantID: 11, Email: fmt.Sprintf("user%d@example.com", i), Name: fmt.Sprintf("user%d", i)}
+			users = append(users, u)
+			if !yield(u) {
+				return
+			}
+		}
+	}
+	if err := InsertUserFrom(ctx, db, seq); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+	n, err := CountUserByTenantID(ctx, db, 11)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != count {
+		t.Errorf("unexpected count: want %d, got %d", count, n)
+	}
+
+	// the duplicated email stops the iteration.
+	users = nil
+	if err := InsertUserFrom(ctx, db, seq); err == nil {
+		t.Error("want error, got nil")
+	}
+	if len(users) != 32 {
+		t.Errorf("want the iteration stops at the first chunk, got %d values", len(users))
+	}
+}
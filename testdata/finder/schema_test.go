@@ -0,0 +1,539 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return nil
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestSelectByUniqueIndexes(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	if err := InsertUser(ctx, db,
+		&User{TenantID: 1, Email: "alice@example.com", Name: "alice"},
+		&User{TenantID: 2, Email: "bob@example.com", Name: "bob"},
+	); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+
+	u, err := SelectUserByEmail(ctx, db, "bob@example.com")
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if u.Name != "bob" {
+		t.Errorf("unexpected name: want bob, got %s", u.Name)
+	}
+
+	u, err = SelectUserByNameAndTenantID(ctx, db, "alice", 1)
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if u.Email != "alice@example.com" {
+		t.Errorf("unexpected email: want alice@example.com, got %s", u.Email)
+	}
+
+	if _, err := SelectUserByEmail(ctx, db, "carol@example.com"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("want sql.ErrNoRows, got %v", err)
+	}
+}
+
+func TestSelectByIndexes(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	if err := InsertUser(ctx, db,
+		&User{TenantID: 3, Email: "carol@example.com", Name: "carol"},
+		&User{TenantID: 3, Email: "dave@example.com", Name: "dave"},
+		&User{TenantID: 3, Email: "ellen@example.com", Name: "ellen"},
+		&User{TenantID: 4, Email: "frank@example.com", Name: "frank"},
+	); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+
+	users, err := SelectAllUserByTenantID(ctx, db, 3)
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if len(users) != 3 {
+		t.Fatalf("unexpected count: want 3, got %d", len(users))
+	}
+
+	users, err = SelectAllUserByTenantID(ctx, db, 3, SelectOrderDesc(), SelectLimit(2))
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if len(users) != 2 || users[0].Name != "ellen" || users[1].Name != "dave" {
+		t.Errorf("unexpected users: %v", users)
+	}
+
+	users, err = SelectAllUserByTenantID(ctx, db, 3, SelectOffset(1))
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if len(users) != 2 || users[0].Name != "dave" || users[1].Name != "ellen" {
+		t.Errorf("unexpected users: %v", users)
+	}
+}
+
+func TestCountAndExists(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	if err := InsertUser(ctx, db,
+		&User{TenantID: 5, Email: "grace@example.com", Name: "grace"},
+		&User{TenantID: 5, Email: "heidi@example.com", Name: "heidi"},
+	); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+
+	n, err := CountUserByTenantID(ctx, db, 5)
+	if err != nil {
+		t.Fatalf("failed to count: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("unexpected count: want 2, got %d", n)
+	}
+
+	exists, err := ExistsUserByEmail(ctx, db, "grace@example.com")
+	if err != nil {
+		t.Fatalf("failed to check existence: %v", err)
+	}
+	if !exists {
+		t.Error("want exists, but not")
+	}
+
+	exists, err = ExistsUserByID(ctx, db, -1)
+	if err != nil {
+		t.Fatalf("failed to check existence: %v", err)
+	}
+	if exists {
+		t.Error("want not exists, but exists")
+	}
+}
+
+func TestDeleteByIndexes(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	if err := InsertUser(ctx, db,
+		&User{TenantID: 9, Email: "mallory@example.com", Name: "mallory"},
+		&User{TenantID: 9, Email: "niaj@example.com", Name: "niaj"},
+		&User{TenantID: 9, Email: "olivia@example.com", Name: "olivia"},
+	); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+
+	n, err := DeleteUserByEmail(ctx, db, "mallory@example.com")
+	if err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("unexpected deleted rows: want 1, got %d", n)
+	}
+
+	n, err = DeleteUserByTenantID(ctx, db, 9)
+	if err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("unexpected deleted rows: want 2, got %d", n)
+	}
+
+	// no rows match, it is not an error.
+	n, err = DeleteUserByNameAndTenantID(ctx, db, "olivia", 9)
+	if err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("unexpected deleted rows: want 0, got %d", n)
+	}
+}
+
+func TestUpdateColumnsByIndexes(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	if err := InsertUser(ctx, db,
+		&User{TenantID: 11, Email: "peggy@example.com", Name: "peggy"},
+		&User{TenantID: 11, Email: "rupert@example.com", Name: "rupert"},
+		&User{TenantID: 11, Email: "sybil@example.com", Name: "sybil"},
+	); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+
+	// move two of them to another tenant.
+	tenantID := int32(12)
+	n, err := UpdateUserColumnsByTenantID(ctx, db, 11, UserChanges{TenantID: &tenantID}, 2)
+	if err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("unexpected updated rows: want 2, got %d", n)
+	}
+
+	// move the rest without the limit.
+	n, err = UpdateUserColumnsByTenantID(ctx, db, 11, UserChanges{TenantID: &tenantID}, 0)
+	if err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("unexpected updated rows: want 1, got %d", n)
+	}
+	count, err := CountUserByTenantID(ctx, db, 12)
+	if err != nil {
+		t.Fatalf("failed to count: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("unexpected count: want 3, got %d", count)
+	}
+
+	// no changes, no statements.
+	n, err = UpdateUserColumnsByTenantID(ctx, db, 12, UserChanges{}, 0)
+	if err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("unexpected updated rows: want 0, got %d", n)
+	}
+}
+
+func TestSelectByIndexesAfter(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	if err := InsertUser(ctx, db,
+		&User{TenantID: 6, Email: "ivan@example.com", Name: "ivan"},
+		&User{TenantID: 6, Email: "judy@example.com", Name: "judy"},
+		&User{TenantID: 6, Email: "mallory@example.com", Name: "mallory"},
+	); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+
+	var names []string
+	var cursor string
+	for i := 0; ; i++ {
+		if i > 3 {
+			t.Fatal("too many pages")
+		}
+		users, next, err := SelectAllUserByTenantIDAfter(ctx, db, 6, cursor, 2)
+		if err != nil {
+			t.Fatalf("failed to select: %v", err)
+		}
+		for _, u := range users {
+			names = append(names, u.Name)
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	if len(names) != 3 || names[0] != "ivan" || names[1] != "judy" || names[2] != "mallory" {
+		t.Errorf("unexpected names: %v", names)
+	}
+
+	if _, _, err := SelectAllUserByTenantIDAfter(ctx, db, 6, "!invalid!", 2); err == nil {
+		t.Error("want some error, got nil")
+	}
+}
+
+func TestSelectOrderBy(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	if err := InsertUser(ctx, db,
+		&User{TenantID: 7, Email: "z@example.com", Name: "oscar"},
+		&User{TenantID: 7, Email: "y@example.com", Name: "peggy"},
+	); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+
+	users, err := SelectAllUserByTenantID(ctx, db, 7, SelectOrderBy("email"))
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if len(users) != 2 || users[0].Name != "peggy" || users[1].Name != "oscar" {
+		t.Errorf("unexpected users: %v", users)
+	}
+
+	users, err = SelectAllUser(ctx, db, SelectOrderBy("email"), SelectOrderDesc(), SelectLimit(1))
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if len(users) != 1 || users[0].Name != "oscar" {
+		t.Errorf("unexpected users: %v", users)
+	}
+}
+
+func TestSelectOrderBy_NotIndexed(t *testing.T) {
+	ctx := context.Background()
+
+	// the error is reported before the query, so the database is not needed.
+	if _, err := SelectAllUser(ctx, nil, SelectOrderBy("password")); !errors.Is(err, ErrInvalidOrderBy) {
+		t.Errorf("want ErrInvalidOrderBy, got %v", err)
+	}
+}
+
+func TestSelectForUpdate(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	if err := InsertUser(ctx, db, &User{TenantID: 8, Email: "trent@example.com", Name: "trent"}); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to begin: %v", err)
+	}
+	defer tx.Rollback()
+
+	u, err := SelectUserByEmailForUpdate(ctx, tx, "trent@example.com")
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	u.Name = "TRENT"
+	if err := UpdateUser(ctx, tx, u); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	if _, err := SelectUserForShare(ctx, tx, &User{ID: u.ID}); err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	if err := InsertUser(ctx, db,
+		&User{TenantID: 30, Email: "judy@example.com", Name: "judy"},
+		&User{TenantID: 30, Email: "mallory@example.com", Name: "mallory"},
+		&User{TenantID: 30, Email: "oscar@example.com", Name: "oscar"},
+		&User{TenantID: 31, Email: "peggy@example.com", Name: "peggy"},
+	); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+
+	users, err := QueryUser(ctx, db, And(
+		UserWhere.TenantID.Eq(30),
+		Or(UserWhere.Name.In("judy", "oscar"), UserWhere.Email.Eq("peggy@example.com")),
+	), SelectOrderBy(UserColumns.Name), SelectOrderDesc())
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	if len(users) != 2 || users[0].Name != "oscar" || users[1].Name != "judy" {
+		t.Errorf("unexpected users: %v", users)
+	}
+
+	users, err = QueryUser(ctx, db, And(UserWhere.TenantID.Ge(30), UserWhere.TenantID.Le(31), Not(UserWhere.Name.Eq("judy"))))
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	if len(users) != 3 {
+		t.Errorf("unexpected users: %v", users)
+	}
+
+	users, err = QueryUser(ctx, db, UserWhere.Name.In())
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	if len(users) != 0 {
+		t.Errorf("unexpected users: %v", users)
+	}
+}
+
+func TestScan(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	if err := InsertUser(ctx, db,
+		&User{TenantID: 40, Email: "rupert@example.com", Name: "rupert"},
+		&User{TenantID: 40, Email: "sybil@example.com", Name: "sybil"},
+	); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+
+	const q = "SELECT `id`, `tenant_id`, `email`, `name` FROM `user` WHERE `tenant_id` = ? ORDER BY `name`"
+	u, err := ScanUser(db.QueryRowContext(ctx, q, 40))
+	if err != nil {
+		t.Fatalf("failed to scan: %v", err)
+	}
+	if u.Name != "rupert" {
+		t.Errorf("unexpected name: want rupert, got %s", u.Name)
+	}
+
+	rows, err := db.QueryContext(ctx, q, 40)
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	users, err := ScanUserRows(rows)
+	if err != nil {
+		t.Fatalf("failed to scan: %v", err)
+	}
+	if len(users) != 2 || users[0].Name != "rupert" || users[1].Name != "sybil" {
+		t.Errorf("unexpected users: %v", users)
+	}
+}
+
+func TestFakeUserQueries(t *testing.T) {
+	ctx := context.Background()
+	var q UserQueries = NewFakeUserQueries()
+
+	if err := q.Insert(ctx,
+		&User{TenantID: 1, Email: "bob@example.com", Name: "bob"},
+		&User{TenantID: 1, Email: "alice@example.com", Name: "alice"},
+	); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+
+	// the auto-increment column is assigned in the order of insertion.
+	u, err := q.Select(ctx, &User{ID: 2})
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if u.Name != "alice" {
+		t.Errorf("unexpected name: want alice, got %s", u.Name)
+	}
+
+	users, err := q.SelectAll(ctx, SelectOrderBy(UserColumns.Name), SelectLimit(1))
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if len(users) != 1 || users[0].Name != "alice" {
+		t.Errorf("unexpected users: %v", users)
+	}
+	if _, err := q.SelectAll(ctx, SelectOrderBy("unknown")); !errors.Is(err, ErrInvalidOrderBy) {
+		t.Errorf("want ErrInvalidOrderBy, got %v", err)
+	}
+
+	u.Name = "carol"
+	if err := q.Update(ctx, u); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	users, err = q.SelectAll(ctx, SelectOrderDesc())
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if len(users) != 2 || users[0].Name != "carol" || users[1].Name != "bob" {
+		t.Errorf("unexpected users: %v", users)
+	}
+
+	if err := q.Delete(ctx, u); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	if err := q.Delete(ctx, u); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("want sql.ErrNoRows, got %v", err)
+	}
+	if _, err := q.Select(ctx, u); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("want sql.ErrNoRows, got %v", err)
+	}
+}
+
+func TestUserQueries(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	q := NewUserQueries(db)
+	if err := q.Insert(ctx, &User{TenantID: 50, Email: "trent@example.com", Name: "trent"}); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+	u, err := SelectUserByEmail(ctx, db, "trent@example.com")
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	got, err := q.Select(ctx, u)
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if got.Name != "trent" {
+		t.Errorf("unexpected name: want trent, got %s", got.Name)
+	}
+	if err := q.Delete(ctx, u); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+}
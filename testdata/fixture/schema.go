@@ -0,0 +1,43 @@
+package schema
+
+import (
+	"github.com/shogo82148/myddlmaker"
+)
+
+type Settings struct {
+	Theme string `json:"theme"`
+}
+
+type User struct {
+	ID       uint64
+	Name     string
+	Active   bool
+	Settings myddlmaker.JSON[Settings]
+}
+
+func (*User) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
+
+// Post refers to User, that is added after Post.
+type Post struct {
+	ID     uint64
+	UserID uint64
+	Title  string
+}
+
+func (*Post) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
+
+func (*Post) Indexes() []*myddlmaker.Index {
+	return []*myddlmaker.Index{
+		myddlmaker.NewIndex("idx_user_id", "user_id"),
+	}
+}
+
+func (*Post) ForeignKeys() []*myddlmaker.ForeignKey {
+	return []*myddlmaker.ForeignKey{
+		myddlmaker.NewForeignKey("fk_post_user", []string{"user_id"}, "user", []string{"id"}),
+	}
+}
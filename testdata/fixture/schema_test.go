@@ -0,0 +1,105 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return nil
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+var fixtures = fstest.MapFS{
+	// the posts refer to the users in the other file.
+	"post.json": &fstest.MapFile{Data: []byte(`{
+		"post": [
+			{"id": 1, "user_id": 18446744073709551615, "title": "Hello"}
+		]
+	}`)},
+	"user.json": &fstest.MapFile{Data: []byte(`{
+		"user": [
+			{"id": 18446744073709551615, "name": "Alice", "active": true, "settings": {"theme": "dark"}}
+		]
+	}`)},
+	"user.txt": &fstest.MapFile{Data: []byte("user")},
+}
+
+func TestLoadFixtures_UnknownTable(t *testing.T) {
+	err := LoadFixtures(context.Background(), nil, Fixtures{"comment": nil})
+	if err == nil || !strings.Contains(err.Error(), `unknown table in the fixtures: "comment"`) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestLoadFixtures_UnknownColumn(t *testing.T) {
+	err := LoadFixtures(context.Background(), nil, Fixtures{
+		"user": {{"id": 1, "email": "alice@example.com"}},
+	})
+	if err == nil || err.Error() != `user: row #0: unknown column "email"` {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestLoadFixturesFile_UnknownExtension(t *testing.T) {
+	err := LoadFixturesFile(context.Background(), nil, fixtures, "user.txt")
+	if err == nil || !strings.Contains(err.Error(), "unknown extension") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestLoadFixturesFile(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+	if _, err := db.ExecContext(ctx, "DELETE FROM `post`"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ExecContext(ctx, "DELETE FROM `user`"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := LoadFixturesFile(ctx, db, fixtures, "post.json", "user.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := SelectUser(ctx, db, &User{ID: 18446744073709551615})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Name != "Alice" || !user.Active || user.Settings.Get().Theme != "dark" {
+		t.Errorf("unexpected user: %#v", user)
+	}
+	post, err := SelectPost(ctx, db, &Post{ID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if post.UserID != 18446744073709551615 || post.Title != "Hello" {
+		t.Errorf("unexpected post: %#v", post)
+	}
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"log"
+
+	"github.com/shogo82148/myddlmaker"
+	schema "github.com/shogo82148/myddlmaker/testdata/foreach"
+)
+
+func main() {
+	m, err := myddlmaker.New(&myddlmaker.Config{})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	m.AddStructs(&schema.Item{}, &schema.Stock{})
+
+	if err := m.GenerateFile(); err != nil {
+		log.Fatal(err)
+	}
+	if err := m.GenerateGoFile(); err != nil {
+		log.Fatal(err)
+	}
+}
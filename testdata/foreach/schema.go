@@ -0,0 +1,24 @@
+package schema
+
+import (
+	"github.com/shogo82148/myddlmaker"
+)
+
+type Item struct {
+	ID   int64 `ddl:",auto"`
+	Name string
+}
+
+func (*Item) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
+
+type Stock struct {
+	WarehouseID int64
+	ItemID      int64
+	Count       int64
+}
+
+func (*Stock) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("warehouse_id", "item_id")
+}
@@ -0,0 +1,131 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return nil
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestForEachItem_BatchSize(t *testing.T) {
+	err := ForEachItem(context.Background(), nil, 0, func(batch []*Item) error {
+		return nil
+	})
+	if err == nil {
+		t.Error("want error, got nil")
+	}
+}
+
+func TestForEachItem(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	var items []*Item
+	for i := 0; i < 5; i++ {
+		items = append(items, &Item{Name: fmt.Sprintf("item%d", i)})
+	}
+	if err := InsertItem(ctx, db, items...); err != nil {
+		t.Fatal(err)
+	}
+
+	// rename all the items in batches, updating the rows while walking the table.
+	var sizes []int
+	err := ForEachItem(ctx, db, 2, func(batch []*Item) error {
+		sizes = append(sizes, len(batch))
+		for _, item := range batch {
+			item.Name = strings.ToUpper(item.Name)
+		}
+		return UpdateItem(ctx, db, batch...)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]int{2, 2, 1}, sizes); diff != "" {
+		t.Errorf("batch sizes mismatch (-want +got):\n%s", diff)
+	}
+	got, err := SelectAllItem(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, item := range got {
+		if item.Name != strings.ToUpper(item.Name) {
+			t.Errorf("item %d is not updated: %q", item.ID, item.Name)
+		}
+	}
+
+	// stop at the error of fn.
+	errStop := errors.New("stop")
+	calls := 0
+	err = ForEachItem(ctx, db, 2, func(batch []*Item) error {
+		calls++
+		return errStop
+	})
+	if !errors.Is(err, errStop) || calls != 1 {
+		t.Errorf("want errStop after 1 call, got %v after %d calls", err, calls)
+	}
+}
+
+func TestForEachStock(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	want := []*Stock{
+		{WarehouseID: 1, ItemID: 1, Count: 10},
+		{WarehouseID: 1, ItemID: 2, Count: 20},
+		{WarehouseID: 2, ItemID: 1, Count: 30},
+	}
+	if err := InsertStock(ctx, db, want[2], want[0], want[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []*Stock
+	err := ForEachStock(ctx, db, 2, func(batch []*Stock) error {
+		got = append(got, batch...)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ForEachStock mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,26 @@
+package schema
+
+import (
+	"github.com/shogo82148/myddlmaker"
+)
+
+type UserID int64
+
+type User struct {
+	ID   UserID `ddl:",auto"`
+	Name string
+}
+
+func (*User) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
+
+type Membership struct {
+	UserID  int64
+	GroupID int64
+	Role    string
+}
+
+func (*Membership) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("user_id", "group_id")
+}
@@ -0,0 +1,123 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return nil
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// keysOf is a helper that works with any table.
+func keysOf[T any, PK comparable](meta TableMeta[T, PK], values []*T) []PK {
+	keys := make([]PK, 0, len(values))
+	for _, v := range values {
+		keys = append(keys, meta.Key(v))
+	}
+	return keys
+}
+
+// getOrInsert is a helper that works with any table.
+func getOrInsert[T any, PK comparable](ctx context.Context, r *Repo[T, PK], v *T) (*T, error) {
+	got, err := r.Get(ctx, r.Meta().Key(v))
+	if err == nil {
+		return got, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err := r.Insert(ctx, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func TestTableMeta(t *testing.T) {
+	if got, want := UserMeta.Name(), "user"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+	if diff := cmp.Diff([]UserID{1, 2}, keysOf(UserMeta, []*User{{ID: 1}, {ID: 2}})); diff != "" {
+		t.Errorf("keys mismatch (-want +got):\n%s", diff)
+	}
+
+	got := keysOf(MembershipMeta, []*Membership{{UserID: 1, GroupID: 2, Role: "owner"}})
+	if diff := cmp.Diff([]MembershipKey{{UserID: 1, GroupID: 2}}, got); diff != "" {
+		t.Errorf("keys mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRepo(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	users := NewUserRepo(db)
+	if err := users.Insert(ctx, &User{Name: "alice"}, &User{Name: "bob"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := users.Update(ctx, &User{ID: 2, Name: "carol"}); err != nil {
+		t.Fatal(err)
+	}
+	list, err := users.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]*User{{ID: 1, Name: "alice"}, {ID: 2, Name: "carol"}}, list); diff != "" {
+		t.Errorf("List mismatch (-want +got):\n%s", diff)
+	}
+	if err := users.Delete(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := users.Get(ctx, 1); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("want sql.ErrNoRows, got %v", err)
+	}
+
+	memberships := NewMembershipRepo(db)
+	m := &Membership{UserID: 2, GroupID: 1, Role: "owner"}
+	for i := 0; i < 2; i++ {
+		got, err := getOrInsert(ctx, memberships, m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(m, got); diff != "" {
+			t.Errorf("getOrInsert mismatch (-want +got):\n%s", diff)
+		}
+	}
+	got, err := memberships.Get(ctx, MembershipKey{UserID: 2, GroupID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(m, got); diff != "" {
+		t.Errorf("Get mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"log"
+
+	"github.com/shogo82148/myddlmaker"
+	schema "github.com/shogo82148/myddlmaker/testdata/gopackage"
+)
+
+func main() {
+	m, err := myddlmaker.New(&myddlmaker.Config{
+		OutGoDir:             "queries",
+		GoPackagePath:        "github.com/shogo82148/myddlmaker/testdata/gopackage/queries",
+		PackageName:          "queries",
+		GenerateQueryBuilder: true,
+		GenerateRepositories: true,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	m.AddStructs(&schema.Task{})
+
+	if err := m.GenerateFile(); err != nil {
+		log.Fatal(err)
+	}
+	if err := m.GenerateGoFile(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,25 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/shogo82148/myddlmaker"
+)
+
+type Status string
+
+const (
+	StatusTodo Status = "todo"
+	StatusDone Status = "done"
+)
+
+type Task struct {
+	ID        int64 `ddl:",auto"`
+	Title     string
+	Status    Status `ddl:",size=16"`
+	CreatedAt time.Time
+}
+
+func (*Task) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
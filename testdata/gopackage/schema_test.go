@@ -0,0 +1,82 @@
+package schema_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/go-cmp/cmp"
+	schema "github.com/shogo82148/myddlmaker/testdata/gopackage"
+	"github.com/shogo82148/myddlmaker/testdata/gopackage/queries"
+)
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return nil
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	cfg.ParseTime = true
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestFakeTaskQueries(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	// the generated package works with the structs of the schema package.
+	var q queries.TaskQueries = queries.NewFakeTaskQueries()
+	if err := q.Insert(ctx, &schema.Task{Title: "write", Status: schema.StatusTodo, CreatedAt: now}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := q.Select(ctx, &schema.Task{ID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &schema.Task{ID: 1, Title: "write", Status: schema.StatusTodo, CreatedAt: now}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Select mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestQueryTask(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	now := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+	if err := queries.InsertTask(ctx, db,
+		&schema.Task{Title: "write", Status: schema.StatusDone, CreatedAt: now},
+		&schema.Task{Title: "review", Status: schema.StatusTodo, CreatedAt: now},
+	); err != nil {
+		t.Fatal(err)
+	}
+	got, err := queries.QueryTask(ctx, db, queries.TaskWhere.Status.Eq(schema.StatusTodo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*schema.Task{{ID: 2, Title: "review", Status: schema.StatusTodo, CreatedAt: now}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("QueryTask mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,23 @@
+package schema
+
+import (
+	"github.com/shogo82148/myddlmaker"
+)
+
+type Item struct {
+	ID   int64 `ddl:",auto"`
+	Name string
+}
+
+func (*Item) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
+
+type Outbox struct {
+	ID      int64 `ddl:",auto"`
+	Payload string
+}
+
+func (*Outbox) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
@@ -0,0 +1,144 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeDriver is a fake driver that records the statements, and fails with the errors before they succeed.
+type fakeDriver struct {
+	mu    sync.Mutex
+	errs  []error
+	execs []string
+}
+
+func (d *fakeDriver) Connect(ctx context.Context) (driver.Conn, error) {
+	return &fakeConn{d: d}, nil
+}
+
+func (d *fakeDriver) Driver() driver.Driver {
+	return nil
+}
+
+type fakeConn struct {
+	d *fakeDriver
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{d: c.d, query: query}, nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+type fakeStmt struct {
+	d     *fakeDriver
+	query string
+}
+
+func (s *fakeStmt) Close() error { return nil }
+
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	s.d.execs = append(s.d.execs, s.query)
+	if len(s.d.errs) > 0 {
+		err := s.d.errs[0]
+		s.d.errs = s.d.errs[1:]
+		return nil, err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, errors.New("not supported")
+}
+
+func TestHooks(t *testing.T) {
+	ctx := context.Background()
+	defer SetItemHooks(ItemHooks{})
+
+	var calls []string
+	SetItemHooks(ItemHooks{
+		BeforeUpdate: func(ctx context.Context, e Execer, values []*Item) error {
+			calls = append(calls, "BeforeUpdate")
+			return nil
+		},
+		AfterUpdate: func(ctx context.Context, e Execer, values []*Item) error {
+			calls = append(calls, "AfterUpdate")
+			// write the outbox with the same handle.
+			return InsertOutbox(ctx, e, &Outbox{Payload: values[0].Name})
+		},
+		BeforeDelete: func(ctx context.Context, e Execer, values []*Item) error {
+			calls = append(calls, "BeforeDelete")
+			return errors.New("deleting items is not allowed")
+		},
+		AfterDelete: func(ctx context.Context, e Execer, values []*Item) error {
+			calls = append(calls, "AfterDelete")
+			return nil
+		},
+	})
+
+	t.Run("update with retries", func(t *testing.T) {
+		calls = nil
+		deadlock := &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+		d := &fakeDriver{errs: []error{deadlock}}
+		db := sql.OpenDB(d)
+		defer db.Close()
+		if err := UpdateItem(ctx, db, &Item{ID: 1, Name: "renamed"}); err != nil {
+			t.Fatal(err)
+		}
+		// the hooks are called once even if the statement is retried.
+		if diff := cmp.Diff([]string{"BeforeUpdate", "AfterUpdate"}, calls); diff != "" {
+			t.Errorf("hooks mismatch (-want +got):\n%s", diff)
+		}
+		want := []string{
+			"UPDATE `item` SET `name` = ? WHERE `id` = ?",
+			"UPDATE `item` SET `name` = ? WHERE `id` = ?",
+			"INSERT INTO `outbox` (`payload`) VALUES (?)",
+		}
+		if diff := cmp.Diff(want, d.execs); diff != "" {
+			t.Errorf("statements mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("before hook fails", func(t *testing.T) {
+		calls = nil
+		d := &fakeDriver{}
+		db := sql.OpenDB(d)
+		defer db.Close()
+		if err := DeleteItem(ctx, db, &Item{ID: 1}); err == nil {
+			t.Error("want error, got nil")
+		}
+		if diff := cmp.Diff([]string{"BeforeDelete"}, calls); diff != "" {
+			t.Errorf("hooks mismatch (-want +got):\n%s", diff)
+		}
+		if len(d.execs) != 0 {
+			t.Errorf("want no statements, got %v", d.execs)
+		}
+	})
+
+	t.Run("statement fails", func(t *testing.T) {
+		calls = nil
+		duplicate := &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}
+		d := &fakeDriver{errs: []error{duplicate}}
+		db := sql.OpenDB(d)
+		defer db.Close()
+		if err := UpdateItem(ctx, db, &Item{ID: 1, Name: "renamed"}); !errors.Is(err, duplicate) {
+			t.Errorf("want the duplicate entry error, got %v", err)
+		}
+		if diff := cmp.Diff([]string{"BeforeUpdate"}, calls); diff != "" {
+			t.Errorf("hooks mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -0,0 +1,14 @@
+package schema
+
+import (
+	"github.com/shogo82148/myddlmaker"
+)
+
+type Event struct {
+	ID   int32 `ddl:",auto"`
+	Name string
+}
+
+func (*Event) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
@@ -0,0 +1,273 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return nil
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+type span struct {
+	Table     string
+	Operation string
+	Statement string
+	Err       bool
+}
+
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []span
+}
+
+func (r *recordingTracer) Start(ctx context.Context, table, operation, statement string) (context.Context, func(err error)) {
+	return ctx, func(err error) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.spans = append(r.spans, span{
+			Table:     table,
+			Operation: operation,
+			Statement: statement,
+			Err:       err != nil,
+		})
+	}
+}
+
+func TestTracer(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	tracer := &recordingTracer{}
+	SetTracer(tracer)
+	defer SetTracer(nil)
+
+	if err := InsertEvent(ctx, db, &Event{Name: "start"}); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+	if _, err := SelectEvent(ctx, db, &Event{ID: 1}); err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if err := UpdateEvent(ctx, db, &Event{ID: 1, Name: "stop"}); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	// sql.ErrNoRows is returned by Scan, the statement itself succeeds.
+	if _, err := SelectEvent(ctx, db, &Event{ID: 2}); err != sql.ErrNoRows {
+		t.Fatalf("want sql.ErrNoRows, got %v", err)
+	}
+
+	want := []span{
+		{
+			Table:     "event",
+			Operation: "InsertEvent",
+			Statement: "INSERT INTO `event` (`name`) VALUES (?)",
+		},
+		{
+			Table:     "event",
+			Operation: "SelectEvent",
+			Statement: "SELECT `id`, `name` FROM `event` WHERE `id` = ?",
+		},
+		{
+			Table:     "event",
+			Operation: "UpdateEvent",
+			Statement: "UPDATE `event` SET `name` = ? WHERE `id` = ?",
+		},
+		{
+			Table:     "event",
+			Operation: "SelectEvent",
+			Statement: "SELECT `id`, `name` FROM `event` WHERE `id` = ?",
+		},
+	}
+	if diff := cmp.Diff(want, tracer.spans); diff != "" {
+		t.Errorf("spans mismatch (-want/+got):\n%s", diff)
+	}
+}
+
+type loggedQuery struct {
+	Query string
+	Args  []any
+	Err   error
+}
+
+type recordingLogger struct {
+	mu      sync.Mutex
+	queries []loggedQuery
+}
+
+func (r *recordingLogger) LogQuery(ctx context.Context, query string, args []any, duration time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queries = append(r.queries, loggedQuery{
+		Query: query,
+		Args:  args,
+		Err:   err,
+	})
+}
+
+func TestQueryLogger(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	logger := &recordingLogger{}
+	SetQueryLogger(logger)
+	defer SetQueryLogger(nil)
+
+	id, err := InsertEventReturningID(ctx, db, &Event{Name: "start"})
+	if err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+	canceled, cancelNow := context.WithCancel(ctx)
+	cancelNow()
+	if err := InsertEvent(canceled, db, &Event{Name: "canceled"}); err == nil {
+		t.Fatal("want error, got nil")
+	}
+	if err := DeleteEventByPK(ctx, db, id); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+
+	if len(logger.queries) != 3 {
+		t.Fatalf("want 3 queries, got %d", len(logger.queries))
+	}
+	if diff := cmp.Diff([]any{"start"}, logger.queries[0].Args); diff != "" {
+		t.Errorf("args mismatch (-want/+got):\n%s", diff)
+	}
+	if logger.queries[0].Err != nil {
+		t.Errorf("want no error, got %v", logger.queries[0].Err)
+	}
+	if logger.queries[1].Err == nil {
+		t.Error("want the error of the canceled context, got nil")
+	}
+	if want := "DELETE FROM `event` WHERE `id` = ?"; logger.queries[2].Query != want {
+		t.Errorf("want %q, got %q", want, logger.queries[2].Query)
+	}
+}
+
+type countingMetrics struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (c *countingMetrics) ObserveQuery(table, operation string, duration time.Duration, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[table+"/"+operation]++
+}
+
+func TestMetrics(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	metrics := &countingMetrics{counts: map[string]int{}}
+	SetMetrics(metrics)
+	defer SetMetrics(nil)
+
+	if err := InsertEvent(ctx, db, &Event{Name: "metrics"}); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := SelectAllEvent(ctx, db); err != nil {
+			t.Fatalf("failed to select: %v", err)
+		}
+	}
+
+	want := map[string]int{
+		"event/InsertEvent":    1,
+		"event/SelectAllEvent": 2,
+	}
+	if diff := cmp.Diff(want, metrics.counts); diff != "" {
+		t.Errorf("counts mismatch (-want/+got):\n%s", diff)
+	}
+}
+
+type staticCommenter map[string]string
+
+func (c staticCommenter) Tags(ctx context.Context) map[string]string {
+	return c
+}
+
+func TestCommentQuery(t *testing.T) {
+	SetSQLCommenter(staticCommenter{
+		"route":       "/events/{id}",
+		"application": "it's */ done",
+	})
+	defer SetSQLCommenter(nil)
+
+	got := commentQuery(context.Background(), "SELECT 1")
+	want := "SELECT 1 /*application='it%27s%20%2A%2F%20done',route='%2Fevents%2F%7Bid%7D'*/"
+	if got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	SetSQLCommenter(staticCommenter{})
+	if got := commentQuery(context.Background(), "SELECT 1"); got != "SELECT 1" {
+		t.Errorf("want %q, got %q", "SELECT 1", got)
+	}
+}
+
+func TestSQLCommenter(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	SetSQLCommenter(staticCommenter{"route": "/events"})
+	defer SetSQLCommenter(nil)
+
+	id, err := InsertEventReturningID(ctx, db, &Event{Name: "commented"})
+	if err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+	if err := UpdateEvent(ctx, db, &Event{ID: id, Name: "updated"}); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	got, err := SelectEvent(ctx, db, &Event{ID: id})
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if got.Name != "updated" {
+		t.Errorf("want %q, got %q", "updated", got.Name)
+	}
+}
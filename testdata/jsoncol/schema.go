@@ -0,0 +1,25 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/shogo82148/myddlmaker"
+)
+
+type Settings struct {
+	Theme    string   `json:"theme"`
+	Language string   `json:"language"`
+	Tags     []string `json:"tags"`
+}
+
+type Profile struct {
+	ID        int64 `ddl:",auto"`
+	Name      string
+	Settings  myddlmaker.JSON[Settings]
+	Labels    myddlmaker.JSON[[]string]
+	UpdatedAt time.Time `ddl:",auto_now"`
+}
+
+func (*Profile) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
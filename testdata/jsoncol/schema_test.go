@@ -0,0 +1,114 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return nil
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestJSONError(t *testing.T) {
+	var syntaxErr *json.SyntaxError
+	err := error(&JSONError{Table: "profile", Column: "settings", Err: json.Unmarshal([]byte("{"), new(Settings))})
+	if !errors.As(err, &syntaxErr) {
+		t.Errorf("want *json.SyntaxError, got %T", errors.Unwrap(err))
+	}
+	if got, want := err.Error(), "profile.settings: unexpected end of JSON input"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestJSONAccessors(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	profile := &Profile{Name: "Alice"}
+	profile.Settings.Set(Settings{Theme: "dark", Language: "en", Tags: []string{"go"}})
+	profile.Labels.Set([]string{"admin"})
+	id, err := InsertProfileReturningID(ctx, db, profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := GetProfileSettings(ctx, db, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(profile.Settings.Get(), settings); diff != "" {
+		t.Errorf("GetProfileSettings (-want/+got):\n%s", diff)
+	}
+
+	want := Settings{Theme: "light", Language: "ja"}
+	if err := SetProfileSettings(ctx, db, id, want); err != nil {
+		t.Fatal(err)
+	}
+	settings, err = GetProfileSettings(ctx, db, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, settings); diff != "" {
+		t.Errorf("SetProfileSettings (-want/+got):\n%s", diff)
+	}
+
+	labels, err := GetProfileLabels(ctx, db, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"admin"}, labels); diff != "" {
+		t.Errorf("GetProfileLabels (-want/+got):\n%s", diff)
+	}
+
+	theme, ok, err := ExtractProfileSettings[string](ctx, db, id, "$.theme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || theme != "light" {
+		t.Errorf("want (light, true), got (%q, %t)", theme, ok)
+	}
+
+	_, ok, err = ExtractProfileSettings[string](ctx, db, id, "$.unknown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Error("want false for the unknown path, got true")
+	}
+
+	_, err = GetProfileSettings(ctx, db, id+1)
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("want sql.ErrNoRows, got %v", err)
+	}
+}
@@ -0,0 +1,14 @@
+package schema
+
+import (
+	"github.com/shogo82148/myddlmaker"
+)
+
+type Counter struct {
+	ID    int32 `ddl:",auto"`
+	Count int64
+}
+
+func (*Counter) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
@@ -0,0 +1,192 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&mysql.MySQLError{Number: 1213}, true},
+		{&mysql.MySQLError{Number: 1205}, true},
+		{fmt.Errorf("wrapped: %w", &mysql.MySQLError{Number: 1213}), true},
+		{&mysql.MySQLError{Number: 1062}, false},
+		{driver.ErrBadConn, true},
+		{mysql.ErrInvalidConn, true},
+		{sql.ErrNoRows, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := IsRetryable(tt.err); got != tt.want {
+			t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+// flakyDriver is a fake driver whose statements fail with the errors before they succeed.
+type flakyDriver struct {
+	mu    sync.Mutex
+	errs  []error
+	execs int
+
+	// hook is called with the number of the executions instead of returning errs if it is set.
+	hook func(execs int) error
+}
+
+func (d *flakyDriver) Connect(ctx context.Context) (driver.Conn, error) {
+	return &flakyConn{d: d}, nil
+}
+
+func (d *flakyDriver) Driver() driver.Driver {
+	return nil
+}
+
+type flakyConn struct {
+	d *flakyDriver
+}
+
+func (c *flakyConn) Prepare(query string) (driver.Stmt, error) {
+	return &flakyStmt{d: c.d}, nil
+}
+
+func (c *flakyConn) Close() error { return nil }
+
+func (c *flakyConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+type flakyStmt struct {
+	d *flakyDriver
+}
+
+func (s *flakyStmt) Close() error { return nil }
+
+func (s *flakyStmt) NumInput() int { return -1 }
+
+func (s *flakyStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	s.d.execs++
+	if s.d.hook != nil {
+		if err := s.d.hook(s.d.execs); err != nil {
+			return nil, err
+		}
+		return driver.RowsAffected(1), nil
+	}
+	if len(s.d.errs) > 0 {
+		err := s.d.errs[0]
+		s.d.errs = s.d.errs[1:]
+		return nil, err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *flakyStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, errors.New("not supported")
+}
+
+func TestRetry(t *testing.T) {
+	SetRetryPolicy(RetryPolicy{
+		MaxAttempts: 3,
+		Backoff: func(n int) time.Duration {
+			return time.Millisecond
+		},
+	})
+	defer SetRetryPolicy(retryPolicy)
+	ctx := context.Background()
+	deadlock := &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+
+	t.Run("success after deadlocks", func(t *testing.T) {
+		d := &flakyDriver{errs: []error{deadlock, deadlock}}
+		db := sql.OpenDB(d)
+		defer db.Close()
+		if err := UpdateCounter(ctx, db, &Counter{ID: 1, Count: 1}); err != nil {
+			t.Fatal(err)
+		}
+		if d.execs != 3 {
+			t.Errorf("want 3 attempts, got %d", d.execs)
+		}
+	})
+
+	t.Run("attempts run out", func(t *testing.T) {
+		d := &flakyDriver{errs: []error{deadlock, deadlock, deadlock, deadlock}}
+		db := sql.OpenDB(d)
+		defer db.Close()
+		if err := UpdateCounter(ctx, db, &Counter{ID: 1, Count: 1}); !errors.Is(err, deadlock) {
+			t.Errorf("want the deadlock error, got %v", err)
+		}
+		if d.execs != 3 {
+			t.Errorf("want 3 attempts, got %d", d.execs)
+		}
+	})
+
+	t.Run("not retryable", func(t *testing.T) {
+		duplicate := &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}
+		d := &flakyDriver{errs: []error{duplicate}}
+		db := sql.OpenDB(d)
+		defer db.Close()
+		if err := DeleteCounterByPK(ctx, db, 1); !errors.Is(err, duplicate) {
+			t.Errorf("want the duplicate entry error, got %v", err)
+		}
+		if d.execs != 1 {
+			t.Errorf("want 1 attempt, got %d", d.execs)
+		}
+	})
+
+	t.Run("each statement is retried", func(t *testing.T) {
+		// the statement of the second value deadlocks once.
+		d := &flakyDriver{hook: func(execs int) error {
+			if execs == 2 {
+				return deadlock
+			}
+			return nil
+		}}
+		db := sql.OpenDB(d)
+		defer db.Close()
+		if err := UpdateCounter(ctx, db, &Counter{ID: 1, Count: 1}, &Counter{ID: 2, Count: 2}); err != nil {
+			t.Fatal(err)
+		}
+		// the first statement is not executed again.
+		if d.execs != 3 {
+			t.Errorf("want 3 executions, got %d", d.execs)
+		}
+	})
+
+	t.Run("the invalid connections are not retried", func(t *testing.T) {
+		d := &flakyDriver{errs: []error{mysql.ErrInvalidConn}}
+		db := sql.OpenDB(d)
+		defer db.Close()
+		if err := UpdateCounter(ctx, db, &Counter{ID: 1, Count: 1}); !errors.Is(err, mysql.ErrInvalidConn) {
+			t.Errorf("want the invalid connection error, got %v", err)
+		}
+		if d.execs != 1 {
+			t.Errorf("want 1 attempt, got %d", d.execs)
+		}
+	})
+
+	t.Run("connections are not retried", func(t *testing.T) {
+		d := &flakyDriver{errs: []error{deadlock}}
+		db := sql.OpenDB(d)
+		defer db.Close()
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		if err := UpdateCounter(ctx, conn, &Counter{ID: 1, Count: 1}); !errors.Is(err, deadlock) {
+			t.Errorf("want the deadlock error, got %v", err)
+		}
+		if d.execs != 1 {
+			t.Errorf("want 1 attempt, got %d", d.execs)
+		}
+	})
+}
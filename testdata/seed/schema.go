@@ -0,0 +1,46 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/shogo82148/myddlmaker"
+)
+
+type User struct {
+	ID        uint64 `ddl:",auto"`
+	Name      string `ddl:",size=32"`
+	CreatedAt time.Time
+}
+
+func (*User) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
+
+func (*User) UniqueIndexes() []*myddlmaker.UniqueIndex {
+	return []*myddlmaker.UniqueIndex{
+		myddlmaker.NewUniqueIndex("uniq_name", "name"),
+	}
+}
+
+// Post refers to User, that is added after Post.
+type Post struct {
+	ID     uint64 `ddl:",auto"`
+	UserID uint64
+	Title  string
+}
+
+func (*Post) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
+
+func (*Post) Indexes() []*myddlmaker.Index {
+	return []*myddlmaker.Index{
+		myddlmaker.NewIndex("idx_user_id", "user_id"),
+	}
+}
+
+func (*Post) ForeignKeys() []*myddlmaker.ForeignKey {
+	return []*myddlmaker.ForeignKey{
+		myddlmaker.NewForeignKey("fk_post_user", []string{"user_id"}, "user", []string{"id"}),
+	}
+}
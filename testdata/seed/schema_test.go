@@ -0,0 +1,81 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return nil
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestSeed_NoRows(t *testing.T) {
+	// nothing is inserted.
+	if err := Seed(context.Background(), nil, &Seeds{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSeed(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+	if _, err := db.ExecContext(ctx, "DELETE FROM `post`"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ExecContext(ctx, "DELETE FROM `user`"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the posts can't be seeded without the users.
+	if err := Seed(ctx, db, &Seeds{Post: PostSeed{Rows: 1}}); err == nil {
+		t.Error("want an error, got nil")
+	}
+
+	err := Seed(ctx, db, &Seeds{
+		Post: PostSeed{Rows: 2500},
+		User: UserSeed{
+			Rows: 30,
+			Name: func(i int) string { return "user" + strconv.Itoa(i) },
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var users, posts, authors int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM `user` WHERE `name` LIKE 'user%'").Scan(&users); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*), COUNT(DISTINCT `user_id`) FROM `post`").Scan(&posts, &authors); err != nil {
+		t.Fatal(err)
+	}
+	if users != 30 || posts != 2500 || authors != 30 {
+		t.Errorf("unexpected counts: users = %d, posts = %d, authors = %d", users, posts, authors)
+	}
+}
@@ -0,0 +1,24 @@
+package schema
+
+import (
+	"database/sql"
+
+	"github.com/shogo82148/myddlmaker"
+)
+
+type Article struct {
+	ID        int32 `ddl:",auto"`
+	AuthorID  int32
+	Title     string
+	DeletedAt sql.NullTime `ddl:",null,size=6,softdelete"`
+}
+
+func (*Article) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
+
+func (*Article) Indexes() []*myddlmaker.Index {
+	return []*myddlmaker.Index{
+		myddlmaker.NewIndex("idx_author_id", "author_id"),
+	}
+}
@@ -0,0 +1,150 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return nil
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestSoftDelete(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	if err := InsertArticle(ctx, db,
+		&Article{ID: 1, AuthorID: 10, Title: "hello"},
+		&Article{ID: 2, AuthorID: 10, Title: "world"},
+	); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+
+	if err := DeleteArticle(ctx, db, &Article{ID: 1}); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	// the row is already deleted.
+	if err := DeleteArticleByPK(ctx, db, 1); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("want sql.ErrNoRows, got %v", err)
+	}
+
+	// the soft-deleted rows are filtered out.
+	if _, err := SelectArticle(ctx, db, &Article{ID: 1}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("want sql.ErrNoRows, got %v", err)
+	}
+	articles, err := SelectAllArticleByAuthorID(ctx, db, 10)
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if len(articles) != 1 || articles[0].ID != 2 {
+		t.Errorf("unexpected articles: %v", articles)
+	}
+	n, err := CountArticleByAuthorID(ctx, db, 10)
+	if err != nil {
+		t.Fatalf("failed to count: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("unexpected count: want 1, got %d", n)
+	}
+
+	// the with-deleted variants return them.
+	a, err := SelectArticleWithDeleted(ctx, db, &Article{ID: 1})
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if !a.DeletedAt.Valid {
+		t.Errorf("want deleted_at is set, got NULL")
+	}
+	articles, err = SelectAllArticleWithDeleted(ctx, db)
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if len(articles) != 2 {
+		t.Errorf("unexpected articles: %v", articles)
+	}
+
+	// hard delete removes the rows.
+	if err := HardDeleteArticle(ctx, db, &Article{ID: 1}); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	if err := HardDeleteArticleByPK(ctx, db, 2); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	articles, err = SelectAllArticleWithDeleted(ctx, db)
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if len(articles) != 0 {
+		t.Errorf("unexpected articles: %v", articles)
+	}
+}
+
+func TestSoftDeleteByIndexes(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	if err := InsertArticle(ctx, db,
+		&Article{ID: 11, AuthorID: 20, Title: "hello"},
+		&Article{ID: 12, AuthorID: 20, Title: "world"},
+	); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+
+	n, err := DeleteArticleByAuthorID(ctx, db, 20)
+	if err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("unexpected deleted rows: want 2, got %d", n)
+	}
+	// the rows are already soft-deleted.
+	n, err = DeleteArticleByAuthorID(ctx, db, 20)
+	if err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("unexpected deleted rows: want 0, got %d", n)
+	}
+
+	n, err = HardDeleteArticleByAuthorID(ctx, db, 20)
+	if err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("unexpected deleted rows: want 2, got %d", n)
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"log"
+
+	"github.com/shogo82148/myddlmaker"
+	schema "github.com/shogo82148/myddlmaker/testdata/split"
+)
+
+func main() {
+	m, err := myddlmaker.New(&myddlmaker.Config{
+		SplitGoFiles:      true,
+		GoBuildConstraint: "go1.18",
+		GenerateRetry:     true,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	m.AddStructs(&schema.User{}, &schema.Post{})
+
+	if err := m.GenerateFile(); err != nil {
+		log.Fatal(err)
+	}
+	if err := m.GenerateGoFile(); err != nil {
+		log.Fatal(err)
+	}
+}
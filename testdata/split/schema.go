@@ -0,0 +1,27 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/shogo82148/myddlmaker"
+)
+
+type User struct {
+	ID        int64 `ddl:",auto"`
+	Name      string
+	CreatedAt time.Time
+}
+
+func (*User) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
+
+type Post struct {
+	ID     int64 `ddl:",auto"`
+	UserID int64
+	Body   string
+}
+
+func (*Post) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
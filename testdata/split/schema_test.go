@@ -0,0 +1,91 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return nil
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	cfg.ParseTime = true
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestSplitFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		imports []string
+	}{
+		{"schema_gen.go", []string{`"database/sql/driver"`, `"github.com/go-sql-driver/mysql"`, `"time"`}},
+		{"user_gen.go", []string{`"time"`}},
+		{"post_gen.go", nil},
+	}
+	for _, tt := range tests {
+		data, err := os.ReadFile(tt.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		src := string(data)
+		if !strings.Contains(src, "//go:build !myddlmaker && go1.18\n") {
+			t.Errorf("%s: the build constraint is not found", tt.name)
+		}
+		for _, imp := range []string{`"database/sql/driver"`, `"github.com/go-sql-driver/mysql"`, `"time"`} {
+			want := false
+			for _, s := range tt.imports {
+				want = want || s == imp
+			}
+			if got := strings.Contains(src, imp); got != want {
+				t.Errorf("%s: import %s: got %v, want %v", tt.name, imp, got, want)
+			}
+		}
+	}
+}
+
+func TestInsertUser(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	now := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+	if err := InsertUser(ctx, db, &User{Name: "alice", CreatedAt: now}); err != nil {
+		t.Fatal(err)
+	}
+	if err := InsertPost(ctx, db, &Post{UserID: 1, Body: "hello"}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := SelectUser(ctx, db, &User{ID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&User{ID: 1, Name: "alice", CreatedAt: now}, got); diff != "" {
+		t.Errorf("SelectUser mismatch (-want +got):\n%s", diff)
+	}
+}
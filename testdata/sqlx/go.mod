@@ -0,0 +1,13 @@
+module github.com/shogo82148/myddlmaker/testdata/sqlx
+
+go 1.18
+
+require (
+	github.com/go-sql-driver/mysql v1.8.1
+	github.com/jmoiron/sqlx v1.4.0
+	github.com/shogo82148/myddlmaker v0.0.0
+)
+
+require filippo.io/edwards25519 v1.1.0 // indirect
+
+replace github.com/shogo82148/myddlmaker => ../..
@@ -0,0 +1,23 @@
+package schema
+
+import (
+	"github.com/shogo82148/myddlmaker"
+)
+
+type User struct {
+	ID   uint64 `db:"id" ddl:",auto"`
+	Name string `db:"name"`
+}
+
+func (*User) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
+
+type Tag struct {
+	UserID uint64 `db:"user_id"`
+	Name   string `db:"name"`
+}
+
+func (*Tag) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("user_id", "name")
+}
@@ -0,0 +1,79 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+)
+
+func TestUser(t *testing.T) {
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	db, err := sqlx.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	// the named query for the slice is expanded into a bulk insert.
+	if err := InsertUser(ctx, db, &User{Name: "Alice"}, &User{Name: "Bob"}); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+	users, err := SelectAllUser(ctx, db)
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if len(users) != 2 || users[0].Name != "Alice" || users[1].Name != "Bob" {
+		t.Fatalf("unexpected users: %v", users)
+	}
+
+	users[0].Name = "Carol"
+	if err := UpdateUser(ctx, db, users[0]); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	got, err := SelectUser(ctx, db, &User{ID: users[0].ID})
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if got.Name != "Carol" {
+		t.Errorf("unexpected name: want %q, got %q", "Carol", got.Name)
+	}
+
+	// the transactions can be used too.
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to begin: %v", err)
+	}
+	defer tx.Rollback()
+	if err := InsertTag(ctx, tx, &Tag{UserID: got.ID, Name: "admin"}); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+	if err := DeleteTag(ctx, tx, &Tag{UserID: got.ID, Name: "admin"}); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	if err := DeleteTag(ctx, tx, &Tag{UserID: got.ID, Name: "admin"}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("want sql.ErrNoRows, got %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"log"
+
+	"github.com/shogo82148/myddlmaker"
+	schema "github.com/shogo82148/myddlmaker/testdata/stmtcache"
+)
+
+func main() {
+	m, err := myddlmaker.New(&myddlmaker.Config{
+		CachePreparedStatements:    true,
+		PreparedStatementCacheSize: 2,
+		GenerateDBPair:             true,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	m.AddStructs(&schema.Item{})
+
+	if err := m.GenerateFile(); err != nil {
+		log.Fatal(err)
+	}
+	if err := m.GenerateGoFile(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,14 @@
+package schema
+
+import (
+	"github.com/shogo82148/myddlmaker"
+)
+
+type Item struct {
+	ID   int32
+	Name string
+}
+
+func (*Item) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
@@ -0,0 +1,269 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return nil
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// countingDriver is a fake driver that counts the prepared and closed statements.
+type countingDriver struct {
+	mu       sync.Mutex
+	prepared map[string]int
+	closed   map[string]int
+}
+
+func (d *countingDriver) Open(name string) (driver.Conn, error) {
+	return &countingConn{d: d}, nil
+}
+
+type countingConn struct {
+	d *countingDriver
+}
+
+func (c *countingConn) Prepare(query string) (driver.Stmt, error) {
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	c.d.prepared[query]++
+	return &countingStmt{d: c.d, query: query}, nil
+}
+
+func (c *countingConn) Close() error { return nil }
+
+func (c *countingConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+type countingStmt struct {
+	d     *countingDriver
+	query string
+}
+
+func (s *countingStmt) Close() error {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	s.d.closed[s.query]++
+	return nil
+}
+
+func (s *countingStmt) NumInput() int { return -1 }
+
+func (s *countingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+
+func (s *countingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &itemRows{}, nil
+}
+
+// itemRows returns an item.
+type itemRows struct {
+	done bool
+}
+
+func (r *itemRows) Columns() []string { return []string{"id", "name"} }
+
+func (r *itemRows) Close() error { return nil }
+
+func (r *itemRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = int64(1)
+	dest[1] = "foo"
+	return nil
+}
+
+func TestPreparedStatementCache(t *testing.T) {
+	db, d := newCountingDB()
+	defer db.Close()
+	ctx := context.Background()
+
+	const (
+		update = "UPDATE `item` SET `name` = ? WHERE `id` = ?"
+		del    = "DELETE FROM `item` WHERE `id` = ?"
+		upsert = "INSERT INTO `item` (`id`, `name`) VALUES (?, ?) AS `new` ON DUPLICATE KEY UPDATE `name` = `new`.`name`"
+	)
+
+	// the statement is prepared once.
+	for i := 0; i < 3; i++ {
+		if err := UpdateItem(ctx, db, &Item{ID: 1, Name: "foo"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := d.prepared[update]; got != 1 {
+		t.Errorf("want %q to be prepared once, got %d", update, got)
+	}
+	if got := d.closed[update]; got != 0 {
+		t.Errorf("want %q not to be closed, got %d", update, got)
+	}
+
+	// the cache has two statements, so the least recently used one is closed.
+	if err := DeleteItemByPK(ctx, db, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := UpsertItem(ctx, db, &Item{ID: 1, Name: "foo"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := d.closed[update]; got != 1 {
+		t.Errorf("want %q to be closed, got %d", update, got)
+	}
+
+	if err := ClosePreparedStatements(db); err != nil {
+		t.Fatal(err)
+	}
+	if d.closed[del] != 1 || d.closed[upsert] != 1 {
+		t.Errorf("want all the statements to be closed, got %v", d.closed)
+	}
+}
+
+func newCountingDB() (*sql.DB, *countingDriver) {
+	d := &countingDriver{
+		prepared: map[string]int{},
+		closed:   map[string]int{},
+	}
+	return sql.OpenDB(connector{d}), d
+}
+
+func TestPreparedStatementCache_Select(t *testing.T) {
+	db, d := newCountingDB()
+	defer db.Close()
+	ctx := context.Background()
+
+	const (
+		sel    = "SELECT `id`, `name` FROM `item` WHERE `id` = ?"
+		insert = "INSERT INTO `item` (`id`, `name`) VALUES (?, ?)"
+	)
+
+	// the statements are prepared once.
+	for i := 0; i < 3; i++ {
+		item, err := SelectItem(ctx, db, &Item{ID: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if item.ID != 1 || item.Name != "foo" {
+			t.Errorf("unexpected item: %v", item)
+		}
+		if err := InsertItem(ctx, db, &Item{ID: 1, Name: "foo"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := d.prepared[sel]; got != 1 {
+		t.Errorf("want %q to be prepared once, got %d", sel, got)
+	}
+	if got := d.prepared[insert]; got != 1 {
+		t.Errorf("want %q to be prepared once, got %d", insert, got)
+	}
+
+	if err := ClosePreparedStatements(db); err != nil {
+		t.Fatal(err)
+	}
+	if d.closed[sel] != 1 || d.closed[insert] != 1 {
+		t.Errorf("want all the statements to be closed, got %v", d.closed)
+	}
+}
+
+func TestPreparedStatementCache_DBPair(t *testing.T) {
+	writer, wd := newCountingDB()
+	defer writer.Close()
+	reader, rd := newCountingDB()
+	defer reader.Close()
+	defer ClosePreparedStatements(writer)
+	defer ClosePreparedStatements(reader)
+	db := DBPair{Writer: writer, Reader: reader}
+	ctx := context.Background()
+
+	const sel = "SELECT `id`, `name` FROM `item` WHERE `id` = ?"
+
+	// the queries are prepared on the reader.
+	for i := 0; i < 3; i++ {
+		if _, err := SelectItem(ctx, db, &Item{ID: 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := rd.prepared[sel]; got != 1 {
+		t.Errorf("want %q to be prepared once on the reader, got %d", sel, got)
+	}
+	if got := wd.prepared[sel]; got != 0 {
+		t.Errorf("want %q not to be prepared on the writer, got %d", sel, got)
+	}
+
+	// the writer is used within UseWriter.
+	if _, err := SelectItem(UseWriter(ctx), db, &Item{ID: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if got := wd.prepared[sel]; got != 1 {
+		t.Errorf("want %q to be prepared once on the writer, got %d", sel, got)
+	}
+}
+
+type connector struct {
+	d *countingDriver
+}
+
+func (c connector) Connect(ctx context.Context) (driver.Conn, error) {
+	return c.d.Open("")
+}
+
+func (c connector) Driver() driver.Driver {
+	return c.d
+}
+
+func TestCachedStatements(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+	defer ClosePreparedStatements(db)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	for i := int32(1); i <= 3; i++ {
+		if err := UpsertItem(ctx, db, &Item{ID: i, Name: "foo"}); err != nil {
+			t.Fatalf("failed to upsert: %v", err)
+		}
+		if err := UpdateItem(ctx, db, &Item{ID: i, Name: "bar"}); err != nil {
+			t.Fatalf("failed to update: %v", err)
+		}
+	}
+	if err := DeleteItemByPK(ctx, db, 1, 2); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	items, err := SelectAllItem(ctx, db)
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if len(items) != 1 || items[0].ID != 3 || items[0].Name != "bar" {
+		t.Errorf("unexpected items: %v", items)
+	}
+}
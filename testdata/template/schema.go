@@ -0,0 +1,23 @@
+package schema
+
+import (
+	"github.com/shogo82148/myddlmaker"
+)
+
+type Note struct {
+	UserID int64
+	Seq    int32
+	Body   string
+}
+
+func (*Note) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("user_id", "seq")
+}
+
+type Tag struct {
+	Name string `ddl:",size=64"`
+}
+
+func (*Tag) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("name")
+}
@@ -0,0 +1,69 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return nil
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestTableNames(t *testing.T) {
+	want := []string{"note", "tag"}
+	if diff := cmp.Diff(want, TableNames); diff != "" {
+		t.Errorf("TableNames mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestStore(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	notes := NewNoteStore(db)
+	if err := notes.Create(ctx, &Note{UserID: 1, Seq: 1, Body: "hello"}, &Note{UserID: 1, Seq: 2, Body: "world"}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := notes.Find(ctx, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&Note{UserID: 1, Seq: 2, Body: "world"}, got); diff != "" {
+		t.Errorf("Find mismatch (-want +got):\n%s", diff)
+	}
+
+	tags := NewTagStore(db)
+	if _, err := tags.Find(ctx, "go"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("want sql.ErrNoRows, got %v", err)
+	}
+}
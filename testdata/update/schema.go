@@ -5,8 +5,9 @@ import (
 )
 
 type User struct {
-	ID   int32 `ddl:",auto"`
-	Name string
+	ID    int32 `ddl:",auto"`
+	Name  string
+	Email string
 }
 
 func (*User) PrimaryKey() *myddlmaker.PrimaryKey {
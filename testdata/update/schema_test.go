@@ -3,6 +3,7 @@ package schema
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"os"
 	"testing"
 	"time"
@@ -72,3 +73,198 @@ func TestFoo1(t *testing.T) {
 		t.Errorf("unexpected name: want CHOOBLARIN, got %s", u.Name)
 	}
 }
+
+func TestDelete(t *testing.T) {
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	if err := InsertUser(ctx, db, &User{Name: "alice"}, &User{Name: "bob"}); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+	all, err := SelectAllUser(ctx, db)
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if len(all) < 2 {
+		t.Fatalf("unexpected users: %v", all)
+	}
+	alice, bob := all[len(all)-2], all[len(all)-1]
+
+	if err := DeleteUser(ctx, db, alice); err != nil {
+		t.Errorf("failed to delete: %v", err)
+	}
+	if err := DeleteUserByPK(ctx, db, bob.ID); err != nil {
+		t.Errorf("failed to delete: %v", err)
+	}
+
+	// the rows are already deleted.
+	if err := DeleteUser(ctx, db, alice); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("want sql.ErrNoRows, got %v", err)
+	}
+	if err := DeleteUserByPK(ctx, db, bob.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("want sql.ErrNoRows, got %v", err)
+	}
+}
+
+func TestUpdateColumns(t *testing.T) {
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	if err := InsertUser(ctx, db, &User{ID: 100, Name: "carol", Email: "carol@example.com"}); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+
+	// update only the email, and keep the name.
+	email := "carol@example.net"
+	if err := UpdateUserColumns(ctx, db, 100, UserChanges{Email: &email}); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	// no changes.
+	if err := UpdateUserColumns(ctx, db, 100, UserChanges{}); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+
+	u, err := SelectUser(ctx, db, &User{ID: 100})
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if u.Name != "carol" {
+		t.Errorf("unexpected name: want carol, got %s", u.Name)
+	}
+	if u.Email != "carol@example.net" {
+		t.Errorf("unexpected email: want carol@example.net, got %s", u.Email)
+	}
+}
+
+func TestInsertReturningID(t *testing.T) {
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	u := &User{Name: "dave"}
+	id, err := InsertUserReturningID(ctx, db, u)
+	if err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+	if id == 0 || u.ID != id {
+		t.Errorf("unexpected id: returned %d, set %d", id, u.ID)
+	}
+
+	got, err := SelectUser(ctx, db, &User{ID: id})
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if got.Name != "dave" {
+		t.Errorf("unexpected name: want dave, got %s", got.Name)
+	}
+}
+
+func TestNames(t *testing.T) {
+	if UserTable != "user" {
+		t.Errorf("unexpected table name: %s", UserTable)
+	}
+	if UserColumns.ID != "id" || UserColumns.Name != "name" || UserColumns.Email != "email" {
+		t.Errorf("unexpected column names: %v", UserColumns)
+	}
+}
+
+func TestTransaction(t *testing.T) {
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("failed to get a connection: %v", err)
+	}
+	defer conn.Close()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to begin: %v", err)
+	}
+	if err := InsertUser(ctx, tx, &User{ID: 200, Name: "rollback"}); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+	if _, err := SelectUser(ctx, tx, &User{ID: 200}); err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("failed to rollback: %v", err)
+	}
+
+	// the row is rolled back.
+	if _, err := SelectUser(ctx, conn, &User{ID: 200}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("want sql.ErrNoRows, got %v", err)
+	}
+}
@@ -0,0 +1,15 @@
+package schema
+
+import (
+	"github.com/shogo82148/myddlmaker"
+)
+
+type Item struct {
+	ID      int32
+	Name    string
+	Creator string `ddl:",noupsert"`
+}
+
+func (*Item) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
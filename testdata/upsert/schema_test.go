@@ -0,0 +1,202 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestUpsert(t *testing.T) {
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	// insert a new row
+	if err := UpsertItem(ctx, db, &Item{ID: 1, Name: "apple", Creator: "alice"}); err != nil {
+		t.Errorf("failed to upsert: %v", err)
+	}
+
+	// update the existing row, except for the creator
+	if err := UpsertItem(ctx, db, &Item{ID: 1, Name: "banana", Creator: "bob"}); err != nil {
+		t.Errorf("failed to upsert: %v", err)
+	}
+
+	item, err := SelectItem(ctx, db, &Item{ID: 1})
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if item.Name != "banana" {
+		t.Errorf("unexpected name: want banana, got %s", item.Name)
+	}
+	if item.Creator != "alice" {
+		t.Errorf("unexpected creator: want alice, got %s", item.Creator)
+	}
+}
+
+func TestBulkUpsert(t *testing.T) {
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	// more rows than a chunk, so they are upserted by multiple statements.
+	items := make([]*Item, 0, 40)
+	for i := 0; i < 40; i++ {
+		items = append(items, &Item{ID: int32(100 + i), Name: "apple", Creator: "alice"})
+	}
+	if err := BulkUpsertItem(ctx, db, items...); err != nil {
+		t.Fatalf("failed to bulk upsert: %v", err)
+	}
+
+	// update the existing rows except for the creator, and insert a new row.
+	for _, item := range items {
+		item.Name = "banana"
+		item.Creator = "bob"
+	}
+	items = append(items, &Item{ID: 140, Name: "cherry", Creator: "carol"})
+	if err := BulkUpsertItem(ctx, db, items...); err != nil {
+		t.Fatalf("failed to bulk upsert: %v", err)
+	}
+
+	for _, want := range []*Item{
+		{ID: 100, Name: "banana", Creator: "alice"},
+		{ID: 139, Name: "banana", Creator: "alice"},
+		{ID: 140, Name: "cherry", Creator: "carol"},
+	} {
+		got, err := SelectItem(ctx, db, &Item{ID: want.ID})
+		if err != nil {
+			t.Fatalf("failed to select: %v", err)
+		}
+		if *got != *want {
+			t.Errorf("unexpected item: want %+v, got %+v", want, got)
+		}
+	}
+}
+
+func TestInsertIgnore(t *testing.T) {
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	inserted, err := InsertIgnoreItem(ctx, db, &Item{ID: 2, Name: "cherry", Creator: "alice"})
+	if err != nil {
+		t.Errorf("failed to insert: %v", err)
+	}
+	if !inserted {
+		t.Error("want inserted, but not")
+	}
+
+	// the row already exists.
+	inserted, err = InsertIgnoreItem(ctx, db, &Item{ID: 2, Name: "durian", Creator: "bob"})
+	if err != nil {
+		t.Errorf("failed to insert: %v", err)
+	}
+	if inserted {
+		t.Error("want ignored, but inserted")
+	}
+
+	item, err := SelectItem(ctx, db, &Item{ID: 2})
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if item.Name != "cherry" {
+		t.Errorf("unexpected name: want cherry, got %s", item.Name)
+	}
+}
+
+func TestReplace(t *testing.T) {
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	if err := ReplaceItem(ctx, db, &Item{ID: 3, Name: "elderberry", Creator: "alice"}); err != nil {
+		t.Errorf("failed to replace: %v", err)
+	}
+
+	// the whole row is replaced, including the creator.
+	if err := ReplaceItem(ctx, db, &Item{ID: 3, Name: "fig", Creator: "bob"}); err != nil {
+		t.Errorf("failed to replace: %v", err)
+	}
+
+	item, err := SelectItem(ctx, db, &Item{ID: 3})
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if item.Name != "fig" {
+		t.Errorf("unexpected name: want fig, got %s", item.Name)
+	}
+	if item.Creator != "bob" {
+		t.Errorf("unexpected creator: want bob, got %s", item.Creator)
+	}
+}
@@ -0,0 +1,27 @@
+package schema
+
+import (
+	"database/sql"
+
+	"github.com/shogo82148/myddlmaker"
+)
+
+type Nickname string
+
+type Profile struct {
+	ID       int64    `ddl:",auto"`
+	Name     string   `ddl:",size=8"`
+	Nickname Nickname `ddl:",size=4"`
+	Bio      *string  `ddl:",type=TINYTEXT"`
+	Email    sql.NullString
+	Website  *string `ddl:",null,size=16"`
+	Status   string  `ddl:",type=ENUM('active','deleted')"`
+	Tags     string  `ddl:",type=SET('go','mysql')"`
+	Age      int16   `ddl:",type=TINYINT,unsigned"`
+	Score    int32   `ddl:",type=SMALLINT"`
+	Avatar   []byte  `ddl:",size=4"`
+}
+
+func (*Profile) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
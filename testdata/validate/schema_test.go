@@ -0,0 +1,66 @@
+package schema
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValidate(t *testing.T) {
+	bio := "hello"
+	valid := Profile{
+		Name:     "アリス",
+		Nickname: "ali",
+		Bio:      &bio,
+		Email:    sql.NullString{String: "alice@example.com", Valid: true},
+		Status:   "active",
+		Tags:     "go,mysql",
+		Age:      255,
+		Score:    -32768,
+		Avatar:   []byte{1, 2, 3, 4},
+	}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("want no error, got %v", err)
+	}
+
+	website := "https://example.com/alice"
+	invalid := Profile{
+		Name:     "Alice Liddell",
+		Nickname: "alice",
+		Website:  &website,
+		Status:   "unknown",
+		Tags:     "go,rust",
+		Age:      -1,
+		Score:    32768,
+		Avatar:   []byte{1, 2, 3, 4, 5},
+	}
+	err := invalid.Validate()
+	var errs ValidationError
+	if !errors.As(err, &errs) {
+		t.Fatalf("want ValidationError, got %v", err)
+	}
+	var got []string
+	for _, e := range errs {
+		got = append(got, e.Field+": "+e.Reason)
+	}
+	want := []string{
+		"Name: must be at most 8 characters",
+		"Nickname: must be at most 4 characters",
+		"Bio: must not be NULL",
+		"Email: must not be NULL",
+		"Website: must be at most 16 characters",
+		"Status: must be one of 'active', 'deleted'",
+		"Tags: must be a comma-separated list of 'go', 'mysql'",
+		"Age: must be between 0 and 255",
+		"Score: must be between -32768 and 32767",
+		"Avatar: must be at most 4 bytes",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("errors mismatch (-want +got):\n%s", diff)
+	}
+	if got, want := errs[0].Error(), "profile.name: must be at most 8 characters"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
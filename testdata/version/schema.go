@@ -0,0 +1,15 @@
+package schema
+
+import (
+	"github.com/shogo82148/myddlmaker"
+)
+
+type Document struct {
+	ID      int32 `ddl:",auto"`
+	Title   string
+	Version int32 `ddl:",version"`
+}
+
+func (*Document) PrimaryKey() *myddlmaker.PrimaryKey {
+	return myddlmaker.NewPrimaryKey("id")
+}
@@ -0,0 +1,92 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	user := os.Getenv("MYSQL_TEST_USER")
+	pass := os.Getenv("MYSQL_TEST_PASS")
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	name := os.Getenv("MYSQL_TEST_DB")
+	if name == "" {
+		return nil
+	}
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Addr = addr
+	cfg.DBName = name
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestOptimisticLocking(t *testing.T) {
+	db := openDB(t)
+	if db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	if err := InsertDocument(ctx, db, &Document{ID: 1, Title: "draft"}); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+
+	doc1, err := SelectDocument(ctx, db, &Document{ID: 1})
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	doc2, err := SelectDocument(ctx, db, &Document{ID: 1})
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+
+	doc1.Title = "first"
+	if err := UpdateDocument(ctx, db, doc1); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	if doc1.Version != 1 {
+		t.Errorf("unexpected version: want 1, got %d", doc1.Version)
+	}
+
+	// doc2 is stale.
+	doc2.Title = "second"
+	var conflict *ConflictError
+	if err := UpdateDocument(ctx, db, doc2); !errors.As(err, &conflict) {
+		t.Fatalf("want ConflictError, got %v", err)
+	}
+	if conflict.Table != "document" {
+		t.Errorf("unexpected table: want document, got %s", conflict.Table)
+	}
+
+	// the partial update increments the version too.
+	title := "third"
+	if err := UpdateDocumentColumns(ctx, db, 1, DocumentChanges{Title: &title}); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	if err := UpdateDocument(ctx, db, doc1); !errors.As(err, &conflict) {
+		t.Fatalf("want ConflictError, got %v", err)
+	}
+
+	doc, err := SelectDocument(ctx, db, &Document{ID: 1})
+	if err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if doc.Title != "third" || doc.Version != 2 {
+		t.Errorf("unexpected document: %v", doc)
+	}
+}
@@ -0,0 +1,298 @@
+package myddlmaker
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// textBytes is the maximum length in bytes of the TEXT and BLOB types.
+var textBytes = map[string]int{
+	"TINYTEXT":   1<<8 - 1,
+	"TEXT":       1<<16 - 1,
+	"MEDIUMTEXT": 1<<24 - 1,
+	"TINYBLOB":   1<<8 - 1,
+	"BLOB":       1<<16 - 1,
+	"MEDIUMBLOB": 1<<24 - 1,
+}
+
+// integerBits is the number of bits of the integer types.
+var integerBits = map[string]int{
+	"TINYINT":   8,
+	"SMALLINT":  16,
+	"MEDIUMINT": 24,
+	"INT":       32,
+	"INTEGER":   32,
+	"BIGINT":    64,
+}
+
+// validation is the validation of a column in the generated Validate method.
+type validation struct {
+	// cond is the condition that the value violates the constraint.
+	cond string
+
+	// reason is the description of the constraint.
+	reason string
+}
+
+// hasValidation reports whether the Go source code has the Validate methods.
+func (m *Maker) hasValidation() bool {
+	return m.config.GenerateValidation && len(m.tables) > 0
+}
+
+// validationImports returns the packages that the Validate methods refer to.
+func (m *Maker) validationImports() map[string]bool {
+	imports := map[string]bool{}
+	if !m.hasValidation() {
+		return imports
+	}
+	for _, table := range m.tables {
+		for _, c := range table.columns {
+			for _, v := range c.validations() {
+				if strings.Contains(v.cond, "utf8.") {
+					imports["unicode/utf8"] = true
+				}
+				if strings.Contains(v.cond, "strings.") {
+					imports["strings"] = true
+				}
+			}
+		}
+	}
+	return imports
+}
+
+// generateGoValidationErrors generates the errors that the Validate methods return.
+func (m *Maker) generateGoValidationErrors(w io.Writer) {
+	fmt.Fprintf(w, `// FieldError is the error of the field that violates the constraint of its column.
+	type FieldError struct {
+		Table  string
+		Column string
+		Field  string
+		Reason string
+	}
+
+	func (e *FieldError) Error() string {
+		return e.Table + "." + e.Column + ": " + e.Reason
+	}
+
+	// ValidationError is the errors of the fields that the Validate methods return.
+	type ValidationError []*FieldError
+
+	func (e ValidationError) Error() string {
+		msg := e[0].Error()
+		for _, err := range e[1:] {
+			msg += "; " + err.Error()
+		}
+		return msg
+	}
+
+	`)
+}
+
+// generateGoTableValidate generates the Validate method of the table.
+func (m *Maker) generateGoTableValidate(w io.Writer, table *table) {
+	fmt.Fprintf(w, "// Validate checks that the fields of v satisfy the constraints of the columns of %s,\n", table.fullName())
+	fmt.Fprintf(w, "// i.e. NOT NULL, the sizes of the strings, the members of ENUM and SET, and the ranges of the integers.\n")
+	fmt.Fprintf(w, "// It returns ValidationError if some fields violate them.\n")
+	fmt.Fprintf(w, "func (v *%s) Validate() error {\n", table.rawName)
+	fmt.Fprintf(w, "var errs ValidationError\n")
+	for _, c := range table.columns {
+		for _, v := range c.validations() {
+			fmt.Fprintf(w, "if %s {\n", v.cond)
+			fmt.Fprintf(w, "errs = append(errs, &FieldError{Table: %q, Column: %q, Field: %q, Reason: %q})\n", table.fullName(), c.name, c.rawName, v.reason)
+			fmt.Fprintf(w, "}\n")
+		}
+	}
+	fmt.Fprintf(w, "if len(errs) > 0 {\n return errs \n}\n")
+	fmt.Fprintf(w, "return nil\n")
+	fmt.Fprintf(w, "}\n\n")
+}
+
+// validations returns the validations of the column.
+func (c *column) validations() []validation {
+	if c.fieldType == nil {
+		return nil
+	}
+
+	// expr is the expression of the value, and guard is the condition that the value is not NULL.
+	expr, guard, isNull := "v."+c.goField, "", ""
+	typ := c.fieldType
+	switch {
+	case typ.Kind() == reflect.Pointer:
+		guard, isNull = expr+" != nil", expr+" == nil"
+		expr = "*" + expr
+		typ = typ.Elem()
+	case typ.Kind() == reflect.Struct && typ.PkgPath() == "database/sql":
+		// sql.NullString, sql.NullInt64 and so on.
+		guard, isNull = expr+".Valid", "!"+expr+".Valid"
+		if typ != nullStringType {
+			typ = nil
+		}
+		expr += ".String"
+	}
+
+	var ret []validation
+	if isNull != "" && !c.null && !c.autoIncr && !c.autoNow && !c.autoNowAdd {
+		ret = append(ret, validation{cond: isNull, reason: "must not be NULL"})
+	}
+	if typ == nil {
+		return ret
+	}
+	if typ == nullStringType {
+		typ = typ.Field(0).Type
+	}
+
+	name, args := parseType(c.typ)
+	fields := strings.Fields(name)
+	if len(fields) == 0 {
+		return ret
+	}
+	name = fields[0]
+	unsigned := c.unsigned || strings.Contains(strings.ToUpper(c.typ), "UNSIGNED")
+	size := c.size
+	if size == 0 && len(args) > 0 {
+		size = args[0]
+	}
+
+	var v *validation
+	switch typ.Kind() {
+	case reflect.String:
+		if typ.Name() != "string" || typ.PkgPath() != "" {
+			expr = "string(" + expr + ")"
+		}
+		v = stringValidation(name, c.typ, size, expr)
+	case reflect.Slice:
+		if typ.Elem().Kind() == reflect.Uint8 && typ != jsonRawMessageType {
+			v = bytesValidation(name, size, expr)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v = integerValidation(name, unsigned, typ, expr)
+	}
+	if v == nil {
+		return ret
+	}
+	if guard != "" {
+		v.cond = guard + " && " + v.cond
+	}
+	return append(ret, *v)
+}
+
+func stringValidation(name, typ string, size int, expr string) *validation {
+	switch name {
+	case "CHAR", "VARCHAR":
+		size := withDefault(size, 1)
+		return &validation{
+			cond:   fmt.Sprintf("utf8.RuneCountInString(%s) > %d", expr, size),
+			reason: fmt.Sprintf("must be at most %d characters", size),
+		}
+	case "TINYTEXT", "TEXT", "MEDIUMTEXT":
+		return &validation{
+			cond:   fmt.Sprintf("len(%s) > %d", expr, textBytes[name]),
+			reason: fmt.Sprintf("must be at most %d bytes", textBytes[name]),
+		}
+	case "ENUM":
+		members := enumMembers(typ)
+		return &validation{
+			cond:   fmt.Sprintf("!%s", goMembership(expr, members)),
+			reason: fmt.Sprintf("must be one of %s", strings.Join(quoteMembers(members), ", ")),
+		}
+	case "SET":
+		members := enumMembers(typ)
+		return &validation{
+			cond: fmt.Sprintf("func() bool {\n for _, s := range strings.Split(%s, \",\") {\n if s != \"\" && !%s {\n return true \n}\n}\n return false \n}()",
+				expr, goMembership("s", members)),
+			reason: fmt.Sprintf("must be a comma-separated list of %s", strings.Join(quoteMembers(members), ", ")),
+		}
+	}
+	return nil
+}
+
+func bytesValidation(name string, size int, expr string) *validation {
+	n := size
+	switch name {
+	case "BINARY":
+		n = withDefault(size, 1)
+	case "VARBINARY":
+	case "TINYBLOB", "BLOB", "MEDIUMBLOB":
+		n = textBytes[name]
+	default:
+		return nil
+	}
+	if n == 0 {
+		return nil
+	}
+	return &validation{
+		cond:   fmt.Sprintf("len(%s) > %d", expr, n),
+		reason: fmt.Sprintf("must be at most %d bytes", n),
+	}
+}
+
+func integerValidation(name string, unsigned bool, typ reflect.Type, expr string) *validation {
+	bits, ok := integerBits[name]
+	if !ok {
+		return nil
+	}
+
+	// the range of the column.
+	var colMin int64
+	var colMax uint64
+	if unsigned {
+		colMax = 1<<bits - 1
+		if bits == 64 {
+			colMax = math.MaxUint64
+		}
+	} else {
+		colMin = -1 << (bits - 1)
+		colMax = 1<<(bits-1) - 1
+	}
+
+	// the range of the field.
+	var goMin int64
+	var goMax uint64
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		bits := typ.Bits()
+		goMin = -1 << (bits - 1)
+		goMax = 1<<(bits-1) - 1
+	default:
+		goMax = math.MaxUint64 >> (64 - typ.Bits())
+	}
+
+	var conds []string
+	if goMin < colMin {
+		conds = append(conds, fmt.Sprintf("%s < %d", expr, colMin))
+	}
+	if goMax > colMax {
+		conds = append(conds, fmt.Sprintf("%s > %d", expr, colMax))
+	}
+	if len(conds) == 0 {
+		return nil
+	}
+	return &validation{
+		cond:   "(" + strings.Join(conds, " || ") + ")",
+		reason: fmt.Sprintf("must be between %d and %d", colMin, colMax),
+	}
+}
+
+// goMembership returns the Go expression that reports whether expr is one of the members.
+func goMembership(expr string, members []string) string {
+	conds := make([]string, 0, len(members))
+	for _, m := range members {
+		conds = append(conds, fmt.Sprintf("%s == %q", expr, m))
+	}
+	if len(conds) == 0 {
+		return "false"
+	}
+	return "(" + strings.Join(conds, " || ") + ")"
+}
+
+func quoteMembers(members []string) []string {
+	ret := make([]string, 0, len(members))
+	for _, m := range members {
+		ret = append(ret, "'"+strings.ReplaceAll(m, "'", "''")+"'")
+	}
+	return ret
+}
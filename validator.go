@@ -3,6 +3,9 @@ package myddlmaker
 import (
 	"fmt"
 	"log"
+	"sort"
+	"strings"
+	"unicode/utf8"
 )
 
 type validationError struct {
@@ -14,16 +17,23 @@ func (e *validationError) Error() string {
 }
 
 type validator struct {
-	SkipValidationFKIndex bool
+	Checks        map[Check]Severity
+	TableChecks   map[string]map[Check]Severity
+	ReservedWords []string
+	Charset       string
+	Collate       string
+	RowFormat     string
+	NamingRules   *NamingRules
 
 	tables []*table
 	errs   []string
+	warns  []string
 
-	// key: table name
+	// key: table name qualified by the schema name
 	// value: table
 	tableMap map[string]*table
 
-	// key: table name, column name
+	// key: table name qualified by the schema name, column name
 	// value: column
 	columnMap map[[2]string]*column
 }
@@ -40,6 +50,19 @@ func (v *validator) Validate() error {
 	for _, table := range v.tables {
 		v.validateIndex(table)
 		v.validateIndexName(table)
+		v.validateReservedWords(table)
+		v.validateKeyLength(table)
+		v.validateRowSize(table)
+		v.validateLimits(table)
+		v.validateComments(table)
+		v.validateRedundantIndexes(table)
+		v.validateDefaults(table)
+		v.validateNamingRules(table)
+		v.validateSpatialIndexes(table)
+		v.validateAutoIncrement(table)
+		v.validateVersion(table)
+		v.validateSoftDelete(table)
+		v.validateFullTextIndexes(table)
 	}
 	v.validateConstraints()
 	v.validateForeignKeys()
@@ -74,19 +97,19 @@ func (v *validator) createTableMap() {
 	columns := make(map[[2]string]*column)
 	for _, table := range v.tables {
 		// validate uniqueness of table names
-		if _, ok := tables[table.name]; ok {
-			v.SaveErrorf("duplicated name of table: %q", table.name)
+		if _, ok := tables[table.fullName()]; ok {
+			v.SaveErrorf("duplicated name of table: %q", table.fullName())
 			continue
 		}
 
-		tables[table.name] = table
+		tables[table.fullName()] = table
 
 		for _, col := range table.columns {
-			name := [2]string{table.name, col.name}
+			name := [2]string{table.fullName(), col.name}
 
 			// validate uniqueness of column names
 			if _, ok := columns[name]; ok {
-				v.SaveErrorf("table %q: duplicated name of column: %q", table.name, col.name)
+				v.SaveErrorf("table %q: duplicated name of column: %q", table.fullName(), col.name)
 				continue
 			}
 
@@ -98,21 +121,18 @@ func (v *validator) createTableMap() {
 }
 
 func (v *validator) validateIndex(table *table) {
-	// check existence of the column in the primary key
-	for _, col := range table.primaryKey.columns {
-		name := [2]string{table.name, col}
-		if _, ok := v.columnMap[name]; !ok {
-			v.SaveErrorf("table %q, primary key: column %q not found", table.name, col)
-			continue
-		}
+	if table.primaryKey == nil {
+		v.Report(CheckMissingPK, SeverityError, table, nil, "table %q: primary key not found, implement the PrimaryKey method", table.fullName())
+	} else {
+		v.validatePrimaryKey(table)
 	}
 
 	for _, idx := range table.indexes {
 		// check existence of the column in the index
 		for _, col := range idx.columns {
-			name := [2]string{table.name, col}
+			name := [2]string{table.fullName(), col}
 			if _, ok := v.columnMap[name]; !ok {
-				v.SaveErrorf("table %q, index %q: column %q not found", table.name, idx.name, col)
+				v.SaveErrorf("table %q, index %q: column %q not found", table.fullName(), idx.name, col)
 				continue
 			}
 		}
@@ -121,21 +141,40 @@ func (v *validator) validateIndex(table *table) {
 	for _, idx := range table.uniqueIndexes {
 		// check existence of the column in the unique index
 		for _, col := range idx.columns {
-			name := [2]string{table.name, col}
+			name := [2]string{table.fullName(), col}
 			if _, ok := v.columnMap[name]; !ok {
-				v.SaveErrorf("table %q, unique index %q: column %q not found", table.name, idx.name, col)
+				v.SaveErrorf("table %q, unique index %q: column %q not found", table.fullName(), idx.name, col)
 				continue
 			}
 		}
 	}
 }
 
+func (v *validator) validatePrimaryKey(table *table) {
+	// check existence of the column in the primary key
+	for _, col := range table.primaryKey.columns {
+		name := [2]string{table.fullName(), col}
+		c, ok := v.columnMap[name]
+		if !ok {
+			if suggestion := closestColumn(table, col); suggestion != "" {
+				v.SaveErrorf("table %q, primary key: column %q not found, did you mean %q?", table.fullName(), col, suggestion)
+			} else {
+				v.SaveErrorf("table %q, primary key: column %q not found", table.fullName(), col)
+			}
+			continue
+		}
+		if c.null {
+			v.Report(CheckNullablePK, SeverityError, table, c, "table %q, primary key: column %q is nullable, the columns of the primary key must be NOT NULL", table.fullName(), col)
+		}
+	}
+}
+
 func (v *validator) validateIndexName(table *table) {
 	seen := map[string]struct{}{}
 
 	for _, idx := range table.indexes {
 		if _, ok := seen[idx.name]; ok {
-			v.SaveErrorf("table %q: duplicated name of index: %q", table.name, idx.name)
+			v.SaveErrorf("table %q: duplicated name of index: %q", table.fullName(), idx.name)
 			continue
 		}
 		seen[idx.name] = struct{}{}
@@ -143,7 +182,7 @@ func (v *validator) validateIndexName(table *table) {
 
 	for _, idx := range table.uniqueIndexes {
 		if _, ok := seen[idx.name]; ok {
-			v.SaveErrorf("table %q: duplicated name of index: %q", table.name, idx.name)
+			v.SaveErrorf("table %q: duplicated name of index: %q", table.fullName(), idx.name)
 			continue
 		}
 		seen[idx.name] = struct{}{}
@@ -151,7 +190,7 @@ func (v *validator) validateIndexName(table *table) {
 
 	for _, idx := range table.fullTextIndexes {
 		if _, ok := seen[idx.name]; ok {
-			v.SaveErrorf("table %q: duplicated name of index: %q", table.name, idx.name)
+			v.SaveErrorf("table %q: duplicated name of index: %q", table.fullName(), idx.name)
 			continue
 		}
 		seen[idx.name] = struct{}{}
@@ -159,23 +198,456 @@ func (v *validator) validateIndexName(table *table) {
 
 	for _, idx := range table.spatialIndexes {
 		if _, ok := seen[idx.name]; ok {
-			v.SaveErrorf("table %q: duplicated name of index: %q", table.name, idx.name)
+			v.SaveErrorf("table %q: duplicated name of index: %q", table.fullName(), idx.name)
 			continue
 		}
 		seen[idx.name] = struct{}{}
 	}
 }
 
+func (v *validator) validateReservedWords(table *table) {
+	// the reserved words are valid identifiers if they are quoted,
+	// but they are error-prone when someone writes raw queries.
+	if isReservedWord(table.name, v.ReservedWords) {
+		v.Report(CheckReservedWord, SeverityWarning, table, nil, "table %q: table name %q is a reserved word", table.fullName(), table.name)
+	}
+	for _, col := range table.columns {
+		if isReservedWord(col.name, v.ReservedWords) {
+			v.Report(CheckReservedWord, SeverityWarning, table, col, "table %q: column name %q is a reserved word", table.fullName(), col.name)
+		}
+	}
+}
+
+func (v *validator) validateKeyLength(table *table) {
+	if table.primaryKey != nil {
+		v.validateKeyLengthOf(table, "primary key", table.primaryKey.columns)
+	}
+	for _, idx := range table.indexes {
+		v.validateKeyLengthOf(table, fmt.Sprintf("index %q", idx.name), idx.columns)
+	}
+	for _, idx := range table.uniqueIndexes {
+		v.validateKeyLengthOf(table, fmt.Sprintf("unique index %q", idx.name), idx.columns)
+	}
+}
+
+func (v *validator) validateKeyLengthOf(table *table, key string, cols []string) {
+	// https://dev.mysql.com/doc/refman/8.0/en/innodb-limits.html
+	const maxKeyLength = 3072
+	maxColumnLength := 3072
+	switch strings.ToUpper(v.RowFormat) {
+	case "REDUNDANT", "COMPACT":
+		maxColumnLength = 767
+	}
+
+	total := 0
+	for _, colName := range cols {
+		col, ok := v.columnMap[[2]string{table.fullName(), colName}]
+		if !ok {
+			// this error is already reported
+			continue
+		}
+		n, ok := col.keyBytes(v.Charset)
+		if !ok {
+			continue
+		}
+		if n > maxColumnLength {
+			v.Report(CheckKeyLength, SeverityError, table, col, "table %q, %s: column %q is %d bytes, exceeds the maximum key length %d bytes", table.fullName(), key, col.name, n, maxColumnLength)
+		}
+		total += n
+	}
+	if total > maxKeyLength {
+		v.Report(CheckKeyLength, SeverityError, table, nil, "table %q, %s: key is %d bytes, exceeds the maximum key length %d bytes", table.fullName(), key, total, maxKeyLength)
+	}
+}
+
+func (v *validator) validateRowSize(table *table) {
+	// https://dev.mysql.com/doc/refman/8.0/en/column-count-limit.html
+	const maxRowSize = 65535
+	const warnRowSize = maxRowSize * 9 / 10
+
+	type columnSize struct {
+		name string
+		size int
+	}
+	var total, nulls int
+	var variables []columnSize
+	for _, col := range table.columns {
+		n, ok := col.rowBytes(v.Charset)
+		if !ok {
+			continue
+		}
+		total += n
+		if col.null {
+			nulls++
+		}
+		typ, _ := parseType(col.typ)
+		if typ == "VARCHAR" || typ == "VARBINARY" {
+			variables = append(variables, columnSize{name: col.name, size: n})
+		}
+	}
+	// the NULL flags
+	total += (nulls + 7) / 8
+
+	if total <= warnRowSize {
+		return
+	}
+
+	// suggest TEXT/BLOB conversions for the widest columns.
+	sort.SliceStable(variables, func(i, j int) bool {
+		return variables[i].size > variables[j].size
+	})
+	if len(variables) > 3 {
+		variables = variables[:3]
+	}
+	var suggestion string
+	if len(variables) > 0 {
+		cols := make([]string, 0, len(variables))
+		for _, col := range variables {
+			cols = append(cols, fmt.Sprintf("%q (%d bytes)", col.name, col.size))
+		}
+		suggestion = ", consider converting " + strings.Join(cols, ", ") + " to TEXT or BLOB"
+	}
+
+	if total > maxRowSize {
+		v.Report(CheckRowSize, SeverityError, table, nil, "table %q: row size is %d bytes, exceeds the maximum row size %d bytes%s", table.fullName(), total, maxRowSize, suggestion)
+	} else {
+		v.Report(CheckRowSize, SeverityWarning, table, nil, "table %q: row size is %d bytes, close to the maximum row size %d bytes%s", table.fullName(), total, maxRowSize, suggestion)
+	}
+}
+
+func (v *validator) validateLimits(table *table) {
+	// https://dev.mysql.com/doc/refman/8.0/en/innodb-limits.html
+	const maxColumns = 1017
+	const maxSecondaryIndexes = 64
+	const maxKeyColumns = 16
+	const maxIdentifierLength = 64
+
+	if len(table.columns) > maxColumns {
+		v.Report(CheckLimits, SeverityError, table, nil, "table %q: %d columns, exceeds the maximum number of columns %d", table.fullName(), len(table.columns), maxColumns)
+	}
+
+	indexes := len(table.indexes) + len(table.uniqueIndexes) + len(table.fullTextIndexes) + len(table.spatialIndexes)
+	if indexes > maxSecondaryIndexes {
+		v.Report(CheckLimits, SeverityError, table, nil, "table %q: %d secondary indexes, exceeds the maximum number of secondary indexes %d", table.fullName(), indexes, maxSecondaryIndexes)
+	}
+
+	if table.primaryKey != nil && len(table.primaryKey.columns) > maxKeyColumns {
+		v.Report(CheckLimits, SeverityError, table, nil, "table %q, primary key: %d columns, exceeds the maximum number of columns in a key %d", table.fullName(), len(table.primaryKey.columns), maxKeyColumns)
+	}
+	for _, idx := range table.indexes {
+		if len(idx.columns) > maxKeyColumns {
+			v.Report(CheckLimits, SeverityError, table, nil, "table %q, index %q: %d columns, exceeds the maximum number of columns in a key %d", table.fullName(), idx.name, len(idx.columns), maxKeyColumns)
+		}
+	}
+	for _, idx := range table.uniqueIndexes {
+		if len(idx.columns) > maxKeyColumns {
+			v.Report(CheckLimits, SeverityError, table, nil, "table %q, unique index %q: %d columns, exceeds the maximum number of columns in a key %d", table.fullName(), idx.name, len(idx.columns), maxKeyColumns)
+		}
+	}
+	for _, fk := range table.foreignKeys {
+		if len(fk.columns) > maxKeyColumns {
+			v.Report(CheckLimits, SeverityError, table, nil, "table %q, foreign key %q: %d columns, exceeds the maximum number of columns in a key %d", table.fullName(), fk.name, len(fk.columns), maxKeyColumns)
+		}
+	}
+
+	// https://dev.mysql.com/doc/refman/8.0/en/identifier-length.html
+	if n := utf8.RuneCountInString(table.schema); n > maxIdentifierLength {
+		v.Report(CheckLimits, SeverityError, table, nil, "table %q: schema name %q is %d characters, exceeds the maximum length %d", table.fullName(), table.schema, n, maxIdentifierLength)
+	}
+	for _, id := range table.identifiers() {
+		n := utf8.RuneCountInString(id.name)
+		if n <= maxIdentifierLength {
+			continue
+		}
+		if id.kind == "table" {
+			v.Report(CheckLimits, SeverityError, table, nil, "table %q: the name is %d characters, exceeds the maximum length %d", table.fullName(), n, maxIdentifierLength)
+		} else {
+			v.Report(CheckLimits, SeverityError, table, id.col, "table %q, %s %q: the name is %d characters, exceeds the maximum length %d", table.fullName(), id.kind, id.name, n, maxIdentifierLength)
+		}
+	}
+}
+
+func (v *validator) validateComments(table *table) {
+	// https://dev.mysql.com/doc/refman/8.0/en/create-table.html
+	const maxTableComment = 2048
+	const maxColumnComment = 1024
+	const maxIndexComment = 1024
+
+	if table.comment != nil {
+		if n := utf8.RuneCountInString(*table.comment); n > maxTableComment {
+			v.Report(CheckCommentLength, SeverityError, table, nil, "table %q: the comment is %d characters, exceeds the maximum length %d", table.fullName(), n, maxTableComment)
+		}
+	}
+	for _, col := range table.columns {
+		if n := utf8.RuneCountInString(col.comment); n > maxColumnComment {
+			v.Report(CheckCommentLength, SeverityError, table, col, "table %q, column %q: the comment is %d characters, exceeds the maximum length %d", table.fullName(), col.name, n, maxColumnComment)
+		}
+	}
+	for _, idx := range table.indexes {
+		if n := utf8.RuneCountInString(idx.comment); n > maxIndexComment {
+			v.Report(CheckCommentLength, SeverityError, table, nil, "table %q, index %q: the comment is %d characters, exceeds the maximum length %d", table.fullName(), idx.name, n, maxIndexComment)
+		}
+	}
+	for _, idx := range table.uniqueIndexes {
+		if n := utf8.RuneCountInString(idx.comment); n > maxIndexComment {
+			v.Report(CheckCommentLength, SeverityError, table, nil, "table %q, unique index %q: the comment is %d characters, exceeds the maximum length %d", table.fullName(), idx.name, n, maxIndexComment)
+		}
+	}
+	for _, idx := range table.fullTextIndexes {
+		if n := utf8.RuneCountInString(idx.comment); n > maxIndexComment {
+			v.Report(CheckCommentLength, SeverityError, table, nil, "table %q, fulltext index %q: the comment is %d characters, exceeds the maximum length %d", table.fullName(), idx.name, n, maxIndexComment)
+		}
+	}
+	for _, idx := range table.spatialIndexes {
+		if n := utf8.RuneCountInString(idx.comment); n > maxIndexComment {
+			v.Report(CheckCommentLength, SeverityError, table, nil, "table %q, spatial index %q: the comment is %d characters, exceeds the maximum length %d", table.fullName(), idx.name, n, maxIndexComment)
+		}
+	}
+}
+
+func (v *validator) validateRedundantIndexes(table *table) {
+	type key struct {
+		name    string // e.g. `index "idx_name"`
+		columns []string
+		unique  bool
+		order   int
+	}
+
+	// the stronger keys come first.
+	var keys []key
+	if table.primaryKey != nil {
+		keys = append(keys, key{name: "primary key", columns: table.primaryKey.columns, unique: true, order: -1})
+	}
+	for i, idx := range table.uniqueIndexes {
+		keys = append(keys, key{name: fmt.Sprintf("unique index %q", idx.name), columns: idx.columns, unique: true, order: len(table.indexes) + i})
+	}
+	for i, idx := range table.indexes {
+		keys = append(keys, key{name: fmt.Sprintf("index %q", idx.name), columns: idx.columns, order: i})
+	}
+
+	// report in the order of the declaration.
+	targets := make([]key, 0, len(keys))
+	for _, k := range keys {
+		if k.order >= 0 {
+			targets = append(targets, k)
+		}
+	}
+	sort.SliceStable(targets, func(i, j int) bool {
+		return targets[i].order < targets[j].order
+	})
+
+	for _, k := range targets {
+		for _, o := range keys {
+			if o.order == k.order {
+				continue
+			}
+			if equalStrings(k.columns, o.columns) {
+				// keep the stronger one, or the first one.
+				if (o.unique && !k.unique) || (o.unique == k.unique && o.order < k.order) {
+					v.Report(CheckRedundantIndex, SeverityWarning, table, nil, "table %q: %s is a duplicate of %s", table.fullName(), k.name, o.name)
+					break
+				}
+				continue
+			}
+			if !k.unique && len(o.columns) > len(k.columns) && hasPrefix(o.columns, k.columns) {
+				v.Report(CheckRedundantIndex, SeverityWarning, table, nil, "table %q: %s is redundant, %s covers it", table.fullName(), k.name, o.name)
+				break
+			}
+		}
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (v *validator) validateDefaults(table *table) {
+	for _, col := range table.columns {
+		if err := col.checkDefault(); err != nil {
+			v.Report(CheckDefaultValue, SeverityError, table, col, "table %q, column %q: invalid default value %s for %s: %v", table.fullName(), col.name, col.def, col.sqlType(), err)
+		}
+	}
+}
+
+func (v *validator) validateNamingRules(table *table) {
+	rules := v.NamingRules
+	if rules == nil {
+		return
+	}
+
+	ids := table.identifiers()
+	for _, id := range ids {
+		if rules.SnakeCase && !isSnakeCase(id.name) {
+			v.Report(CheckNaming, SeverityError, table, id.col, "table %q: %s name %q is not snake case", table.fullName(), id.kind, id.name)
+		}
+		if maxLen := rules.MaxIdentifierLength; maxLen > 0 {
+			if n := utf8.RuneCountInString(id.name); n > maxLen {
+				v.Report(CheckNaming, SeverityError, table, id.col, "table %q: %s name %q is %d characters, exceeds the maximum length %d", table.fullName(), id.kind, id.name, n, maxLen)
+			}
+		}
+	}
+
+	switch rules.TableNameNumber {
+	case TableNameSingular:
+		if isPlural(table.name) {
+			v.Report(CheckNaming, SeverityError, table, nil, "table %q: table name %q is not singular", table.fullName(), table.name)
+		}
+	case TableNamePlural:
+		if !isPlural(table.name) {
+			v.Report(CheckNaming, SeverityError, table, nil, "table %q: table name %q is not plural", table.fullName(), table.name)
+		}
+	}
+
+	if suffix := rules.ForeignKeyColumnSuffix; suffix != "" {
+		for _, fk := range table.foreignKeys {
+			for _, col := range fk.columns {
+				if !strings.HasSuffix(col, suffix) {
+					v.Report(CheckNaming, SeverityError, table, v.columnMap[[2]string{table.fullName(), col}], "table %q, foreign key %q: column %q doesn't have the suffix %q", table.fullName(), fk.name, col, suffix)
+				}
+			}
+		}
+	}
+}
+
+func (v *validator) validateSpatialIndexes(table *table) {
+	// https://dev.mysql.com/doc/refman/8.0/en/creating-spatial-indexes.html
+	for _, idx := range table.spatialIndexes {
+		col, ok := v.columnMap[[2]string{table.fullName(), idx.column}]
+		if !ok {
+			v.SaveErrorf("table %q, spatial index %q: column %q not found", table.fullName(), idx.name, idx.column)
+			continue
+		}
+		if !col.isGeometry() {
+			v.Report(CheckSpatialIndex, SeverityError, table, col, "table %q, spatial index %q: column %q is %s, want a spatial data type such as GEOMETRY", table.fullName(), idx.name, col.name, col.sqlType())
+			continue
+		}
+		if col.null {
+			v.Report(CheckSpatialIndex, SeverityError, table, col, "table %q, spatial index %q: column %q is nullable, spatial indexes require NOT NULL columns", table.fullName(), idx.name, col.name)
+		}
+		if col.srid == nil {
+			v.Report(CheckSpatialIndex, SeverityError, table, col, "table %q, spatial index %q: column %q has no SRID, the optimizer doesn't use spatial indexes on columns without SRID", table.fullName(), idx.name, col.name)
+		}
+	}
+}
+
+func (v *validator) validateFullTextIndexes(table *table) {
+	// https://dev.mysql.com/doc/refman/8.0/en/fulltext-search.html
+	for _, idx := range table.fullTextIndexes {
+		col, ok := v.columnMap[[2]string{table.fullName(), idx.column}]
+		if !ok {
+			v.SaveErrorf("table %q, fulltext index %q: column %q not found", table.fullName(), idx.name, idx.column)
+			continue
+		}
+		if !col.isText() {
+			v.Report(CheckFullTextIndex, SeverityError, table, col, "table %q, fulltext index %q: column %q is %s, want CHAR, VARCHAR or TEXT", table.fullName(), idx.name, col.name, col.sqlType())
+		}
+	}
+}
+
+func (v *validator) validateAutoIncrement(table *table) {
+	// https://dev.mysql.com/doc/refman/8.0/en/innodb-auto-increment-handling.html
+	// There can be only one auto column, and it must be the first column of some key.
+	var autos []*column
+	for _, col := range table.columns {
+		if col.autoIncr {
+			autos = append(autos, col)
+		}
+	}
+	if len(autos) > 1 {
+		names := make([]string, 0, len(autos))
+		for _, col := range autos {
+			names = append(names, fmt.Sprintf("%q", col.name))
+		}
+		v.Report(CheckAutoIncrement, SeverityError, table, nil, "table %q: multiple auto-increment columns %s, a table can have only one", table.fullName(), strings.Join(names, ", "))
+	}
+
+	for _, col := range autos {
+		if !table.hasIndex([]string{col.name}) {
+			v.Report(CheckAutoIncrement, SeverityError, table, col, "table %q: auto-increment column %q must be the first column of the primary key or an index", table.fullName(), col.name)
+		}
+	}
+}
+
+func (v *validator) validateVersion(table *table) {
+	var versions []*column
+	for _, col := range table.columns {
+		if col.version {
+			versions = append(versions, col)
+		}
+	}
+	if len(versions) > 1 {
+		names := make([]string, 0, len(versions))
+		for _, col := range versions {
+			names = append(names, fmt.Sprintf("%q", col.name))
+		}
+		v.SaveErrorf("table %q: multiple version columns %s, a table can have only one", table.fullName(), strings.Join(names, ", "))
+	}
+
+	for _, col := range versions {
+		if !col.isInteger() || col.null {
+			v.SaveErrorf("table %q: version column %q is %s, want a NOT NULL integer type", table.fullName(), col.name, col.sqlType())
+		}
+		if table.primaryKey == nil {
+			v.SaveErrorf("table %q: version column %q requires the primary key", table.fullName(), col.name)
+			continue
+		}
+		for _, key := range table.primaryKey.columns {
+			if key == col.name {
+				v.SaveErrorf("table %q: version column %q must not be a primary key column", table.fullName(), col.name)
+			}
+		}
+	}
+}
+
+func (v *validator) validateSoftDelete(table *table) {
+	var cols []*column
+	for _, col := range table.columns {
+		if col.softDelete {
+			cols = append(cols, col)
+		}
+	}
+	if len(cols) > 1 {
+		names := make([]string, 0, len(cols))
+		for _, col := range cols {
+			names = append(names, fmt.Sprintf("%q", col.name))
+		}
+		v.SaveErrorf("table %q: multiple soft delete columns %s, a table can have only one", table.fullName(), strings.Join(names, ", "))
+	}
+
+	for _, col := range cols {
+		if !col.isTime() || !col.null {
+			v.SaveErrorf("table %q: soft delete column %q is %s, want a NULL DATETIME or TIMESTAMP", table.fullName(), col.name, col.sqlType())
+		}
+		if table.primaryKey == nil {
+			v.SaveErrorf("table %q: soft delete column %q requires the primary key", table.fullName(), col.name)
+		}
+	}
+}
+
 func (v *validator) validateConstraints() {
-	seen := map[string]struct{}{}
+	// the names of constraints must be unique per schema.
+	// key: schema name, constraint name
+	seen := map[[2]string]struct{}{}
 
 	for _, table := range v.tables {
 		for _, fk := range table.foreignKeys {
-			if _, ok := seen[fk.name]; ok {
-				v.SaveErrorf("table %q: duplicated name of foreign key constraint: %q", table.name, fk.name)
+			if fk.logical {
+				continue
+			}
+			name := [2]string{table.schema, fk.name}
+			if _, ok := seen[name]; ok {
+				v.SaveErrorf("table %q: duplicated name of foreign key constraint: %q", table.fullName(), fk.name)
 				continue
 			}
-			seen[fk.name] = struct{}{}
+			seen[name] = struct{}{}
 		}
 	}
 }
@@ -192,90 +664,125 @@ func (v *validator) validateForeignKeys() {
 func (v *validator) validateFKColumns(table *table, fk *ForeignKey) {
 	passed := true
 	for _, col := range fk.columns {
-		name := [2]string{table.name, col}
+		name := [2]string{table.fullName(), col}
 		if _, ok := v.columnMap[name]; !ok {
-			v.SaveErrorf("table %q, foreign key %q: column %q not found", table.name, fk.name, col)
+			v.SaveErrorf("table %q, foreign key %q: column %q not found", table.fullName(), fk.name, col)
 			passed = false
 			continue
 		}
 	}
 
-	if !v.SkipValidationFKIndex {
-		if passed && !v.hasIndex(table, fk.columns) {
-			v.SaveErrorf("table %q, foreign key %q: index required on table %q", table.name, fk.name, table.name)
-		}
+	severity := SeverityError
+	if fk.logical {
+		severity = SeverityWarning
+	}
+	if passed && !table.hasIndex(fk.columns) {
+		v.Report(CheckFKIndex, severity, table, nil, "table %q, foreign key %q: index required on table %q", table.fullName(), fk.name, table.fullName())
 	}
 }
 
 func (v *validator) validateFKRef(table *table, fk *ForeignKey) {
-	ref, ok := v.tableMap[fk.table]
+	ref, ok := v.tableMap[fk.referencedTable(table)]
 	if !ok {
-		v.SaveErrorf("table %q, foreign key %q: referenced table %q not found", table.name, fk.name, fk.table)
+		v.SaveErrorf("table %q, foreign key %q: referenced table %q not found", table.fullName(), fk.name, fk.referencedTable(table))
 		return
 	}
 
+	severity := SeverityError
+	if fk.logical {
+		severity = SeverityWarning
+	}
+
 	passed := true
 	for i, col := range fk.references {
-		refcol, ok := v.columnMap[[2]string{ref.name, col}]
+		refcol, ok := v.columnMap[[2]string{ref.fullName(), col}]
 		if !ok {
 			passed = false
-			v.SaveErrorf("table %q, foreign key %q: referenced column %q.%q not found", table.name, fk.name, ref.name, col)
+			v.SaveErrorf("table %q, foreign key %q: referenced column %q.%q not found", table.fullName(), fk.name, ref.fullName(), col)
 			continue
 		}
 
 		// type check
-		mycol, ok := v.columnMap[[2]string{table.name, fk.columns[i]}]
+		mycol, ok := v.columnMap[[2]string{table.fullName(), fk.columns[i]}]
 		if !ok {
 			// this error is already reported
 			// just ignore it
 			continue
 		}
-		if refcol.typ != mycol.typ || refcol.unsigned != mycol.unsigned {
-			v.SaveErrorf("table %q, foreign key %q: column %q and referenced column %q.%q type mismatch", table.name, fk.name, mycol.name, ref.name, col)
+		if refcol.sqlType() != mycol.sqlType() {
+			v.Report(CheckFKType, SeverityError, table, mycol, "table %q, foreign key %q: column %q and referenced column %q.%q type mismatch: %s != %s", table.fullName(), fk.name, mycol.name, ref.fullName(), col, mycol.sqlType(), refcol.sqlType())
 		}
-		if refcol.charset != mycol.charset {
-			v.SaveErrorf("table %q, foreign key %q: column %q and referenced column %q.%q character set mismatch", table.name, fk.name, mycol.name, ref.name, col)
+		// MySQL rejects foreign key constraints between different character sets or collations.
+		// Logical relations are valid, but joins between them can't use indexes.
+		if mycharset, refcharset := v.columnCharset(mycol), v.columnCharset(refcol); mycharset != refcharset {
+			v.Report(CheckFKCollation, severity, table, mycol, "table %q, foreign key %q: column %q and referenced column %q.%q character set mismatch: %s != %s", table.fullName(), fk.name, mycol.name, ref.fullName(), col, withDefault(mycharset, "(default)"), withDefault(refcharset, "(default)"))
 		}
-		if refcol.collate != mycol.collate {
-			v.SaveErrorf("table %q, foreign key %q: column %q and referenced column %q.%q collate mismatch", table.name, fk.name, mycol.name, ref.name, col)
+		if mycollate, refcollate := v.columnCollate(mycol), v.columnCollate(refcol); mycollate != refcollate {
+			v.Report(CheckFKCollation, severity, table, mycol, "table %q, foreign key %q: column %q and referenced column %q.%q collate mismatch: %s != %s", table.fullName(), fk.name, mycol.name, ref.fullName(), col, withDefault(mycollate, "(default)"), withDefault(refcollate, "(default)"))
 		}
 	}
 
-	if !v.SkipValidationFKIndex {
-		if passed && !v.hasIndex(ref, fk.references) {
-			v.SaveErrorf("table %q, foreign key %q: index required on table %q", table.name, fk.name, ref.name)
-		}
+	if passed && !ref.hasIndex(fk.references) {
+		v.Report(CheckFKRefIndex, severity, table, nil, "table %q, foreign key %q: index required on table %q", table.fullName(), fk.name, ref.fullName())
 	}
 }
 
-func (v *validator) hasIndex(table *table, cols []string) bool {
-	if v.hasPrefix(table.primaryKey.columns, cols) {
-		return true
-	}
-
-	for _, idx := range table.indexes {
-		if v.hasPrefix(idx.columns, cols) {
-			return true
+// closestColumn returns the name of the column that is the most similar to name.
+// It returns an empty string if no column is similar enough.
+func closestColumn(table *table, name string) string {
+	var closest string
+	best := len(name)/2 + 1
+	for _, col := range table.columns {
+		if d := editDistance(strings.ToLower(name), strings.ToLower(col.name)); d < best {
+			best = d
+			closest = col.name
 		}
 	}
+	return closest
+}
 
-	for _, idx := range table.uniqueIndexes {
-		if v.hasPrefix(idx.columns, cols) {
-			return true
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = prev[j-1] + cost
+			if prev[j]+1 < curr[j] {
+				curr[j] = prev[j] + 1
+			}
+			if curr[j-1]+1 < curr[j] {
+				curr[j] = curr[j-1] + 1
+			}
 		}
+		prev, curr = curr, prev
 	}
+	return prev[len(rb)]
+}
 
-	return false
+// columnCharset returns the character set of the column, or the default character set of the table.
+func (v *validator) columnCharset(col *column) string {
+	return withDefault(col.charset, v.Charset)
 }
 
-func (v *validator) hasPrefix(s []string, prefix []string) bool {
-	if len(s) < len(prefix) {
-		return false
+// columnCollate returns the collation of the column, or the default collation of the table.
+// It returns an empty string for the default collation of the character set.
+func (v *validator) columnCollate(col *column) string {
+	if col.collate != "" {
+		return col.collate
 	}
-	for i := range prefix {
-		if s[i] != prefix[i] {
-			return false
-		}
+	if col.charset != "" {
+		// the default collation of the character set.
+		return ""
 	}
-	return true
+	return v.Collate
 }